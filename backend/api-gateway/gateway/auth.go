@@ -0,0 +1,57 @@
+// auth.go - auth: required rotalarda oturum jetonunun gateway'de doğrulanması
+package gateway
+
+import (
+	"eros/shared/apierror"
+	"eros/shared/auth"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// UserIDHeader - Doğrulanmış kullanıcının upstream'e iletildiği başlık
+const UserIDHeader = auth.UserIDHeader
+
+// authenticate - İstekteki jetonu doğrula; geçersizse 401 yaz
+// Tarayıcıların WebSocket API'si başlık gönderemediğinden yükseltme isteklerinde jeton
+// ?token= parametresiyle de gelebilir; parametre upstream'e iletilmeden silinir.
+func (g *Gateway) authenticate(w http.ResponseWriter, r *http.Request, upgrade bool) (int, bool) {
+	if g.tokens == nil {
+		apierror.Write(w, r, apierror.Unavailable("Authentication unavailable"))
+		return 0, false
+	}
+
+	token := auth.BearerToken(r.Header.Get("Authorization"))
+	if upgrade {
+		query := r.URL.Query()
+		if token == "" {
+			token = query.Get("token")
+		}
+		if query.Has("token") {
+			query.Del("token")
+			u := *r.URL
+			u.RawQuery = query.Encode()
+			r.URL = &u
+		}
+	}
+
+	if token == "" {
+		unauthorized(w, r, "Authentication required")
+		return 0, false
+	}
+	claims, err := g.tokens.Verify(token, time.Now())
+	if errors.Is(err, auth.ErrExpiredToken) {
+		unauthorized(w, r, "Session expired")
+		return 0, false
+	}
+	if err != nil {
+		unauthorized(w, r, "Invalid session token")
+		return 0, false
+	}
+	return claims.UserID, true
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, msg string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="eros"`)
+	apierror.Write(w, r, apierror.Unauthorized(msg))
+}
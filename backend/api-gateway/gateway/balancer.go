@@ -0,0 +1,96 @@
+// balancer.go - Servis örnekleri arasında yük dengeleme
+package gateway
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync/atomic"
+)
+
+// Upstream - Bir servisin tek bir örneği
+type Upstream struct {
+	URL     *url.URL
+	proxy   *httputil.ReverseProxy
+	healthy atomic.Bool
+	active  atomic.Int64 // Şu an işlenen istek sayısı (least_conn için)
+}
+
+func newUpstream(raw string, transport http.RoundTripper) (*Upstream, error) {
+	target, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &Upstream{URL: target}
+	u.proxy = httputil.NewSingleHostReverseProxy(target)
+	u.proxy.Transport = transport
+	u.proxy.ErrorHandler = proxyErrorHandler
+	u.proxy.ModifyResponse = proxyModifyResponse
+	u.healthy.Store(true) // İlk kontrole kadar sağlıklı sayılır
+	return u, nil
+}
+
+// Healthy - Örnek dağıtıma açık mı
+func (u *Upstream) Healthy() bool {
+	return u.healthy.Load()
+}
+
+// ActiveRequests - Örneğin şu an işlediği istek sayısı
+func (u *Upstream) ActiveRequests() int64 {
+	return u.active.Load()
+}
+
+// serve - İsteği örneğe ilet (aktif istek sayacını tutarak)
+func (u *Upstream) serve(w http.ResponseWriter, r *http.Request) {
+	u.active.Add(1)
+	defer u.active.Add(-1)
+	u.proxy.ServeHTTP(w, r)
+}
+
+// Balancer - Sağlıklı örneklerden isteği alacak olanı seçer
+type Balancer interface {
+	Next(healthy []*Upstream) *Upstream
+}
+
+// NewBalancer - Strateji adına göre dengeleyici (bilinmeyen ad round_robin)
+func NewBalancer(strategy string) Balancer {
+	if strategy == BalancerLeastConn {
+		return &leastConnBalancer{}
+	}
+	return &roundRobinBalancer{}
+}
+
+// roundRobinBalancer - Örnekleri sırayla dolaşır
+type roundRobinBalancer struct {
+	next atomic.Uint64
+}
+
+func (b *roundRobinBalancer) Next(healthy []*Upstream) *Upstream {
+	if len(healthy) == 0 {
+		return nil
+	}
+	n := b.next.Add(1) - 1
+	return healthy[n%uint64(len(healthy))]
+}
+
+// leastConnBalancer - En az aktif isteği olan örneği seçer (eşitlikte sıra dolaşılır)
+type leastConnBalancer struct {
+	next atomic.Uint64
+}
+
+func (b *leastConnBalancer) Next(healthy []*Upstream) *Upstream {
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	start := int(b.next.Add(1) % uint64(len(healthy)))
+	best := healthy[start]
+	for i := 1; i < len(healthy); i++ {
+		u := healthy[(start+i)%len(healthy)]
+		if u.ActiveRequests() < best.ActiveRequests() {
+			best = u
+		}
+	}
+	return best
+}
@@ -0,0 +1,200 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testUpstreams - Vekili olmayan, yalnızca seçim testleri için örnekler
+func testUpstreams(t *testing.T, n int) []*Upstream {
+	t.Helper()
+	upstreams := make([]*Upstream, n)
+	for i := range upstreams {
+		u, err := newUpstream(fmt.Sprintf("http://backend-%d:8080", i), http.DefaultTransport)
+		if err != nil {
+			t.Fatal(err)
+		}
+		upstreams[i] = u
+	}
+	return upstreams
+}
+
+func TestRoundRobinBalancer(t *testing.T) {
+	upstreams := testUpstreams(t, 3)
+	b := NewBalancer(BalancerRoundRobin)
+
+	for i := 0; i < 7; i++ {
+		if got := b.Next(upstreams); got != upstreams[i%3] {
+			t.Fatalf("pick %d = %s, want %s", i, got.URL, upstreams[i%3].URL)
+		}
+	}
+	if got := b.Next(nil); got != nil {
+		t.Fatalf("no upstreams: got %s", got.URL)
+	}
+	if _, ok := NewBalancer("unknown").(*roundRobinBalancer); !ok {
+		t.Fatal("unknown strategy should fall back to round robin")
+	}
+}
+
+func TestLeastConnBalancer(t *testing.T) {
+	upstreams := testUpstreams(t, 3)
+	b := NewBalancer(BalancerLeastConn)
+
+	upstreams[0].active.Store(4)
+	upstreams[1].active.Store(1)
+	upstreams[2].active.Store(3)
+	for i := 0; i < 3; i++ {
+		if got := b.Next(upstreams); got != upstreams[1] {
+			t.Fatalf("pick %d = %s, want the least busy upstream", i, got.URL)
+		}
+	}
+
+	// Eşitlikte örnekler sırayla seçilir
+	for _, u := range upstreams {
+		u.active.Store(0)
+	}
+	seen := make(map[*Upstream]bool)
+	for i := 0; i < 3; i++ {
+		seen[b.Next(upstreams)] = true
+	}
+	if len(seen) != 3 {
+		t.Fatalf("ties picked %d distinct upstreams, want 3", len(seen))
+	}
+	if got := b.Next(nil); got != nil {
+		t.Fatalf("no upstreams: got %s", got.URL)
+	}
+}
+
+func TestServicePickSkipsUnhealthy(t *testing.T) {
+	for _, strategy := range []string{BalancerRoundRobin, BalancerLeastConn} {
+		t.Run(strategy, func(t *testing.T) {
+			upstreams := testUpstreams(t, 3)
+			svc := &Service{upstreams: upstreams, balancer: NewBalancer(strategy)}
+			upstreams[1].healthy.Store(false)
+
+			for i := 0; i < 10; i++ {
+				if got := svc.pick(); got == upstreams[1] {
+					t.Fatal("picked an unhealthy upstream")
+				}
+			}
+
+			for _, u := range upstreams {
+				u.healthy.Store(false)
+			}
+			if got := svc.pick(); got != nil {
+				t.Fatalf("all upstreams down: picked %s", got.URL)
+			}
+		})
+	}
+}
+
+// namedBackend - Adını yanıt gövdesinde döndüren upstream
+func namedBackend(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, name)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGatewayRoutesAroundUnhealthyBackend(t *testing.T) {
+	a, b := namedBackend(t, "a"), namedBackend(t, "b")
+	cfg := &Config{
+		Services: map[string]*ServiceConfig{
+			"user": {Instances: []string{a.URL, b.URL}, HealthCheck: HealthCheckConfig{Disabled: true}},
+		},
+		Routes: []RouteConfig{{Prefix: "/api/users", Service: "user", RateLimit: "off"}},
+	}
+	if err := cfg.normalize(); err != nil {
+		t.Fatal(err)
+	}
+	g, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := g.handler(cfg.Routes[0], g.services["user"])
+
+	get := func() (int, string) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/1", nil))
+		body, _ := io.ReadAll(rec.Body)
+		return rec.Code, string(body)
+	}
+
+	counts := map[string]int{}
+	for i := 0; i < 4; i++ {
+		_, body := get()
+		counts[body]++
+	}
+	if counts["a"] != 2 || counts["b"] != 2 {
+		t.Fatalf("round robin spread = %v, want 2 each", counts)
+	}
+
+	upstreams := g.services["user"].upstreams
+	upstreams[0].healthy.Store(false)
+	for i := 0; i < 3; i++ {
+		if code, body := get(); code != http.StatusOK || body != "b" {
+			t.Fatalf("with a down: %d %q, want 200 from b", code, body)
+		}
+	}
+
+	upstreams[1].healthy.Store(false)
+	if code, _ := get(); code != http.StatusServiceUnavailable {
+		t.Fatalf("with every backend down: status %d, want 503", code)
+	}
+}
+
+func TestHealthCheckerThresholds(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/readyz" {
+			t.Errorf("probe path = %s", r.URL.Path)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	cfg := &ServiceConfig{Instances: []string{srv.URL}, HealthCheck: HealthCheckConfig{UnhealthyThreshold: 2, HealthyThreshold: 2}}
+	c := &Config{Services: map[string]*ServiceConfig{"user": cfg}, Routes: []RouteConfig{{Prefix: "/api/users", Service: "user"}}}
+	if err := c.normalize(); err != nil {
+		t.Fatal(err)
+	}
+	u, err := newUpstream(srv.URL, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker := newHealthChecker(&Service{Name: "user", config: cfg, upstreams: []*Upstream{u}})
+	ctx := context.Background()
+
+	steps := []struct {
+		status  int
+		healthy bool
+	}{
+		{http.StatusServiceUnavailable, true}, // Tek başarısızlık yetmez
+		{http.StatusOK, true},                 // Sayaç sıfırlanır
+		{http.StatusServiceUnavailable, true},
+		{http.StatusServiceUnavailable, false}, // İkinci ardışık başarısızlık
+		{http.StatusOK, false},                 // Tek başarı yetmez
+		{http.StatusNotFound, true},            // 500 altı yanıtlar sağlıklıdır
+	}
+	for i, step := range steps {
+		status = step.status
+		checker.checkAll(ctx)
+		if u.Healthy() != step.healthy {
+			t.Fatalf("step %d (status %d): healthy = %v, want %v", i, step.status, u.Healthy(), step.healthy)
+		}
+	}
+
+	// Ulaşılamayan örnek de başarısız sayılır
+	srv.Close()
+	checker.checkAll(ctx)
+	checker.checkAll(ctx)
+	if u.Healthy() {
+		t.Fatal("unreachable upstream is still healthy")
+	}
+}
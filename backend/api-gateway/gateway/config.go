@@ -0,0 +1,348 @@
+// config.go - Gateway servis ve yönlendirme ayarları (routes.yaml + ortam değişkenleri)
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dengeleme stratejileri
+const (
+	BalancerRoundRobin = "round_robin"
+	BalancerLeastConn  = "least_conn"
+)
+
+// Config - Servisler (upstream örnekleri) ve yol → servis tablosu
+type Config struct {
+	Services   map[string]*ServiceConfig `yaml:"services"`
+	Routes     []RouteConfig             `yaml:"routes"`
+	RateLimits RateLimitConfig           `yaml:"rate_limits"`
+}
+
+// ServiceConfig - Bir servisin örnekleri ve istek ayarları
+type ServiceConfig struct {
+	Name        string            `yaml:"name"`
+	Instances   []string          `yaml:"instances"`
+	Balancer    string            `yaml:"balancer"` // round_robin (varsayılan) veya least_conn
+	Timeout     time.Duration     `yaml:"timeout"`  // Yanıt için en fazla bekleme, varsayılan 30s
+	HealthCheck HealthCheckConfig `yaml:"health_check"`
+}
+
+// HealthCheckConfig - Aktif sağlık kontrolü
+// Örneğe Interval aralıklarla GET Path (varsayılan /readyz) isteği atılır; 500'ün altındaki her
+// yanıt sağlıklıdır, hazır olmayan servis 503 döndüğü için dağıtımdan çıkar.
+// UnhealthyThreshold ardışık başarısızlıkta örnek dağıtımdan çıkarılır, HealthyThreshold
+// ardışık başarıda geri alınır.
+type HealthCheckConfig struct {
+	Path               string        `yaml:"path"`
+	Interval           time.Duration `yaml:"interval"`
+	Timeout            time.Duration `yaml:"timeout"`
+	UnhealthyThreshold int           `yaml:"unhealthy_threshold"`
+	HealthyThreshold   int           `yaml:"healthy_threshold"`
+	Disabled           bool          `yaml:"disabled"`
+}
+
+// RouteConfig - Yol önekinin yönlendirildiği servis ve rota ayarları
+type RouteConfig struct {
+	Prefix        string           `yaml:"prefix"`
+	Service       string           `yaml:"service"`
+	Methods       []string         `yaml:"methods"`        // Boşsa tüm metodlar
+	Timeout       time.Duration    `yaml:"timeout"`        // Servisin zaman aşımını ezer
+	StripPrefix   string           `yaml:"strip_prefix"`   // Upstream'e iletmeden önce yoldan silinir
+	RewritePrefix string           `yaml:"rewrite_prefix"` // Eşleşen önek bununla değiştirilir (/api/ws/3 → /ws/3)
+	RateLimit     string           `yaml:"rate_limit"`     // Politika adı; boşsa varsayılan politika, "off" sınırsız
+	Auth          string           `yaml:"auth"`           // required: geçerli oturum jetonu olmadan istek iletilmez
+	WebSocket     *WebSocketConfig `yaml:"websocket"`      // Tanımlıysa WebSocket yükseltmeleri vekillenir
+}
+
+// Rota kimlik doğrulama modları
+const (
+	AuthNone     = "none"
+	AuthRequired = "required"
+)
+
+// WebSocketConfig - WebSocket bağlantılarının süreleri ve sınırları
+// Yükseltilmiş bağlantılara rota/servis zaman aşımı uygulanmaz; IdleTimeout boyunca iki
+// yönde de trafik olmazsa bağlantı kapatılır. Sınırlar 0 ise sınırsızdır.
+type WebSocketConfig struct {
+	UpgradeTimeout        time.Duration `yaml:"upgrade_timeout"`          // Upstream bağlantısı ve 101 yanıtı için, varsayılan 10s
+	IdleTimeout           time.Duration `yaml:"idle_timeout"`             // Varsayılan 5m
+	MaxConnections        int           `yaml:"max_connections"`          // Rotadaki toplam açık bağlantı
+	MaxConnectionsPerUser int           `yaml:"max_connections_per_user"` // Kullanıcı (auth yoksa IP) başına açık bağlantı
+}
+
+// Hız sınırı anahtarları
+const (
+	RateLimitKeyIP   = "ip"   // İstemci IP adresi
+	RateLimitKeyUser = "user" // Doğrulanmış jetondaki kullanıcı, yoksa IP
+)
+
+// RateLimitConfig - Hız sınırı politikaları ve kova deposu
+type RateLimitConfig struct {
+	Store     string                     `yaml:"store"`      // memory (varsayılan) veya redis
+	RedisAddr string                     `yaml:"redis_addr"` // store: redis için
+	Default   string                     `yaml:"default"`    // rate_limit tanımlamayan rotaların politikası
+	Policies  map[string]RateLimitPolicy `yaml:"policies"`
+}
+
+// RateLimitPolicy - Per süresinde Requests istek, anlık en fazla Burst istek (token bucket)
+type RateLimitPolicy struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"`
+	Key      string        `yaml:"key"` // ip veya user (varsayılan ip)
+}
+
+// DefaultConfig - Yapılandırma dosyası yokken kullanılan yerel geliştirme ayarları
+func DefaultConfig() *Config {
+	return &Config{
+		Services: map[string]*ServiceConfig{
+			"user":  {Name: "User Service", Instances: []string{"http://localhost:8081"}},
+			"match": {Name: "Match Service", Instances: []string{"http://localhost:8082"}},
+			"chat":  {Name: "Chat Service", Instances: []string{"http://localhost:8083"}},
+		},
+		Routes: []RouteConfig{
+			{Prefix: "/api/auth", Service: "user", RateLimit: "strict"},
+			{Prefix: "/api/users", Service: "user"},
+			{Prefix: "/api/photos", Service: "user", Auth: AuthRequired},
+			{Prefix: "/api/photos/file", Service: "user"}, // İmzalı adresler oturumsuz açılır
+			{Prefix: "/api/form", Service: "user"},
+			{Prefix: "/api/verification", Service: "user"},
+			{Prefix: "/api/admin/photos", Service: "user"},
+			{Prefix: "/api/admin/users", Service: "user"},
+			{Prefix: "/api/swipe", Service: "match", RateLimit: "moderate"},
+			{Prefix: "/api/matches", Service: "match"},
+			{Prefix: "/api/blind", Service: "match", RateLimit: "moderate"},
+			{Prefix: "/api/messages", Service: "chat", RateLimit: "moderate"},
+			{
+				Prefix:        "/api/ws",
+				Service:       "chat",
+				RewritePrefix: "/ws",
+				Auth:          AuthRequired,
+				WebSocket:     &WebSocketConfig{MaxConnections: 10000, MaxConnectionsPerUser: 5},
+			},
+			{Prefix: "/api/admin/moderation", Service: "chat"},
+		},
+		RateLimits: RateLimitConfig{
+			Default: "standard",
+			Policies: map[string]RateLimitPolicy{
+				"strict":   {Requests: 10, Per: time.Minute, Burst: 5, Key: RateLimitKeyIP},
+				"moderate": {Requests: 120, Per: time.Minute, Burst: 30, Key: RateLimitKeyUser},
+				"standard": {Requests: 600, Per: time.Minute, Burst: 100, Key: RateLimitKeyUser},
+			},
+		},
+	}
+}
+
+// LoadConfig - Ayarları path'ten oku (dosya yoksa DefaultConfig), ortam değişkenlerini uygula
+// <SERVIS>_SERVICE_URLS (örn. USER_SERVICE_URLS=http://a:8081,http://b:8081) servisin
+// örneklerini ezer; RATE_LIMIT_STORE ve REDIS_ADDR hız sınırı deposunu seçer.
+func LoadConfig(path string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		cfg = &Config{}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("gateway: parsing %s: %w", path, err)
+		}
+	}
+
+	for key, svc := range cfg.Services {
+		if v := os.Getenv(strings.ToUpper(key) + "_SERVICE_URLS"); v != "" {
+			svc.Instances = splitList(v)
+		}
+	}
+
+	if v := os.Getenv("RATE_LIMIT_STORE"); v != "" {
+		cfg.RateLimits.Store = v
+	}
+	if v := os.Getenv("REDIS_ADDR"); v != "" {
+		cfg.RateLimits.RedisAddr = v
+	}
+
+	if err := cfg.normalize(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ConfigFromEnv - GATEWAY_CONFIG (varsayılan routes.yaml) dosyasından ayarları yükle
+func ConfigFromEnv() (*Config, error) {
+	path := os.Getenv("GATEWAY_CONFIG")
+	if path == "" {
+		path = "routes.yaml"
+	}
+	return LoadConfig(path)
+}
+
+// normalize - Varsayılanları doldur ve tutarlılığı denetle
+func (c *Config) normalize() error {
+	if len(c.Services) == 0 {
+		return errors.New("gateway: no services configured")
+	}
+
+	for key, svc := range c.Services {
+		if svc == nil {
+			return fmt.Errorf("gateway: service %q has no settings", key)
+		}
+		if svc.Name == "" {
+			svc.Name = key
+		}
+		if len(svc.Instances) == 0 {
+			return fmt.Errorf("gateway: service %q has no instances", key)
+		}
+		for _, raw := range svc.Instances {
+			u, err := url.Parse(raw)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				return fmt.Errorf("gateway: service %q has invalid instance URL %q", key, raw)
+			}
+		}
+
+		switch svc.Balancer {
+		case "":
+			svc.Balancer = BalancerRoundRobin
+		case BalancerRoundRobin, BalancerLeastConn:
+		default:
+			return fmt.Errorf("gateway: service %q has unknown balancer %q", key, svc.Balancer)
+		}
+		if svc.Timeout <= 0 {
+			svc.Timeout = 30 * time.Second
+		}
+
+		hc := &svc.HealthCheck
+		if hc.Path == "" {
+			hc.Path = "/readyz"
+		}
+		if hc.Interval <= 0 {
+			hc.Interval = 10 * time.Second
+		}
+		if hc.Timeout <= 0 {
+			hc.Timeout = 2 * time.Second
+		}
+		if hc.UnhealthyThreshold <= 0 {
+			hc.UnhealthyThreshold = 2
+		}
+		if hc.HealthyThreshold <= 0 {
+			hc.HealthyThreshold = 1
+		}
+	}
+
+	if len(c.Routes) == 0 {
+		return errors.New("gateway: no routes configured")
+	}
+	for i, route := range c.Routes {
+		if !strings.HasPrefix(route.Prefix, "/") {
+			return fmt.Errorf("gateway: route %d has invalid prefix %q", i, route.Prefix)
+		}
+		if _, ok := c.Services[route.Service]; !ok {
+			return fmt.Errorf("gateway: route %s points to unknown service %q", route.Prefix, route.Service)
+		}
+		for j, m := range route.Methods {
+			c.Routes[i].Methods[j] = strings.ToUpper(m)
+		}
+		if _, ok := c.RateLimits.Policies[route.RateLimit]; route.RateLimit != "" && route.RateLimit != "off" && !ok {
+			return fmt.Errorf("gateway: route %s uses unknown rate limit policy %q", route.Prefix, route.RateLimit)
+		}
+		if route.RewritePrefix != "" {
+			if route.StripPrefix != "" {
+				return fmt.Errorf("gateway: route %s sets both strip_prefix and rewrite_prefix", route.Prefix)
+			}
+			if !strings.HasPrefix(route.RewritePrefix, "/") {
+				return fmt.Errorf("gateway: route %s has invalid rewrite_prefix %q", route.Prefix, route.RewritePrefix)
+			}
+		}
+		switch route.Auth {
+		case "":
+			c.Routes[i].Auth = AuthNone
+		case AuthNone, AuthRequired:
+		default:
+			return fmt.Errorf("gateway: route %s has unknown auth mode %q", route.Prefix, route.Auth)
+		}
+		if ws := route.WebSocket; ws != nil {
+			if ws.UpgradeTimeout <= 0 {
+				ws.UpgradeTimeout = 10 * time.Second
+			}
+			if ws.IdleTimeout <= 0 {
+				ws.IdleTimeout = 5 * time.Minute
+			}
+			if ws.MaxConnections < 0 || ws.MaxConnectionsPerUser < 0 {
+				return fmt.Errorf("gateway: route %s has negative websocket connection limits", route.Prefix)
+			}
+		}
+	}
+
+	return c.RateLimits.normalize()
+}
+
+// normalize - Hız sınırı politikalarını denetle
+func (c *RateLimitConfig) normalize() error {
+	switch c.Store {
+	case "":
+		c.Store = "memory"
+	case "memory":
+	case "redis":
+		if c.RedisAddr == "" {
+			return errors.New("gateway: rate limit store redis needs redis_addr (REDIS_ADDR)")
+		}
+	default:
+		return fmt.Errorf("gateway: unknown rate limit store %q", c.Store)
+	}
+
+	if _, ok := c.Policies[c.Default]; c.Default != "" && !ok {
+		return fmt.Errorf("gateway: default rate limit policy %q is not defined", c.Default)
+	}
+	for name, p := range c.Policies {
+		if p.Requests <= 0 || p.Per <= 0 {
+			return fmt.Errorf("gateway: rate limit policy %q needs positive requests and per", name)
+		}
+		switch p.Key {
+		case "":
+			p.Key = RateLimitKeyIP
+		case RateLimitKeyIP, RateLimitKeyUser:
+		default:
+			return fmt.Errorf("gateway: rate limit policy %q has unknown key %q", name, p.Key)
+		}
+		c.Policies[name] = p
+	}
+
+	return nil
+}
+
+// MaxRequestTimeout - Rota ve servis zaman aşımlarının en büyüğü
+// Sunucunun yazma zaman aşımı bundan kısa olursa uzun istekler upstream yanıtlamadan kesilir.
+func (c *Config) MaxRequestTimeout() time.Duration {
+	var max time.Duration
+	for _, svc := range c.Services {
+		if svc.Timeout > max {
+			max = svc.Timeout
+		}
+	}
+	for _, route := range c.Routes {
+		if route.Timeout > max {
+			max = route.Timeout
+		}
+	}
+	return max
+}
+
+// splitList - Virgülle ayrılmış listeyi boşlukları atarak böl
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
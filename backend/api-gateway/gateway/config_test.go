@@ -0,0 +1,151 @@
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfigDefaults(t *testing.T) {
+	t.Setenv("USER_SERVICE_URLS", "http://user-1:8081, http://user-2:8081,")
+	t.Setenv("RATE_LIMIT_STORE", "")
+	t.Setenv("REDIS_ADDR", "")
+
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := cfg.Services["user"]
+	if len(user.Instances) != 2 || user.Instances[1] != "http://user-2:8081" {
+		t.Fatalf("user instances = %v, want the USER_SERVICE_URLS list", user.Instances)
+	}
+	if user.Balancer != BalancerRoundRobin || user.Timeout != 30*time.Second {
+		t.Fatalf("user defaults = %s %v", user.Balancer, user.Timeout)
+	}
+	hc := user.HealthCheck
+	if hc.Path != "/readyz" || hc.Interval != 10*time.Second || hc.Timeout != 2*time.Second || hc.UnhealthyThreshold != 2 || hc.HealthyThreshold != 1 {
+		t.Fatalf("health check defaults = %+v", hc)
+	}
+	if cfg.RateLimits.Store != "memory" {
+		t.Fatalf("rate limit store = %q, want memory", cfg.RateLimits.Store)
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.yaml")
+	yaml := `
+services:
+  chat:
+    instances: [http://chat-1:8083, http://chat-2:8083]
+    balancer: least_conn
+    timeout: 5s
+    health_check:
+      path: /healthz
+      unhealthy_threshold: 3
+routes:
+  - prefix: /api/messages
+    service: chat
+    methods: [get, post]
+    timeout: 90s
+  - prefix: /api/ws
+    service: chat
+    rewrite_prefix: /ws
+    auth: required
+    websocket: {}
+rate_limits:
+  default: standard
+  policies:
+    standard: {requests: 60, per: 1m}
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CHAT_SERVICE_URLS", "")
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chat := cfg.Services["chat"]
+	if chat.Name != "chat" || chat.Balancer != BalancerLeastConn || chat.Timeout != 5*time.Second {
+		t.Fatalf("chat = %+v", chat)
+	}
+	if chat.HealthCheck.Path != "/healthz" || chat.HealthCheck.UnhealthyThreshold != 3 || chat.HealthCheck.HealthyThreshold != 1 {
+		t.Fatalf("chat health check = %+v", chat.HealthCheck)
+	}
+	if m := cfg.Routes[0].Methods; m[0] != "GET" || m[1] != "POST" || cfg.Routes[0].Auth != AuthNone {
+		t.Fatalf("messages route = %+v", cfg.Routes[0])
+	}
+	if ws := cfg.Routes[1].WebSocket; ws.UpgradeTimeout != 10*time.Second || ws.IdleTimeout != 5*time.Minute {
+		t.Fatalf("websocket defaults = %+v", ws)
+	}
+	if p := cfg.RateLimits.Policies["standard"]; p.Key != RateLimitKeyIP {
+		t.Fatalf("policy key = %q, want ip", p.Key)
+	}
+	if got := cfg.MaxRequestTimeout(); got != 90*time.Second {
+		t.Fatalf("MaxRequestTimeout = %v, want 90s", got)
+	}
+
+	if err := os.WriteFile(path, []byte("services: [not a map"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "parsing") {
+		t.Fatalf("invalid yaml: err = %v", err)
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	valid := func() *Config {
+		return &Config{
+			Services: map[string]*ServiceConfig{"user": {Instances: []string{"http://user:8081"}}},
+			Routes:   []RouteConfig{{Prefix: "/api/users", Service: "user"}},
+			RateLimits: RateLimitConfig{
+				Policies: map[string]RateLimitPolicy{"strict": {Requests: 10, Per: time.Minute}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string // Hata mesajında geçmesi gereken parça
+	}{
+		{"no services", func(c *Config) { c.Services = nil }, "no services"},
+		{"service without settings", func(c *Config) { c.Services["chat"] = nil }, "has no settings"},
+		{"no instances", func(c *Config) { c.Services["user"].Instances = nil }, "has no instances"},
+		{"relative instance url", func(c *Config) { c.Services["user"].Instances = []string{"user:8081"} }, "invalid instance URL"},
+		{"unknown balancer", func(c *Config) { c.Services["user"].Balancer = "random" }, "unknown balancer"},
+		{"no routes", func(c *Config) { c.Routes = nil }, "no routes"},
+		{"prefix without slash", func(c *Config) { c.Routes[0].Prefix = "api/users" }, "invalid prefix"},
+		{"unknown service", func(c *Config) { c.Routes[0].Service = "billing" }, "unknown service"},
+		{"unknown rate limit policy", func(c *Config) { c.Routes[0].RateLimit = "loose" }, "unknown rate limit policy"},
+		{"strip and rewrite", func(c *Config) { c.Routes[0].StripPrefix, c.Routes[0].RewritePrefix = "/api", "/v1" }, "both strip_prefix and rewrite_prefix"},
+		{"relative rewrite", func(c *Config) { c.Routes[0].RewritePrefix = "v1" }, "invalid rewrite_prefix"},
+		{"unknown auth mode", func(c *Config) { c.Routes[0].Auth = "optional" }, "unknown auth mode"},
+		{"negative websocket limits", func(c *Config) { c.Routes[0].WebSocket = &WebSocketConfig{MaxConnections: -1} }, "negative websocket"},
+		{"unknown store", func(c *Config) { c.RateLimits.Store = "memcached" }, "unknown rate limit store"},
+		{"redis without address", func(c *Config) { c.RateLimits.Store = "redis" }, "needs redis_addr"},
+		{"undefined default policy", func(c *Config) { c.RateLimits.Default = "standard" }, "is not defined"},
+		{"policy without rate", func(c *Config) { c.RateLimits.Policies["strict"] = RateLimitPolicy{Requests: 10} }, "positive requests and per"},
+		{"unknown policy key", func(c *Config) {
+			c.RateLimits.Policies["strict"] = RateLimitPolicy{Requests: 10, Per: time.Minute, Key: "session"}
+		}, "unknown key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.modify(c)
+			err := c.normalize()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+
+	if err := valid().normalize(); err != nil {
+		t.Fatalf("valid config: %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+// gateway.go - Yol önekine göre servislere yönlendiren ters vekil (reverse proxy)
+package gateway
+
+import (
+	"context"
+	"eros/api-gateway/ratelimit"
+	"eros/shared/apierror"
+	"eros/shared/auth"
+	"eros/shared/logging"
+	"eros/shared/tracing"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+// Service - Bir servisin örnekleri ve dengeleyicisi
+type Service struct {
+	Key       string
+	Name      string
+	config    *ServiceConfig
+	upstreams []*Upstream
+	balancer  Balancer
+}
+
+// pick - Sağlıklı örneklerden birini seç (hiçbiri sağlıklı değilse nil)
+func (s *Service) pick() *Upstream {
+	healthy := make([]*Upstream, 0, len(s.upstreams))
+	for _, u := range s.upstreams {
+		if u.Healthy() {
+			healthy = append(healthy, u)
+		}
+	}
+	return s.balancer.Next(healthy)
+}
+
+// Gateway - Yapılandırmadan kurulan servisler ve rotalar
+type Gateway struct {
+	config   *Config
+	services map[string]*Service
+	limiter  *ratelimit.Limiter
+	tokens   *auth.Signer // nil ise auth: required rotalar 503 döner
+
+	mu      sync.Mutex
+	sockets []*socketProxy
+}
+
+// New - Servis örneklerini ve dengeleyicileri hazırla
+func New(cfg *Config) (*Gateway, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 32
+
+	// Vekillenen isteklere istemci span'i açılır ve traceparent eklenir; iz servislerde devam eder
+	proxyTransport := tracing.Transport(transport)
+
+	store, err := newRateLimitStore(cfg.RateLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Gateway{
+		config:   cfg,
+		services: make(map[string]*Service, len(cfg.Services)),
+		limiter:  ratelimit.NewLimiter(store),
+	}
+
+	// Oturum jetonları user-service ile aynı JWT_SECRET ile doğrulanır
+	if g.tokens, err = auth.SignerFromEnv(); err != nil {
+		for _, route := range cfg.Routes {
+			if route.Auth == AuthRequired {
+				slog.Warn("JWT_SECRET not set, route requires auth and will answer 503", "route", route.Prefix)
+			}
+		}
+	}
+
+	for key, sc := range cfg.Services {
+		svc := &Service{Key: key, Name: sc.Name, config: sc, balancer: NewBalancer(sc.Balancer)}
+		for _, raw := range sc.Instances {
+			u, err := newUpstream(raw, proxyTransport)
+			if err != nil {
+				return nil, fmt.Errorf("gateway: service %q: %w", key, err)
+			}
+			svc.upstreams = append(svc.upstreams, u)
+		}
+		g.services[key] = svc
+	}
+
+	return g, nil
+}
+
+// Start - Aktif sağlık kontrollerini ctx iptal edilene kadar arka planda çalıştır
+func (g *Gateway) Start(ctx context.Context) {
+	for _, svc := range g.services {
+		if svc.config.HealthCheck.Disabled {
+			continue
+		}
+		go newHealthChecker(svc).run(ctx)
+	}
+}
+
+// Shutdown - Vekillenen WebSocket bağlantılarını kapat (http.Server.Shutdown bunları izlemez)
+func (g *Gateway) Shutdown(ctx context.Context) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	closed := 0
+	for _, p := range g.sockets {
+		closed += p.closeAll()
+	}
+	if closed > 0 {
+		slog.InfoContext(ctx, "closed proxied websocket connections", "count", closed)
+	}
+}
+
+// Register - Rotaları router'a ekle
+// Uzun önekler önce eşleşir; /api/admin/moderation, /api/admin'den önce denenir.
+func (g *Gateway) Register(router *mux.Router) {
+	routes := make([]RouteConfig, len(g.config.Routes))
+	copy(routes, g.config.Routes)
+	sort.SliceStable(routes, func(i, j int) bool { return len(routes[i].Prefix) > len(routes[j].Prefix) })
+
+	for _, route := range routes {
+		r := router.PathPrefix(route.Prefix).Handler(g.handler(route, g.services[route.Service]))
+		if len(route.Methods) > 0 {
+			// CORS ön kontrol istekleri metod kısıtından etkilenmez
+			methods := append([]string{http.MethodOptions}, route.Methods...)
+			r.Methods(methods...)
+		}
+	}
+}
+
+// handler - Rotanın isteklerini servisin seçilen örneğine ilet
+func (g *Gateway) handler(route RouteConfig, svc *Service) http.Handler {
+	timeout := route.Timeout
+	if timeout <= 0 {
+		timeout = svc.config.Timeout
+	}
+	limit := g.routeLimitFor(route)
+
+	var sockets *socketProxy
+	if route.WebSocket != nil {
+		sockets = newSocketProxy(route.Prefix, *route.WebSocket)
+		g.mu.Lock()
+		g.sockets = append(g.sockets, sockets)
+		g.mu.Unlock()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Kimlik upstream'e sadece gateway tarafından iletilir; istemcinin gönderdiği başlık silinir
+		r.Header.Del(UserIDHeader)
+
+		if !g.allow(w, r, limit) {
+			return
+		}
+
+		upgrade := sockets != nil && isWebSocketUpgrade(r)
+		client := "ip:" + clientIP(r)
+		if route.Auth == AuthRequired {
+			userID, ok := g.authenticate(w, r, upgrade)
+			if !ok {
+				return
+			}
+			r.Header.Set(UserIDHeader, strconv.Itoa(userID))
+			client = "user:" + strconv.Itoa(userID)
+		}
+
+		upstream := svc.pick()
+		if upstream == nil {
+			slog.WarnContext(r.Context(), "no healthy upstream", "upstream_service", svc.Name, "method", r.Method, "path", r.URL.Path)
+			apierror.Write(w, r, apierror.Unavailable("Service unavailable"))
+			return
+		}
+
+		r.URL = rewritePath(route, r.URL)
+
+		// Yükseltilmiş bağlantılar uzun ömürlüdür; istek zaman aşımı yerine boşta kalma süresi uygulanır
+		if upgrade {
+			slog.DebugContext(r.Context(), "routing websocket", "upstream_service", svc.Name, "upstream", upstream.URL.Host, "path", r.URL.Path)
+			sockets.serve(w, r, upstream, client)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		slog.DebugContext(r.Context(), "routing request", "upstream_service", svc.Name, "upstream", upstream.URL.Host, "method", r.Method, "path", r.URL.Path)
+		upstream.serve(w, r)
+	})
+}
+
+// rewritePath - Rotanın strip_prefix / rewrite_prefix ayarına göre upstream yolu
+func rewritePath(route RouteConfig, in *url.URL) *url.URL {
+	var path string
+	switch {
+	case route.StripPrefix != "":
+		path = "/" + strings.TrimLeft(strings.TrimPrefix(in.Path, route.StripPrefix), "/")
+	case route.RewritePrefix != "":
+		rest := strings.TrimPrefix(in.Path, route.Prefix)
+		path = strings.TrimRight(route.RewritePrefix, "/") + "/" + strings.TrimLeft(rest, "/")
+		if rest == "" {
+			path = route.RewritePrefix
+		}
+	default:
+		return in
+	}
+
+	u := *in
+	u.Path = path
+	u.RawPath = ""
+	return &u
+}
+
+// proxyErrorHandler - Upstream'e ulaşılamadığında veya zaman aşımında yanıt
+func proxyErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		// İstemci bağlantıyı kapattı; yanıt gönderilecek kimse yok
+		return
+	case errors.Is(err, context.DeadlineExceeded):
+		slog.ErrorContext(r.Context(), "upstream timeout", "method", r.Method, "path", r.URL.Path, "error", err)
+		apierror.Write(w, r, apierror.GatewayTimeout("Upstream timeout"))
+	default:
+		slog.ErrorContext(r.Context(), "upstream error", "method", r.Method, "path", r.URL.Path, "error", err)
+		apierror.Write(w, r, apierror.BadGateway("Bad gateway"))
+	}
+}
+
+// proxyModifyResponse - İstek kimliği yanıta gateway tarafından yazılır; upstream'inki tekrarlanmasın
+func proxyModifyResponse(resp *http.Response) error {
+	resp.Header.Del(logging.RequestIDHeader)
+	return nil
+}
@@ -0,0 +1,201 @@
+// health.go - Servis örneklerinin aktif sağlık kontrolü ve toplu hazırlık raporu
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"eros/shared/health"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// healthChecker - Bir servisin örneklerini düzenli aralıklarla yoklar
+type healthChecker struct {
+	service *Service
+	client  *http.Client
+	// Ardışık sonuç sayaçları; sadece kontrol döngüsünden erişilir
+	failures  map[*Upstream]int
+	successes map[*Upstream]int
+}
+
+func newHealthChecker(service *Service) *healthChecker {
+	return &healthChecker{
+		service:   service,
+		client:    &http.Client{Timeout: service.config.HealthCheck.Timeout},
+		failures:  make(map[*Upstream]int),
+		successes: make(map[*Upstream]int),
+	}
+}
+
+// run - ctx iptal edilene kadar kontrolleri sürdür
+func (h *healthChecker) run(ctx context.Context) {
+	ticker := time.NewTicker(h.service.config.HealthCheck.Interval)
+	defer ticker.Stop()
+
+	h.checkAll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.checkAll(ctx)
+		}
+	}
+}
+
+// checkAll - Tüm örnekleri yokla ve eşikleri aşanların durumunu değiştir
+func (h *healthChecker) checkAll(ctx context.Context) {
+	cfg := h.service.config.HealthCheck
+
+	for _, u := range h.service.upstreams {
+		if h.probe(ctx, u) {
+			h.failures[u] = 0
+			h.successes[u]++
+			if !u.Healthy() && h.successes[u] >= cfg.HealthyThreshold {
+				u.healthy.Store(true)
+				slog.InfoContext(ctx, "upstream is healthy again", "upstream_service", h.service.Name, "upstream", u.URL.String())
+			}
+			continue
+		}
+
+		h.successes[u] = 0
+		h.failures[u]++
+		if u.Healthy() && h.failures[u] >= cfg.UnhealthyThreshold {
+			u.healthy.Store(false)
+			slog.WarnContext(ctx, "upstream marked unhealthy", "upstream_service", h.service.Name, "upstream", u.URL.String(), "failed_checks", h.failures[u])
+		}
+	}
+}
+
+// probe - Örnek yanıt veriyor mu (500'ün altındaki her durum kodu sağlıklıdır)
+func (h *healthChecker) probe(ctx context.Context, u *Upstream) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(u.URL.String(), "/")+h.service.config.HealthCheck.Path, nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode < http.StatusInternalServerError
+}
+
+// Toplu sağlık durumları
+const (
+	HealthHealthy   = "healthy"   // Tüm servisler hazır
+	HealthDegraded  = "degraded"  // Bazı örnekler veya kritik olmayan bağımlılıklar çalışmıyor
+	HealthUnhealthy = "unhealthy" // En az bir serviste hazır örnek yok
+)
+
+// HealthReport - Gateway'in /health ve /readyz yanıtı
+type HealthReport struct {
+	Status   string                   `json:"status"`
+	Services map[string]ServiceHealth `json:"services"`
+}
+
+// ServiceHealth - Bir servisin örneklerinin hazırlık durumu
+type ServiceHealth struct {
+	Name      string           `json:"name"`
+	Balancer  string           `json:"balancer"`
+	Status    string           `json:"status"` // En iyi örneğin durumu (ok, degraded, unavailable)
+	Instances []InstanceHealth `json:"instances"`
+}
+
+// InstanceHealth - Örneğin /readyz yanıtı ve gecikmesi
+type InstanceHealth struct {
+	URL            string                        `json:"url"`
+	InRotation     bool                          `json:"in_rotation"` // Aktif sağlık kontrolüne göre dağıtımda mı
+	ActiveRequests int64                         `json:"active_requests"`
+	Status         string                        `json:"status"`
+	LatencyMS      float64                       `json:"latency_ms"`
+	Checks         map[string]health.CheckResult `json:"checks,omitempty"`
+	Error          string                        `json:"error,omitempty"`
+}
+
+// Health - Tüm örneklerin hazırlık uçlarını paralel yokla
+func (g *Gateway) Health(ctx context.Context) HealthReport {
+	report := HealthReport{Status: HealthHealthy, Services: make(map[string]ServiceHealth, len(g.services))}
+
+	// Örnek sonuçları servislerin Instances dizilerine doğrudan yazılır
+	var wg sync.WaitGroup
+	for key, svc := range g.services {
+		sh := ServiceHealth{Name: svc.Name, Balancer: svc.config.Balancer, Instances: make([]InstanceHealth, len(svc.upstreams))}
+		for i, u := range svc.upstreams {
+			wg.Add(1)
+			go func(svc *Service, i int, u *Upstream) {
+				defer wg.Done()
+				sh.Instances[i] = probeReadiness(ctx, svc.config.HealthCheck, u)
+			}(svc, i, u)
+		}
+		report.Services[key] = sh
+	}
+	wg.Wait()
+
+	for key, sh := range report.Services {
+		sh.Status = health.StatusDown
+		for _, inst := range sh.Instances {
+			switch {
+			case inst.Status == health.StatusOK:
+				sh.Status = health.StatusOK
+			case inst.Status == health.StatusDegraded && sh.Status == health.StatusDown:
+				sh.Status = health.StatusDegraded
+			}
+			if inst.Status != health.StatusOK && report.Status == HealthHealthy {
+				report.Status = HealthDegraded
+			}
+		}
+		if sh.Status == health.StatusDown {
+			report.Status = HealthUnhealthy
+		}
+		report.Services[key] = sh
+	}
+
+	return report
+}
+
+// probeReadiness - Örneğin hazırlık ucunu çağır ve yanıtını çöz
+func probeReadiness(ctx context.Context, cfg HealthCheckConfig, u *Upstream) InstanceHealth {
+	inst := InstanceHealth{URL: u.URL.String(), InRotation: u.Healthy(), ActiveRequests: u.ActiveRequests(), Status: health.StatusDown}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(u.URL.String(), "/")+cfg.Path, nil)
+	if err != nil {
+		inst.Error = err.Error()
+		return inst
+	}
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	inst.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		inst.Error = err.Error()
+		return inst
+	}
+	defer resp.Body.Close()
+
+	var ready health.Report
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&ready); err != nil || ready.Status == "" {
+		// Hazırlık raporu vermeyen upstream: durum koduna göre karar ver
+		if resp.StatusCode < http.StatusInternalServerError {
+			inst.Status = health.StatusOK
+		} else {
+			inst.Error = resp.Status
+		}
+		return inst
+	}
+
+	inst.Status = ready.Status
+	inst.Checks = ready.Checks
+	if resp.StatusCode >= http.StatusInternalServerError && inst.Status == health.StatusOK {
+		inst.Status = health.StatusDown
+		inst.Error = resp.Status
+	}
+	return inst
+}
@@ -0,0 +1,148 @@
+package gateway
+
+import (
+	"context"
+	"eros/shared/health"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// readyBackend - /readyz ucu verilen duruma göre yanıt veren örnek
+//
+//	ok, degraded, down  shared/health raporu (down: kritik bağımlılık çalışmıyor, 503)
+//	plain               Rapor vermeyen 200
+//	error               Rapor vermeyen 500
+//	closed              Ulaşılamayan örnek
+func readyBackend(t *testing.T, kind string) string {
+	t.Helper()
+	checker := health.New("backend", time.Second)
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+	switch kind {
+	case "degraded":
+		checker.Add("ai", false, failing)
+	case "down":
+		checker.Add("db", true, failing)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch kind {
+		case "plain":
+			w.Write([]byte("ok"))
+		case "error":
+			http.Error(w, "boom", http.StatusInternalServerError)
+		default:
+			checker.Ready(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	if kind == "closed" {
+		srv.Close()
+	}
+	return srv.URL
+}
+
+func TestGatewayHealthAggregation(t *testing.T) {
+	tests := []struct {
+		name     string
+		services map[string][]string
+		want     map[string]string
+		status   string
+	}{
+		{
+			"all ready",
+			map[string][]string{"user": {"ok", "ok"}, "chat": {"plain"}},
+			map[string]string{"user": health.StatusOK, "chat": health.StatusOK},
+			HealthHealthy,
+		},
+		{
+			"one instance down",
+			map[string][]string{"user": {"ok", "down"}},
+			map[string]string{"user": health.StatusOK},
+			HealthDegraded,
+		},
+		{
+			"optional dependency failing",
+			map[string][]string{"user": {"ok"}, "match": {"degraded"}},
+			map[string]string{"user": health.StatusOK, "match": health.StatusDegraded},
+			HealthDegraded,
+		},
+		{
+			"degraded beats down",
+			map[string][]string{"match": {"down", "degraded"}},
+			map[string]string{"match": health.StatusDegraded},
+			HealthDegraded,
+		},
+		{
+			"no ready instance",
+			map[string][]string{"user": {"ok"}, "chat": {"down", "error", "closed"}},
+			map[string]string{"user": health.StatusOK, "chat": health.StatusDown},
+			HealthUnhealthy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Services: map[string]*ServiceConfig{}}
+			for key, kinds := range tt.services {
+				sc := &ServiceConfig{HealthCheck: HealthCheckConfig{Disabled: true}}
+				for _, kind := range kinds {
+					sc.Instances = append(sc.Instances, readyBackend(t, kind))
+				}
+				cfg.Services[key] = sc
+				cfg.Routes = append(cfg.Routes, RouteConfig{Prefix: "/api/" + key, Service: key})
+			}
+			if err := cfg.normalize(); err != nil {
+				t.Fatal(err)
+			}
+			g, err := New(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			report := g.Health(context.Background())
+			if report.Status != tt.status {
+				t.Errorf("status = %q, want %q", report.Status, tt.status)
+			}
+			for key, want := range tt.want {
+				if got := report.Services[key]; got.Status != want || len(got.Instances) != len(tt.services[key]) {
+					t.Errorf("%s = %+v, want status %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGatewayHealthReportsFailures(t *testing.T) {
+	cfg := &Config{
+		Services: map[string]*ServiceConfig{
+			"user": {Instances: []string{readyBackend(t, "down"), readyBackend(t, "error"), readyBackend(t, "closed")}, HealthCheck: HealthCheckConfig{Disabled: true}},
+		},
+		Routes: []RouteConfig{{Prefix: "/api/users", Service: "user"}},
+	}
+	if err := cfg.normalize(); err != nil {
+		t.Fatal(err)
+	}
+	g, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.services["user"].upstreams[2].healthy.Store(false)
+
+	instances := g.Health(context.Background()).Services["user"].Instances
+
+	// Kritik bağımlılığı çalışmayan örneğin kontrolleri rapora taşınır
+	if db := instances[0].Checks["db"]; instances[0].Status != health.StatusDown || db.Status != health.StatusDown || db.Error != "connection refused" {
+		t.Errorf("down instance = %+v", instances[0])
+	}
+	if instances[1].Status != health.StatusDown || instances[1].Error != "500 Internal Server Error" {
+		t.Errorf("error instance = %+v", instances[1])
+	}
+	if instances[2].Status != health.StatusDown || instances[2].Error == "" || instances[2].InRotation {
+		t.Errorf("closed instance = %+v", instances[2])
+	}
+	if !instances[0].InRotation || instances[0].URL != cfg.Services["user"].Instances[0] {
+		t.Errorf("instance order or rotation state lost: %+v", instances[0])
+	}
+}
@@ -0,0 +1,155 @@
+// limit.go - Rota başına hız sınırı (istemci IP'si veya doğrulanmış kullanıcı başına token bucket)
+package gateway
+
+import (
+	"context"
+	"eros/api-gateway/ratelimit"
+	"eros/shared/apierror"
+	"eros/shared/auth"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// userSessionsPerIP - "user" politikalarında bir IP'nin toplam sınırı, kullanıcı sınırının bu katıdır
+const userSessionsPerIP = 10
+
+// routeLimit - Rotaya uygulanan politika
+type routeLimit struct {
+	name   string
+	key    string
+	limit  ratelimit.Limit
+	prefix string
+}
+
+// newRateLimitStore - Ayarlara göre bellek içi veya Redis deposu
+func newRateLimitStore(cfg RateLimitConfig) (ratelimit.Store, error) {
+	if cfg.Store != "redis" {
+		return ratelimit.NewMemoryStore(), nil
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("gateway: connecting to redis at %s: %w", cfg.RedisAddr, err)
+	}
+	return ratelimit.NewRedisStore(client, "eros:ratelimit:"), nil
+}
+
+// routeLimitFor - Rotanın politikası (sınırsızsa nil)
+func (g *Gateway) routeLimitFor(route RouteConfig) *routeLimit {
+	name := route.RateLimit
+	if name == "" {
+		name = g.config.RateLimits.Default
+	}
+	policy, ok := g.config.RateLimits.Policies[name]
+	if name == "off" || !ok {
+		return nil
+	}
+
+	return &routeLimit{
+		name:   name,
+		key:    policy.Key,
+		limit:  ratelimit.Per(policy.Requests, policy.Per, policy.Burst),
+		prefix: route.Prefix,
+	}
+}
+
+// allow - İsteğe izin ver ya da 429 yaz; depo hatasında istek geçer (gateway kilitlenmez)
+func (g *Gateway) allow(w http.ResponseWriter, r *http.Request, rl *routeLimit) bool {
+	if rl == nil {
+		return true
+	}
+
+	bucket := rl.name + ":" + rl.prefix + ":"
+	key := g.clientKey(r, rl.key)
+	decision, err := g.limiter.Allow(r.Context(), bucket+key, rl.limit)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "rate limit store error", "route", rl.prefix, "error", err)
+		return true
+	}
+
+	// Jeton değiştirerek sınırdan kaçılmasın: aynı IP'nin tüm oturumları toplamda
+	// userSessionsPerIP katı ile sınırlıdır (NAT arkasındaki kullanıcılar etkilenmez)
+	if decision.Allowed && strings.HasPrefix(key, "user:") {
+		ipLimit := ratelimit.Limit{Rate: rl.limit.Rate * userSessionsPerIP, Burst: rl.limit.Burst * userSessionsPerIP}
+		ipDecision, err := g.limiter.Allow(r.Context(), bucket+"ip:"+clientIP(r), ipLimit)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "rate limit store error", "route", rl.prefix, "error", err)
+			return true
+		}
+		if !ipDecision.Allowed {
+			decision = ipDecision
+		}
+	}
+
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rl.limit.Burst))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	if decision.Allowed {
+		return true
+	}
+
+	retryAfter := int(math.Ceil(decision.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	apierror.Write(w, r, apierror.RateLimited("Too many requests"))
+	return false
+}
+
+// clientKey - Kovanın sahibi: "user" politikalarında jetondaki kullanıcı, yoksa istemci IP'si
+// Jeton imzası ve süresi burada da doğrulanır; her istekte yeni sahte jeton gönderen istemci
+// yeni kova açamaz, IP kovasına düşer. WebSocket yükseltmelerinde jeton ?token= ile de gelebilir.
+func (g *Gateway) clientKey(r *http.Request, by string) string {
+	if by != RateLimitKeyUser || g.tokens == nil {
+		return "ip:" + clientIP(r)
+	}
+
+	token := auth.BearerToken(r.Header.Get("Authorization"))
+	if token == "" && isWebSocketUpgrade(r) {
+		token = r.URL.Query().Get("token")
+	}
+	if token == "" {
+		return "ip:" + clientIP(r)
+	}
+	claims, err := g.tokens.Verify(token, time.Now())
+	if err != nil {
+		return "ip:" + clientIP(r)
+	}
+	return "user:" + strconv.Itoa(claims.UserID)
+}
+
+// clientIP - İsteği yapan istemcinin adresi
+// X-Forwarded-For sadece önümüzdeki yük dengeleyici (loopback / özel ağ) tarafından eklenmişse
+// dikkate alınır; zincirin en sağındaki adres dengeleyicinin gördüğü istemcidir.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	peer, err := netip.ParseAddr(host)
+	if err != nil || !(peer.IsLoopback() || peer.IsPrivate()) {
+		return host
+	}
+
+	forwarded := r.Header.Get("X-Forwarded-For")
+	if forwarded == "" {
+		return host
+	}
+	parts := strings.Split(forwarded, ",")
+	if addr, err := netip.ParseAddr(strings.TrimSpace(parts[len(parts)-1])); err == nil {
+		return addr.String()
+	}
+	return host
+}
@@ -0,0 +1,133 @@
+package gateway
+
+import (
+	"context"
+	"eros/api-gateway/ratelimit"
+	"eros/shared/auth"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newLimitGateway - JWT_SECRET'lı, bellek içi depolu gateway
+func newLimitGateway(t *testing.T) (*Gateway, *auth.Signer) {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "test-secret")
+	g, err := New(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g, auth.NewSigner([]byte("test-secret"), time.Hour)
+}
+
+func TestClientKey(t *testing.T) {
+	g, signer := newLimitGateway(t)
+	valid, err := signer.Sign(42, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := signer.Sign(42, time.Now().Add(-2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := auth.NewSigner([]byte("other-secret"), time.Hour).Sign(42, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, by, authorization, query string
+		upgrade                        bool
+		want                           string
+	}{
+		{"valid token", RateLimitKeyUser, "Bearer " + valid, "", false, "user:42"},
+		{"no token", RateLimitKeyUser, "", "", false, "ip:203.0.113.7"},
+		{"garbage token", RateLimitKeyUser, "Bearer not-a-jwt", "", false, "ip:203.0.113.7"},
+		{"expired token", RateLimitKeyUser, "Bearer " + expired, "", false, "ip:203.0.113.7"},
+		{"token signed with another secret", RateLimitKeyUser, "Bearer " + forged, "", false, "ip:203.0.113.7"},
+		{"websocket query token", RateLimitKeyUser, "", "?token=" + valid, true, "user:42"},
+		{"query token without upgrade", RateLimitKeyUser, "", "?token=" + valid, false, "ip:203.0.113.7"},
+		{"ip policy ignores token", RateLimitKeyIP, "Bearer " + valid, "", false, "ip:203.0.113.7"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/api/swipe"+tt.query, nil)
+		r.RemoteAddr = "203.0.113.7:5000"
+		if tt.authorization != "" {
+			r.Header.Set("Authorization", tt.authorization)
+		}
+		if tt.upgrade {
+			r.Header.Set("Connection", "Upgrade")
+			r.Header.Set("Upgrade", "websocket")
+		}
+		if got := g.clientKey(r, tt.by); got != tt.want {
+			t.Errorf("%s: clientKey = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAllowRotatingFakeTokensShareIPBucket(t *testing.T) {
+	g, _ := newLimitGateway(t)
+	rl := &routeLimit{name: "moderate", key: RateLimitKeyUser, limit: ratelimit.Limit{Rate: 0.001, Burst: 3}, prefix: "/api/swipe"}
+
+	// Her istekte yeni sahte jeton: kova IP'ye düşer ve burst sonrası reddedilir
+	var codes []int
+	for i := 0; i < 5; i++ {
+		r := httptest.NewRequest(http.MethodPost, "/api/swipe", nil)
+		r.RemoteAddr = "203.0.113.7:5000"
+		r.Header.Set("Authorization", "Bearer fake-"+strconv.Itoa(i))
+		w := httptest.NewRecorder()
+		if !g.allow(w, r, rl) {
+			codes = append(codes, w.Code)
+		}
+	}
+	if len(codes) != 2 || codes[0] != http.StatusTooManyRequests {
+		t.Fatalf("rejected responses %v, want the last 2 requests to get 429", codes)
+	}
+}
+
+func TestAllowRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit ratelimit.Limit
+		want  string
+	}{
+		{"rounds up to whole seconds", ratelimit.Limit{Rate: 1.0 / 2.5, Burst: 1}, "3"},
+		{"never below one second", ratelimit.Limit{Rate: 100, Burst: 1}, "1"},
+		{"one minute interval", ratelimit.Per(1, time.Minute, 1), "60"},
+	}
+	for _, tt := range tests {
+		g, _ := newLimitGateway(t)
+		rl := &routeLimit{name: "test", key: RateLimitKeyIP, limit: tt.limit, prefix: "/api/auth"}
+		now := time.Unix(1_700_000_000, 0)
+		g.limiter = ratelimit.NewLimiter(clockStore{ratelimit.NewMemoryStore(), now})
+
+		var w *httptest.ResponseRecorder
+		for i := 0; i < 2; i++ {
+			r := httptest.NewRequest(http.MethodPost, "/api/auth/login", nil)
+			r.RemoteAddr = "203.0.113.7:5000"
+			w = httptest.NewRecorder()
+			g.allow(w, r, rl)
+		}
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("%s: second request status %d, want 429", tt.name, w.Code)
+		}
+		if got := w.Header().Get("Retry-After"); got != tt.want {
+			t.Errorf("%s: Retry-After = %q, want %q", tt.name, got, tt.want)
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != "0" {
+			t.Errorf("%s: X-RateLimit-Remaining = %q", tt.name, got)
+		}
+	}
+}
+
+// clockStore - İstekler aynı anda gelmiş gibi sabit saatle çalışan depo
+type clockStore struct {
+	ratelimit.Store
+	now time.Time
+}
+
+func (s clockStore) Take(ctx context.Context, key string, limit ratelimit.Limit, _ time.Time) (ratelimit.Decision, error) {
+	return s.Store.Take(ctx, key, limit, s.now)
+}
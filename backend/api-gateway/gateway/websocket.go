@@ -0,0 +1,288 @@
+// websocket.go - WebSocket yükseltmelerinin vekillenmesi (boşta kalma süresi ve bağlantı sınırları)
+package gateway
+
+import (
+	"bufio"
+	"crypto/tls"
+	"eros/shared/apierror"
+	"eros/shared/logging"
+	"eros/shared/metrics"
+	"eros/shared/tracing"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// isWebSocketUpgrade - İstek WebSocket'e yükseltme isteği mi
+func isWebSocketUpgrade(r *http.Request) bool {
+	return headerHasToken(r.Header, "Connection", "upgrade") && strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, part := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var openSockets = metrics.NewGauge("gateway_websocket_connections",
+	"Open proxied WebSocket connections by route prefix.", "route")
+
+// socketProxy - Bir rotanın WebSocket bağlantıları
+type socketProxy struct {
+	route  string
+	config WebSocketConfig
+
+	mu       sync.Mutex
+	open     int
+	byClient map[string]int
+	conns    map[net.Conn]struct{} // Kapanışta kapatılacak istemci ve upstream bağlantıları
+	closing  bool
+}
+
+func newSocketProxy(route string, cfg WebSocketConfig) *socketProxy {
+	return &socketProxy{route: route, config: cfg, byClient: make(map[string]int), conns: make(map[net.Conn]struct{})}
+}
+
+// track - Vekillenen bağlantıları kaydet (kapanış başladıysa false)
+func (p *socketProxy) track(conns ...net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closing {
+		return false
+	}
+	for _, c := range conns {
+		p.conns[c] = struct{}{}
+	}
+	return true
+}
+
+func (p *socketProxy) untrack(conns ...net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, c := range conns {
+		delete(p.conns, c)
+	}
+}
+
+// closeAll - Açık tüm bağlantıları kapat
+// Gateway çerçevelerin ortasına kapanış çerçevesi ekleyemez; upstream kapanırken kendi kapanış
+// çerçevesini gönderir, gateway kapanırken istemciler bağlantı kopmasıyla yeniden bağlanır.
+func (p *socketProxy) closeAll() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closing = true
+	for c := range p.conns {
+		c.Close()
+	}
+	return len(p.conns) / 2
+}
+
+// acquire - Bağlantı için yer ayır; sınır doluysa yazılacak HTTP durumu döner
+func (p *socketProxy) acquire(client string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.config.MaxConnections > 0 && p.open >= p.config.MaxConnections {
+		return http.StatusServiceUnavailable
+	}
+	if p.config.MaxConnectionsPerUser > 0 && p.byClient[client] >= p.config.MaxConnectionsPerUser {
+		return http.StatusTooManyRequests
+	}
+	p.open++
+	p.byClient[client]++
+	openSockets.Inc(p.route)
+	return 0
+}
+
+func (p *socketProxy) release(client string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.open--
+	openSockets.Dec(p.route)
+	if p.byClient[client]--; p.byClient[client] <= 0 {
+		delete(p.byClient, client)
+	}
+}
+
+// serve - Yükseltme isteğini upstream'e ilet; 101 gelirse iki bağlantı arasında veri taşı
+func (p *socketProxy) serve(w http.ResponseWriter, r *http.Request, upstream *Upstream, client string) {
+	switch p.acquire(client) {
+	case http.StatusServiceUnavailable:
+		apierror.Write(w, r, apierror.Unavailable("Too many connections"))
+		return
+	case http.StatusTooManyRequests:
+		apierror.Write(w, r, apierror.RateLimited("Too many connections for this user"))
+		return
+	}
+	defer p.release(client)
+
+	upstream.active.Add(1)
+	defer upstream.active.Add(-1)
+
+	backend, err := p.dial(upstream)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "websocket dial failed", "upstream", upstream.URL.Host, "error", err)
+		apierror.Write(w, r, apierror.BadGateway("Bad gateway"))
+		return
+	}
+	defer backend.Close()
+
+	// Bağlantı ve el sıkışma upgrade_timeout içinde bitmeli
+	backend.SetDeadline(time.Now().Add(p.config.UpgradeTimeout))
+
+	out := r.Clone(r.Context())
+	out.URL.Scheme = upstream.URL.Scheme
+	out.URL.Host = upstream.URL.Host
+	out.RequestURI = ""
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		if prior := out.Header.Get("X-Forwarded-For"); prior != "" {
+			host = prior + ", " + host
+		}
+		out.Header.Set("X-Forwarded-For", host)
+	}
+	tracing.Inject(r.Context(), out.Header)
+	if err := out.Write(backend); err != nil {
+		slog.ErrorContext(r.Context(), "websocket handshake failed", "upstream", upstream.URL.Host, "error", err)
+		apierror.Write(w, r, apierror.BadGateway("Bad gateway"))
+		return
+	}
+
+	backendReader := bufio.NewReader(backend)
+	resp, err := http.ReadResponse(backendReader, out)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			slog.ErrorContext(r.Context(), "websocket handshake timed out", "upstream", upstream.URL.Host)
+			apierror.Write(w, r, apierror.GatewayTimeout("Upstream timeout"))
+			return
+		}
+		slog.ErrorContext(r.Context(), "websocket handshake failed", "upstream", upstream.URL.Host, "error", err)
+		apierror.Write(w, r, apierror.BadGateway("Bad gateway"))
+		return
+	}
+	defer resp.Body.Close()
+
+	// Upstream yükseltmeyi reddettiyse (404, 400, ...) yanıtı olduğu gibi ilet
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		resp.Header.Del(logging.RequestIDHeader)
+		for key, values := range resp.Header {
+			for _, v := range values {
+				w.Header().Add(key, v)
+			}
+		}
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+		return
+	}
+	backend.SetDeadline(time.Time{})
+
+	conn, clientBuf, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "websocket hijack failed", "error", err)
+		apierror.Write(w, r, apierror.Fallback(err, "Websocket not supported"))
+		return
+	}
+	defer conn.Close()
+
+	// Sunucunun yazma/okuma zaman aşımları ele geçirilen bağlantıda kalır; boşta kalma süresi yönetir
+	conn.SetDeadline(time.Time{})
+	if !p.track(conn, backend) {
+		return
+	}
+	defer p.untrack(conn, backend)
+
+	fmt.Fprintf(clientBuf, "HTTP/1.1 %s\r\n", resp.Status)
+	resp.Header.Write(clientBuf)
+	clientBuf.WriteString("\r\n")
+	if err := clientBuf.Flush(); err != nil {
+		return
+	}
+
+	started := time.Now()
+	idle := pipe(conn, clientBuf.Reader, backend, backendReader, p.config.IdleTimeout)
+	if idle {
+		slog.InfoContext(r.Context(), "websocket closed after idle timeout", "path", r.URL.Path, "idle_timeout", p.config.IdleTimeout.String(), "open", time.Since(started).Round(time.Second).String())
+	}
+}
+
+// dial - Upstream örneğine TCP (https ise TLS) bağlantısı
+func (p *socketProxy) dial(upstream *Upstream) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: p.config.UpgradeTimeout}
+	addr := upstream.URL.Host
+	if upstream.URL.Port() == "" {
+		port := "80"
+		if upstream.URL.Scheme == "https" {
+			port = "443"
+		}
+		addr = net.JoinHostPort(upstream.URL.Hostname(), port)
+	}
+
+	if upstream.URL.Scheme == "https" {
+		return tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: upstream.URL.Hostname()})
+	}
+	return dialer.Dial("tcp", addr)
+}
+
+// pipe - İki yönde veri taşı; taraflardan biri kapanınca ya da idle boyunca hiç trafik
+// olmazsa iki bağlantıyı da kapat. Boşta kalma yüzünden kapandıysa true döner.
+func pipe(client net.Conn, clientReader io.Reader, backend net.Conn, backendReader io.Reader, idle time.Duration) bool {
+	var lastActive atomic.Int64
+	lastActive.Store(time.Now().UnixNano())
+
+	done := make(chan struct{}, 2)
+	transfer := func(dst net.Conn, src io.Reader) {
+		defer func() { done <- struct{}{} }()
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := src.Read(buf)
+			if n > 0 {
+				lastActive.Store(time.Now().UnixNano())
+				if _, werr := dst.Write(buf[:n]); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+	go transfer(backend, clientReader)
+	go transfer(client, backendReader)
+
+	check := idle / 4
+	if check < time.Second {
+		check = time.Second
+	}
+	ticker := time.NewTicker(check)
+	defer ticker.Stop()
+
+	timedOut := false
+	for finished := 0; finished < 2; {
+		select {
+		case <-done:
+			finished++
+			client.Close()
+			backend.Close()
+		case <-ticker.C:
+			if !timedOut && time.Since(time.Unix(0, lastActive.Load())) >= idle {
+				timedOut = true
+				client.Close()
+				backend.Close()
+			}
+		}
+	}
+	return timedOut
+}
@@ -2,68 +2,50 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"eros/api-gateway/gateway"
+	"eros/shared/apierror"
+	"eros/shared/apispec"
+	"eros/shared/logging"
+	"eros/shared/metrics"
+	"eros/shared/server"
+	"eros/shared/tracing"
+	"log/slog"
 	"net/http"
-	"net/http/httputil"
-	"net/url"
 	"os"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 )
 
-type ServiceConfig struct {
-	Name string
-	URL  string
-}
-
-var services = map[string]ServiceConfig{
-	"user": {
-		Name: "User Service",
-		URL:  "http://localhost:8081",
-	},
-	"match": {
-		Name: "Match Service",
-		URL:  "http://localhost:8082",
-	},
-	"chat": {
-		Name: "Chat Service",
-		URL:  "http://localhost:8083",
-	},
-}
-
 func main() {
 	// .env dosyasını yükle
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using default values")
-	}
-
-	router := mux.NewRouter()
-
-	// CORS middleware
-	router.Use(corsMiddleware)
+	envErr := godotenv.Load()
 
-	// Health check
-	router.HandleFunc("/health", healthCheck).Methods("GET")
-
-	// API routes
-	apiRouter := router.PathPrefix("/api").Subrouter()
+	// JSON günlükler (LOG_LEVEL .env'den de okunabilir)
+	logging.Setup("api-gateway")
+	if envErr != nil {
+		slog.Info("no .env file found, using default values")
+	}
 
-	// User service routes
-	apiRouter.PathPrefix("/auth").Handler(createReverseProxy("user"))
-	apiRouter.PathPrefix("/users").Handler(createReverseProxy("user"))
-	apiRouter.PathPrefix("/photos").Handler(createReverseProxy("user"))
-	apiRouter.PathPrefix("/form").Handler(createReverseProxy("user"))
+	// Dağıtık izleme (OTEL_TRACES_EXPORTER=otlp|console; varsayılan kapalı)
+	if err := tracing.Setup("api-gateway"); err != nil {
+		logging.Fatal("failed to initialize tracing", err)
+	}
 
-	// Match service routes
-	apiRouter.PathPrefix("/swipe").Handler(createReverseProxy("match"))
-	apiRouter.PathPrefix("/matches").Handler(createReverseProxy("match"))
-	apiRouter.PathPrefix("/blind").Handler(createReverseProxy("match"))
+	// Servisler ve rotalar (GATEWAY_CONFIG, varsayılan routes.yaml)
+	cfg, err := gateway.ConfigFromEnv()
+	if err != nil {
+		logging.Fatal("failed to load gateway config", err)
+	}
+	gw, err := gateway.New(cfg)
+	if err != nil {
+		logging.Fatal("failed to initialize gateway", err)
+	}
 
-	// Chat service routes
-	apiRouter.PathPrefix("/messages").Handler(createReverseProxy("chat"))
-	apiRouter.PathPrefix("/ws").Handler(createReverseProxy("chat"))
+	router := newRouter(gw)
 
 	// Sunucuyu başlat
 	port := os.Getenv("API_GATEWAY_PORT")
@@ -71,30 +53,65 @@ func main() {
 		port = "8080"
 	}
 
-	log.Printf("API Gateway starting on port %s", port)
-	log.Fatal(http.ListenAndServe(":"+port, router))
+	// Yazma zaman aşımı en uzun upstream zaman aşımından kısa olmamalı
+	srvConfig := server.ConfigFromEnv(":" + port)
+	if min := cfg.MaxRequestTimeout() + 10*time.Second; srvConfig.WriteTimeout < min {
+		srvConfig.WriteTimeout = min
+	}
+	srv := server.New("API Gateway", logging.Middleware(router), srvConfig, nil)
+
+	// Sağlık kontrolleri kapanışta durur; vekillenen WebSocket'ler kapatılır
+	gw.Start(srv.Workers.Context())
+	srv.OnShutdown(gw.Shutdown)
+	runErr := srv.Run()
+
+	// İstekler boşaltıldıktan sonra kuyruktaki span'leri gönder
+	if err := tracing.Shutdown(context.Background()); err != nil {
+		slog.Warn("failed to flush spans", "error", err)
+	}
+	if runErr != nil {
+		logging.Fatal("server failed", runErr)
+	}
 }
 
-func createReverseProxy(serviceName string) http.Handler {
-	service := services[serviceName]
-	targetURL, _ := url.Parse(service.URL)
+// newRouter - Gateway'in kendi uçları (metrikler, sağlık, API belgeleri) ve servis rotaları
+func newRouter(gw *gateway.Gateway) *mux.Router {
+	router := mux.NewRouter()
+	// Eşleşmeyen rota ve yöntemler de ortak JSON hata zarfıyla döner
+	router.NotFoundHandler = apierror.NotFoundHandler()
+	router.MethodNotAllowedHandler = apierror.MethodNotAllowedHandler()
+
+	// CORS middleware
+	router.Use(corsMiddleware)
+
+	// Prometheus metrikleri (istek süreleri rota önekine, yönteme ve durum koduna göre)
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+	router.Use(metrics.Middleware)
+	router.Use(tracing.Middleware)
 
-	proxy := httputil.NewSingleHostReverseProxy(targetURL)
+	// Health check: /healthz gateway süreci, /readyz ve /health servislerin hazırlık durumu
+	router.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	}).Methods("GET")
+	router.HandleFunc("/readyz", healthCheck(gw, true)).Methods("GET")
+	router.HandleFunc("/health", healthCheck(gw, false)).Methods("GET")
 
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Log request
-		log.Printf("Routing request to %s: %s %s", service.Name, r.Method, r.URL.Path)
+	// API belgeleri: HTTP uçları (OpenAPI) ve sohbet WebSocket çerçeveleri (AsyncAPI)
+	router.Handle("/"+apispec.OpenAPIFile, apispec.Handler()).Methods("GET")
+	router.Handle("/"+apispec.AsyncAPIFile, apispec.Handler()).Methods("GET")
 
-		// Forward request
-		proxy.ServeHTTP(w, r)
-	})
+	// API routes
+	gw.Register(router)
+	return router
 }
 
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Admin-Token, X-Request-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -105,12 +122,17 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func healthCheck(w http.ResponseWriter, r *http.Request) {
-	response := map[string]interface{}{
-		"status":   "healthy",
-		"services": services,
-	}
+// healthCheck - Servis örneklerinin hazırlık durumu ve gecikmeleri
+// strict ise (/readyz) hazır örneği olmayan servis varken 503 döner; /health her zaman 200 döner.
+func healthCheck(gw *gateway.Gateway, strict bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := gw.Health(r.Context())
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if strict && report.Status == gateway.HealthUnhealthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	}
 }
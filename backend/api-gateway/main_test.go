@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"eros/api-gateway/gateway"
+	"eros/shared/apispec"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// Belgeler servis rotalarının gölgesinde kalmadan gateway'in kendisinden sunulur
+func TestRouterServesAPISpecs(t *testing.T) {
+	cfg, err := gateway.LoadConfig("routes.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gw, err := gateway.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newRouter(gw)
+
+	for _, file := range []string{apispec.OpenAPIFile, apispec.AsyncAPIFile} {
+		want, err := apispec.Load(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/"+file, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /%s: status %d", file, rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("GET /%s: Content-Type %q", file, ct)
+		}
+		if rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+			t.Errorf("GET /%s: no CORS header; browser tooling could not fetch the spec", file)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("GET /%s: %v", file, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GET /%s: body differs from the embedded document", file)
+		}
+	}
+}
+
+// Fotoğraf uçları oturum ister; imzalı dosya adresleri <img> ile oturumsuz açılır
+func TestPhotoRoutesRequireAuth(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	fromFile, err := gateway.LoadConfig("routes.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, cfg := range map[string]*gateway.Config{"routes.yaml": fromFile, "default": gateway.DefaultConfig()} {
+		gw, err := gateway.New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		router := newRouter(gw)
+
+		for _, path := range []string{"/api/photos", "/api/photos/reorder", "/api/photos/upload"} {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, path, nil))
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("%s: %s without a token: status %d, want 401", name, path, rec.Code)
+			}
+		}
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/photos/file/photos/1/a.jpg?expires=1&sig=x", nil))
+		if rec.Code == http.StatusUnauthorized {
+			t.Errorf("%s: signed photo file URL asked for a session token", name)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// memory.go - Tek gateway örneği için bellek içi kova deposu
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval - Dolmuş (kullanılmayan) kovaların temizlenme aralığı
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // Bu andan sonra kova dolu olur ve silinebilir
+}
+
+// MemoryStore - Kovaları süreç belleğinde tutar
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket)}
+}
+
+// Take - Store arayüzü
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+
+	tokens, decision := take(refill(b.tokens, b.last, now, limit), limit)
+	b.tokens = tokens
+	b.last = now
+	b.full = now.Add(time.Duration((float64(limit.Burst) - tokens) / limit.Rate * float64(time.Second)))
+	return decision, nil
+}
+
+// sweep - Dolmuş kovaları sil (dolu kova ile hiç görülmemiş anahtar aynı davranır)
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if now.After(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
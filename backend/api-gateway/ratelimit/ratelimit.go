@@ -0,0 +1,69 @@
+// ratelimit.go - Token bucket hız sınırlayıcı ve kova deposu arayüzü
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit - Kova ayarı: saniyede Rate jeton dolar, en fazla Burst jeton birikir
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Per - "per süresinde n istek" biçiminden Limit; burst 0 ise n kullanılır
+func Per(n int, per time.Duration, burst int) Limit {
+	if burst <= 0 {
+		burst = n
+	}
+	return Limit{Rate: float64(n) / per.Seconds(), Burst: burst}
+}
+
+// Decision - Tek bir isteğin sonucu
+type Decision struct {
+	Allowed    bool
+	Remaining  int           // Kovada kalan jeton
+	RetryAfter time.Duration // Reddedildiyse bir sonraki jetona kadar bekleme
+}
+
+// Store - Kovaları tutan depo
+// Birden çok gateway örneği aynı sınırları paylaşacaksa ortak bir depo (Redis) kullanılır.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error)
+}
+
+// Limiter - Anahtar başına kova uygulayan sınırlayıcı
+type Limiter struct {
+	store Store
+	now   func() time.Time
+}
+
+func NewLimiter(store Store) *Limiter {
+	return &Limiter{store: store, now: time.Now}
+}
+
+// Allow - key için bir jeton harca
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) (Decision, error) {
+	return l.store.Take(ctx, key, limit, l.now())
+}
+
+// refill - Son güncellemeden bu yana dolan jetonlarla kovanın yeni seviyesi
+func refill(tokens float64, last, now time.Time, limit Limit) float64 {
+	if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+		tokens += elapsed * limit.Rate
+	}
+	return math.Min(tokens, float64(limit.Burst))
+}
+
+// take - Kovadan jeton almayı dene; yeni seviye ve karar
+func take(tokens float64, limit Limit) (float64, Decision) {
+	if tokens >= 1 {
+		tokens--
+		return tokens, Decision{Allowed: true, Remaining: int(tokens)}
+	}
+
+	wait := time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+	return tokens, Decision{RetryAfter: wait}
+}
@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestPer(t *testing.T) {
+	tests := []struct {
+		n     int
+		per   time.Duration
+		burst int
+		want  Limit
+	}{
+		{120, time.Minute, 30, Limit{Rate: 2, Burst: 30}},
+		{10, time.Second, 0, Limit{Rate: 10, Burst: 10}},
+		{1, time.Hour, 1, Limit{Rate: 1.0 / 3600, Burst: 1}},
+	}
+	for _, tt := range tests {
+		if got := Per(tt.n, tt.per, tt.burst); got != tt.want {
+			t.Errorf("Per(%d, %s, %d) = %+v, want %+v", tt.n, tt.per, tt.burst, got, tt.want)
+		}
+	}
+}
+
+// stores - Aynı kova davranışı her depoda denenir; Redis betiği miniredis'in Lua yorumlayıcısında çalışır
+func stores(t *testing.T) map[string]Store {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"redis":  NewRedisStore(client, "test:"),
+	}
+}
+
+func TestStoreTokenBucket(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 3} // Saniyede 2 jeton, en fazla 3
+	start := time.Unix(1_700_000_000, 0)
+
+	// Her adım bir önceki adımın kovasını görür
+	steps := []struct {
+		at        time.Duration
+		allowed   bool
+		remaining int
+		retry     time.Duration
+	}{
+		{0, true, 2, 0},
+		{0, true, 1, 0},
+		{0, true, 0, 0},
+		{0, false, 0, 500 * time.Millisecond}, // Bir jeton 1/rate = 500ms'de dolar
+		{250 * time.Millisecond, false, 0, 250 * time.Millisecond}, // Yarım jeton birikti
+		{500 * time.Millisecond, true, 0, 0},
+		{10 * time.Second, true, 2, 0}, // Uzun bekleme burst'ü aşmaz
+	}
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			for i, step := range steps {
+				d, err := store.Take(context.Background(), "ip:203.0.113.7", limit, start.Add(step.at))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if d.Allowed != step.allowed || d.Remaining != step.remaining || d.RetryAfter != step.retry {
+					t.Errorf("step %d at +%s: got %+v, want allowed=%v remaining=%d retry=%s",
+						i, step.at, d, step.allowed, step.remaining, step.retry)
+				}
+			}
+
+			// Kovalar anahtar başınadır
+			d, err := store.Take(context.Background(), "ip:203.0.113.8", limit, start)
+			if err != nil || !d.Allowed || d.Remaining != 2 {
+				t.Errorf("other key: %+v, %v; want a full bucket", d, err)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Rate: 1, Burst: 2}
+	start := time.Unix(1_700_000_000, 0)
+
+	store.Take(context.Background(), "idle", limit, start)
+	store.Take(context.Background(), "busy", limit, start)
+
+	// "idle" 1 saniyede dolar; "busy" süpürmeden hemen önce boşaltılır
+	later := start.Add(sweepInterval + time.Second)
+	store.Take(context.Background(), "busy", limit, later.Add(-time.Millisecond))
+	store.Take(context.Background(), "busy", limit, later.Add(-time.Millisecond))
+	store.Take(context.Background(), "trigger", limit, later)
+
+	if _, ok := store.buckets["idle"]; ok {
+		t.Error("full bucket was not swept")
+	}
+	if _, ok := store.buckets["busy"]; !ok {
+		t.Error("bucket that is still refilling was swept")
+	}
+}
+
+func TestLimiterUsesClock(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := NewLimiter(NewMemoryStore())
+	l.now = func() time.Time { return now }
+	limit := Limit{Rate: 1, Burst: 1}
+
+	if d, _ := l.Allow(context.Background(), "k", limit); !d.Allowed {
+		t.Fatal("first request rejected")
+	}
+	if d, _ := l.Allow(context.Background(), "k", limit); d.Allowed {
+		t.Fatal("second request in the same instant allowed")
+	}
+	now = now.Add(time.Second)
+	if d, _ := l.Allow(context.Background(), "k", limit); !d.Allowed {
+		t.Fatal("request after the refill interval rejected")
+	}
+}
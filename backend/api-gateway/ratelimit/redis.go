@@ -0,0 +1,69 @@
+// redis.go - Birden çok gateway örneğinin paylaştığı Redis kova deposu
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript - Kovayı tek adımda (atomik) doldur ve jeton al
+// KEYS[1] kova, ARGV: rate (jeton/sn), burst, now (ms). Dönüş: {izin, kalan, bekleme ms}
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) / 1000 * rate)
+end
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {allowed, math.floor(tokens), wait}
+`)
+
+// RedisStore - Kovaları Redis'te tutar; tüm gateway örnekleri aynı sınırları görür
+type RedisStore struct {
+	client redis.Scripter
+	prefix string
+}
+
+// NewRedisStore - Anahtarlar prefix ile başlar (örn. "eros:ratelimit:")
+func NewRedisStore(client redis.Scripter, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Take - Store arayüzü
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	values, err := takeScript.Run(ctx, s.client, []string{s.prefix + key},
+		strconv.FormatFloat(limit.Rate, 'f', -1, 64), limit.Burst, now.UnixMilli()).Int64Slice()
+	if err != nil {
+		return Decision{}, err
+	}
+
+	return Decision{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
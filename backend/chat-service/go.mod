@@ -7,6 +7,9 @@ require (
 	github.com/gorilla/mux v1.8.1
 	github.com/gorilla/websocket v1.5.0
 	github.com/joho/godotenv v1.5.1
+	github.com/mattn/go-sqlite3 v1.14.28
 )
 
+require github.com/lib/pq v1.10.9
+
 replace eros/shared => ../shared
@@ -0,0 +1,132 @@
+// admin.go - Moderasyon kararlarını inceleme (admin)
+package handler
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"eros/chat-service/model"
+	"eros/chat-service/service"
+	"eros/shared/apierror"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type AdminHandler struct {
+	moderationService *service.ModerationService
+	adminToken        string
+}
+
+func NewAdminHandler(moderationService *service.ModerationService, adminToken string) *AdminHandler {
+	return &AdminHandler{
+		moderationService: moderationService,
+		adminToken:        adminToken,
+	}
+}
+
+// RequireAdmin - X-Admin-Token başlığını doğrulayan middleware
+// ADMIN_TOKEN tanımlı değilse admin endpoint'leri kapalıdır.
+func (h *AdminHandler) RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Admin-Token")
+		if h.adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+			apierror.Write(w, r, apierror.Forbidden("Forbidden"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// ListDecisions - Moderasyon kararlarını listele (?user_id=&action=&overturned=&limit=)
+func (h *AdminHandler) ListDecisions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := model.DecisionFilter{Action: query.Get("action")}
+
+	if v := query.Get("user_id"); v != "" {
+		userID, err := strconv.Atoi(v)
+		if err != nil {
+			apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
+			return
+		}
+		filter.UserID = userID
+	}
+	if v := query.Get("overturned"); v != "" {
+		overturned, err := strconv.ParseBool(v)
+		if err != nil {
+			apierror.Write(w, r, apierror.BadRequest("Invalid overturned filter"))
+			return
+		}
+		filter.Overturned = &overturned
+	}
+	if v := query.Get("limit"); v != "" {
+		if limit, err := strconv.Atoi(v); err == nil {
+			filter.Limit = limit
+		}
+	}
+
+	decisions, err := h.moderationService.ListDecisions(r.Context(), filter)
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to list decisions"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(decisions)
+}
+
+// OverturnDecision - Kararı geçersiz kıl ve ihlal puanını geri al
+func (h *AdminHandler) OverturnDecision(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	decisionID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid decision ID"))
+		return
+	}
+
+	var request struct {
+		Reviewer string `json:"reviewer"`
+	}
+	json.NewDecoder(r.Body).Decode(&request)
+	if request.Reviewer == "" {
+		request.Reviewer = "admin"
+	}
+
+	decision, err := h.moderationService.OverturnDecision(r.Context(), decisionID, request.Reviewer)
+	if errors.Is(err, sql.ErrNoRows) {
+		apierror.Write(w, r, apierror.NotFound("Decision not found"))
+		return
+	}
+	if errors.Is(err, service.ErrAlreadyOverturned) {
+		apierror.Write(w, r, apierror.Conflict(err.Error()))
+		return
+	}
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to overturn decision"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(decision)
+}
+
+// GetUserStrikes - Kullanıcının ihlal sayacı ve kısıtlamaları
+func (h *AdminHandler) GetUserStrikes(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["user_id"])
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
+		return
+	}
+
+	strikes, err := h.moderationService.GetUserStrikes(r.Context(), userID)
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to get user strikes"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(strikes)
+}
@@ -0,0 +1,13 @@
+package handler_test
+
+import (
+	"eros/chat-service/handler/contracttest"
+	"testing"
+)
+
+// Rotalar, istek gövdeleri, yanıtlar ve WebSocket çerçeveleri shared/apispec belgeleriyle karşılaştırılır
+func TestContract(t *testing.T) {
+	if err := contracttest.Test(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,181 @@
+// contract.go - Chat servisi handler'larının OpenAPI ve AsyncAPI belgelerine uyumluluk testleri
+//
+// Kullanım (servisin test dosyasında):
+//
+//	if err := contracttest.Test(); err != nil {
+//		t.Fatal(err)
+//	}
+package contracttest
+
+import (
+	"context"
+	"eros/chat-service/handler"
+	"eros/chat-service/repository"
+	"eros/chat-service/service"
+	"eros/shared/apispec/contracttest"
+	"eros/shared/moderation"
+	"eros/shared/server"
+	"eros/shared/sqldb"
+	"eros/shared/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+// serviceName - openapi.json'daki x-service değeri
+const serviceName = "chat"
+
+// wsChannel - asyncapi.json'daki kanal (gateway yolu; servis /ws/{match_id} altında sunar)
+const wsChannel = "/api/ws/{match_id}"
+
+// matchesTable - Eşleşmeler match-service'e aittir; chat-service'in okuduğu sütunlar yeterli
+// Eşleşme 1'in tarafları 1 ve 2'dir.
+const matchesTable = `
+	CREATE TABLE matches (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user1_id INTEGER NOT NULL,
+		user2_id INTEGER NOT NULL
+	);
+	INSERT INTO matches (user1_id, user2_id) VALUES (1, 2)`
+
+// Test - Rotaları, istek gövdelerini, mesaj uçlarının yanıtlarını ve WebSocket çerçevelerini belgelerle karşılaştır
+// Bellek içi SQLite kullanır; OPENROUTER_API_KEY yok sayılır, AI gerektiren uçlar 502 döner.
+func Test() error {
+	env, err := newEnv()
+	if err != nil {
+		return err
+	}
+	defer env.close()
+
+	return errors.Join(
+		contracttest.CheckRoutes(serviceName, env.router),
+		checkRequests(),
+		env.run(),
+		env.runWebSocket(),
+	)
+}
+
+// checkRequests - Handler'ların çözdüğü yapılar belgedeki istek şemalarıyla aynı mı
+func checkRequests() error {
+	return errors.Join(
+		contracttest.CheckRequest("POST", "/api/messages/send", handler.SendMessageRequest{}),
+		contracttest.CheckRequest("POST", "/api/messages/analyze", handler.AnalyzeRequest{}),
+	)
+}
+
+// env - Gerçek servis ve repository'lerle kurulmuş router
+type env struct {
+	db        *sqldb.DB
+	workers   *server.Workers
+	websocket *handler.WebSocketHandler
+	router    *mux.Router
+}
+
+func newEnv() (*env, error) {
+	db, err := sqldb.Open(sqldb.SQLite, ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	// Bellek içi SQLite her bağlantıda ayrı bir veritabanıdır
+	db.SetMaxOpenConns(1)
+
+	migrator, err := repository.NewMigrator(db)
+	if err == nil {
+		_, err = migrator.Up(context.Background())
+	}
+	if err == nil {
+		_, err = db.Exec(matchesTable)
+	}
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// İlk engellenen mesajda susturma: 403 yanıtı da denenebilsin
+	strikes := service.DefaultStrikePolicy()
+	strikes.MuteAfter = 1
+	moderationService := service.NewModerationService(repository.NewModerationRepository(db), moderation.Default(), strikes, nil)
+
+	// Testler ağa çıkmaz: anahtarsız istemci AI çağrılarını sağlayıcı hatasıyla reddeder
+	key, hadKey := os.LookupEnv("OPENROUTER_API_KEY")
+	os.Unsetenv("OPENROUTER_API_KEY")
+	workers := server.NewWorkers()
+	chatService := service.NewChatService(repository.NewMessageRepository(db), repository.NewMatchRepository(db), moderationService, utils.DefaultContactPolicy(), workers)
+	if hadKey {
+		os.Setenv("OPENROUTER_API_KEY", key)
+	}
+
+	wsHandler := handler.NewWebSocketHandler(chatService)
+	router := mux.NewRouter()
+	handler.Handlers{
+		Message:   handler.NewMessageHandler(chatService),
+		WebSocket: wsHandler,
+		Admin:     handler.NewAdminHandler(moderationService, "contract-test"),
+	}.Register(router)
+
+	return &env{db: db, workers: workers, websocket: wsHandler, router: router}, nil
+}
+
+func (e *env) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	e.websocket.Shutdown(ctx)
+	e.workers.Stop(ctx)
+	e.db.Close()
+}
+
+// run - Mesaj gönderme (izin verilen, engellenen, susturulmuş), listeleme ve analiz
+func (e *env) run() error {
+	return contracttest.Run(serviceName, e.router, []contracttest.Case{
+		{Name: "send", Method: "POST", Path: "/api/messages/send", Body: `{"match_id":1,"user_id":1,"message":"Merhaba, nasılsın?"}`, Status: http.StatusOK},
+		{Name: "send contact info", Method: "POST", Path: "/api/messages/send", Body: `{"match_id":1,"user_id":1,"message":"Numaram 0555 123 45 67"}`, Status: http.StatusUnprocessableEntity},
+		{Name: "send inappropriate", Method: "POST", Path: "/api/messages/send", Body: `{"match_id":1,"user_id":2,"message":"gebertirim"}`, Status: http.StatusUnprocessableEntity},
+		{Name: "send while muted", Method: "POST", Path: "/api/messages/send", Body: `{"match_id":1,"user_id":2,"message":"Merhaba"}`, Status: http.StatusForbidden},
+		{Name: "send malformed", Method: "POST", Path: "/api/messages/send", Body: `{`, Status: http.StatusBadRequest},
+		{Name: "messages", Method: "GET", Path: "/api/messages/1", Status: http.StatusOK},
+		{Name: "messages of empty match", Method: "GET", Path: "/api/messages/99", Status: http.StatusOK},
+		{Name: "messages invalid match", Method: "GET", Path: "/api/messages/abc", Status: http.StatusBadRequest},
+		{Name: "analyze without AI", Method: "POST", Path: "/api/messages/analyze", Body: `{"match_id":1}`, Status: http.StatusBadGateway},
+		{Name: "analyze malformed", Method: "POST", Path: "/api/messages/analyze", Body: `{`, Status: http.StatusBadRequest},
+	})
+}
+
+// runWebSocket - Gönderilen ve alınan çerçeveler asyncapi.json'a uyuyor mu
+func (e *env) runWebSocket() error {
+	srv := httptest.NewServer(e.router)
+	defer srv.Close()
+
+	header := http.Header{"X-User-ID": {"1"}}
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws/1", header)
+	if err != nil {
+		return fmt.Errorf("contracttest: websocket dial: %w", err)
+	}
+	defer conn.Close()
+
+	var errs []error
+	for _, frame := range []string{
+		`{"type":"send_message","message":"Bu akşam müsait misin?"}`,
+		`{"type":"send_message","message":"Instagram: @ayse.eros"}`,
+	} {
+		errs = append(errs, contracttest.CheckFrame(wsChannel, "publish", []byte(frame)))
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(frame)); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, reply, err := conn.ReadMessage()
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		errs = append(errs, contracttest.CheckFrame(wsChannel, "subscribe", reply))
+	}
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return errors.Join(errs...)
+}
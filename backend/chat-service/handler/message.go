@@ -4,6 +4,8 @@ package handler
 import (
 	"encoding/json"
 	"eros/chat-service/service"
+	"eros/shared/apierror"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -20,22 +22,30 @@ func NewMessageHandler(chatService *service.ChatService) *MessageHandler {
 	}
 }
 
+// SendMessageRequest - Mesaj gönderme isteği
+type SendMessageRequest struct {
+	MatchID int    `json:"match_id"`
+	UserID  int    `json:"user_id"`
+	Message string `json:"message"`
+}
+
+// AnalyzeRequest - Sohbet analizi isteği
+type AnalyzeRequest struct {
+	MatchID int `json:"match_id"`
+}
+
 // SendMessage - Mesaj gönder
 func (h *MessageHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
-	var request struct {
-		MatchID int    `json:"match_id"`
-		UserID  int    `json:"user_id"`
-		Message string `json:"message"`
-	}
+	var request SendMessageRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
 		return
 	}
 
-	message, err := h.chatService.SendMessage(request.MatchID, request.UserID, request.Message)
+	message, err := h.chatService.SendMessage(r.Context(), request.MatchID, request.UserID, request.Message)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, sendMessageError(err))
 		return
 	}
 
@@ -43,18 +53,29 @@ func (h *MessageHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(message)
 }
 
+// sendMessageError - Mesaj gönderme hatasını yanıta çevir (HTTP ve WebSocket aynı eşlemeyi kullanır)
+func sendMessageError(err error) *apierror.Error {
+	switch {
+	case errors.Is(err, service.ErrUserMuted), errors.Is(err, service.ErrUserSuspended):
+		return apierror.Forbidden(err.Error())
+	case errors.Is(err, service.ErrInappropriateContent), errors.Is(err, service.ErrContactInfoNotAllowed):
+		return apierror.Unprocessable(err.Error())
+	}
+	return apierror.Fallback(err, "Failed to send message")
+}
+
 // GetMessages - Mesajları getir
 func (h *MessageHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	matchID, err := strconv.Atoi(vars["match_id"])
 	if err != nil {
-		http.Error(w, "Invalid match ID", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid match ID"))
 		return
 	}
 
-	messages, err := h.chatService.GetMessages(matchID)
+	messages, err := h.chatService.GetMessages(r.Context(), matchID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to get messages"))
 		return
 	}
 
@@ -64,18 +85,16 @@ func (h *MessageHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 
 // AnalyzeConversation - Sohbet analizi
 func (h *MessageHandler) AnalyzeConversation(w http.ResponseWriter, r *http.Request) {
-	var request struct {
-		MatchID int `json:"match_id"`
-	}
+	var request AnalyzeRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
 		return
 	}
 
-	analysis, err := h.chatService.AnalyzeConversation(request.MatchID)
+	analysis, err := h.chatService.AnalyzeConversation(r.Context(), request.MatchID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to analyze conversation"))
 		return
 	}
 
@@ -0,0 +1,27 @@
+// routes.go - API ve WebSocket rotaları (main ve sözleşme testleri aynı tabloyu kullanır)
+package handler
+
+import "github.com/gorilla/mux"
+
+// Handlers - Rotalara bağlanan handler'lar
+type Handlers struct {
+	Message   *MessageHandler
+	WebSocket *WebSocketHandler
+	Admin     *AdminHandler
+}
+
+// Register - API ve WebSocket rotalarını router'a ekle
+func (h Handlers) Register(router *mux.Router) {
+	// Message routes
+	router.HandleFunc("/api/messages/send", h.Message.SendMessage).Methods("POST")
+	router.HandleFunc("/api/messages/{match_id}", h.Message.GetMessages).Methods("GET")
+	router.HandleFunc("/api/messages/analyze", h.Message.AnalyzeConversation).Methods("POST")
+
+	// Admin moderasyon routes
+	router.HandleFunc("/api/admin/moderation/decisions", h.Admin.RequireAdmin(h.Admin.ListDecisions)).Methods("GET")
+	router.HandleFunc("/api/admin/moderation/decisions/{id}/overturn", h.Admin.RequireAdmin(h.Admin.OverturnDecision)).Methods("POST")
+	router.HandleFunc("/api/admin/moderation/users/{user_id}", h.Admin.RequireAdmin(h.Admin.GetUserStrikes)).Methods("GET")
+
+	// WebSocket route
+	router.HandleFunc("/ws/{match_id}", h.WebSocket.HandleWebSocket)
+}
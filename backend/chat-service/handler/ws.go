@@ -2,18 +2,34 @@
 package handler
 
 import (
+	"context"
 	"eros/chat-service/service"
-	"log"
+	"eros/shared/apierror"
+	"eros/shared/auth"
+	"eros/shared/metrics"
+	"eros/shared/tracing"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 )
 
+var openConnections = metrics.NewGauge("websocket_connections",
+	"Open chat WebSocket connections.")
+
 type WebSocketHandler struct {
 	chatService *service.ChatService
 	upgrader    websocket.Upgrader
+
+	mu       sync.Mutex
+	conns    map[*websocket.Conn]struct{}
+	closing  bool
+	handlers sync.WaitGroup
 }
 
 func NewWebSocketHandler(chatService *service.ChatService) *WebSocketHandler {
@@ -24,6 +40,59 @@ func NewWebSocketHandler(chatService *service.ChatService) *WebSocketHandler {
 				return true // CORS için
 			},
 		},
+		conns: make(map[*websocket.Conn]struct{}),
+	}
+}
+
+// track - Bağlantıyı kapanışta kapatılacaklar listesine ekle (kapanış başladıysa false)
+func (h *WebSocketHandler) track(conn *websocket.Conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closing {
+		return false
+	}
+	h.conns[conn] = struct{}{}
+	h.handlers.Add(1)
+	openConnections.Inc()
+	return true
+}
+
+func (h *WebSocketHandler) untrack(conn *websocket.Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.conns, conn)
+	h.handlers.Done()
+	openConnections.Dec()
+}
+
+// Shutdown - Açık bağlantılara "going away" kapanış çerçevesi gönder ve kapanmalarını bekle
+// İstemcinin kapanışı onaylaması ctx süresince beklenir, sonra bağlantılar zorla kapatılır.
+func (h *WebSocketHandler) Shutdown(ctx context.Context) {
+	h.mu.Lock()
+	h.closing = true
+	conns := make([]*websocket.Conn, 0, len(h.conns))
+	for conn := range h.conns {
+		conns = append(conns, conn)
+	}
+	h.mu.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	for _, conn := range conns {
+		conn.WriteControl(websocket.CloseMessage, msg, deadline)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.handlers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		for _, conn := range conns {
+			conn.Close()
+		}
 	}
 }
 
@@ -32,42 +101,75 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
 	vars := mux.Vars(r)
 	matchID, err := strconv.Atoi(vars["match_id"])
 	if err != nil {
-		http.Error(w, "Invalid match ID", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid match ID"))
+		return
+	}
+
+	// Gateway doğruladığı kullanıcıyı X-User-ID ile iletir; mesajlar yalnızca bu kullanıcı adına gönderilir
+	userID, ok := auth.RequestUserID(r)
+	if !ok {
+		apierror.Write(w, r, apierror.Unauthorized("Authentication required"))
+		return
+	}
+
+	// Yalnızca eşleşmenin iki tarafı sohbeti okuyabilir ve yazabilir
+	err = h.chatService.CheckParticipant(r.Context(), matchID, userID)
+	if errors.Is(err, service.ErrNotParticipant) {
+		apierror.Write(w, r, apierror.Forbidden("Not a participant of this match"))
+		return
+	}
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to check match"))
 		return
 	}
 
 	// WebSocket bağlantısını yükselt
 	conn, err := h.upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("WebSocket upgrade failed: %v", err)
+		slog.WarnContext(r.Context(), "websocket upgrade failed", "error", err)
 		return
 	}
 	defer conn.Close()
 
-	log.Printf("WebSocket connected for match %d", matchID)
+	if !h.track(conn) {
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"), time.Now().Add(time.Second))
+		return
+	}
+	defer h.untrack(conn)
+
+	slog.InfoContext(r.Context(), "websocket connected", "match_id", matchID, "user_id", userID)
 
 	// Mesaj dinleme döngüsü
 	for {
 		var message struct {
 			Type    string `json:"type"`
-			UserID  int    `json:"user_id"`
 			Message string `json:"message"`
 		}
 
 		err := conn.ReadJSON(&message)
 		if err != nil {
-			log.Printf("WebSocket read error: %v", err)
+			slog.WarnContext(r.Context(), "websocket read error", "match_id", matchID, "error", err)
 			break
 		}
 
 		// Mesajı işle
 		if message.Type == "send_message" {
-			chatMessage, err := h.chatService.SendMessage(matchID, message.UserID, message.Message)
+			// Bağlantı boyunca açık kalan sunucu span'i altında her mesaj için ayrı span
+			msgCtx, span := tracing.Start(r.Context(), "websocket send_message")
+			span.SetAttr("match_id", matchID)
+			chatMessage, err := h.chatService.SendMessage(msgCtx, matchID, userID, message.Message)
+			span.SetAttr("sent", err == nil)
+			span.End()
 			if err != nil {
-				// Hata mesajını gönder
+				// Hata mesajını HTTP zarfıyla aynı kod ve mesajla gönder; iç hata istemciye sızmaz
+				apiErr := sendMessageError(err)
+				if apiErr.Status >= http.StatusInternalServerError {
+					slog.ErrorContext(msgCtx, "websocket send_message failed", "match_id", matchID, "error", err)
+				}
 				errorResponse := map[string]interface{}{
 					"type":  "error",
-					"error": err.Error(),
+					"error": apiErr.Message,
+					"code":  apiErr.Code,
 				}
 				conn.WriteJSON(errorResponse)
 				continue
@@ -82,5 +184,5 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
 		}
 	}
 
-	log.Printf("WebSocket disconnected for match %d", matchID)
+	slog.InfoContext(r.Context(), "websocket disconnected", "match_id", matchID)
 }
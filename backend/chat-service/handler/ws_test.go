@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"eros/chat-service/repository"
+	"eros/chat-service/service"
+	"eros/shared/moderation"
+	"eros/shared/server"
+	"eros/shared/sqldb"
+	"eros/shared/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+// newWebSocketServer - Eşleşme 1'in tarafları 1 ve 2 olan veritabanıyla WebSocket ucu
+func newWebSocketServer(t *testing.T) (*httptest.Server, *service.ChatService) {
+	t.Helper()
+	db, err := sqldb.Open(sqldb.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Bellek içi SQLite her bağlantıda ayrı bir veritabanıdır
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := repository.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// matches tablosu match-service'e aittir; okunan sütunlar yeterli
+	if _, err := db.Exec(`CREATE TABLE matches (id INTEGER PRIMARY KEY, user1_id INTEGER NOT NULL, user2_id INTEGER NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO matches (id, user1_id, user2_id) VALUES (1, 1, 2)`); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("OPENROUTER_API_KEY", "")
+	workers := server.NewWorkers()
+	moderationService := service.NewModerationService(repository.NewModerationRepository(db), moderation.Default(), service.DefaultStrikePolicy(), nil)
+	chatService := service.NewChatService(repository.NewMessageRepository(db), repository.NewMatchRepository(db), moderationService, utils.DefaultContactPolicy(), workers)
+	ws := NewWebSocketHandler(chatService)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/ws/{match_id}", ws.HandleWebSocket)
+	srv := httptest.NewServer(router)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Close()
+		ws.Shutdown(ctx)
+		workers.Stop(ctx)
+	})
+	return srv, chatService
+}
+
+// dial - X-User-ID ile bağlan; userID boşsa başlık gönderilmez
+func dial(t *testing.T, srv *httptest.Server, path, userID string) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+	header := http.Header{}
+	if userID != "" {
+		header.Set("X-User-ID", userID)
+	}
+	return websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+path, header)
+}
+
+func TestWebSocketRejectsBeforeUpgrade(t *testing.T) {
+	srv, _ := newWebSocketServer(t)
+
+	tests := []struct {
+		name, path, userID string
+		status             int
+	}{
+		{"no gateway user", "/ws/1", "", http.StatusUnauthorized},
+		{"malformed gateway user", "/ws/1", "abc", http.StatusUnauthorized},
+		{"not a participant", "/ws/1", "3", http.StatusForbidden},
+		{"unknown match", "/ws/99", "1", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		conn, resp, err := dial(t, srv, tt.path, tt.userID)
+		if err == nil {
+			conn.Close()
+			t.Errorf("%s: upgrade succeeded", tt.name)
+			continue
+		}
+		if resp == nil || resp.StatusCode != tt.status {
+			t.Errorf("%s: response %v, want status %d", tt.name, resp, tt.status)
+		}
+	}
+}
+
+func TestWebSocketSendsAsGatewayUser(t *testing.T) {
+	srv, chatService := newWebSocketServer(t)
+
+	conn, _, err := dial(t, srv, "/ws/1", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Çerçevedeki user_id kimlik yerine geçmez
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"send_message","user_id":1,"message":"Merhaba"}`)); err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var reply struct {
+		Type string `json:"type"`
+	}
+	if err := conn.ReadJSON(&reply); err != nil || reply.Type != "message_sent" {
+		t.Fatalf("reply %+v, err %v", reply, err)
+	}
+
+	messages, err := chatService.GetMessages(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 || messages[0].UserID != 2 {
+		t.Fatalf("messages = %+v, want one from user 2", messages)
+	}
+}
@@ -2,11 +2,24 @@
 package main
 
 import (
+	"context"
 	"eros/chat-service/handler"
+	"eros/chat-service/repository"
 	"eros/chat-service/service"
-	"log"
+	"eros/shared/apierror"
+	"eros/shared/health"
+	"eros/shared/logging"
+	"eros/shared/metrics"
+	"eros/shared/migrate"
+	"eros/shared/moderation"
+	"eros/shared/server"
+	"eros/shared/sqldb"
+	"eros/shared/tracing"
+	"eros/shared/utils"
+	"log/slog"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
@@ -14,34 +27,95 @@ import (
 
 func main() {
 	// .env dosyasını yükle
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using default values")
+	envErr := godotenv.Load()
+
+	// JSON günlükler (LOG_LEVEL .env'den de okunabilir)
+	logging.Setup("chat-service")
+	if envErr != nil {
+		slog.Info("no .env file found, using default values")
+	}
+
+	// Dağıtık izleme (OTEL_TRACES_EXPORTER=otlp|console; varsayılan kapalı)
+	if err := tracing.Setup("chat-service"); err != nil {
+		logging.Fatal("failed to initialize tracing", err)
+	}
+
+	// Veritabanını başlat (DB_DRIVER=sqlite için DB_PATH, DB_DRIVER=postgres için DATABASE_URL)
+	db, err := sqldb.FromEnv("./eros_chat.db")
+	if err != nil {
+		logging.Fatal("failed to connect to database", err)
+	}
+	defer db.Close()
+
+	migrator, err := repository.NewMigrator(db)
+	if err != nil {
+		logging.Fatal("failed to load migrations", err)
+	}
+
+	// "go run . migrate up|down|status" - göçleri elle yönet ve çık
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate.Command(context.Background(), migrator, os.Args[2:], os.Stdout); err != nil {
+			logging.Fatal("migrate command failed", err)
+		}
+		return
 	}
 
-	// Chat Service'i oluştur
-	chatService := service.NewChatService()
+	// Bekleyen şema göçlerini uygula (AUTO_MIGRATE=false ise sadece kontrol et)
+	if err := migrate.Startup(context.Background(), migrator, migrate.AutoMigrateFromEnv()); err != nil {
+		logging.Fatal("failed to migrate database", err)
+	}
+
+	// Repository'leri oluştur
+	messageRepo := repository.NewMessageRepository(db)
+	moderationRepo := repository.NewModerationRepository(db)
+
+	// Service'leri oluştur
+	// İhlal puanıyla askıya alınan hesaplar user-service'te de askıya alınır (ADMIN_TOKEN, USER_SERVICE_URL)
+	suspender, err := service.UserSuspenderFromEnv()
+	if err != nil {
+		logging.Fatal("failed to initialize user suspender", err)
+	}
+	if suspender == nil {
+		slog.Warn("ADMIN_TOKEN not set, strike suspensions will not suspend user accounts")
+	}
+	moderationService := service.NewModerationService(moderationRepo, moderation.Default(), service.StrikePolicyFromEnv(), suspender)
+	// Arka plan işleri (mesaj analizi); kapanışta bitmeleri beklenir
+	workers := server.NewWorkers()
+	chatService := service.NewChatService(messageRepo, repository.NewMatchRepository(db), moderationService, utils.ContactPolicyFromEnv(), workers)
 
 	// Handler'ları oluştur
 	messageHandler := handler.NewMessageHandler(chatService)
 	wsHandler := handler.NewWebSocketHandler(chatService)
+	adminHandler := handler.NewAdminHandler(moderationService, os.Getenv("ADMIN_TOKEN"))
 
 	// Router'ı oluştur
 	router := mux.NewRouter()
+	// Eşleşmeyen rota ve yöntemler de ortak JSON hata zarfıyla döner
+	router.NotFoundHandler = apierror.NotFoundHandler()
+	router.MethodNotAllowedHandler = apierror.MethodNotAllowedHandler()
 
-	// Message routes
-	router.HandleFunc("/api/messages/send", messageHandler.SendMessage).Methods("POST")
-	router.HandleFunc("/api/messages/{match_id}", messageHandler.GetMessages).Methods("GET")
-	router.HandleFunc("/api/messages/analyze", messageHandler.AnalyzeConversation).Methods("POST")
+	// API ve WebSocket routes
+	handler.Handlers{Message: messageHandler, WebSocket: wsHandler, Admin: adminHandler}.Register(router)
 
-	// WebSocket route
-	router.HandleFunc("/ws/{match_id}", wsHandler.HandleWebSocket)
+	// Canlılık ve hazırlık (veritabanı, şema göçleri; AI kritik değil, çalışmazsa "degraded")
+	checker := health.New("chat-service", 2*time.Second)
+	checker.Add("database", true, health.Ping(db))
+	checker.Add("migrations", true, health.Migrations(migrator))
+	checker.Add("ai_provider", false, chatService.AIHealth)
+	router.HandleFunc("/healthz", checker.Live).Methods("GET")
+	router.HandleFunc("/readyz", checker.Ready).Methods("GET")
+
+	// Prometheus metrikleri (istek süreleri rota şablonu, yöntem ve durum koduna göre)
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+	router.Use(metrics.Middleware)
+	router.Use(tracing.Middleware)
 
 	// CORS middleware
 	router.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Admin-Token")
 
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
@@ -58,6 +132,15 @@ func main() {
 		port = "8083"
 	}
 
-	log.Printf("Chat Service starting on port %s", port)
-	log.Fatal(http.ListenAndServe(":"+port, router))
+	srv := server.New("Chat Service", logging.Middleware(router), server.ConfigFromEnv(":"+port), workers)
+	srv.OnShutdown(wsHandler.Shutdown)
+	runErr := srv.Run()
+
+	// İstekler boşaltıldıktan sonra kuyruktaki span'leri gönder
+	if err := tracing.Shutdown(context.Background()); err != nil {
+		slog.Warn("failed to flush spans", "error", err)
+	}
+	if runErr != nil {
+		logging.Fatal("server failed", runErr)
+	}
 }
@@ -0,0 +1,38 @@
+// moderation.go - Moderasyon denetim kaydı ve ihlal modelleri
+package model
+
+import "time"
+
+// ModerationDecision - Kaydedilmiş moderasyon kararı
+type ModerationDecision struct {
+	ID          int        `json:"id" db:"id"`
+	UserID      int        `json:"user_id" db:"user_id"`
+	MatchID     int        `json:"match_id" db:"match_id"`
+	MessageHash string     `json:"message_hash" db:"message_hash"` // Mesajın SHA-256 özeti, içerik saklanmaz
+	Category    string     `json:"category" db:"category"`
+	Severity    string     `json:"severity" db:"severity"` // Kuralın kendi ciddiyeti
+	Action      string     `json:"action" db:"action"`     // Politika sonrası uygulanan aksiyon
+	Rules       string     `json:"rules" db:"rules"`       // Eşleşen kurallar
+	Strike      bool       `json:"strike" db:"strike"`     // Kullanıcıya ihlal puanı yazıldı mı
+	Overturned  bool       `json:"overturned" db:"overturned"`
+	ReviewedBy  string     `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// UserStrikes - Kullanıcının ihlal sayacı ve aktif kısıtlamaları
+type UserStrikes struct {
+	UserID         int        `json:"user_id" db:"user_id"`
+	Strikes        int        `json:"strikes" db:"strikes"`
+	MutedUntil     *time.Time `json:"muted_until,omitempty" db:"muted_until"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty" db:"suspended_until"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// DecisionFilter - Admin listeleme filtresi
+type DecisionFilter struct {
+	UserID     int
+	Action     string
+	Overturned *bool
+	Limit      int
+}
@@ -0,0 +1,32 @@
+// match_repository.go - Eşleşme katılımcıları (matches tablosu match-service'e aittir, sadece okunur)
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eros/shared/sqldb"
+	"eros/shared/tracing"
+)
+
+type MatchRepository struct {
+	db *sqldb.DB
+}
+
+func NewMatchRepository(db *sqldb.DB) *MatchRepository {
+	return &MatchRepository{db: db}
+}
+
+// IsParticipant - Kullanıcı eşleşmenin iki tarafından biri mi (eşleşme yoksa false)
+func (r *MatchRepository) IsParticipant(ctx context.Context, matchID, userID int) (bool, error) {
+	ctx, span := tracing.Start(ctx, "MatchRepository.IsParticipant")
+	defer span.End()
+
+	var one int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT 1 FROM matches WHERE id = ? AND (user1_id = ? OR user2_id = ?)
+	`, matchID, userID, userID).Scan(&one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,89 @@
+// message_repository.go - Chat mesajları veritabanı işlemleri
+package repository
+
+import (
+	"context"
+	"eros/chat-service/model"
+	"eros/shared/sqldb"
+	"eros/shared/tracing"
+)
+
+type MessageRepository struct {
+	db *sqldb.DB
+}
+
+func NewMessageRepository(db *sqldb.DB) *MessageRepository {
+	return &MessageRepository{db: db}
+}
+
+// CreateMessage - Mesajı kaydet
+func (r *MessageRepository) CreateMessage(ctx context.Context, message *model.ChatMessage) error {
+	ctx, span := tracing.Start(ctx, "MessageRepository.CreateMessage")
+	defer span.End()
+
+	id, err := r.db.InsertIDContext(ctx, `
+		INSERT INTO messages (match_id, user_id, message, created_at)
+		VALUES (?, ?, ?, ?)
+	`, message.MatchID, message.UserID, message.Message, message.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	message.ID = int(id)
+	return nil
+}
+
+// GetMessagesByMatchID - Eşleşmenin mesajlarını getir (eskiden yeniye)
+func (r *MessageRepository) GetMessagesByMatchID(ctx context.Context, matchID int) ([]model.ChatMessage, error) {
+	ctx, span := tracing.Start(ctx, "MessageRepository.GetMessagesByMatchID")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, match_id, user_id, message, created_at
+		FROM messages WHERE match_id = ?
+		ORDER BY created_at ASC, id ASC
+	`, matchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []model.ChatMessage{}
+	for rows.Next() {
+		var message model.ChatMessage
+		err := rows.Scan(&message.ID, &message.MatchID, &message.UserID, &message.Message, &message.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+// CountMessagesByUser - Eşleşmede kullanıcı başına gönderilen mesaj sayısı
+func (r *MessageRepository) CountMessagesByUser(ctx context.Context, matchID int) (map[int]int, error) {
+	ctx, span := tracing.Start(ctx, "MessageRepository.CountMessagesByUser")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id, COUNT(*) FROM messages
+		WHERE match_id = ?
+		GROUP BY user_id
+	`, matchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var userID, count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+		counts[userID] = count
+	}
+
+	return counts, rows.Err()
+}
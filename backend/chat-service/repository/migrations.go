@@ -0,0 +1,98 @@
+// migrations.go - Chat service şema göçleri
+package repository
+
+import (
+	"database/sql"
+	"eros/shared/migrate"
+	"eros/shared/sqldb"
+)
+
+// chatMigrations - Yeni göçler listenin sonuna, bir sonraki versiyon numarasıyla eklenir
+// Her göçün PostgreSQL karşılığı postgres.go'da aynı versiyon numarasıyla eklenir.
+var chatMigrations = []migrate.Migration{
+	// Sohbetin göç sisteminden önce tablosu yoktu; versiyon 1 eski kayıtlarla uyum için durur
+	{Version: 1, Name: "baseline", Up: migrate.SQL(), Down: migrate.SQL()},
+	{
+		Version: 2,
+		Name:    "moderation",
+		Up:      moderationSchema,
+		Down:    migrate.SQL(`DROP TABLE IF EXISTS user_strikes`, `DROP TABLE IF EXISTS moderation_decisions`),
+	},
+	{
+		Version: 3,
+		Name:    "messages",
+		Up:      messagesSchema,
+		Down:    migrate.SQL(`DROP TABLE IF EXISTS messages`),
+	},
+}
+
+// NewMigrator - Chat service veritabanının göç çalıştırıcısı (lehçeye göre SQLite veya PostgreSQL göçleri)
+func NewMigrator(db *sqldb.DB) (*migrate.Migrator, error) {
+	migrations := chatMigrations
+	if db.IsPostgres() {
+		migrations = postgresMigrations
+	}
+	// Servisler aynı veritabanını paylaşabilir; her biri kendi göç tablosunu kullanır
+	return migrate.New(db.DB, migrations, migrate.Options{
+		Dialect:     db.Dialect,
+		Table:       "chat_schema_migrations",
+		LegacyTable: "schema_migrations",
+	})
+}
+
+// moderationSchema - Moderasyon kararları ve kullanıcı ihlal sayaçları
+// Eski baseline bu tabloları oluşturmuştu; IF NOT EXISTS sayesinde orada değişiklik yapmaz.
+func moderationSchema(tx *sql.Tx) error {
+	// Moderasyon kararları tablosu (denetim kaydı)
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS moderation_decisions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			match_id INTEGER NOT NULL,
+			message_hash TEXT NOT NULL,
+			category TEXT,
+			severity TEXT NOT NULL,
+			action TEXT NOT NULL,
+			rules TEXT,
+			strike BOOLEAN DEFAULT FALSE,
+			overturned BOOLEAN DEFAULT FALSE,
+			reviewed_by TEXT,
+			reviewed_at DATETIME,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Kullanıcı ihlal sayacı ve kısıtlamaları
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS user_strikes (
+			user_id INTEGER PRIMARY KEY,
+			strikes INTEGER DEFAULT 0,
+			muted_until DATETIME,
+			suspended_until DATETIME,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+// messagesSchema - Kalıcı sohbet mesajları
+func messagesSchema(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS messages (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			match_id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL,
+			message TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_messages_match ON messages (match_id, created_at)`)
+	return err
+}
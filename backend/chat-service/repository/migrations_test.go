@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"eros/shared/sqldb"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigratorIgnoresUserServiceMigrations(t *testing.T) {
+	db, err := sqldb.Open(sqldb.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Bellek içi SQLite her bağlantıda ayrı bir veritabanıdır
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+	ctx := context.Background()
+
+	// Eski sürümlerde user-service göçlerini ortak "schema_migrations" tablosuna yazıyordu
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES
+		(1, 'baseline', CURRENT_TIMESTAMP), (2, 'user_preferences_categories', CURRENT_TIMESTAMP),
+		(3, 'photos_unique_order', CURRENT_TIMESTAMP), (4, 'clear_stale_verified_at', CURRENT_TIMESTAMP)`); err != nil {
+		t.Fatal(err)
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != len(chatMigrations) {
+		t.Fatalf("status lists %d migrations, want %d", len(statuses), len(chatMigrations))
+	}
+	for _, s := range statuses {
+		if !s.Applied || s.Unknown {
+			t.Errorf("migration %d (%s) applied=%v unknown=%v", s.Version, s.Name, s.Applied, s.Unknown)
+		}
+	}
+}
+
+// chatTableCount - Sohbet tablolarından kaç tanesi var
+func chatTableCount(t *testing.T, db *sqldb.DB) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('messages', 'moderation_decisions', 'user_strikes')`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	db, err := sqldb.Open(sqldb.SQLite, filepath.Join(t.TempDir(), "chat.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := chatTableCount(t, db); n != 3 {
+		t.Fatalf("%d chat tables after up, want 3", n)
+	}
+	if _, err := m.Down(ctx, len(chatMigrations)); err != nil {
+		t.Fatal(err)
+	}
+	if n := chatTableCount(t, db); n != 0 {
+		t.Fatalf("%d chat tables left after rolling back everything", n)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("up after full rollback: %v", err)
+	}
+}
+
+func TestFeatureMigrationsOnOldBaseline(t *testing.T) {
+	db, err := sqldb.Open(sqldb.SQLite, filepath.Join(t.TempDir(), "chat.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	// Önceki sürümlerin baseline'ı tüm tabloları oluşturuyordu; yalnızca 1 kayıtlıdır
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO messages (match_id, user_id, message) VALUES (1, 1, 'Selam')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`DELETE FROM chat_schema_migrations WHERE version > 1`); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(chatMigrations)-1 {
+		t.Fatalf("applied %d migrations, want %d", len(applied), len(chatMigrations)-1)
+	}
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM messages`).Scan(&n); err != nil || n != 1 {
+		t.Fatalf("messages = %d, err = %v; want the existing message kept", n, err)
+	}
+}
@@ -0,0 +1,265 @@
+// moderation_repository.go - Moderasyon kararları ve ihlal sayacı veritabanı işlemleri
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eros/chat-service/model"
+	"eros/shared/sqldb"
+	"eros/shared/tracing"
+	"errors"
+	"time"
+)
+
+type ModerationRepository struct {
+	db *sqldb.DB
+}
+
+func NewModerationRepository(db *sqldb.DB) *ModerationRepository {
+	return &ModerationRepository{db: db}
+}
+
+// ErrAlreadyOverturned - Karar daha önce geçersiz kılınmış
+var ErrAlreadyOverturned = errors.New("decision already overturned")
+
+// StrikeRule - İhlal sayısı değiştikten sonra susturma/askı sürelerini belirler
+// Eşikler servisin politikasıdır; repository yalnızca sayaçla aynı işlemde uygular.
+type StrikeRule func(strikes *model.UserStrikes, delta int)
+
+// CreateDecision - Moderasyon kararını kaydet
+// Karar ihlal puanı yazıyorsa sayaç aynı işlemde artırılır ve rule uygulanır.
+func (r *ModerationRepository) CreateDecision(ctx context.Context, decision *model.ModerationDecision, rule StrikeRule) error {
+	ctx, span := tracing.Start(ctx, "ModerationRepository.CreateDecision")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	id, err := tx.InsertIDContext(ctx, `
+		INSERT INTO moderation_decisions (user_id, match_id, message_hash, category, severity, action, rules, strike, overturned, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, decision.UserID, decision.MatchID, decision.MessageHash, decision.Category, decision.Severity,
+		decision.Action, decision.Rules, decision.Strike, decision.Overturned, decision.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if decision.Strike {
+		if err := adjustStrikes(ctx, tx, decision.UserID, 1, decision.CreatedAt, rule); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	decision.ID = int(id)
+	return nil
+}
+
+// ListDecisions - Filtreye göre kararları getir (en yeniler önce)
+func (r *ModerationRepository) ListDecisions(ctx context.Context, filter model.DecisionFilter) ([]model.ModerationDecision, error) {
+	ctx, span := tracing.Start(ctx, "ModerationRepository.ListDecisions")
+	defer span.End()
+
+	query := `
+		SELECT id, user_id, match_id, message_hash, category, severity, action, rules, strike, overturned, reviewed_by, reviewed_at, created_at
+		FROM moderation_decisions WHERE 1 = 1
+	`
+	var args []interface{}
+
+	if filter.UserID > 0 {
+		query += " AND user_id = ?"
+		args = append(args, filter.UserID)
+	}
+	if filter.Action != "" {
+		query += " AND action = ?"
+		args = append(args, filter.Action)
+	}
+	if filter.Overturned != nil {
+		query += " AND overturned = ?"
+		args = append(args, *filter.Overturned)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	decisions := []model.ModerationDecision{}
+	for rows.Next() {
+		decision, err := scanDecision(rows)
+		if err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, *decision)
+	}
+
+	return decisions, rows.Err()
+}
+
+// OverturnDecision - Kararı geçersiz kıl ve yazdığı ihlal puanını aynı işlemde geri al
+// Karar bulunamazsa sql.ErrNoRows, zaten geçersizse ErrAlreadyOverturned döner.
+func (r *ModerationRepository) OverturnDecision(ctx context.Context, decisionID int, reviewer string, reviewedAt time.Time, rule StrikeRule) (*model.ModerationDecision, error) {
+	ctx, span := tracing.Start(ctx, "ModerationRepository.OverturnDecision")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Koşullu UPDATE iki yöneticinin aynı kararı iki kez geri almasını engeller
+	res, err := tx.ExecContext(ctx, `
+		UPDATE moderation_decisions SET overturned = TRUE, reviewed_by = ?, reviewed_at = ?
+		WHERE id = ? AND overturned = FALSE
+	`, reviewer, reviewedAt, decisionID)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	decision, err := scanDecision(tx.QueryRowContext(ctx, `
+		SELECT id, user_id, match_id, message_hash, category, severity, action, rules, strike, overturned, reviewed_by, reviewed_at, created_at
+		FROM moderation_decisions WHERE id = ?
+	`, decisionID))
+	if err != nil {
+		return nil, err
+	}
+	if updated == 0 {
+		return nil, ErrAlreadyOverturned
+	}
+
+	if decision.Strike {
+		if err := adjustStrikes(ctx, tx, decision.UserID, -1, reviewedAt, rule); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return decision, nil
+}
+
+// GetStrikes - Kullanıcının ihlal kaydını getir (kayıt yoksa sıfır değerli kayıt döner)
+func (r *ModerationRepository) GetStrikes(ctx context.Context, userID int) (*model.UserStrikes, error) {
+	ctx, span := tracing.Start(ctx, "ModerationRepository.GetStrikes")
+	defer span.End()
+
+	strikes := &model.UserStrikes{UserID: userID}
+	var mutedUntil, suspendedUntil sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT strikes, muted_until, suspended_until, updated_at
+		FROM user_strikes WHERE user_id = ?
+	`, userID).Scan(&strikes.Strikes, &mutedUntil, &suspendedUntil, &strikes.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return strikes, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if mutedUntil.Valid {
+		strikes.MutedUntil = &mutedUntil.Time
+	}
+	if suspendedUntil.Valid {
+		strikes.SuspendedUntil = &suspendedUntil.Time
+	}
+
+	return strikes, nil
+}
+
+// adjustStrikes - İhlal sayacını işlem içinde değiştir ve rule ile kısıtlamaları güncelle
+// Sayaç okunup yazılmaz, "strikes + ?" ile artırılır; UPDATE satırı işlem sonuna kadar
+// kilitlediği için eşzamanlı ihlaller birbirini ezmez.
+func adjustStrikes(ctx context.Context, tx *sqldb.Tx, userID, delta int, now time.Time, rule StrikeRule) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO user_strikes (user_id, strikes, updated_at) VALUES (?, 0, ?)
+		ON CONFLICT(user_id) DO NOTHING
+	`, userID, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE user_strikes
+		SET strikes = CASE WHEN strikes + ? < 0 THEN 0 ELSE strikes + ? END, updated_at = ?
+		WHERE user_id = ?
+	`, delta, delta, now, userID)
+	if err != nil {
+		return err
+	}
+
+	if rule == nil {
+		return nil
+	}
+
+	strikes := &model.UserStrikes{UserID: userID}
+	var mutedUntil, suspendedUntil sql.NullTime
+	err = tx.QueryRowContext(ctx, `
+		SELECT strikes, muted_until, suspended_until, updated_at
+		FROM user_strikes WHERE user_id = ?
+	`, userID).Scan(&strikes.Strikes, &mutedUntil, &suspendedUntil, &strikes.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if mutedUntil.Valid {
+		strikes.MutedUntil = &mutedUntil.Time
+	}
+	if suspendedUntil.Valid {
+		strikes.SuspendedUntil = &suspendedUntil.Time
+	}
+
+	rule(strikes, delta)
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE user_strikes SET muted_until = ?, suspended_until = ? WHERE user_id = ?
+	`, strikes.MutedUntil, strikes.SuspendedUntil, userID)
+	return err
+}
+
+// rowScanner - *sql.Row ve *sql.Rows için ortak arayüz
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanDecision - Satırı moderasyon kararına çevir
+func scanDecision(row rowScanner) (*model.ModerationDecision, error) {
+	var decision model.ModerationDecision
+	var category, rules, reviewedBy sql.NullString
+	var reviewedAt sql.NullTime
+
+	err := row.Scan(&decision.ID, &decision.UserID, &decision.MatchID, &decision.MessageHash,
+		&category, &decision.Severity, &decision.Action, &rules, &decision.Strike,
+		&decision.Overturned, &reviewedBy, &reviewedAt, &decision.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	decision.Category = category.String
+	decision.Rules = rules.String
+	decision.ReviewedBy = reviewedBy.String
+	if reviewedAt.Valid {
+		decision.ReviewedAt = &reviewedAt.Time
+	}
+
+	return &decision, nil
+}
@@ -0,0 +1,58 @@
+// postgres.go - PostgreSQL sürücüsü ve şema göçleri (Chat Service)
+package repository
+
+import (
+	"eros/shared/migrate"
+
+	_ "github.com/lib/pq"
+)
+
+// postgresMigrations - chatMigrations ile aynı versiyonlar, PostgreSQL söz dizimiyle
+var postgresMigrations = []migrate.Migration{
+	{Version: 1, Name: "baseline", Up: migrate.SQL(), Down: migrate.SQL()},
+	{
+		Version: 2,
+		Name:    "moderation",
+		Up: migrate.SQL(
+			`CREATE TABLE IF NOT EXISTS moderation_decisions (
+				id SERIAL PRIMARY KEY,
+				user_id INTEGER NOT NULL,
+				match_id INTEGER NOT NULL,
+				message_hash TEXT NOT NULL,
+				category TEXT,
+				severity TEXT NOT NULL,
+				action TEXT NOT NULL,
+				rules TEXT,
+				strike BOOLEAN DEFAULT FALSE,
+				overturned BOOLEAN DEFAULT FALSE,
+				reviewed_by TEXT,
+				reviewed_at TIMESTAMPTZ,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS user_strikes (
+				user_id INTEGER PRIMARY KEY,
+				strikes INTEGER DEFAULT 0,
+				muted_until TIMESTAMPTZ,
+				suspended_until TIMESTAMPTZ,
+				updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+		),
+		Down: migrate.SQL(`DROP TABLE IF EXISTS user_strikes`, `DROP TABLE IF EXISTS moderation_decisions`),
+	},
+	{
+		Version: 3,
+		Name:    "messages",
+		Up: migrate.SQL(
+			`CREATE TABLE IF NOT EXISTS messages (
+				id BIGSERIAL PRIMARY KEY,
+				match_id INTEGER NOT NULL,
+				user_id INTEGER NOT NULL,
+				message TEXT NOT NULL,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_messages_match ON messages (match_id, created_at)`,
+		),
+		Down: migrate.SQL(`DROP TABLE IF EXISTS messages`),
+	},
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"eros/chat-service/model"
+	"eros/shared/sqldb"
+	"eros/shared/sqldb/sqldbtest"
+	"errors"
+	"testing"
+	"time"
+)
+
+// forEachDB - Testi bellek içi SQLite'ta, TEST_DATABASE_URL tanımlıysa PostgreSQL'de de çalıştır
+// matches tablosu match-service'e aittir; testler için en küçük hali oluşturulur.
+func forEachDB(t *testing.T, test func(t *testing.T, db *sqldb.DB)) {
+	t.Run(sqldb.SQLite.Name, func(t *testing.T) {
+		db, err := sqldb.Open(sqldb.SQLite, ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Bellek içi SQLite her bağlantıda ayrı bir veritabanıdır
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+		test(t, migrateTestDB(t, db))
+	})
+
+	if sqldbtest.URL() == "" {
+		return
+	}
+	t.Run(sqldb.Postgres.Name, func(t *testing.T) {
+		db, err := sqldbtest.OpenPostgres("chat_repository_test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		test(t, migrateTestDB(t, db))
+	})
+}
+
+// migrateTestDB - matches tablosunu oluştur ve göçleri uygula
+func migrateTestDB(t *testing.T, db *sqldb.DB) *sqldb.DB {
+	t.Helper()
+	if _, err := db.Exec(`CREATE TABLE matches (id INTEGER PRIMARY KEY, user1_id INTEGER NOT NULL, user2_id INTEGER NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestMessageRepository(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *sqldb.DB) {
+		ctx := context.Background()
+		repo := NewMessageRepository(db)
+		start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		// Aynı saniyede yazılan mesajlar id sırasıyla döner
+		sent := []model.ChatMessage{
+			{MatchID: 1, UserID: 1, Message: "Selam", CreatedAt: start},
+			{MatchID: 1, UserID: 2, Message: "Merhaba", CreatedAt: start.Add(time.Minute)},
+			{MatchID: 1, UserID: 1, Message: "Nasılsın?", CreatedAt: start.Add(time.Minute)},
+			{MatchID: 2, UserID: 3, Message: "Başka sohbet", CreatedAt: start},
+		}
+		for i := range sent {
+			if err := repo.CreateMessage(ctx, &sent[i]); err != nil {
+				t.Fatal(err)
+			}
+			if sent[i].ID == 0 {
+				t.Fatalf("message %d has no id", i)
+			}
+		}
+
+		messages, err := repo.GetMessagesByMatchID(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(messages) != 3 {
+			t.Fatalf("got %d messages, want 3", len(messages))
+		}
+		for i, m := range messages {
+			if m.ID != sent[i].ID || m.Message != sent[i].Message || !m.CreatedAt.Equal(sent[i].CreatedAt) {
+				t.Errorf("message %d = %+v, want %+v", i, m, sent[i])
+			}
+		}
+
+		counts, err := repo.CountMessagesByUser(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(counts) != 2 || counts[1] != 2 || counts[2] != 1 {
+			t.Fatalf("counts = %v, want map[1:2 2:1]", counts)
+		}
+
+		if empty, err := repo.GetMessagesByMatchID(ctx, 99); err != nil || empty == nil || len(empty) != 0 {
+			t.Fatalf("unknown match = %v, err = %v; want an empty list", empty, err)
+		}
+	})
+}
+
+func TestIsParticipant(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *sqldb.DB) {
+		ctx := context.Background()
+		repo := NewMatchRepository(db)
+		if _, err := db.Exec(`INSERT INTO matches (id, user1_id, user2_id) VALUES (1, 10, 20)`); err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			matchID, userID int
+			want            bool
+		}{
+			{1, 10, true},
+			{1, 20, true},
+			{1, 30, false},
+			{2, 10, false},
+		}
+		for _, tt := range tests {
+			got, err := repo.IsParticipant(ctx, tt.matchID, tt.userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("IsParticipant(%d, %d) = %v, want %v", tt.matchID, tt.userID, got, tt.want)
+			}
+		}
+	})
+}
+
+func TestModerationRepository(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *sqldb.DB) {
+		ctx := context.Background()
+		repo := NewModerationRepository(db)
+		now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		// İkinci ihlalde susturan kural
+		muteAt := func(strikes *model.UserStrikes, delta int) {
+			if strikes.Strikes >= 2 {
+				until := now.Add(time.Hour)
+				strikes.MutedUntil = &until
+			} else {
+				strikes.MutedUntil = nil
+			}
+		}
+
+		decisions := []*model.ModerationDecision{
+			{UserID: 1, MatchID: 1, MessageHash: "a", Category: "harassment", Severity: "high", Action: "block", Strike: true, CreatedAt: now},
+			{UserID: 1, MatchID: 1, MessageHash: "b", Severity: "low", Action: "warn", CreatedAt: now.Add(time.Minute)},
+			{UserID: 1, MatchID: 1, MessageHash: "c", Category: "harassment", Severity: "high", Action: "block", Strike: true, CreatedAt: now.Add(2 * time.Minute)},
+			{UserID: 2, MatchID: 2, MessageHash: "d", Severity: "high", Action: "block", Strike: true, CreatedAt: now},
+		}
+		for _, d := range decisions {
+			if err := repo.CreateDecision(ctx, d, muteAt); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		strikes, err := repo.GetStrikes(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strikes.Strikes != 2 || strikes.MutedUntil == nil {
+			t.Fatalf("strikes = %+v, want 2 strikes and a mute", strikes)
+		}
+
+		blocked, err := repo.ListDecisions(ctx, model.DecisionFilter{UserID: 1, Action: "block"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(blocked) != 2 || blocked[0].ID != decisions[2].ID || blocked[1].ID != decisions[0].ID {
+			t.Fatalf("blocked decisions = %+v, want the two blocks newest first", blocked)
+		}
+
+		// Geçersiz kılma puanı geri alır ve kuralı yeniden uygular
+		overturned, err := repo.OverturnDecision(ctx, decisions[2].ID, "admin", now.Add(time.Hour), muteAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !overturned.Overturned || overturned.ReviewedBy != "admin" || overturned.ReviewedAt == nil {
+			t.Fatalf("overturned = %+v", overturned)
+		}
+		if strikes, _ := repo.GetStrikes(ctx, 1); strikes.Strikes != 1 || strikes.MutedUntil != nil {
+			t.Fatalf("strikes after overturn = %+v, want 1 strike and no mute", strikes)
+		}
+		if _, err := repo.OverturnDecision(ctx, decisions[2].ID, "admin", now, muteAt); !errors.Is(err, ErrAlreadyOverturned) {
+			t.Fatalf("second overturn err = %v, want ErrAlreadyOverturned", err)
+		}
+
+		yes := true
+		if list, err := repo.ListDecisions(ctx, model.DecisionFilter{Overturned: &yes}); err != nil || len(list) != 1 {
+			t.Fatalf("overturned list = %+v, err = %v; want 1", list, err)
+		}
+		if strikes, err := repo.GetStrikes(ctx, 99); err != nil || strikes.Strikes != 0 {
+			t.Fatalf("unknown user strikes = %+v, err = %v; want zero", strikes, err)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+// sqlite.go - SQLite bağlantı ve işlemleri (Chat Service)
+package repository
+
+import (
+	"context"
+	"eros/shared/sqldb"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func NewSQLiteDB(dbPath string) (*sqldb.DB, error) {
+	return sqldb.Open(sqldb.SQLite, dbPath)
+}
+
+// InitChatDatabase - Bekleyen şema göçlerini uygula
+func InitChatDatabase(db *sqldb.DB) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	_, err = migrator.Up(context.Background())
+	return err
+}
@@ -2,64 +2,150 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"eros/chat-service/model"
+	"eros/chat-service/repository"
+	"eros/shared/apierror"
+	"eros/shared/server"
 	"eros/shared/utils"
+	"errors"
 	"fmt"
 	"time"
 )
 
+var (
+	// ErrInappropriateContent - Moderasyon mesajı engelledi
+	ErrInappropriateContent = errors.New("inappropriate content detected")
+	// ErrContactInfoNotAllowed - Sohbetin başında iletişim bilgisi paylaşılamaz
+	ErrContactInfoNotAllowed = errors.New("sharing contact information is not allowed yet")
+	// ErrNotParticipant - Kullanıcı eşleşmenin taraflarından biri değil
+	ErrNotParticipant = errors.New("user is not a participant of this match")
+)
+
 type ChatService struct {
-	aiService *utils.OpenRouterClient
+	aiService         *utils.OpenRouterClient
+	messageRepo       *repository.MessageRepository
+	matchRepo         *repository.MatchRepository
+	moderationService *ModerationService
+	contactPolicy     utils.ContactPolicy
+	workers           *server.Workers
 }
 
-func NewChatService() *ChatService {
+func NewChatService(messageRepo *repository.MessageRepository, matchRepo *repository.MatchRepository, moderationService *ModerationService, contactPolicy utils.ContactPolicy, workers *server.Workers) *ChatService {
 	return &ChatService{
-		aiService: utils.NewOpenRouterClientFromEnv(),
+		aiService:         utils.NewOpenRouterClientFromEnv(),
+		messageRepo:       messageRepo,
+		matchRepo:         matchRepo,
+		moderationService: moderationService,
+		contactPolicy:     contactPolicy,
+		workers:           workers,
 	}
 }
 
+// AIHealth - AI sağlayıcısının durumu (hazırlık kontrolü için)
+func (s *ChatService) AIHealth(ctx context.Context) error {
+	return s.aiService.Health(ctx)
+}
+
 // SendMessage - Mesaj gönder ve AI analizi yap
-func (s *ChatService) SendMessage(matchID int, userID int, message string) (*model.ChatMessage, error) {
-	// Güvenlik filtresi
-	isSafe, err := s.aiService.SecurityFilter(message)
-	if err != nil {
-		return nil, fmt.Errorf("security check failed: %v", err)
+func (s *ChatService) SendMessage(ctx context.Context, matchID int, userID int, message string) (*model.ChatMessage, error) {
+	// Susturulmuş veya askıya alınmış kullanıcı mesaj gönderemez
+	if err := s.moderationService.CheckRestrictions(ctx, userID); err != nil {
+		switch {
+		case errors.Is(err, ErrUserMuted):
+			messagesBlocked.Inc("match", "muted")
+		case errors.Is(err, ErrUserSuspended):
+			messagesBlocked.Inc("match", "suspended")
+		}
+		return nil, err
+	}
+
+	// İçerik moderasyonu; her mesaj için tek karar kaydı yazılır
+	decision := s.moderationService.Evaluate(message)
+	if !decision.Allowed() {
+		if err := s.moderationService.Record(ctx, userID, matchID, message, decision); err != nil {
+			return nil, fmt.Errorf("moderation failed: %v", err)
+		}
+		messagesBlocked.Inc("match", "moderation")
+		return nil, ErrInappropriateContent
 	}
 
-	if !isSafe {
-		return nil, fmt.Errorf("inappropriate content detected")
+	// İletişim bilgisi: iki taraf da yeterince mesajlaşana kadar engellenir
+	if contacts := utils.DetectContactInfo(message); len(contacts) > 0 {
+		allowed, err := s.contactInfoAllowed(ctx, matchID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			if err := s.moderationService.RecordContactInfo(ctx, userID, matchID, message, contacts); err != nil {
+				return nil, fmt.Errorf("moderation failed: %v", err)
+			}
+			messagesBlocked.Inc("match", "contact_info")
+			return nil, ErrContactInfoNotAllowed
+		}
 	}
 
-	// Mesajı kaydet
+	if err := s.moderationService.Record(ctx, userID, matchID, message, decision); err != nil {
+		return nil, fmt.Errorf("moderation failed: %v", err)
+	}
+
+	// Mesajı kaydet (maskeleme kararında maskelenmiş haliyle)
 	chatMessage := &model.ChatMessage{
 		MatchID:   matchID,
 		UserID:    userID,
-		Message:   message,
+		Message:   decision.Masked,
 		CreatedAt: time.Now(),
 	}
 
-	// TODO: Database'e kaydet
-	// if err := s.messageRepo.CreateMessage(chatMessage); err != nil {
-	//     return nil, err
-	// }
+	if err := s.messageRepo.CreateMessage(ctx, chatMessage); err != nil {
+		return nil, err
+	}
+	messagesSent.Inc("match")
 
-	// AI analizi (asenkron)
-	go s.analyzeMessage(matchID, message)
+	// AI analizi (asenkron, kapanışta bitmesi beklenir)
+	s.workers.Go(func(context.Context) { s.analyzeMessage(matchID, message) })
 
 	return chatMessage, nil
 }
 
+// CheckParticipant - Kullanıcı eşleşmenin taraflarından biri değilse ErrNotParticipant
+func (s *ChatService) CheckParticipant(ctx context.Context, matchID, userID int) error {
+	ok, err := s.matchRepo.IsParticipant(ctx, matchID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotParticipant
+	}
+	return nil
+}
+
 // GetMessages - Mesajları getir
-func (s *ChatService) GetMessages(matchID int) ([]model.ChatMessage, error) {
-	// TODO: Database'den getir
-	// return s.messageRepo.GetMessagesByMatchID(matchID)
-	return []model.ChatMessage{}, nil
+func (s *ChatService) GetMessages(ctx context.Context, matchID int) ([]model.ChatMessage, error) {
+	return s.messageRepo.GetMessagesByMatchID(ctx, matchID)
+}
+
+// contactInfoAllowed - Gönderen ve karşı taraf politikadaki mesaj sayısına ulaştı mı
+func (s *ChatService) contactInfoAllowed(ctx context.Context, matchID, userID int) (bool, error) {
+	counts, err := s.messageRepo.CountMessagesByUser(ctx, matchID)
+	if err != nil {
+		return false, err
+	}
+
+	other := 0
+	for id, count := range counts {
+		if id != userID {
+			other += count
+		}
+	}
+
+	return s.contactPolicy.Allows(counts[userID], other), nil
 }
 
 // AnalyzeConversation - Sohbet analizi
-func (s *ChatService) AnalyzeConversation(matchID int) (*model.ConversationAnalysis, error) {
-	messages, err := s.GetMessages(matchID)
+func (s *ChatService) AnalyzeConversation(ctx context.Context, matchID int) (*model.ConversationAnalysis, error) {
+	messages, err := s.GetMessages(ctx, matchID)
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +157,7 @@ func (s *ChatService) AnalyzeConversation(matchID int) (*model.ConversationAnaly
 	}
 
 	// AI analizi
-	analysis, err := s.aiService.ChatAnalysis(conversation)
+	analysis, err := s.aiService.ChatAnalysis(ctx, conversation)
 	if err != nil {
 		return nil, err
 	}
@@ -79,37 +165,27 @@ func (s *ChatService) AnalyzeConversation(matchID int) (*model.ConversationAnaly
 	// JSON parse et
 	var result model.ConversationAnalysis
 	if err := json.Unmarshal([]byte(analysis), &result); err != nil {
-		return nil, err
+		// Model beklenen JSON yerine serbest metin döndürdüyse sağlayıcı hatası sayılır
+		return nil, apierror.AIUnavailable(fmt.Errorf("invalid AI response: %w", err))
 	}
 
 	return &result, nil
 }
 
 // GenerateIceBreaker - Buz kırıcı mesaj oluştur
-func (s *ChatService) GenerateIceBreaker(user1, user2 map[string]interface{}) (string, error) {
-	return s.aiService.IceBreaker(user1, user2)
+func (s *ChatService) GenerateIceBreaker(ctx context.Context, user1, user2 map[string]interface{}) (string, error) {
+	return s.aiService.IceBreaker(ctx, user1, user2)
 }
 
 // analyzeMessage - Mesaj analizi (asenkron)
+// Güvenlik kontrolü SendMessage içinde ModerationService ile senkron yapılır.
 func (s *ChatService) analyzeMessage(matchID int, message string) {
-	// Mesaj içeriğini analiz et
-	isSafe, err := s.aiService.SecurityFilter(message)
-	if err != nil {
-		// Log error
-		return
-	}
-
-	if !isSafe {
-		// TODO: Mesajı engelle ve kullanıcıyı uyar
-		return
-	}
-
 	// TODO: Mesaj analizi sonuçlarını kaydet
 }
 
 // GetConversationStats - Sohbet istatistikleri
-func (s *ChatService) GetConversationStats(matchID int) (*model.ConversationStats, error) {
-	messages, err := s.GetMessages(matchID)
+func (s *ChatService) GetConversationStats(ctx context.Context, matchID int) (*model.ConversationStats, error) {
+	messages, err := s.GetMessages(ctx, matchID)
 	if err != nil {
 		return nil, err
 	}
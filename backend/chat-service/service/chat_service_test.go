@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"eros/chat-service/model"
+	"eros/chat-service/repository"
+	"eros/shared/moderation"
+	"eros/shared/server"
+	"eros/shared/sqldb"
+	"eros/shared/utils"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newChatService - Bellek içi SQLite üzerinde sohbet ve moderasyon servisi
+func newChatService(t *testing.T) (*ChatService, *ModerationService) {
+	t.Helper()
+	db, err := sqldb.Open(sqldb.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Bellek içi SQLite her bağlantıda ayrı bir veritabanıdır
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := repository.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("OPENROUTER_API_KEY", "")
+	workers := server.NewWorkers()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		workers.Stop(ctx)
+	})
+	moderationService := NewModerationService(repository.NewModerationRepository(db), moderation.Default(), DefaultStrikePolicy(), nil)
+	return NewChatService(repository.NewMessageRepository(db), repository.NewMatchRepository(db), moderationService, utils.DefaultContactPolicy(), workers), moderationService
+}
+
+func TestSendMessageRecordsOneDecision(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		wantErr  error
+		category string
+		action   moderation.Severity
+	}{
+		{name: "clean message", message: "Merhaba, nasılsın?", action: moderation.SeverityAllow},
+		{name: "blocked by moderation", message: "gebertirim", wantErr: ErrInappropriateContent, category: string(moderation.CategoryViolence), action: moderation.SeverityBlock},
+		{name: "early contact info", message: "ara beni 0532 123 45 67", wantErr: ErrContactInfoNotAllowed, category: ContactInfoCategory, action: moderation.SeverityBlock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chat, mod := newChatService(t)
+			ctx := context.Background()
+
+			_, err := chat.SendMessage(ctx, 1, 1, tt.message)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			decisions, err := mod.ListDecisions(ctx, model.DecisionFilter{UserID: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(decisions) != 1 {
+				t.Fatalf("got %d decisions, want 1: %+v", len(decisions), decisions)
+			}
+			if d := decisions[0]; d.Category != tt.category || d.Action != tt.action.String() {
+				t.Errorf("decision = %+v, want category %q action %s", d, tt.category, tt.action)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+// metrics.go - Sohbet ve moderasyon metrikleri
+package service
+
+import "eros/shared/metrics"
+
+var (
+	messagesSent = metrics.NewCounter("messages_sent_total",
+		"Messages stored by chat (match).", "chat")
+	messagesBlocked = metrics.NewCounter("messages_blocked_total",
+		"Messages rejected before storing by chat and reason (moderation, contact_info, muted, suspended).", "chat", "reason")
+)
@@ -0,0 +1,260 @@
+// moderation_service.go - Moderasyon kararlarının kaydı, ihlal sayacı ve kısıtlamalar
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"eros/chat-service/model"
+	"eros/chat-service/repository"
+	"eros/shared/moderation"
+	"eros/shared/utils"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUserMuted         = errors.New("user is temporarily muted")
+	ErrUserSuspended     = errors.New("user is suspended")
+	ErrAlreadyOverturned = repository.ErrAlreadyOverturned
+)
+
+// ContactInfoCategory - İletişim bilgisi kararlarının denetim kaydındaki kategorisi
+const ContactInfoCategory = "contact_info"
+
+// StrikePolicy - İhlal puanı ve otomatik kısıtlama eşikleri
+type StrikePolicy struct {
+	StrikeSeverity  moderation.Severity // Bu ciddiyet ve üstü kararlar ihlal puanı yazar
+	MuteAfter       int                 // Bu kadar ihlalde geçici susturma
+	MuteDuration    time.Duration
+	SuspendAfter    int // Bu kadar ihlalde hesap askıya alma
+	SuspendDuration time.Duration
+}
+
+// DefaultStrikePolicy - Varsayılan eşikler
+func DefaultStrikePolicy() StrikePolicy {
+	return StrikePolicy{
+		StrikeSeverity:  moderation.SeverityBlock,
+		MuteAfter:       3,
+		MuteDuration:    24 * time.Hour,
+		SuspendAfter:    5,
+		SuspendDuration: 7 * 24 * time.Hour,
+	}
+}
+
+// StrikePolicyFromEnv - Eşikleri ortam değişkenlerinden oku
+func StrikePolicyFromEnv() StrikePolicy {
+	policy := DefaultStrikePolicy()
+
+	if v := os.Getenv("MODERATION_STRIKE_SEVERITY"); v != "" {
+		if severity, ok := moderation.ParseSeverity(v); ok {
+			policy.StrikeSeverity = severity
+		}
+	}
+	if v := os.Getenv("MODERATION_MUTE_AFTER"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			policy.MuteAfter = n
+		}
+	}
+	if v := os.Getenv("MODERATION_MUTE_HOURS"); v != "" {
+		if hours, err := strconv.ParseFloat(v, 64); err == nil {
+			policy.MuteDuration = time.Duration(hours * float64(time.Hour))
+		}
+	}
+	if v := os.Getenv("MODERATION_SUSPEND_AFTER"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			policy.SuspendAfter = n
+		}
+	}
+	if v := os.Getenv("MODERATION_SUSPEND_HOURS"); v != "" {
+		if hours, err := strconv.ParseFloat(v, 64); err == nil {
+			policy.SuspendDuration = time.Duration(hours * float64(time.Hour))
+		}
+	}
+
+	return policy
+}
+
+type ModerationService struct {
+	repo      *repository.ModerationRepository
+	engine    *moderation.Engine
+	policy    StrikePolicy
+	suspender UserSuspender
+}
+
+// NewModerationService - suspender nil ise askıya alma yalnızca sohbette uygulanır
+func NewModerationService(repo *repository.ModerationRepository, engine *moderation.Engine, policy StrikePolicy, suspender UserSuspender) *ModerationService {
+	return &ModerationService{
+		repo:      repo,
+		engine:    engine,
+		policy:    policy,
+		suspender: suspender,
+	}
+}
+
+// CheckRestrictions - Kullanıcı şu an mesaj gönderebilir mi
+func (s *ModerationService) CheckRestrictions(ctx context.Context, userID int) error {
+	strikes, err := s.repo.GetStrikes(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if strikes.SuspendedUntil != nil && now.Before(*strikes.SuspendedUntil) {
+		return fmt.Errorf("%w until %s", ErrUserSuspended, strikes.SuspendedUntil.Format(time.RFC3339))
+	}
+	if strikes.MutedUntil != nil && now.Before(*strikes.MutedUntil) {
+		return fmt.Errorf("%w until %s", ErrUserMuted, strikes.MutedUntil.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// Evaluate - Mesajı değerlendir; karar kaydedilmez
+// Gönderim başka bir kuraldan (ör. iletişim bilgisi) dönebileceği için kayıt Record ile
+// ayrıca yazılır; böylece her mesaj için tek karar kaydı olur.
+func (s *ModerationService) Evaluate(message string) moderation.Decision {
+	return s.engine.Evaluate(message)
+}
+
+// Review - Mesajı değerlendir, kararı kaydet ve gerekirse ihlal puanı yaz
+func (s *ModerationService) Review(ctx context.Context, userID, matchID int, message string) (moderation.Decision, error) {
+	decision := s.Evaluate(message)
+	return decision, s.Record(ctx, userID, matchID, message, decision)
+}
+
+// Record - Kararı denetim kaydına yaz ve gerekirse ihlal puanı yaz
+// Allow dahil her karar kaydedilir; mesajın kendisi değil özeti saklanır. İhlal puanı
+// hesabı askıya aldırdıysa bu, kayıt tamamlandıktan sonra user-service'e iletilir.
+func (s *ModerationService) Record(ctx context.Context, userID, matchID int, message string, decision moderation.Decision) error {
+	record := &model.ModerationDecision{
+		UserID:      userID,
+		MatchID:     matchID,
+		MessageHash: hashMessage(message),
+		Category:    string(decision.TopCategory()),
+		Severity:    topRuleSeverity(decision).String(),
+		Action:      decision.Action.String(),
+		Rules:       matchedRules(decision),
+		Strike:      decision.Action != moderation.SeverityAllow && decision.Action >= s.policy.StrikeSeverity,
+		CreatedAt:   time.Now(),
+	}
+
+	suspended := false
+	err := s.repo.CreateDecision(ctx, record, func(strikes *model.UserStrikes, delta int) {
+		before := strikes.SuspendedUntil
+		s.restrict(strikes, delta)
+		suspended = strikes.SuspendedUntil != nil && strikes.SuspendedUntil != before
+	})
+	if err != nil {
+		return err
+	}
+
+	if suspended && s.suspender != nil {
+		// Sohbet askısı zaten yazıldı; hesap askısı iletilemezse mesaj yine reddedilir
+		if err := s.suspender.SuspendUser(ctx, userID); err != nil {
+			slog.ErrorContext(ctx, "failed to suspend user account", "user_id", userID, "error", err)
+		} else {
+			slog.InfoContext(ctx, "user account suspended after strikes", "user_id", userID)
+		}
+	}
+	return nil
+}
+
+// RecordContactInfo - Erken paylaşılan iletişim bilgisini denetim kaydına yaz
+// Platform dışına yönlendirme tek başına ihlal sayılmaz, ihlal puanı yazılmaz.
+func (s *ModerationService) RecordContactInfo(ctx context.Context, userID, matchID int, message string, contacts []utils.ContactMatch) error {
+	seen := make(map[utils.ContactKind]bool)
+	var kinds []string
+	for _, c := range contacts {
+		if !seen[c.Kind] {
+			seen[c.Kind] = true
+			kinds = append(kinds, string(c.Kind))
+		}
+	}
+
+	return s.repo.CreateDecision(ctx, &model.ModerationDecision{
+		UserID:      userID,
+		MatchID:     matchID,
+		MessageHash: hashMessage(message),
+		Category:    ContactInfoCategory,
+		Severity:    moderation.SeverityBlock.String(),
+		Action:      moderation.SeverityBlock.String(),
+		Rules:       strings.Join(kinds, ","),
+		CreatedAt:   time.Now(),
+	}, nil)
+}
+
+// ListDecisions - Admin için kararları listele
+func (s *ModerationService) ListDecisions(ctx context.Context, filter model.DecisionFilter) ([]model.ModerationDecision, error) {
+	return s.repo.ListDecisions(ctx, filter)
+}
+
+// GetUserStrikes - Kullanıcının ihlal kaydı
+func (s *ModerationService) GetUserStrikes(ctx context.Context, userID int) (*model.UserStrikes, error) {
+	return s.repo.GetStrikes(ctx, userID)
+}
+
+// OverturnDecision - Kararı geçersiz kıl ve yazılan ihlal puanını geri al
+func (s *ModerationService) OverturnDecision(ctx context.Context, decisionID int, reviewer string) (*model.ModerationDecision, error) {
+	return s.repo.OverturnDecision(ctx, decisionID, reviewer, time.Now(), s.restrict)
+}
+
+// restrict - Güncel ihlal sayısına göre kısıtlamaları eşiklere göre güncelle
+// Repository bunu sayacı değiştirdiği işlemin içinde çağırır.
+func (s *ModerationService) restrict(strikes *model.UserStrikes, delta int) {
+	now := strikes.UpdatedAt
+
+	if delta > 0 {
+		// Eşik aşıldıysa her yeni ihlal kısıtlamayı yeniler
+		if s.policy.SuspendAfter > 0 && strikes.Strikes >= s.policy.SuspendAfter {
+			until := now.Add(s.policy.SuspendDuration)
+			strikes.SuspendedUntil = &until
+		} else if s.policy.MuteAfter > 0 && strikes.Strikes >= s.policy.MuteAfter {
+			until := now.Add(s.policy.MuteDuration)
+			strikes.MutedUntil = &until
+		}
+	} else {
+		// Geri alınan ihlal eşiğin altına düşürdüyse kısıtlamayı kaldır
+		if s.policy.SuspendAfter <= 0 || strikes.Strikes < s.policy.SuspendAfter {
+			strikes.SuspendedUntil = nil
+		}
+		if s.policy.MuteAfter <= 0 || strikes.Strikes < s.policy.MuteAfter {
+			strikes.MutedUntil = nil
+		}
+	}
+}
+
+// hashMessage - Mesaj içeriğinin SHA-256 özeti
+func hashMessage(message string) string {
+	sum := sha256.Sum256([]byte(message))
+	return hex.EncodeToString(sum[:])
+}
+
+// topRuleSeverity - Politika uygulanmadan önceki en yüksek kural ciddiyeti
+func topRuleSeverity(decision moderation.Decision) moderation.Severity {
+	severity := moderation.SeverityAllow
+	for _, m := range decision.Matches {
+		if m.Rule.Severity > severity {
+			severity = m.Rule.Severity
+		}
+	}
+	return severity
+}
+
+// matchedRules - Eşleşen kural ifadeleri (tekrarsız)
+func matchedRules(decision moderation.Decision) string {
+	seen := make(map[string]bool)
+	var rules []string
+	for _, m := range decision.Matches {
+		if !seen[m.Rule.Phrase] {
+			seen[m.Rule.Phrase] = true
+			rules = append(rules, m.Rule.Phrase)
+		}
+	}
+	return strings.Join(rules, ",")
+}
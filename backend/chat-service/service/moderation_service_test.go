@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"eros/chat-service/model"
+	"eros/chat-service/repository"
+	"eros/shared/moderation"
+	"eros/shared/sqldb"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// newModerationService - Göçleri uygulanmış dosya tabanlı SQLite üzerinde servis
+// Eşzamanlılık için birden fazla bağlantı gerekir; bu yüzden :memory: kullanılmaz.
+func newModerationService(t *testing.T, policy StrikePolicy) *ModerationService {
+	t.Helper()
+	db, err := sqldb.Open(sqldb.SQLite, filepath.Join(t.TempDir(), "chat.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := repository.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return NewModerationService(repository.NewModerationRepository(db), moderation.Default(), policy, nil)
+}
+
+func TestReviewCountsConcurrentStrikes(t *testing.T) {
+	policy := DefaultStrikePolicy()
+	s := newModerationService(t, policy)
+	ctx := context.Background()
+
+	const messages = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, messages)
+	for i := 0; i < messages; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Review(ctx, 7, 1, "gebertirim"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	strikes, err := s.GetUserStrikes(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strikes.Strikes != messages {
+		t.Fatalf("strikes = %d, want %d", strikes.Strikes, messages)
+	}
+	if strikes.SuspendedUntil == nil {
+		t.Error("user past the suspend threshold is not suspended")
+	}
+	if err := s.CheckRestrictions(ctx, 7); !errors.Is(err, ErrUserSuspended) {
+		t.Errorf("CheckRestrictions = %v, want %v", err, ErrUserSuspended)
+	}
+}
+
+func TestReviewRecordsEveryDecision(t *testing.T) {
+	s := newModerationService(t, DefaultStrikePolicy())
+	ctx := context.Background()
+
+	for _, message := range []string{"Merhaba, nasılsın?", "gebertirim"} {
+		if _, err := s.Review(ctx, 3, 1, message); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	decisions, err := s.ListDecisions(ctx, model.DecisionFilter{UserID: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decisions) != 2 {
+		t.Fatalf("got %d decisions, want 2", len(decisions))
+	}
+	// En yeni önce gelir
+	if d := decisions[1]; d.Action != moderation.SeverityAllow.String() || d.Strike {
+		t.Errorf("allowed message recorded as %+v", d)
+	}
+	if d := decisions[0]; d.Action != moderation.SeverityBlock.String() || !d.Strike {
+		t.Errorf("blocked message recorded as %+v", d)
+	}
+}
+
+func TestOverturnDecision(t *testing.T) {
+	policy := DefaultStrikePolicy()
+	policy.MuteAfter = 1
+	s := newModerationService(t, policy)
+	ctx := context.Background()
+
+	if _, err := s.Review(ctx, 5, 1, "gebertirim"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CheckRestrictions(ctx, 5); !errors.Is(err, ErrUserMuted) {
+		t.Fatalf("CheckRestrictions = %v, want %v", err, ErrUserMuted)
+	}
+	decisions, err := s.ListDecisions(ctx, model.DecisionFilter{UserID: 5})
+	if err != nil || len(decisions) != 1 {
+		t.Fatalf("decisions = %v, err = %v", decisions, err)
+	}
+
+	// İki yönetici aynı kararı aynı anda geri alır; puan yalnızca bir kez düşer
+	var wg sync.WaitGroup
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.OverturnDecision(ctx, decisions[0].ID, "admin")
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	var ok, already int
+	for err := range results {
+		switch {
+		case err == nil:
+			ok++
+		case errors.Is(err, ErrAlreadyOverturned):
+			already++
+		default:
+			t.Fatal(err)
+		}
+	}
+	if ok != 1 || already != 1 {
+		t.Fatalf("overturned %d times, rejected %d; want 1 and 1", ok, already)
+	}
+
+	strikes, err := s.GetUserStrikes(ctx, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strikes.Strikes != 0 || strikes.MutedUntil != nil {
+		t.Errorf("strikes = %+v, want zero strikes and no mute", strikes)
+	}
+	if err := s.CheckRestrictions(ctx, 5); err != nil {
+		t.Errorf("CheckRestrictions = %v, want nil", err)
+	}
+}
+
+// recordingSuspender - Askıya alınan hesapları kaydeder
+type recordingSuspender struct {
+	mu    sync.Mutex
+	users []int
+}
+
+func (s *recordingSuspender) SuspendUser(ctx context.Context, userID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users = append(s.users, userID)
+	return nil
+}
+
+func TestStrikeSuspensionSuspendsAccount(t *testing.T) {
+	policy := DefaultStrikePolicy()
+	policy.MuteAfter = 0
+	policy.SuspendAfter = 2
+	s := newModerationService(t, policy)
+	suspender := &recordingSuspender{}
+	s.suspender = suspender
+	ctx := context.Background()
+
+	for _, message := range []string{"gebertirim", "Merhaba, nasılsın?"} {
+		if _, err := s.Review(ctx, 9, 1, message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(suspender.users) != 0 {
+		t.Fatalf("suspended %v below the threshold", suspender.users)
+	}
+
+	if _, err := s.Review(ctx, 9, 1, "gebertirim"); err != nil {
+		t.Fatal(err)
+	}
+	if len(suspender.users) != 1 || suspender.users[0] != 9 {
+		t.Fatalf("suspended = %v, want [9]", suspender.users)
+	}
+	if err := s.CheckRestrictions(ctx, 9); !errors.Is(err, ErrUserSuspended) {
+		t.Errorf("CheckRestrictions = %v, want %v", err, ErrUserSuspended)
+	}
+}
+
+func TestHTTPUserSuspender(t *testing.T) {
+	var gotPath, gotToken string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotToken = r.Method+" "+r.URL.Path, r.Header.Get("X-Admin-Token")
+		if r.URL.Path == "/api/admin/users/404/suspend" {
+			http.Error(w, `{"error":"User not found"}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	suspender, err := NewHTTPUserSuspender(srv.URL+"/", "gizli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := suspender.SuspendUser(context.Background(), 9); err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "POST /api/admin/users/9/suspend" || gotToken != "gizli" {
+		t.Fatalf("request = %q with token %q", gotPath, gotToken)
+	}
+	if err := suspender.SuspendUser(context.Background(), 404); err == nil {
+		t.Fatal("404 from user-service was not reported")
+	}
+
+	if _, err := NewHTTPUserSuspender(srv.URL, ""); err == nil {
+		t.Fatal("suspender without an admin token was created")
+	}
+}
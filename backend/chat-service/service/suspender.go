@@ -0,0 +1,80 @@
+// suspender.go - İhlal puanıyla verilen askıya almanın user-service'e iletilmesi
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// UserSuspender - Hesabı user-service'te askıya alır (users.suspended_at)
+// Sohbet askısı süreli olsa da hesap askısı kalıcıdır; kaldırılması admin kararıdır.
+type UserSuspender interface {
+	SuspendUser(ctx context.Context, userID int) error
+}
+
+// HTTPUserSuspender - user-service'in admin askıya alma uç noktasını çağırır
+//
+//	POST {USER_SERVICE_URL}/api/admin/users/{id}/suspend (X-Admin-Token: {ADMIN_TOKEN})
+type HTTPUserSuspender struct {
+	baseURL    string
+	adminToken string
+	client     *http.Client
+}
+
+func NewHTTPUserSuspender(baseURL, adminToken string) (*HTTPUserSuspender, error) {
+	if baseURL == "" {
+		return nil, errors.New("USER_SERVICE_URL is required")
+	}
+	if adminToken == "" {
+		return nil, errors.New("ADMIN_TOKEN is required")
+	}
+
+	return &HTTPUserSuspender{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		adminToken: adminToken,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// UserSuspenderFromEnv - USER_SERVICE_URL ve ADMIN_TOKEN ile istemciyi oluştur
+// ADMIN_TOKEN tanımlı değilse nil döner; askıya alma yalnızca sohbette uygulanır.
+func UserSuspenderFromEnv() (UserSuspender, error) {
+	token := os.Getenv("ADMIN_TOKEN")
+	if token == "" {
+		return nil, nil
+	}
+	baseURL := os.Getenv("USER_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8081"
+	}
+	return NewHTTPUserSuspender(baseURL, token)
+}
+
+// SuspendUser - Hesabı askıya al (zaten askıdaysa user-service ilk tarihi korur)
+func (s *HTTPUserSuspender) SuspendUser(ctx context.Context, userID int) error {
+	url := fmt.Sprintf("%s/api/admin/users/%d/suspend", s.baseURL, userID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Admin-Token", s.adminToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("user-service returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
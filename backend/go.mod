@@ -8,6 +8,22 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/mattn/go-sqlite3 v1.14.28
 	golang.org/x/crypto v0.39.0
+	golang.org/x/image v0.28.0
+)
+
+require github.com/lib/pq v1.10.9
+
+require (
+	github.com/alicebob/miniredis/v2 v2.31.1
+	github.com/redis/go-redis/v9 v9.5.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/alicebob/gopher-json v0.0.0-20200520072559-a9ecdc9d1d3a // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/yuin/gopher-lua v1.1.0 // indirect
 )
 
 replace eros/shared => ./shared
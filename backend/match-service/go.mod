@@ -9,4 +9,6 @@ require (
 	github.com/mattn/go-sqlite3 v1.14.28
 )
 
+require github.com/lib/pq v1.10.9
+
 replace eros/shared => ../shared
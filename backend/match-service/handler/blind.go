@@ -4,7 +4,8 @@ package handler
 import (
 	"encoding/json"
 	"eros/match-service/service"
-	"log"
+	"eros/shared/apierror"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -49,32 +50,32 @@ type BlindChatResponse struct {
 // RequestBlindMatch - Blind date eşleştirme isteği
 func (h *BlindHandler) RequestBlindMatch(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apierror.Write(w, r, apierror.MethodNotAllowed())
 		return
 	}
 
 	var req BlindMatchRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
 		return
 	}
 
 	// Kullanıcının aktif blind date'i var mı kontrol et
-	hasActiveMatch, err := h.matchService.HasActiveBlindMatch(req.UserID)
+	hasActiveMatch, err := h.matchService.HasActiveBlindMatch(r.Context(), req.UserID)
 	if err != nil {
-		http.Error(w, "Failed to check active matches", http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to check active matches"))
 		return
 	}
 
 	if hasActiveMatch {
-		http.Error(w, "User already has an active blind date", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("User already has an active blind date"))
 		return
 	}
 
 	// Blind date eşleştirmesi yap
-	matchID, expiresAt, err := h.matchService.CreateBlindMatch(req.UserID)
+	matchID, expiresAt, err := h.matchService.CreateBlindMatch(r.Context(), req.UserID)
 	if err != nil {
-		http.Error(w, "Failed to create blind match", http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to create blind match"))
 		return
 	}
 
@@ -85,6 +86,7 @@ func (h *BlindHandler) RequestBlindMatch(w http.ResponseWriter, r *http.Request)
 		ExpiresAt: expiresAt,
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
@@ -92,37 +94,35 @@ func (h *BlindHandler) RequestBlindMatch(w http.ResponseWriter, r *http.Request)
 // SendBlindMessage - Blind chat mesajı gönder
 func (h *BlindHandler) SendBlindMessage(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apierror.Write(w, r, apierror.MethodNotAllowed())
 		return
 	}
 
 	var req BlindChatRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
 		return
 	}
 
 	// Mesajı gönder ve AI analizi yap
-	messageID, err := h.matchService.SendBlindMessage(req.MatchID, req.UserID, req.Message)
-	if err != nil {
-		http.Error(w, "Failed to send message", http.StatusInternalServerError)
+	sent, err := h.matchService.SendBlindMessage(r.Context(), req.MatchID, req.UserID, req.Message)
+	if errors.Is(err, service.ErrInappropriateContent) || errors.Is(err, service.ErrContactInfoNotAllowed) {
+		apierror.Write(w, r, apierror.Unprocessable(err.Error()))
 		return
 	}
-
-	// AI buz kırıcı mesajı oluştur
-	_, err = h.matchService.GenerateAIIceBreaker(req.MatchID)
 	if err != nil {
-		// AI mesajı başarısız olsa bile ana mesaj gönderildi
-		log.Printf("Failed to generate AI message: %v", err)
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to send message"))
+		return
 	}
 
 	response := BlindChatResponse{
-		MessageID: messageID,
-		Message:   req.Message,
+		MessageID: sent.ID,
+		Message:   sent.Message,
 		Timestamp: time.Now().Format(time.RFC3339),
 		IsAI:      false,
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
@@ -130,23 +130,24 @@ func (h *BlindHandler) SendBlindMessage(w http.ResponseWriter, r *http.Request)
 // GetBlindMessages - Blind chat mesajlarını getir
 func (h *BlindHandler) GetBlindMessages(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apierror.Write(w, r, apierror.MethodNotAllowed())
 		return
 	}
 
 	matchIDStr := r.URL.Query().Get("match_id")
 	matchID, err := strconv.Atoi(matchIDStr)
 	if err != nil {
-		http.Error(w, "Invalid match ID", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid match ID"))
 		return
 	}
 
-	messages, err := h.matchService.GetBlindMessages(matchID)
+	messages, err := h.matchService.GetBlindMessages(r.Context(), matchID)
 	if err != nil {
-		http.Error(w, "Failed to get messages", http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to get messages"))
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(messages)
 }
@@ -154,24 +155,25 @@ func (h *BlindHandler) GetBlindMessages(w http.ResponseWriter, r *http.Request)
 // CompleteBlindDate - Blind date'i tamamla ve date görevi öner
 func (h *BlindHandler) CompleteBlindDate(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apierror.Write(w, r, apierror.MethodNotAllowed())
 		return
 	}
 
 	matchIDStr := r.URL.Query().Get("match_id")
 	matchID, err := strconv.Atoi(matchIDStr)
 	if err != nil {
-		http.Error(w, "Invalid match ID", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid match ID"))
 		return
 	}
 
 	// Blind date'i tamamla ve date görevi oluştur
-	dateTask, err := h.matchService.CompleteBlindDate(matchID)
+	dateTask, err := h.matchService.CompleteBlindDate(r.Context(), matchID)
 	if err != nil {
-		http.Error(w, "Failed to complete blind date", http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to complete blind date"))
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message":   "Blind date completed successfully",
@@ -182,23 +184,24 @@ func (h *BlindHandler) CompleteBlindDate(w http.ResponseWriter, r *http.Request)
 // GetBlindMatchStatus - Blind date durumunu getir
 func (h *BlindHandler) GetBlindMatchStatus(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apierror.Write(w, r, apierror.MethodNotAllowed())
 		return
 	}
 
 	userIDStr := r.URL.Query().Get("user_id")
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
 		return
 	}
 
-	status, err := h.matchService.GetBlindMatchStatus(userID)
+	status, err := h.matchService.GetBlindMatchStatus(r.Context(), userID)
 	if err != nil {
-		http.Error(w, "Failed to get match status", http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to get match status"))
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(status)
 }
@@ -0,0 +1,13 @@
+package handler_test
+
+import (
+	"eros/match-service/handler/contracttest"
+	"testing"
+)
+
+// Rotalar, istek gövdeleri ve yanıtlar shared/apispec belgeleriyle karşılaştırılır
+func TestContract(t *testing.T) {
+	if err := contracttest.Test(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,194 @@
+// contract.go - Match servisi handler'larının OpenAPI belgesine uyumluluk testleri
+//
+// Kullanım (servisin test dosyasında):
+//
+//	if err := contracttest.Test(); err != nil {
+//		t.Fatal(err)
+//	}
+package contracttest
+
+import (
+	"context"
+	"eros/match-service/handler"
+	"eros/match-service/repository"
+	"eros/match-service/service"
+	"eros/shared/apispec/contracttest"
+	"eros/shared/moderation"
+	"eros/shared/server"
+	"eros/shared/sqldb"
+	"eros/shared/utils"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// serviceName - openapi.json'daki x-service değeri
+const serviceName = "match"
+
+// usersTable - Kullanıcılar user-service'e aittir; match-service'in okuduğu sütunlar yeterli
+const usersTable = `
+	CREATE TABLE users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		email TEXT UNIQUE NOT NULL,
+		bio TEXT NOT NULL DEFAULT '',
+		age INTEGER NOT NULL,
+		age_range TEXT NOT NULL DEFAULT '',
+		distance INTEGER NOT NULL DEFAULT 50,
+		seriousness INTEGER NOT NULL DEFAULT 5,
+		height INTEGER NOT NULL DEFAULT 0,
+		weight INTEGER NOT NULL DEFAULT 0,
+		smokes BOOLEAN NOT NULL DEFAULT FALSE,
+		drinks BOOLEAN NOT NULL DEFAULT FALSE,
+		job TEXT NOT NULL DEFAULT '',
+		job_category TEXT NOT NULL DEFAULT '',
+		education TEXT NOT NULL DEFAULT '',
+		hobbies TEXT NOT NULL DEFAULT '[]',
+		hobby_categories TEXT NOT NULL DEFAULT '[]',
+		verified_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`
+
+// Test - Rotaları, istek gövdelerini ve swipe/blind date uçlarının yanıtlarını belgeyle karşılaştır
+// Bellek içi SQLite kullanır; OPENROUTER_API_KEY yok sayılır, AI gerektiren uçlar 502 döner.
+func Test() error {
+	env, err := newEnv()
+	if err != nil {
+		return err
+	}
+	defer env.close()
+
+	return errors.Join(
+		contracttest.CheckRoutes(serviceName, env.router),
+		checkRequests(),
+		env.run(),
+	)
+}
+
+// checkRequests - Handler'ların çözdüğü yapılar belgedeki istek şemalarıyla aynı mı
+func checkRequests() error {
+	return errors.Join(
+		contracttest.CheckRequest("POST", "/api/swipe", handler.SwipeRequest{}),
+		contracttest.CheckRequest("POST", "/api/blind/request", handler.BlindMatchRequest{}),
+		contracttest.CheckRequest("POST", "/api/blind/message", handler.BlindChatRequest{}),
+	)
+}
+
+// env - Gerçek servis ve repository'lerle kurulmuş router
+type env struct {
+	db      *sqldb.DB
+	workers *server.Workers
+	router  *mux.Router
+}
+
+func newEnv() (*env, error) {
+	db, err := sqldb.Open(sqldb.SQLite, ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	// Bellek içi SQLite her bağlantıda ayrı bir veritabanıdır
+	db.SetMaxOpenConns(1)
+
+	if err := seed(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Testler ağa çıkmaz: anahtarsız istemci AI çağrılarını sağlayıcı hatasıyla reddeder
+	key, hadKey := os.LookupEnv("OPENROUTER_API_KEY")
+	os.Unsetenv("OPENROUTER_API_KEY")
+	aiService := service.NewAIService()
+	if hadKey {
+		os.Setenv("OPENROUTER_API_KEY", key)
+	}
+
+	workers := server.NewWorkers()
+	matchService := service.NewMatchService(repository.NewMatchRepository(db), repository.NewUserRepository(db), aiService, service.DefaultIceBreakerPolicy(), utils.DefaultContactPolicy(), moderation.Default(), workers)
+
+	router := mux.NewRouter()
+	handler.Handlers{
+		Swipe: handler.NewSwipeHandler(matchService),
+		Blind: handler.NewBlindHandler(matchService),
+	}.Register(router)
+
+	return &env{db: db, workers: workers, router: router}, nil
+}
+
+// seed - Göçleri uygula ve üç kullanıcı ekle (1 ve 2 swipe ile eşleşir, 3 blind date ister)
+func seed(db *sqldb.DB) error {
+	if _, err := db.Exec(usersTable); err != nil {
+		return err
+	}
+	migrator, err := repository.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		return err
+	}
+
+	users := []struct {
+		name, email string
+		age         int
+		verified    bool
+	}{
+		{"Ayşe", "ayse@example.com", 28, true},
+		{"Mehmet", "mehmet@example.com", 30, false},
+		{"Zeynep", "zeynep@example.com", 27, true},
+	}
+	for _, u := range users {
+		var verifiedAt interface{}
+		if u.verified {
+			verifiedAt = time.Now()
+		}
+		_, err := db.Exec(`INSERT INTO users (name, email, age, hobbies, hobby_categories, verified_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			u.name, u.email, u.age, `["Yürüyüş","Kitap okuma"]`, `["Spor","Sosyal"]`, verifiedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *env) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	e.workers.Stop(ctx)
+	e.db.Close()
+}
+
+// run - Swipe ve karşılıklı eşleşme, ardından blind date akışı
+// Eşleşme kimlikleri sırayla verilir: swipe eşleşmesi 1, blind date 2.
+func (e *env) run() error {
+	return contracttest.Run(serviceName, e.router, []contracttest.Case{
+		{Name: "swipe", Method: "POST", Path: "/api/swipe", Body: `{"user_id":1,"target_id":2,"direction":"right"}`, Status: http.StatusOK},
+		{Name: "swipe mutual", Method: "POST", Path: "/api/swipe", Body: `{"user_id":2,"target_id":1,"direction":"right"}`, Status: http.StatusOK},
+		{Name: "swipe invalid direction", Method: "POST", Path: "/api/swipe", Body: `{"user_id":1,"target_id":3,"direction":"up"}`, Status: http.StatusBadRequest},
+		{Name: "swipe malformed", Method: "POST", Path: "/api/swipe", Body: `{`, Status: http.StatusBadRequest},
+		{Name: "potential matches", Method: "GET", Path: "/api/matches/potential?user_id=1&limit=5", Status: http.StatusOK},
+		{Name: "potential matches verified only", Method: "GET", Path: "/api/matches/potential?user_id=1&verified_only=true", Status: http.StatusOK},
+		{Name: "potential matches unknown user", Method: "GET", Path: "/api/matches/potential?user_id=999", Status: http.StatusNotFound},
+		{Name: "potential matches invalid user", Method: "GET", Path: "/api/matches/potential?user_id=abc", Status: http.StatusBadRequest},
+		{Name: "match history", Method: "GET", Path: "/api/matches/history?user_id=1", Status: http.StatusOK},
+		{Name: "match history invalid user", Method: "GET", Path: "/api/matches/history", Status: http.StatusBadRequest},
+		{Name: "blind status without match", Method: "GET", Path: "/api/blind/status?user_id=3", Status: http.StatusOK},
+		{Name: "blind request", Method: "POST", Path: "/api/blind/request", Body: `{"user_id":3}`, Status: http.StatusOK},
+		{Name: "blind request while active", Method: "POST", Path: "/api/blind/request", Body: `{"user_id":3}`, Status: http.StatusBadRequest},
+		{Name: "blind request unknown user", Method: "POST", Path: "/api/blind/request", Body: `{"user_id":999}`, Status: http.StatusNotFound},
+		{Name: "blind status", Method: "GET", Path: "/api/blind/status?user_id=3", Status: http.StatusOK},
+		{Name: "blind status invalid user", Method: "GET", Path: "/api/blind/status?user_id=abc", Status: http.StatusBadRequest},
+		{Name: "blind message", Method: "POST", Path: "/api/blind/message", Body: `{"match_id":2,"user_id":3,"message":"Merhaba, nasılsın?"}`, Status: http.StatusOK},
+		{Name: "blind message inappropriate", Method: "POST", Path: "/api/blind/message", Body: `{"match_id":2,"user_id":3,"message":"seni öldüreceğim"}`, Status: http.StatusUnprocessableEntity},
+		{Name: "blind message contact info", Method: "POST", Path: "/api/blind/message", Body: `{"match_id":2,"user_id":3,"message":"Numaram 0555 123 45 67"}`, Status: http.StatusUnprocessableEntity},
+		{Name: "blind message malformed", Method: "POST", Path: "/api/blind/message", Body: `{`, Status: http.StatusBadRequest},
+		{Name: "blind messages", Method: "GET", Path: "/api/blind/messages?match_id=2", Status: http.StatusOK},
+		{Name: "blind messages invalid match", Method: "GET", Path: "/api/blind/messages?match_id=abc", Status: http.StatusBadRequest},
+		{Name: "blind complete without AI", Method: "POST", Path: "/api/blind/complete?match_id=2", Status: http.StatusBadGateway},
+		{Name: "blind complete unknown match", Method: "POST", Path: "/api/blind/complete?match_id=999", Status: http.StatusNotFound},
+		{Name: "blind complete invalid match", Method: "POST", Path: "/api/blind/complete", Status: http.StatusBadRequest},
+	})
+}
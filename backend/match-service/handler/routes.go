@@ -0,0 +1,25 @@
+// routes.go - API rotaları (main ve sözleşme testleri aynı tabloyu kullanır)
+package handler
+
+import "github.com/gorilla/mux"
+
+// Handlers - Rotalara bağlanan handler'lar
+type Handlers struct {
+	Swipe *SwipeHandler
+	Blind *BlindHandler
+}
+
+// Register - API rotalarını router'a ekle
+func (h Handlers) Register(router *mux.Router) {
+	// Swipe routes (Klasik Tinder tarzı)
+	router.HandleFunc("/api/swipe", h.Swipe.Swipe).Methods("POST")
+	router.HandleFunc("/api/matches/potential", h.Swipe.GetPotentialMatches).Methods("GET")
+	router.HandleFunc("/api/matches/history", h.Swipe.GetMatchHistory).Methods("GET")
+
+	// Blind date routes
+	router.HandleFunc("/api/blind/request", h.Blind.RequestBlindMatch).Methods("POST")
+	router.HandleFunc("/api/blind/message", h.Blind.SendBlindMessage).Methods("POST")
+	router.HandleFunc("/api/blind/messages", h.Blind.GetBlindMessages).Methods("GET")
+	router.HandleFunc("/api/blind/complete", h.Blind.CompleteBlindDate).Methods("POST")
+	router.HandleFunc("/api/blind/status", h.Blind.GetBlindMatchStatus).Methods("GET")
+}
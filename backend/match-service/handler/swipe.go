@@ -7,6 +7,7 @@ import (
     "strconv"
     "eros/match-service/service"
     "eros/match-service/model"
+    "eros/shared/apierror"
 )
 
 type SwipeHandler struct {
@@ -34,26 +35,26 @@ type SwipeResponse struct {
 // Swipe - Kullanıcı swipe yapar
 func (h *SwipeHandler) Swipe(w http.ResponseWriter, r *http.Request) {
     if r.Method != http.MethodPost {
-        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        apierror.Write(w, r, apierror.MethodNotAllowed())
         return
     }
 
     var req SwipeRequest
     if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
         return
     }
 
     // Swipe yönü kontrolü
     if req.Direction != "right" && req.Direction != "left" {
-        http.Error(w, "Invalid direction", http.StatusBadRequest)
+        apierror.Write(w, r, apierror.BadRequest("Invalid direction"))
         return
     }
 
     // Swipe işlemini gerçekleştir
-    isMatch, err := h.matchService.ProcessSwipe(req.UserID, req.TargetID, req.Direction)
+    isMatch, err := h.matchService.ProcessSwipe(r.Context(), req.UserID, req.TargetID, req.Direction)
     if err != nil {
-        http.Error(w, "Swipe processing failed", http.StatusInternalServerError)
+        apierror.Write(w, r, apierror.Fallback(err, "Swipe processing failed"))
         return
     }
 
@@ -64,12 +65,13 @@ func (h *SwipeHandler) Swipe(w http.ResponseWriter, r *http.Request) {
 
     // Eğer eşleşme varsa, date görevi öner
     if isMatch {
-        dateTask, err := h.matchService.GenerateDateTask(req.UserID, req.TargetID)
+        dateTask, err := h.matchService.GenerateDateTask(r.Context(), req.UserID, req.TargetID)
         if err == nil {
             response.DateTask = dateTask
         }
     }
 
+    w.Header().Set("Content-Type", "application/json")
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(response)
 }
@@ -77,14 +79,14 @@ func (h *SwipeHandler) Swipe(w http.ResponseWriter, r *http.Request) {
 // GetPotentialMatches - Potansiyel eşleşmeleri getir
 func (h *SwipeHandler) GetPotentialMatches(w http.ResponseWriter, r *http.Request) {
     if r.Method != http.MethodGet {
-        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        apierror.Write(w, r, apierror.MethodNotAllowed())
         return
     }
 
     userIDStr := r.URL.Query().Get("user_id")
     userID, err := strconv.Atoi(userIDStr)
     if err != nil {
-        http.Error(w, "Invalid user ID", http.StatusBadRequest)
+        apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
         return
     }
 
@@ -96,12 +98,16 @@ func (h *SwipeHandler) GetPotentialMatches(w http.ResponseWriter, r *http.Reques
         }
     }
 
-    matches, err := h.matchService.GetPotentialMatches(userID, limit)
+    // ?verified_only=true - Sadece selfie doğrulamasından geçmiş profiller
+    verifiedOnly, _ := strconv.ParseBool(r.URL.Query().Get("verified_only"))
+
+    matches, err := h.matchService.GetPotentialMatches(r.Context(), userID, limit, verifiedOnly)
     if err != nil {
-        http.Error(w, "Failed to get potential matches", http.StatusInternalServerError)
+        apierror.Write(w, r, apierror.Fallback(err, "Failed to get potential matches"))
         return
     }
 
+    w.Header().Set("Content-Type", "application/json")
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(matches)
 }
@@ -109,23 +115,24 @@ func (h *SwipeHandler) GetPotentialMatches(w http.ResponseWriter, r *http.Reques
 // GetMatchHistory - Eşleşme geçmişini getir
 func (h *SwipeHandler) GetMatchHistory(w http.ResponseWriter, r *http.Request) {
     if r.Method != http.MethodGet {
-        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        apierror.Write(w, r, apierror.MethodNotAllowed())
         return
     }
 
     userIDStr := r.URL.Query().Get("user_id")
     userID, err := strconv.Atoi(userIDStr)
     if err != nil {
-        http.Error(w, "Invalid user ID", http.StatusBadRequest)
+        apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
         return
     }
 
-    history, err := h.matchService.GetMatchHistory(userID)
+    history, err := h.matchService.GetMatchHistory(r.Context(), userID)
     if err != nil {
-        http.Error(w, "Failed to get match history", http.StatusInternalServerError)
+        apierror.Write(w, r, apierror.Fallback(err, "Failed to get match history"))
         return
     }
 
+    w.Header().Set("Content-Type", "application/json")
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(history)
 }
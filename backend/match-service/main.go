@@ -2,37 +2,66 @@
 package main
 
 import (
-    "log"
+    "context"
+    "log/slog"
     "net/http"
     "os"
+    "time"
     "eros/match-service/handler"
     "eros/match-service/repository"
     "eros/match-service/service"
+    "eros/shared/apierror"
+    "eros/shared/health"
+    "eros/shared/logging"
+    "eros/shared/metrics"
+    "eros/shared/migrate"
+    "eros/shared/moderation"
+    "eros/shared/server"
+    "eros/shared/sqldb"
+    "eros/shared/tracing"
+    "eros/shared/utils"
     "github.com/gorilla/mux"
     "github.com/joho/godotenv"
 )
 
 func main() {
     // .env dosyasını yükle
-    if err := godotenv.Load(); err != nil {
-        log.Println("No .env file found, using default values")
+    envErr := godotenv.Load()
+
+    // JSON günlükler (LOG_LEVEL .env'den de okunabilir)
+    logging.Setup("match-service")
+    if envErr != nil {
+        slog.Info("no .env file found, using default values")
     }
 
-    // SQLite veritabanını başlat
-    dbPath := os.Getenv("DB_PATH")
-    if dbPath == "" {
-        dbPath = "./eros_match.db"
+    // Dağıtık izleme (OTEL_TRACES_EXPORTER=otlp|console; varsayılan kapalı)
+    if err := tracing.Setup("match-service"); err != nil {
+        logging.Fatal("failed to initialize tracing", err)
     }
 
-    db, err := repository.NewSQLiteDB(dbPath)
+    // Veritabanını başlat (DB_DRIVER=sqlite için DB_PATH, DB_DRIVER=postgres için DATABASE_URL)
+    db, err := sqldb.FromEnv("./eros_match.db")
     if err != nil {
-        log.Fatal("Failed to connect to database:", err)
+        logging.Fatal("failed to connect to database", err)
     }
     defer db.Close()
 
-    // Veritabanı tablolarını oluştur
-    if err := repository.InitMatchDatabase(db); err != nil {
-        log.Fatal("Failed to initialize database:", err)
+    migrator, err := repository.NewMigrator(db)
+    if err != nil {
+        logging.Fatal("failed to load migrations", err)
+    }
+
+    // "go run . migrate up|down|status" - göçleri elle yönet ve çık
+    if len(os.Args) > 1 && os.Args[1] == "migrate" {
+        if err := migrate.Command(context.Background(), migrator, os.Args[2:], os.Stdout); err != nil {
+            logging.Fatal("migrate command failed", err)
+        }
+        return
+    }
+
+    // Bekleyen şema göçlerini uygula (AUTO_MIGRATE=false ise sadece kontrol et)
+    if err := migrate.Startup(context.Background(), migrator, migrate.AutoMigrateFromEnv()); err != nil {
+        logging.Fatal("failed to migrate database", err)
     }
 
     // Repository'leri oluştur
@@ -42,8 +71,14 @@ func main() {
     // AI Service'i oluştur
     aiService := service.NewAIService()
 
+    // Arka plan işleri (buz kırıcılar, AI analizi); kapanışta bitmeleri beklenir
+    workers := server.NewWorkers()
+
     // Service'leri oluştur
-    matchService := service.NewMatchService(matchRepo, userRepo, aiService)
+    matchService := service.NewMatchService(matchRepo, userRepo, aiService, service.IceBreakerPolicyFromEnv(), utils.ContactPolicyFromEnv(), moderation.Default(), workers)
+
+    // Süresi dolan blind date'leri kapatan, sessiz kalan sohbetlere AI buz kırıcı mesajı ekleyen worker
+    workers.Go(func(ctx context.Context) { matchService.RunBlindMatchWorker(ctx, 15*time.Minute) })
 
     // Handler'ları oluştur
     swipeHandler := handler.NewSwipeHandler(matchService)
@@ -51,18 +86,25 @@ func main() {
 
     // Router'ı oluştur
     router := mux.NewRouter()
+    // Eşleşmeyen rota ve yöntemler de ortak JSON hata zarfıyla döner
+    router.NotFoundHandler = apierror.NotFoundHandler()
+    router.MethodNotAllowedHandler = apierror.MethodNotAllowedHandler()
+
+    // API routes
+    handler.Handlers{Swipe: swipeHandler, Blind: blindHandler}.Register(router)
 
-    // Swipe routes (Klasik Tinder tarzı)
-    router.HandleFunc("/api/swipe", swipeHandler.Swipe).Methods("POST")
-    router.HandleFunc("/api/matches/potential", swipeHandler.GetPotentialMatches).Methods("GET")
-    router.HandleFunc("/api/matches/history", swipeHandler.GetMatchHistory).Methods("GET")
+    // Canlılık ve hazırlık (veritabanı, şema göçleri; AI kritik değil, çalışmazsa "degraded")
+    checker := health.New("match-service", 2*time.Second)
+    checker.Add("database", true, health.Ping(db))
+    checker.Add("migrations", true, health.Migrations(migrator))
+    checker.Add("ai_provider", false, aiService.Health)
+    router.HandleFunc("/healthz", checker.Live).Methods("GET")
+    router.HandleFunc("/readyz", checker.Ready).Methods("GET")
 
-    // Blind date routes
-    router.HandleFunc("/api/blind/request", blindHandler.RequestBlindMatch).Methods("POST")
-    router.HandleFunc("/api/blind/message", blindHandler.SendBlindMessage).Methods("POST")
-    router.HandleFunc("/api/blind/messages", blindHandler.GetBlindMessages).Methods("GET")
-    router.HandleFunc("/api/blind/complete", blindHandler.CompleteBlindDate).Methods("POST")
-    router.HandleFunc("/api/blind/status", blindHandler.GetBlindMatchStatus).Methods("GET")
+    // Prometheus metrikleri (istek süreleri rota şablonu, yöntem ve durum koduna göre)
+    router.Handle("/metrics", metrics.Handler()).Methods("GET")
+    router.Use(metrics.Middleware)
+    router.Use(tracing.Middleware)
 
     // CORS middleware
     router.Use(func(next http.Handler) http.Handler {
@@ -86,6 +128,14 @@ func main() {
         port = "8082"
     }
 
-    log.Printf("Match Service starting on port %s", port)
-    log.Fatal(http.ListenAndServe(":"+port, router))
+    srv := server.New("Match Service", logging.Middleware(router), server.ConfigFromEnv(":"+port), workers)
+    runErr := srv.Run()
+
+    // İstekler boşaltıldıktan sonra kuyruktaki span'leri gönder
+    if err := tracing.Shutdown(context.Background()); err != nil {
+        slog.Warn("failed to flush spans", "error", err)
+    }
+    if runErr != nil {
+        logging.Fatal("server failed", runErr)
+    }
 }
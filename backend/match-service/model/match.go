@@ -27,9 +27,10 @@ type Swipe struct {
 type BlindMessage struct {
     ID        int       `json:"id" db:"id"`
     MatchID   int       `json:"match_id" db:"match_id"`
-    UserID    int       `json:"user_id" db:"user_id"`
+    UserID    int       `json:"user_id" db:"user_id"` // AI mesajlarında 0 (veritabanında NULL)
     Message   string    `json:"message" db:"message"`
     IsAI      bool      `json:"is_ai" db:"is_ai"`
+    AISlot    string    `json:"-" db:"ai_slot"`       // AI mesajının yeri; aynı yere ikinci mesaj eklenmez
     CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -21,6 +21,7 @@ type User struct {
     Education       string    `json:"education" db:"education"`
     Hobbies         []string  `json:"hobbies" db:"hobbies"`
     HobbyCategories []string  `json:"hobby_categories" db:"hobby_categories"`
+    IsVerified      bool      `json:"is_verified" db:"is_verified"` // Selfie doğrulama rozeti (user-service verified_at)
     CreatedAt       time.Time `json:"created_at" db:"created_at"`
     UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 } 
\ No newline at end of file
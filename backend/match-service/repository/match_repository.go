@@ -2,49 +2,53 @@
 package repository
 
 import (
+    "context"
     "database/sql"
     "eros/match-service/model"
+    "eros/shared/sqldb"
+    "eros/shared/tracing"
 )
 
 type MatchRepository struct {
-    db *sql.DB
+    db *sqldb.DB
 }
 
-func NewMatchRepository(db *sql.DB) *MatchRepository {
+func NewMatchRepository(db *sqldb.DB) *MatchRepository {
     return &MatchRepository{db: db}
 }
 
 // CreateMatch - Yeni eşleşme oluştur
-func (r *MatchRepository) CreateMatch(match *model.Match) error {
+func (r *MatchRepository) CreateMatch(ctx context.Context, match *model.Match) error {
+    ctx, span := tracing.Start(ctx, "MatchRepository.CreateMatch")
+    defer span.End()
+
     query := `
         INSERT INTO matches (user1_id, user2_id, match_type, status, created_at, expires_at)
         VALUES (?, ?, ?, ?, ?, ?)
     `
     
-    result, err := r.db.Exec(query, match.User1ID, match.User2ID, match.MatchType, 
+    id, err := r.db.InsertIDContext(ctx, query, match.User1ID, match.User2ID, match.MatchType, 
                            match.Status, match.CreatedAt, match.ExpiresAt)
     if err != nil {
         return err
     }
     
-    id, err := result.LastInsertId()
-    if err != nil {
-        return err
-    }
-    
     match.ID = int(id)
     return nil
 }
 
 // GetMatchByID - ID ile eşleşme getir
-func (r *MatchRepository) GetMatchByID(matchID int) (*model.Match, error) {
+func (r *MatchRepository) GetMatchByID(ctx context.Context, matchID int) (*model.Match, error) {
+    ctx, span := tracing.Start(ctx, "MatchRepository.GetMatchByID")
+    defer span.End()
+
     query := `
         SELECT id, user1_id, user2_id, match_type, status, created_at, expires_at
         FROM matches WHERE id = ?
     `
     
     match := &model.Match{}
-    err := r.db.QueryRow(query, matchID).Scan(
+    err := r.db.QueryRowContext(ctx, query, matchID).Scan(
         &match.ID, &match.User1ID, &match.User2ID, &match.MatchType,
         &match.Status, &match.CreatedAt, &match.ExpiresAt,
     )
@@ -57,7 +61,10 @@ func (r *MatchRepository) GetMatchByID(matchID int) (*model.Match, error) {
 }
 
 // GetActiveMatch - Aktif eşleşme getir
-func (r *MatchRepository) GetActiveMatch(userID int) (*model.Match, error) {
+func (r *MatchRepository) GetActiveMatch(ctx context.Context, userID int) (*model.Match, error) {
+    ctx, span := tracing.Start(ctx, "MatchRepository.GetActiveMatch")
+    defer span.End()
+
     query := `
         SELECT id, user1_id, user2_id, match_type, status, created_at, expires_at
         FROM matches 
@@ -66,11 +73,15 @@ func (r *MatchRepository) GetActiveMatch(userID int) (*model.Match, error) {
     `
     
     match := &model.Match{}
-    err := r.db.QueryRow(query, userID, userID).Scan(
+    err := r.db.QueryRowContext(ctx, query, userID, userID).Scan(
         &match.ID, &match.User1ID, &match.User2ID, &match.MatchType,
         &match.Status, &match.CreatedAt, &match.ExpiresAt,
     )
     
+    // Aktif eşleşme yoksa hata değil, nil döner
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
     if err != nil {
         return nil, err
     }
@@ -78,45 +89,80 @@ func (r *MatchRepository) GetActiveMatch(userID int) (*model.Match, error) {
     return match, nil
 }
 
+// GetActiveBlindMatches - Tüm aktif blind date'leri getir
+func (r *MatchRepository) GetActiveBlindMatches(ctx context.Context) ([]model.Match, error) {
+    ctx, span := tracing.Start(ctx, "MatchRepository.GetActiveBlindMatches")
+    defer span.End()
+
+    query := `
+        SELECT id, user1_id, user2_id, match_type, status, created_at, expires_at
+        FROM matches 
+        WHERE match_type = 'blind' AND status = 'active'
+    `
+    
+    rows, err := r.db.QueryContext(ctx, query)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+    
+    var matches []model.Match
+    for rows.Next() {
+        var match model.Match
+        err := rows.Scan(&match.ID, &match.User1ID, &match.User2ID, 
+                        &match.MatchType, &match.Status, &match.CreatedAt, &match.ExpiresAt)
+        if err != nil {
+            return nil, err
+        }
+        matches = append(matches, match)
+    }
+    
+    return matches, nil
+}
+
 // UpdateMatchStatus - Eşleşme durumunu güncelle
-func (r *MatchRepository) UpdateMatchStatus(matchID int, status string) error {
+func (r *MatchRepository) UpdateMatchStatus(ctx context.Context, matchID int, status string) error {
+    ctx, span := tracing.Start(ctx, "MatchRepository.UpdateMatchStatus")
+    defer span.End()
+
     query := `UPDATE matches SET status = ? WHERE id = ?`
-    _, err := r.db.Exec(query, status, matchID)
+    _, err := r.db.ExecContext(ctx, query, status, matchID)
     return err
 }
 
 // CreateSwipe - Swipe kaydet
-func (r *MatchRepository) CreateSwipe(swipe *model.Swipe) error {
+func (r *MatchRepository) CreateSwipe(ctx context.Context, swipe *model.Swipe) error {
+    ctx, span := tracing.Start(ctx, "MatchRepository.CreateSwipe")
+    defer span.End()
+
     query := `
         INSERT INTO swipes (user_id, target_id, direction, created_at)
         VALUES (?, ?, ?, ?)
     `
     
-    result, err := r.db.Exec(query, swipe.UserID, swipe.TargetID, 
+    id, err := r.db.InsertIDContext(ctx, query, swipe.UserID, swipe.TargetID, 
                            swipe.Direction, swipe.CreatedAt)
     if err != nil {
         return err
     }
     
-    id, err := result.LastInsertId()
-    if err != nil {
-        return err
-    }
-    
     swipe.ID = int(id)
     return nil
 }
 
 // CheckMutualSwipe - Karşılıklı swipe kontrolü
-func (r *MatchRepository) CheckMutualSwipe(user1ID, user2ID int) (bool, error) {
+func (r *MatchRepository) CheckMutualSwipe(ctx context.Context, user1ID, user2ID int) (bool, error) {
+    ctx, span := tracing.Start(ctx, "MatchRepository.CheckMutualSwipe")
+    defer span.End()
+
     query := `
-        SELECT COUNT(*) FROM swipes 
+        SELECT COUNT(DISTINCT user_id) FROM swipes 
         WHERE (user_id = ? AND target_id = ? AND direction = 'right')
-        AND (user_id = ? AND target_id = ? AND direction = 'right')
+        OR (user_id = ? AND target_id = ? AND direction = 'right')
     `
     
     var count int
-    err := r.db.QueryRow(query, user1ID, user2ID, user2ID, user1ID).Scan(&count)
+    err := r.db.QueryRowContext(ctx, query, user1ID, user2ID, user2ID, user1ID).Scan(&count)
     if err != nil {
         return false, err
     }
@@ -125,37 +171,63 @@ func (r *MatchRepository) CheckMutualSwipe(user1ID, user2ID int) (bool, error) {
 }
 
 // CreateBlindMessage - Blind mesaj oluştur
-func (r *MatchRepository) CreateBlindMessage(message *model.BlindMessage) error {
+func (r *MatchRepository) CreateBlindMessage(ctx context.Context, message *model.BlindMessage) error {
+    ctx, span := tracing.Start(ctx, "MatchRepository.CreateBlindMessage")
+    defer span.End()
+
     query := `
         INSERT INTO blind_messages (match_id, user_id, message, is_ai, created_at)
         VALUES (?, ?, ?, ?, ?)
     `
     
-    result, err := r.db.Exec(query, message.MatchID, message.UserID, 
+    id, err := r.db.InsertIDContext(ctx, query, message.MatchID, message.UserID, 
                            message.Message, message.IsAI, message.CreatedAt)
     if err != nil {
         return err
     }
     
-    id, err := result.LastInsertId()
+    message.ID = int(id)
+    return nil
+}
+
+// CreateAIBlindMessage - AI mesajını ekle; aynı eşleşmede aynı yere (AISlot) daha önce
+// mesaj eklendiyse eklemez ve false döner (başlangıç görevi ile periyodik tarama yarışabilir)
+func (r *MatchRepository) CreateAIBlindMessage(ctx context.Context, message *model.BlindMessage) (bool, error) {
+    ctx, span := tracing.Start(ctx, "MatchRepository.CreateAIBlindMessage")
+    defer span.End()
+
+    query := `
+        INSERT INTO blind_messages (match_id, user_id, message, is_ai, ai_slot, created_at)
+        VALUES (?, NULL, ?, TRUE, ?, ?)
+        ON CONFLICT (match_id, ai_slot) DO NOTHING
+    `
+    
+    id, err := r.db.InsertIDContext(ctx, query, message.MatchID, message.Message,
+                           message.AISlot, message.CreatedAt)
+    if err == sql.ErrNoRows {
+        return false, nil
+    }
     if err != nil {
-        return err
+        return false, err
     }
     
     message.ID = int(id)
-    return nil
+    return true, nil
 }
 
 // GetBlindMessages - Blind mesajları getir
-func (r *MatchRepository) GetBlindMessages(matchID int) ([]model.BlindMessage, error) {
+func (r *MatchRepository) GetBlindMessages(ctx context.Context, matchID int) ([]model.BlindMessage, error) {
+    ctx, span := tracing.Start(ctx, "MatchRepository.GetBlindMessages")
+    defer span.End()
+
     query := `
-        SELECT id, match_id, user_id, message, is_ai, created_at
+        SELECT id, match_id, user_id, message, is_ai, ai_slot, created_at
         FROM blind_messages 
         WHERE match_id = ?
         ORDER BY created_at ASC
     `
     
-    rows, err := r.db.Query(query, matchID)
+    rows, err := r.db.QueryContext(ctx, query, matchID)
     if err != nil {
         return nil, err
     }
@@ -164,11 +236,16 @@ func (r *MatchRepository) GetBlindMessages(matchID int) ([]model.BlindMessage, e
     var messages []model.BlindMessage
     for rows.Next() {
         var msg model.BlindMessage
-        err := rows.Scan(&msg.ID, &msg.MatchID, &msg.UserID, 
-                        &msg.Message, &msg.IsAI, &msg.CreatedAt)
+        var userID sql.NullInt64
+        var aiSlot sql.NullString
+        err := rows.Scan(&msg.ID, &msg.MatchID, &userID, 
+                        &msg.Message, &msg.IsAI, &aiSlot, &msg.CreatedAt)
         if err != nil {
             return nil, err
         }
+        // AI mesajlarının göndereni NULL; API'de 0 olarak görünür
+        msg.UserID = int(userID.Int64)
+        msg.AISlot = aiSlot.String
         messages = append(messages, msg)
     }
     
@@ -176,30 +253,31 @@ func (r *MatchRepository) GetBlindMessages(matchID int) ([]model.BlindMessage, e
 }
 
 // CreateDateTask - Date görevi oluştur
-func (r *MatchRepository) CreateDateTask(task *model.DateTask) error {
+func (r *MatchRepository) CreateDateTask(ctx context.Context, task *model.DateTask) error {
+    ctx, span := tracing.Start(ctx, "MatchRepository.CreateDateTask")
+    defer span.End()
+
     query := `
         INSERT INTO date_tasks (match_id, title, description, location, duration, difficulty, status, created_at)
         VALUES (?, ?, ?, ?, ?, ?, ?, ?)
     `
     
-    result, err := r.db.Exec(query, task.MatchID, task.Title, task.Description,
+    id, err := r.db.InsertIDContext(ctx, query, task.MatchID, task.Title, task.Description,
                            task.Location, task.Duration, task.Difficulty, 
                            task.Status, task.CreatedAt)
     if err != nil {
         return err
     }
     
-    id, err := result.LastInsertId()
-    if err != nil {
-        return err
-    }
-    
     task.ID = int(id)
     return nil
 }
 
 // GetMatchHistory - Eşleşme geçmişi
-func (r *MatchRepository) GetMatchHistory(userID int) ([]model.Match, error) {
+func (r *MatchRepository) GetMatchHistory(ctx context.Context, userID int) ([]model.Match, error) {
+    ctx, span := tracing.Start(ctx, "MatchRepository.GetMatchHistory")
+    defer span.End()
+
     query := `
         SELECT id, user1_id, user2_id, match_type, status, created_at, expires_at
         FROM matches 
@@ -207,7 +285,7 @@ func (r *MatchRepository) GetMatchHistory(userID int) ([]model.Match, error) {
         ORDER BY created_at DESC
     `
     
-    rows, err := r.db.Query(query, userID, userID)
+    rows, err := r.db.QueryContext(ctx, query, userID, userID)
     if err != nil {
         return nil, err
     }
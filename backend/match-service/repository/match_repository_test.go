@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"eros/match-service/model"
+	"eros/shared/sqldb"
+	"eros/shared/sqldb/sqldbtest"
+	"testing"
+	"time"
+)
+
+// forEachDB - Testi bellek içi SQLite'ta, TEST_DATABASE_URL tanımlıysa PostgreSQL'de de çalıştır
+func forEachDB(t *testing.T, test func(t *testing.T, db *sqldb.DB)) {
+	t.Run(sqldb.SQLite.Name, func(t *testing.T) {
+		db, err := sqldb.Open(sqldb.SQLite, "file::memory:?_foreign_keys=on")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Bellek içi SQLite her bağlantıda ayrı bir veritabanıdır
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+		test(t, migrateTestDB(t, db, `CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL)`))
+	})
+
+	if sqldbtest.URL() == "" {
+		return
+	}
+	t.Run(sqldb.Postgres.Name, func(t *testing.T) {
+		db, err := sqldbtest.OpenPostgres("match_repository_test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		test(t, migrateTestDB(t, db, `CREATE TABLE users (id SERIAL PRIMARY KEY, name TEXT NOT NULL)`))
+	})
+}
+
+// migrateTestDB - users tablosunu oluştur ve göçleri uygula
+// users tablosu user-service'e aittir; testler için en küçük hali oluşturulur.
+func migrateTestDB(t *testing.T, db *sqldb.DB, usersTable string) *sqldb.DB {
+	t.Helper()
+	if _, err := db.Exec(usersTable); err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestCreateAIBlindMessage(t *testing.T) {
+	forEachDB(t, testCreateAIBlindMessage)
+}
+
+func testCreateAIBlindMessage(t *testing.T, db *sqldb.DB) {
+	ctx := context.Background()
+	repo := NewMatchRepository(db)
+
+	for _, name := range []string{"Ayşe", "Mehmet"} {
+		if _, err := db.Exec(`INSERT INTO users (name) VALUES (?)`, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	match := &model.Match{User1ID: 1, User2ID: 2, MatchType: "blind", Status: "active", CreatedAt: time.Now()}
+	if err := repo.CreateMatch(ctx, match); err != nil {
+		t.Fatal(err)
+	}
+
+	// Aynı yere iki görev aynı anda mesaj eklemeye çalışır; yalnızca biri eklenir
+	for i, want := range []bool{true, false} {
+		msg := &model.BlindMessage{MatchID: match.ID, Message: "Merhaba!", IsAI: true, AISlot: "start", CreatedAt: time.Now()}
+		created, err := repo.CreateAIBlindMessage(ctx, msg)
+		if err != nil {
+			t.Fatalf("attempt %d: %v", i+1, err)
+		}
+		if created != want {
+			t.Fatalf("attempt %d: created = %v, want %v", i+1, created, want)
+		}
+	}
+
+	human := &model.BlindMessage{MatchID: match.ID, UserID: 1, Message: "Selam", CreatedAt: time.Now()}
+	if err := repo.CreateBlindMessage(ctx, human); err != nil {
+		t.Fatal(err)
+	}
+	// Farklı yer (kullanıcı mesajının ardı) yeni bir mesajdır
+	next := &model.BlindMessage{MatchID: match.ID, Message: "Nasıl gidiyor?", IsAI: true, AISlot: "after:2", CreatedAt: time.Now()}
+	if created, err := repo.CreateAIBlindMessage(ctx, next); err != nil || !created {
+		t.Fatalf("created = %v, err = %v; want a new message", created, err)
+	}
+
+	messages, err := repo.GetBlindMessages(ctx, match.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 3 {
+		t.Fatalf("got %d messages, want 3", len(messages))
+	}
+	if m := messages[0]; !m.IsAI || m.UserID != 0 || m.AISlot != "start" {
+		t.Errorf("AI message = %+v, want is_ai with user_id 0 and slot start", m)
+	}
+	if m := messages[1]; m.IsAI || m.UserID != 1 {
+		t.Errorf("user message = %+v, want user_id 1", m)
+	}
+}
+
+func TestBlindMessageSenderMustExist(t *testing.T) {
+	forEachDB(t, testBlindMessageSenderMustExist)
+}
+
+func testBlindMessageSenderMustExist(t *testing.T, db *sqldb.DB) {
+	ctx := context.Background()
+	repo := NewMatchRepository(db)
+
+	if _, err := db.Exec(`INSERT INTO users (name) VALUES ('Ayşe'), ('Mehmet')`); err != nil {
+		t.Fatal(err)
+	}
+	match := &model.Match{User1ID: 1, User2ID: 2, MatchType: "blind", Status: "active", CreatedAt: time.Now()}
+	if err := repo.CreateMatch(ctx, match); err != nil {
+		t.Fatal(err)
+	}
+
+	// Yabancı anahtar gerçekten denetleniyor: var olmayan gönderen reddedilir
+	bogus := &model.BlindMessage{MatchID: match.ID, UserID: 999, Message: "Selam", CreatedAt: time.Now()}
+	if err := repo.CreateBlindMessage(ctx, bogus); err == nil {
+		t.Fatal("message from unknown user was accepted; foreign keys are not enforced")
+	}
+}
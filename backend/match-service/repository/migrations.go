@@ -0,0 +1,157 @@
+// migrations.go - Match service şema göçleri
+package repository
+
+import (
+	"database/sql"
+	"eros/shared/migrate"
+	"eros/shared/sqldb"
+)
+
+// matchMigrations - Yeni göçler listenin sonuna, bir sonraki versiyon numarasıyla eklenir
+// Her göçün PostgreSQL karşılığı postgres.go'da aynı versiyon numarasıyla eklenir.
+var matchMigrations = []migrate.Migration{
+	{
+		Version: 1,
+		Name:    "baseline",
+		Up:      baselineSchema,
+		Down: migrate.SQL(
+			`DROP TABLE IF EXISTS date_tasks`,
+			`DROP TABLE IF EXISTS blind_messages`,
+			`DROP TABLE IF EXISTS swipes`,
+			`DROP TABLE IF EXISTS matches`,
+		),
+	},
+	{
+		Version: 2,
+		Name:    "blind_messages_ai_sender",
+		// AI mesajlarının göndereni NULL (users'a yabancı anahtar 0'ı kabul etmez);
+		// ai_slot aynı buz kırıcının iki kez eklenmesini engeller
+		Up: migrate.SQL(
+			`CREATE TABLE blind_messages_new (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            match_id INTEGER NOT NULL,
+            user_id INTEGER, -- AI mesajlarında NULL
+            message TEXT NOT NULL,
+            is_ai BOOLEAN DEFAULT FALSE,
+            ai_slot TEXT, -- AI mesajının yeri ("start" veya "after:<mesaj id>")
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (match_id) REFERENCES matches (id),
+            FOREIGN KEY (user_id) REFERENCES users (id)
+        )`,
+			`INSERT INTO blind_messages_new (id, match_id, user_id, message, is_ai, created_at)
+            SELECT id, match_id, CASE WHEN is_ai THEN NULL ELSE user_id END, message, is_ai, created_at
+            FROM blind_messages`,
+			`DROP TABLE blind_messages`,
+			`ALTER TABLE blind_messages_new RENAME TO blind_messages`,
+			`CREATE UNIQUE INDEX idx_blind_messages_ai_slot ON blind_messages (match_id, ai_slot)`,
+		),
+		// Eski şemada AI mesajlarının göndereni 0'dı; mesajlar korunur, ai_slot kaybolur
+		Down: migrate.SQL(
+			`CREATE TABLE blind_messages_old (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            match_id INTEGER NOT NULL,
+            user_id INTEGER NOT NULL,
+            message TEXT NOT NULL,
+            is_ai BOOLEAN DEFAULT FALSE,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (match_id) REFERENCES matches (id),
+            FOREIGN KEY (user_id) REFERENCES users (id)
+        )`,
+			`INSERT INTO blind_messages_old (id, match_id, user_id, message, is_ai, created_at)
+            SELECT id, match_id, COALESCE(user_id, 0), message, is_ai, created_at
+            FROM blind_messages`,
+			`DROP TABLE blind_messages`,
+			`ALTER TABLE blind_messages_old RENAME TO blind_messages`,
+		),
+	},
+}
+
+// NewMigrator - Match service veritabanının göç çalıştırıcısı (lehçeye göre SQLite veya PostgreSQL göçleri)
+func NewMigrator(db *sqldb.DB) (*migrate.Migrator, error) {
+	migrations := matchMigrations
+	if db.IsPostgres() {
+		migrations = postgresMigrations
+	}
+	// Servisler aynı veritabanını paylaşabilir; her biri kendi göç tablosunu kullanır
+	return migrate.New(db.DB, migrations, migrate.Options{
+		Dialect:     db.Dialect,
+		Table:       "match_schema_migrations",
+		LegacyTable: "schema_migrations",
+	})
+}
+
+// baselineSchema - Göç sisteminden önceki şema (InitMatchDatabase'in son hali)
+// Tablolar IF NOT EXISTS ile oluşturulduğu için eski veritabanlarında da çalışır.
+func baselineSchema(tx *sql.Tx) error {
+	// Matches tablosu
+	_, err := tx.Exec(`
+        CREATE TABLE IF NOT EXISTS matches (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user1_id INTEGER NOT NULL,
+            user2_id INTEGER NOT NULL,
+            match_type TEXT NOT NULL,
+            status TEXT DEFAULT 'active',
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            expires_at DATETIME,
+            FOREIGN KEY (user1_id) REFERENCES users (id),
+            FOREIGN KEY (user2_id) REFERENCES users (id)
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	// Swipes tablosu
+	_, err = tx.Exec(`
+        CREATE TABLE IF NOT EXISTS swipes (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user_id INTEGER NOT NULL,
+            target_id INTEGER NOT NULL,
+            direction TEXT NOT NULL,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (user_id) REFERENCES users (id),
+            FOREIGN KEY (target_id) REFERENCES users (id)
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	// Blind messages tablosu
+	_, err = tx.Exec(`
+        CREATE TABLE IF NOT EXISTS blind_messages (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            match_id INTEGER NOT NULL,
+            user_id INTEGER NOT NULL,
+            message TEXT NOT NULL,
+            is_ai BOOLEAN DEFAULT FALSE,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (match_id) REFERENCES matches (id),
+            FOREIGN KEY (user_id) REFERENCES users (id)
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	// Date tasks tablosu
+	_, err = tx.Exec(`
+        CREATE TABLE IF NOT EXISTS date_tasks (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            match_id INTEGER NOT NULL,
+            title TEXT NOT NULL,
+            description TEXT NOT NULL,
+            location TEXT NOT NULL,
+            duration TEXT NOT NULL,
+            difficulty TEXT NOT NULL,
+            status TEXT DEFAULT 'pending',
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (match_id) REFERENCES matches (id)
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"eros/shared/sqldb"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigratorIgnoresUserServiceMigrations(t *testing.T) {
+	db, err := sqldb.Open(sqldb.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Bellek içi SQLite her bağlantıda ayrı bir veritabanıdır
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+	ctx := context.Background()
+
+	// Eski sürümlerde user-service göçlerini ortak "schema_migrations" tablosuna yazıyordu
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES
+		(1, 'baseline', CURRENT_TIMESTAMP), (2, 'user_preferences_categories', CURRENT_TIMESTAMP),
+		(3, 'photos_unique_order', CURRENT_TIMESTAMP), (4, 'clear_stale_verified_at', CURRENT_TIMESTAMP)`); err != nil {
+		t.Fatal(err)
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != len(matchMigrations) {
+		t.Fatalf("status lists %d migrations, want %d", len(statuses), len(matchMigrations))
+	}
+	for _, s := range statuses {
+		if !s.Applied || s.Unknown {
+			t.Errorf("migration %d (%s) applied=%v unknown=%v", s.Version, s.Name, s.Applied, s.Unknown)
+		}
+	}
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	db, err := sqldb.Open(sqldb.SQLite, filepath.Join(t.TempDir(), "match.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO matches (id, user1_id, user2_id, match_type) VALUES (1, 1, 2, 'blind')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO blind_messages (match_id, user_id, message, is_ai, ai_slot) VALUES
+		(1, NULL, 'Merhaba!', TRUE, 'start'), (1, 1, 'Selam', FALSE, NULL)`); err != nil {
+		t.Fatal(err)
+	}
+
+	// AI göndereni geri alınır: mesajlar korunur, AI mesajlarının göndereni 0 olur
+	if _, err := m.Down(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	var aiSender, total int
+	if err := db.QueryRow(`SELECT COUNT(*), SUM(CASE WHEN is_ai THEN user_id ELSE 0 END) FROM blind_messages`).Scan(&total, &aiSender); err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || aiSender != 0 {
+		t.Fatalf("after down: %d messages, AI sender %d; want 2 messages, AI sender 0", total, aiSender)
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var nullSenders int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM blind_messages WHERE user_id IS NULL`).Scan(&nullSenders); err != nil {
+		t.Fatal(err)
+	}
+	if nullSenders != 1 {
+		t.Fatalf("after up again: %d AI messages without a sender, want 1", nullSenders)
+	}
+
+	if _, err := m.Down(ctx, len(matchMigrations)); err != nil {
+		t.Fatal(err)
+	}
+	var tables int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('matches', 'swipes', 'blind_messages', 'date_tasks')`).Scan(&tables); err != nil {
+		t.Fatal(err)
+	}
+	if tables != 0 {
+		t.Fatalf("%d tables left after rolling back everything", tables)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("up after full rollback: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+// postgres.go - PostgreSQL sürücüsü ve şema göçleri (Match Service)
+package repository
+
+import (
+	"eros/shared/migrate"
+
+	_ "github.com/lib/pq"
+)
+
+// postgresMigrations - matchMigrations ile aynı versiyonlar, PostgreSQL söz dizimiyle
+// users tablosu user-service'e aittir (aynı veritabanı paylaşılır); göçler sırası garanti
+// olmadığından ona yabancı anahtar tanımlanmaz.
+var postgresMigrations = []migrate.Migration{
+	{
+		Version: 1,
+		Name:    "baseline",
+		Up: migrate.SQL(
+			`CREATE TABLE IF NOT EXISTS matches (
+				id SERIAL PRIMARY KEY,
+				user1_id INTEGER NOT NULL,
+				user2_id INTEGER NOT NULL,
+				match_type TEXT NOT NULL,
+				status TEXT DEFAULT 'active',
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				expires_at TIMESTAMPTZ
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_matches_user1 ON matches (user1_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_matches_user2 ON matches (user2_id)`,
+
+			`CREATE TABLE IF NOT EXISTS swipes (
+				id SERIAL PRIMARY KEY,
+				user_id INTEGER NOT NULL,
+				target_id INTEGER NOT NULL,
+				direction TEXT NOT NULL,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_swipes_user ON swipes (user_id, target_id)`,
+
+			`CREATE TABLE IF NOT EXISTS blind_messages (
+				id SERIAL PRIMARY KEY,
+				match_id INTEGER NOT NULL REFERENCES matches (id),
+				user_id INTEGER NOT NULL,
+				message TEXT NOT NULL,
+				is_ai BOOLEAN DEFAULT FALSE,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS date_tasks (
+				id SERIAL PRIMARY KEY,
+				match_id INTEGER NOT NULL REFERENCES matches (id),
+				title TEXT NOT NULL,
+				description TEXT NOT NULL,
+				location TEXT NOT NULL,
+				duration TEXT NOT NULL,
+				difficulty TEXT NOT NULL,
+				status TEXT DEFAULT 'pending',
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+		),
+		Down: migrate.SQL(
+			`DROP TABLE IF EXISTS date_tasks`,
+			`DROP TABLE IF EXISTS blind_messages`,
+			`DROP TABLE IF EXISTS swipes`,
+			`DROP TABLE IF EXISTS matches`,
+		),
+	},
+	{
+		Version: 2,
+		Name:    "blind_messages_ai_sender",
+		Up: migrate.SQL(
+			`ALTER TABLE blind_messages ALTER COLUMN user_id DROP NOT NULL`,
+			`UPDATE blind_messages SET user_id = NULL WHERE is_ai`,
+			`ALTER TABLE blind_messages ADD COLUMN ai_slot TEXT`,
+			`CREATE UNIQUE INDEX idx_blind_messages_ai_slot ON blind_messages (match_id, ai_slot)`,
+		),
+		// Eski şemada AI mesajlarının göndereni 0'dı
+		Down: migrate.SQL(
+			`DROP INDEX IF EXISTS idx_blind_messages_ai_slot`,
+			`ALTER TABLE blind_messages DROP COLUMN IF EXISTS ai_slot`,
+			`UPDATE blind_messages SET user_id = 0 WHERE user_id IS NULL`,
+			`ALTER TABLE blind_messages ALTER COLUMN user_id SET NOT NULL`,
+		),
+	},
+}
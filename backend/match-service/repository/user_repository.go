@@ -2,36 +2,41 @@
 package repository
 
 import (
-    "database/sql"
+    "context"
     "eros/match-service/model"
+    "eros/shared/sqldb"
+    "eros/shared/tracing"
     "encoding/json"
 )
 
 type UserRepository struct {
-    db *sql.DB
+    db *sqldb.DB
 }
 
-func NewUserRepository(db *sql.DB) *UserRepository {
+func NewUserRepository(db *sqldb.DB) *UserRepository {
     return &UserRepository{db: db}
 }
 
 // GetUserByID - ID ile kullanıcı getir
-func (r *UserRepository) GetUserByID(userID int) (*model.User, error) {
+func (r *UserRepository) GetUserByID(ctx context.Context, userID int) (*model.User, error) {
+    ctx, span := tracing.Start(ctx, "UserRepository.GetUserByID")
+    defer span.End()
+
     query := `
         SELECT id, name, email, bio, age, age_range, distance, seriousness,
                height, weight, smokes, drinks, job, job_category, education,
-               hobbies, hobby_categories, created_at, updated_at
+               hobbies, hobby_categories, verified_at IS NOT NULL, created_at, updated_at
         FROM users WHERE id = ?
     `
     
     user := &model.User{}
     var hobbiesStr, hobbyCategoriesStr string
     
-    err := r.db.QueryRow(query, userID).Scan(
+    err := r.db.QueryRowContext(ctx, query, userID).Scan(
         &user.ID, &user.Name, &user.Email, &user.Bio, &user.Age, &user.AgeRange,
         &user.Distance, &user.Seriousness, &user.Height, &user.Weight,
         &user.Smokes, &user.Drinks, &user.Job, &user.JobCategory, &user.Education,
-        &hobbiesStr, &hobbyCategoriesStr, &user.CreatedAt, &user.UpdatedAt,
+        &hobbiesStr, &hobbyCategoriesStr, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
     )
     
     if err != nil {
@@ -50,17 +55,26 @@ func (r *UserRepository) GetUserByID(userID int) (*model.User, error) {
 }
 
 // GetPotentialMatches - Potansiyel eşleşmeleri getir
-func (r *UserRepository) GetPotentialMatches(user *model.User, limit int) ([]model.User, error) {
+// verifiedOnly ise sadece selfie doğrulamasından geçmiş kullanıcılar döner.
+func (r *UserRepository) GetPotentialMatches(ctx context.Context, user *model.User, limit int, verifiedOnly bool) ([]model.User, error) {
+    ctx, span := tracing.Start(ctx, "UserRepository.GetPotentialMatches")
+    defer span.End()
+
     query := `
         SELECT id, name, email, bio, age, age_range, distance, seriousness,
                height, weight, smokes, drinks, job, job_category, education,
-               hobbies, hobby_categories, created_at, updated_at
+               hobbies, hobby_categories, verified_at IS NOT NULL, created_at, updated_at
         FROM users 
         WHERE id != ? AND age BETWEEN ? AND ?
-        ORDER BY RANDOM() LIMIT ?
     `
+    if verifiedOnly {
+        query += " AND verified_at IS NOT NULL"
+    }
+    query += " ORDER BY RANDOM() LIMIT ?"
     
-    rows, err := r.db.Query(query, user.ID, 18, 100, limit)
+    span.SetAttr("limit", limit)
+    span.SetAttr("verified_only", verifiedOnly)
+    rows, err := r.db.QueryContext(ctx, query, user.ID, 18, 100, limit)
     if err != nil {
         return nil, err
     }
@@ -75,7 +89,7 @@ func (r *UserRepository) GetPotentialMatches(user *model.User, limit int) ([]mod
             &user.ID, &user.Name, &user.Email, &user.Bio, &user.Age, &user.AgeRange,
             &user.Distance, &user.Seriousness, &user.Height, &user.Weight,
             &user.Smokes, &user.Drinks, &user.Job, &user.JobCategory, &user.Education,
-            &hobbiesStr, &hobbyCategoriesStr, &user.CreatedAt, &user.UpdatedAt,
+            &hobbiesStr, &hobbyCategoriesStr, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
         )
         
         if err != nil {
@@ -93,11 +107,15 @@ func (r *UserRepository) GetPotentialMatches(user *model.User, limit int) ([]mod
         users = append(users, user)
     }
     
+    span.SetAttr("candidates", len(users))
     return users, nil
 }
 
 // GetUsersByIDs - ID listesi ile kullanıcıları getir
-func (r *UserRepository) GetUsersByIDs(userIDs []int) ([]model.User, error) {
+func (r *UserRepository) GetUsersByIDs(ctx context.Context, userIDs []int) ([]model.User, error) {
+    ctx, span := tracing.Start(ctx, "UserRepository.GetUsersByIDs")
+    defer span.End()
+
     if len(userIDs) == 0 {
         return []model.User{}, nil
     }
@@ -116,11 +134,11 @@ func (r *UserRepository) GetUsersByIDs(userIDs []int) ([]model.User, error) {
     query := `
         SELECT id, name, email, bio, age, age_range, distance, seriousness,
                height, weight, smokes, drinks, job, job_category, education,
-               hobbies, hobby_categories, created_at, updated_at
+               hobbies, hobby_categories, verified_at IS NOT NULL, created_at, updated_at
         FROM users WHERE id IN (` + placeholders + `)
     `
     
-    rows, err := r.db.Query(query, args...)
+    rows, err := r.db.QueryContext(ctx, query, args...)
     if err != nil {
         return nil, err
     }
@@ -135,7 +153,7 @@ func (r *UserRepository) GetUsersByIDs(userIDs []int) ([]model.User, error) {
             &user.ID, &user.Name, &user.Email, &user.Bio, &user.Age, &user.AgeRange,
             &user.Distance, &user.Seriousness, &user.Height, &user.Weight,
             &user.Smokes, &user.Drinks, &user.Job, &user.JobCategory, &user.Education,
-            &hobbiesStr, &hobbyCategoriesStr, &user.CreatedAt, &user.UpdatedAt,
+            &hobbiesStr, &hobbyCategoriesStr, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
         )
         
         if err != nil {
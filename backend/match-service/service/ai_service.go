@@ -2,8 +2,11 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"eros/match-service/model"
+	"eros/shared/apierror"
+	"eros/shared/tracing"
 	"eros/shared/utils"
 	"fmt"
 	"time"
@@ -19,20 +22,31 @@ func NewAIService() *AIService {
 	}
 }
 
+// Health - AI sağlayıcısının durumu (hazırlık kontrolü için)
+func (s *AIService) Health(ctx context.Context) error {
+	return s.openRouterClient.Health(ctx)
+}
+
 // FindBestBlindMatch - Blind date için en uygun eşleşmeyi bul
-func (s *AIService) FindBestBlindMatch(user *model.User, candidates []model.User) *model.User {
+func (s *AIService) FindBestBlindMatch(ctx context.Context, user *model.User, candidates []model.User) *model.User {
+	_, span := tracing.Start(ctx, "AIService.FindBestBlindMatch")
+	defer span.End()
+	span.SetAttr("candidates", len(candidates))
+
 	if len(candidates) == 0 {
 		return nil
 	}
 
 	bestScore := 0.0
 	var bestMatch *model.User
+	fallbacks := 0
 
 	for _, candidate := range candidates {
 		score, err := s.openRouterClient.ProfileMatching(s.userToMap(user), s.userToMap(&candidate))
 		if err != nil {
 			// API hatası durumunda basit skorlama kullan
 			score = s.calculateSimpleCompatibilityScore(user, &candidate)
+			fallbacks++
 		}
 
 		if score > bestScore {
@@ -40,6 +54,8 @@ func (s *AIService) FindBestBlindMatch(user *model.User, candidates []model.User
 			bestMatch = &candidate
 		}
 	}
+	span.SetAttr("best_score", bestScore)
+	span.SetAttr("fallback_scores", fallbacks)
 
 	return bestMatch
 }
@@ -97,13 +113,13 @@ func (s *AIService) calculateSimpleCompatibilityScore(user1, user2 *model.User)
 }
 
 // GenerateIceBreaker - Buz kırıcı mesaj oluştur
-func (s *AIService) GenerateIceBreaker(user1, user2 *model.User) (string, error) {
-	return s.openRouterClient.IceBreaker(s.userToMap(user1), s.userToMap(user2))
+func (s *AIService) GenerateIceBreaker(ctx context.Context, user1, user2 *model.User) (string, error) {
+	return s.openRouterClient.IceBreaker(ctx, s.userToMap(user1), s.userToMap(user2))
 }
 
 // GenerateDateTask - Date görevi oluştur
-func (s *AIService) GenerateDateTask(user1, user2 *model.User) (*model.DateTask, error) {
-	response, err := s.openRouterClient.DateSuggestion(s.userToMap(user1), s.userToMap(user2))
+func (s *AIService) GenerateDateTask(ctx context.Context, user1, user2 *model.User) (*model.DateTask, error) {
+	response, err := s.openRouterClient.DateSuggestion(ctx, s.userToMap(user1), s.userToMap(user2))
 	if err != nil {
 		return nil, err
 	}
@@ -111,14 +127,15 @@ func (s *AIService) GenerateDateTask(user1, user2 *model.User) (*model.DateTask,
 	// JSON parse et
 	var task model.DateTask
 	if err := json.Unmarshal([]byte(response), &task); err != nil {
-		return nil, err
+		// Model beklenen JSON yerine serbest metin döndürdüyse sağlayıcı hatası sayılır
+		return nil, apierror.AIUnavailable(fmt.Errorf("invalid AI response: %w", err))
 	}
 
 	return &task, nil
 }
 
 // GenerateBlindDateTask - Blind date için görev oluştur
-func (s *AIService) GenerateBlindDateTask(match *model.Match, messages []model.BlindMessage) (*model.DateTask, error) {
+func (s *AIService) GenerateBlindDateTask(ctx context.Context, match *model.Match, messages []model.BlindMessage) (*model.DateTask, error) {
 	// Mesajları analiz et
 	conversation := ""
 	for _, msg := range messages {
@@ -126,7 +143,7 @@ func (s *AIService) GenerateBlindDateTask(match *model.Match, messages []model.B
 	}
 
 	// Chat analysis ile sohbeti analiz et (şimdilik kullanmıyoruz)
-	_, err := s.openRouterClient.ChatAnalysis(conversation)
+	_, err := s.openRouterClient.ChatAnalysis(ctx, conversation)
 	if err != nil {
 		return nil, err
 	}
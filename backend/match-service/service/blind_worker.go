@@ -0,0 +1,55 @@
+// blind_worker.go - Aktif blind date'lerin periyodik bakımı (süre dolumu ve buz kırıcılar)
+package service
+
+import (
+	"context"
+	"eros/shared/tracing"
+	"log/slog"
+	"time"
+)
+
+// RunBlindMatchWorker - Aktif blind date'leri periyodik olarak tarar: süresi dolanları kapatır,
+// sessiz kalan sohbetlere AI buz kırıcı mesajı ekler
+func (s *MatchService) RunBlindMatchWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweepBlindMatches(ctx, s.now())
+		}
+	}
+}
+
+// sweepBlindMatches - Tüm aktif blind date'ler için süreyi ve buz kırıcı politikasını uygula
+// Her tarama kendi izini başlatır.
+func (s *MatchService) sweepBlindMatches(ctx context.Context, now time.Time) {
+	ctx, span := tracing.Start(ctx, "MatchService.sweepBlindMatches")
+	defer span.End()
+
+	matches, err := s.matchRepo.GetActiveBlindMatches(ctx)
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to list active blind matches", "error", err)
+		return
+	}
+	span.SetAttr("active_matches", len(matches))
+
+	for _, match := range matches {
+		if match.ExpiresAt != nil && now.After(*match.ExpiresAt) {
+			if err := s.matchRepo.UpdateMatchStatus(ctx, match.ID, "expired"); err != nil {
+				slog.ErrorContext(ctx, "failed to expire blind match", "match_id", match.ID, "error", err)
+				continue
+			}
+			blindMatchesEnded.Inc("expired")
+			continue
+		}
+
+		if _, err := s.DeliverAIIceBreaker(ctx, match.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to deliver AI ice breaker", "match_id", match.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// icebreaker.go - Blind chat için AI buz kırıcı politikası
+package service
+
+import (
+	"context"
+	"eros/match-service/model"
+	"eros/shared/tracing"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// aiSenderID - AI mesajlarının API'deki user_id değeri (veritabanında gönderen NULL'dır)
+const aiSenderID = 0
+
+// IceBreakerPolicy - AI buz kırıcı mesajlarının ne zaman ekleneceğini belirler
+type IceBreakerPolicy struct {
+	OnStart   bool          // Blind date başladığında ilk mesajı ekle
+	Lull      time.Duration // Sohbet bu süre sessiz kalırsa yeni mesaj ekle
+	MaxPerDay int           // Bir eşleşme için son 24 saatte en fazla AI mesajı
+}
+
+// DefaultIceBreakerPolicy - Varsayılan politika
+func DefaultIceBreakerPolicy() IceBreakerPolicy {
+	return IceBreakerPolicy{
+		OnStart:   true,
+		Lull:      6 * time.Hour,
+		MaxPerDay: 3,
+	}
+}
+
+// IceBreakerPolicyFromEnv - Politikayı ortam değişkenlerinden oku
+func IceBreakerPolicyFromEnv() IceBreakerPolicy {
+	policy := DefaultIceBreakerPolicy()
+
+	if v := os.Getenv("BLIND_ICEBREAKER_ON_START"); v != "" {
+		if onStart, err := strconv.ParseBool(v); err == nil {
+			policy.OnStart = onStart
+		}
+	}
+	if v := os.Getenv("BLIND_ICEBREAKER_LULL_HOURS"); v != "" {
+		if hours, err := strconv.ParseFloat(v, 64); err == nil && hours > 0 {
+			policy.Lull = time.Duration(hours * float64(time.Hour))
+		}
+	}
+	if v := os.Getenv("BLIND_ICEBREAKER_MAX_PER_DAY"); v != "" {
+		if limit, err := strconv.Atoi(v); err == nil && limit >= 0 {
+			policy.MaxPerDay = limit
+		}
+	}
+
+	return policy
+}
+
+// ShouldInsert - Mevcut mesajlara göre yeni bir AI mesajı eklenmeli mi
+func (p IceBreakerPolicy) ShouldInsert(match *model.Match, messages []model.BlindMessage, now time.Time) bool {
+	if match.MatchType != "blind" || match.Status != "active" {
+		return false
+	}
+	if match.ExpiresAt != nil && now.After(*match.ExpiresAt) {
+		return false
+	}
+
+	// Günlük limit
+	aiToday := 0
+	for _, msg := range messages {
+		if msg.IsAI && now.Sub(msg.CreatedAt) < 24*time.Hour {
+			aiToday++
+		}
+	}
+	if aiToday >= p.MaxPerDay {
+		return false
+	}
+
+	// Henüz hiç mesaj yok: blind date başlangıcı
+	if len(messages) == 0 {
+		return p.OnStart || now.Sub(match.CreatedAt) >= p.Lull
+	}
+
+	// Son mesaj zaten AI'dan geldiyse kullanıcıların cevabını bekle
+	last := messages[len(messages)-1]
+	if last.IsAI {
+		return false
+	}
+
+	return now.Sub(last.CreatedAt) >= p.Lull
+}
+
+// DeliverAIIceBreaker - Politika izin veriyorsa AI buz kırıcı mesajını blind chat'e ekle
+// Politika izin vermiyorsa veya mesaj başka bir görevce zaten eklendiyse nil döner
+func (s *MatchService) DeliverAIIceBreaker(ctx context.Context, matchID int) (*model.BlindMessage, error) {
+	ctx, span := tracing.Start(ctx, "MatchService.DeliverAIIceBreaker")
+	defer span.End()
+	span.SetAttr("match_id", matchID)
+
+	match, err := s.matchRepo.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.matchRepo.GetBlindMessages(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.iceBreakerPolicy.ShouldInsert(match, messages, s.now()) {
+		return nil, nil
+	}
+
+	// Mesajın yeri: başlangıç veya son kullanıcı mesajının ardı. Blind date açılışındaki görev
+	// ile periyodik tarama aynı yeri hesaplar; veritabanı yalnızca birinin eklemesine izin verir.
+	slot := "start"
+	if len(messages) > 0 {
+		slot = fmt.Sprintf("after:%d", messages[len(messages)-1].ID)
+	}
+
+	text, err := s.GenerateAIIceBreaker(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	aiMessage := &model.BlindMessage{
+		MatchID:   matchID,
+		UserID:    aiSenderID,
+		Message:   text,
+		IsAI:      true,
+		AISlot:    slot,
+		CreatedAt: s.now(),
+	}
+
+	created, err := s.matchRepo.CreateAIBlindMessage(ctx, aiMessage)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		// Aynı buz kırıcı başka bir görev tarafından zaten eklendi
+		span.SetAttr("duplicate", true)
+		return nil, nil
+	}
+
+	return aiMessage, nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"eros/match-service/model"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestShouldInsert(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	expires := start.Add(72 * time.Hour)
+	blind := &model.Match{MatchType: "blind", Status: "active", CreatedAt: start, ExpiresAt: &expires}
+
+	user := func(at time.Duration) model.BlindMessage {
+		return model.BlindMessage{UserID: 1, CreatedAt: start.Add(at)}
+	}
+	ai := func(at time.Duration) model.BlindMessage {
+		return model.BlindMessage{IsAI: true, CreatedAt: start.Add(at)}
+	}
+	noStart := DefaultIceBreakerPolicy()
+	noStart.OnStart = false
+	noLimit := DefaultIceBreakerPolicy()
+	noLimit.MaxPerDay = 0
+
+	tests := []struct {
+		name     string
+		policy   IceBreakerPolicy
+		match    *model.Match
+		messages []model.BlindMessage
+		at       time.Duration // Eşleşmenin başlangıcından itibaren geçen süre
+		want     bool
+	}{
+		{name: "on start", policy: DefaultIceBreakerPolicy(), match: blind, want: true},
+		{name: "start disabled, before lull", policy: noStart, match: blind, at: time.Hour, want: false},
+		{name: "start disabled, silent past lull", policy: noStart, match: blind, at: 6 * time.Hour, want: true},
+		{name: "waiting for a reply to the ai", messages: []model.BlindMessage{ai(0)}, at: 10 * time.Hour, want: false},
+		{name: "conversation is active", messages: []model.BlindMessage{ai(0), user(time.Hour)}, at: 3 * time.Hour, want: false},
+		{name: "lull after a user message", messages: []model.BlindMessage{ai(0), user(time.Hour)}, at: 7 * time.Hour, want: true},
+		{
+			name:     "daily cap reached",
+			messages: []model.BlindMessage{ai(0), user(time.Hour), ai(7 * time.Hour), user(8 * time.Hour), ai(14 * time.Hour), user(15 * time.Hour)},
+			at:       21 * time.Hour,
+			want:     false,
+		},
+		{
+			name:     "cap counts only the last 24 hours",
+			messages: []model.BlindMessage{ai(0), user(time.Hour), ai(7 * time.Hour), user(8 * time.Hour), ai(14 * time.Hour), user(15 * time.Hour)},
+			at:       24 * time.Hour,
+			want:     true,
+		},
+		{name: "cap of zero disables ice breakers", policy: noLimit, match: blind, want: false},
+		{name: "not a blind match", match: &model.Match{MatchType: "normal", Status: "active", CreatedAt: start}, want: false},
+		{name: "ended match", match: &model.Match{MatchType: "blind", Status: "ended", CreatedAt: start}, want: false},
+		{name: "expired match", match: blind, at: 73 * time.Hour, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, match := tt.policy, tt.match
+			if policy == (IceBreakerPolicy{}) {
+				policy = DefaultIceBreakerPolicy()
+			}
+			if match == nil {
+				match = blind
+			}
+			if got := policy.ShouldInsert(match, tt.messages, start.Add(tt.at)); got != tt.want {
+				t.Fatalf("ShouldInsert = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// stubIceBreakerAI - AI sağlayıcısını sabit bir buz kırıcı döndüren test sunucusuyla değiştir
+func stubIceBreakerAI(t *testing.T, s *MatchService) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"En sevdiğiniz seyahat anısı ne?"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := s.aiService.openRouterClient
+	client.BaseURL = server.URL
+	for model := range client.Keys {
+		client.Keys[model] = "test"
+	}
+}
+
+func TestDeliverAIIceBreaker(t *testing.T) {
+	s, matchRepo, matchID := newBlindTestService(t)
+	stubIceBreakerAI(t, s)
+	ctx := context.Background()
+	start := time.Now()
+	now := start
+	s.now = func() time.Time { return now }
+	s.iceBreakerPolicy.MaxPerDay = 2
+
+	reply := func(at time.Duration) {
+		t.Helper()
+		if err := matchRepo.CreateBlindMessage(ctx, &model.BlindMessage{MatchID: matchID, UserID: 1, Message: "Merhaba", CreatedAt: start.Add(at)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	steps := []struct {
+		name     string
+		replyAt  time.Duration // Adımdan önce yazılan kullanıcı mesajı (0 ise yok)
+		at       time.Duration
+		want     bool
+		wantSlot string
+	}{
+		{name: "start", at: 0, want: true, wantSlot: "start"},
+		{name: "waiting for a reply", at: 10 * time.Hour, want: false},
+		{name: "conversation is active", replyAt: 11 * time.Hour, at: 12 * time.Hour, want: false},
+		{name: "lull", at: 17 * time.Hour, want: true},
+		{name: "daily cap", replyAt: 17*time.Hour + 30*time.Minute, at: 23*time.Hour + 40*time.Minute, want: false},
+		{name: "first message left the day", at: 24*time.Hour + time.Minute, want: true},
+	}
+	for _, step := range steps {
+		if step.replyAt > 0 {
+			reply(step.replyAt)
+		}
+		now = start.Add(step.at)
+		msg, err := s.DeliverAIIceBreaker(ctx, matchID)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if (msg != nil) != step.want {
+			t.Fatalf("%s: inserted = %v, want %v", step.name, msg != nil, step.want)
+		}
+		if msg != nil && (msg.UserID != aiSenderID || !msg.CreatedAt.Equal(now) || (step.wantSlot != "" && msg.AISlot != step.wantSlot)) {
+			t.Fatalf("%s: message = %+v", step.name, msg)
+		}
+	}
+}
@@ -2,28 +2,51 @@
 package service
 
 import (
+    "context"
     "errors"
     "eros/match-service/model"
     "eros/match-service/repository"
+    "eros/shared/apierror"
+    "eros/shared/moderation"
+    "eros/shared/server"
+    "eros/shared/utils"
+    "log/slog"
     "time"
 )
 
+var (
+    // ErrInappropriateContent - Moderasyon mesajı engelledi
+    ErrInappropriateContent = errors.New("inappropriate content detected")
+    // ErrContactInfoNotAllowed - Blind chat'in başında iletişim bilgisi paylaşılamaz
+    ErrContactInfoNotAllowed = errors.New("sharing contact information is not allowed yet")
+)
+
 type MatchService struct {
-    matchRepo *repository.MatchRepository
-    userRepo  *repository.UserRepository
-    aiService *AIService
+    matchRepo        *repository.MatchRepository
+    userRepo         *repository.UserRepository
+    aiService        *AIService
+    iceBreakerPolicy IceBreakerPolicy
+    contactPolicy    utils.ContactPolicy
+    moderation       *moderation.Engine
+    workers          *server.Workers
+    now              func() time.Time
 }
 
-func NewMatchService(matchRepo *repository.MatchRepository, userRepo *repository.UserRepository, aiService *AIService) *MatchService {
+func NewMatchService(matchRepo *repository.MatchRepository, userRepo *repository.UserRepository, aiService *AIService, iceBreakerPolicy IceBreakerPolicy, contactPolicy utils.ContactPolicy, engine *moderation.Engine, workers *server.Workers) *MatchService {
     return &MatchService{
-        matchRepo: matchRepo,
-        userRepo:  userRepo,
-        aiService: aiService,
+        matchRepo:        matchRepo,
+        userRepo:         userRepo,
+        aiService:        aiService,
+        iceBreakerPolicy: iceBreakerPolicy,
+        contactPolicy:    contactPolicy,
+        moderation:       engine,
+        workers:          workers,
+        now:              time.Now,
     }
 }
 
 // ProcessSwipe - Swipe işlemini gerçekleştir
-func (s *MatchService) ProcessSwipe(userID, targetID int, direction string) (bool, error) {
+func (s *MatchService) ProcessSwipe(ctx context.Context, userID, targetID int, direction string) (bool, error) {
     // Swipe kaydını oluştur
     swipe := &model.Swipe{
         UserID:    userID,
@@ -32,22 +55,23 @@ func (s *MatchService) ProcessSwipe(userID, targetID int, direction string) (boo
         CreatedAt: time.Now(),
     }
 
-    if err := s.matchRepo.CreateSwipe(swipe); err != nil {
+    if err := s.matchRepo.CreateSwipe(ctx, swipe); err != nil {
         return false, err
     }
+    swipesTotal.Inc(direction)
 
     // Eğer sağa kaydırma ise, karşılıklı swipe kontrolü yap
     if direction == "right" {
-        return s.checkMutualSwipe(userID, targetID)
+        return s.checkMutualSwipe(ctx, userID, targetID)
     }
 
     return false, nil
 }
 
 // checkMutualSwipe - Karşılıklı swipe kontrolü
-func (s *MatchService) checkMutualSwipe(userID, targetID int) (bool, error) {
+func (s *MatchService) checkMutualSwipe(ctx context.Context, userID, targetID int) (bool, error) {
     // Karşılıklı swipe kontrolü
-    isMutual, err := s.matchRepo.CheckMutualSwipe(userID, targetID)
+    isMutual, err := s.matchRepo.CheckMutualSwipe(ctx, userID, targetID)
     if err != nil {
         return false, err
     }
@@ -62,9 +86,10 @@ func (s *MatchService) checkMutualSwipe(userID, targetID int) (bool, error) {
             CreatedAt: time.Now(),
         }
 
-        if err := s.matchRepo.CreateMatch(match); err != nil {
+        if err := s.matchRepo.CreateMatch(ctx, match); err != nil {
             return false, err
         }
+        matchesCreated.Inc(match.MatchType)
 
         return true, nil
     }
@@ -72,31 +97,31 @@ func (s *MatchService) checkMutualSwipe(userID, targetID int) (bool, error) {
     return false, nil
 }
 
-// GetPotentialMatches - Potansiyel eşleşmeleri getir
-func (s *MatchService) GetPotentialMatches(userID, limit int) ([]model.User, error) {
-    user, err := s.userRepo.GetUserByID(userID)
+// GetPotentialMatches - Potansiyel eşleşmeleri getir (verifiedOnly: sadece doğrulanmış profiller)
+func (s *MatchService) GetPotentialMatches(ctx context.Context, userID, limit int, verifiedOnly bool) ([]model.User, error) {
+    user, err := s.userRepo.GetUserByID(ctx, userID)
     if err != nil {
         return nil, err
     }
 
-    return s.userRepo.GetPotentialMatches(user, limit)
+    return s.userRepo.GetPotentialMatches(ctx, user, limit, verifiedOnly)
 }
 
 // GetMatchHistory - Eşleşme geçmişini getir
-func (s *MatchService) GetMatchHistory(userID int) ([]model.Match, error) {
-    return s.matchRepo.GetMatchHistory(userID)
+func (s *MatchService) GetMatchHistory(ctx context.Context, userID int) ([]model.Match, error) {
+    return s.matchRepo.GetMatchHistory(ctx, userID)
 }
 
 // CreateBlindMatch - Blind date eşleştirmesi oluştur
-func (s *MatchService) CreateBlindMatch(userID int) (int, string, error) {
+func (s *MatchService) CreateBlindMatch(ctx context.Context, userID int) (int, string, error) {
     // Uygun eşleşme bul
-    targetUser, err := s.findBlindMatch(userID)
+    targetUser, err := s.findBlindMatch(ctx, userID)
     if err != nil {
         return 0, "", err
     }
 
     if targetUser == nil {
-        return 0, "", errors.New("no suitable blind match found")
+        return 0, "", apierror.NotFound("no suitable blind match found")
     }
 
     // Blind match oluştur
@@ -104,120 +129,174 @@ func (s *MatchService) CreateBlindMatch(userID int) (int, string, error) {
         User1ID:   userID,
         User2ID:   targetUser.ID,
         MatchType: "blind",
+        Status:    "active",
         CreatedAt: time.Now(),
         ExpiresAt: func() *time.Time { t := time.Now().Add(72 * time.Hour); return &t }(), // 3 gün
     }
 
-    if err := s.matchRepo.CreateMatch(match); err != nil {
+    if err := s.matchRepo.CreateMatch(ctx, match); err != nil {
         return 0, "", err
     }
+    matchesCreated.Inc(match.MatchType)
+
+    // Blind date başlangıcında AI buz kırıcı mesajı ekle (asenkron)
+    // İstek bitince iptal edilmeyen ama istek kimliğini taşıyan bağlam
+    matchID := match.ID
+    aiCtx := context.WithoutCancel(ctx)
+    s.workers.Go(func(context.Context) {
+        if _, err := s.DeliverAIIceBreaker(aiCtx, matchID); err != nil {
+            slog.ErrorContext(aiCtx, "failed to deliver AI ice breaker", "match_id", matchID, "error", err)
+        }
+    })
 
     return match.ID, match.ExpiresAt.Format(time.RFC3339), nil
 }
 
 // findBlindMatch - Blind date için uygun eşleşme bul
-func (s *MatchService) findBlindMatch(userID int) (*model.User, error) {
-    user, err := s.userRepo.GetUserByID(userID)
+func (s *MatchService) findBlindMatch(ctx context.Context, userID int) (*model.User, error) {
+    user, err := s.userRepo.GetUserByID(ctx, userID)
     if err != nil {
         return nil, err
     }
 
     // Kullanıcının tercihlerine göre potansiyel eşleşmeleri getir
-    candidates, err := s.userRepo.GetPotentialMatches(user, 50)
+    candidates, err := s.userRepo.GetPotentialMatches(ctx, user, 50, false)
     if err != nil {
         return nil, err
     }
 
     // AI skorlama algoritması ile en uygun eşleşmeyi bul
-    bestMatch := s.aiService.FindBestBlindMatch(user, candidates)
+    bestMatch := s.aiService.FindBestBlindMatch(ctx, user, candidates)
     return bestMatch, nil
 }
 
 // HasActiveBlindMatch - Aktif blind date kontrolü
-func (s *MatchService) HasActiveBlindMatch(userID int) (bool, error) {
-    match, err := s.matchRepo.GetActiveMatch(userID)
+func (s *MatchService) HasActiveBlindMatch(ctx context.Context, userID int) (bool, error) {
+    match, err := s.matchRepo.GetActiveMatch(ctx, userID)
     if err != nil {
         return false, err
     }
     return match != nil && match.MatchType == "blind", nil
 }
 
-// SendBlindMessage - Blind chat mesajı gönder
-func (s *MatchService) SendBlindMessage(matchID, userID int, message string) (int, error) {
+// SendBlindMessage - Blind chat mesajı gönder (maskeleme kararında maskelenmiş haliyle saklanır)
+func (s *MatchService) SendBlindMessage(ctx context.Context, matchID, userID int, message string) (*model.BlindMessage, error) {
+    // İçerik moderasyonu (sohbet servisiyle aynı motor)
+    decision := s.moderation.Evaluate(message)
+    if !decision.Allowed() {
+        messagesBlocked.Inc("blind", "moderation")
+        return nil, ErrInappropriateContent
+    }
+
+    // İletişim bilgisi: iki taraf da yeterince mesajlaşana kadar engellenir
+    if utils.ContainsContactInfo(message) {
+        allowed, err := s.contactInfoAllowed(ctx, matchID, userID)
+        if err != nil {
+            return nil, err
+        }
+        if !allowed {
+            messagesBlocked.Inc("blind", "contact_info")
+            return nil, ErrContactInfoNotAllowed
+        }
+    }
+
     // Mesajı kaydet
     chatMessage := &model.BlindMessage{
         MatchID:  matchID,
         UserID:   userID,
-        Message:  message,
+        Message:  decision.Masked,
         CreatedAt: time.Now(),
     }
 
-    if err := s.matchRepo.CreateBlindMessage(chatMessage); err != nil {
-        return 0, err
+    if err := s.matchRepo.CreateBlindMessage(ctx, chatMessage); err != nil {
+        return nil, err
     }
+    messagesSent.Inc("blind")
 
     // AI analizi yap
-    go s.aiService.AnalyzeBlindMessage(matchID, message)
+    s.workers.Go(func(context.Context) { s.aiService.AnalyzeBlindMessage(matchID, message) })
 
-    return chatMessage.ID, nil
+    return chatMessage, nil
 }
 
 // GetBlindMessages - Blind chat mesajlarını getir
-func (s *MatchService) GetBlindMessages(matchID int) ([]model.BlindMessage, error) {
-    return s.matchRepo.GetBlindMessages(matchID)
+func (s *MatchService) GetBlindMessages(ctx context.Context, matchID int) ([]model.BlindMessage, error) {
+    return s.matchRepo.GetBlindMessages(ctx, matchID)
+}
+
+// contactInfoAllowed - Gönderen ve karşı taraf politikadaki mesaj sayısına ulaştı mı (AI mesajları sayılmaz)
+func (s *MatchService) contactInfoAllowed(ctx context.Context, matchID, userID int) (bool, error) {
+    messages, err := s.matchRepo.GetBlindMessages(ctx, matchID)
+    if err != nil {
+        return false, err
+    }
+
+    sender, other := 0, 0
+    for _, msg := range messages {
+        switch msg.UserID {
+        case aiSenderID:
+        case userID:
+            sender++
+        default:
+            other++
+        }
+    }
+
+    return s.contactPolicy.Allows(sender, other), nil
 }
 
 // GenerateAIIceBreaker - AI buz kırıcı mesajı oluştur
-func (s *MatchService) GenerateAIIceBreaker(matchID int) (string, error) {
-    match, err := s.matchRepo.GetMatchByID(matchID)
+func (s *MatchService) GenerateAIIceBreaker(ctx context.Context, matchID int) (string, error) {
+    match, err := s.matchRepo.GetMatchByID(ctx, matchID)
     if err != nil {
         return "", err
     }
 
-    user1, err := s.userRepo.GetUserByID(match.User1ID)
+    user1, err := s.userRepo.GetUserByID(ctx, match.User1ID)
     if err != nil {
         return "", err
     }
 
-    user2, err := s.userRepo.GetUserByID(match.User2ID)
+    user2, err := s.userRepo.GetUserByID(ctx, match.User2ID)
     if err != nil {
         return "", err
     }
 
-    return s.aiService.GenerateIceBreaker(user1, user2)
+    return s.aiService.GenerateIceBreaker(ctx, user1, user2)
 }
 
 // CompleteBlindDate - Blind date'i tamamla
-func (s *MatchService) CompleteBlindDate(matchID int) (*model.DateTask, error) {
-    match, err := s.matchRepo.GetMatchByID(matchID)
+func (s *MatchService) CompleteBlindDate(ctx context.Context, matchID int) (*model.DateTask, error) {
+    match, err := s.matchRepo.GetMatchByID(ctx, matchID)
     if err != nil {
         return nil, err
     }
 
     // Mesajları analiz et
-    messages, err := s.matchRepo.GetBlindMessages(matchID)
+    messages, err := s.matchRepo.GetBlindMessages(ctx, matchID)
     if err != nil {
         return nil, err
     }
 
     // AI ile date görevi oluştur
-    dateTask, err := s.aiService.GenerateBlindDateTask(match, messages)
+    dateTask, err := s.aiService.GenerateBlindDateTask(ctx, match, messages)
     if err != nil {
         return nil, err
     }
 
     // Match'i tamamlandı olarak işaretle
     match.Status = "completed"
-    if err := s.matchRepo.UpdateMatchStatus(match.ID, "completed"); err != nil {
+    if err := s.matchRepo.UpdateMatchStatus(ctx, match.ID, "completed"); err != nil {
         return nil, err
     }
+    blindMatchesEnded.Inc("completed")
 
     return dateTask, nil
 }
 
 // GetBlindMatchStatus - Blind date durumunu getir
-func (s *MatchService) GetBlindMatchStatus(userID int) (*model.BlindMatchStatus, error) {
-    match, err := s.matchRepo.GetActiveMatch(userID)
+func (s *MatchService) GetBlindMatchStatus(ctx context.Context, userID int) (*model.BlindMatchStatus, error) {
+    match, err := s.matchRepo.GetActiveMatch(ctx, userID)
     if err != nil {
         return nil, err
     }
@@ -228,7 +307,7 @@ func (s *MatchService) GetBlindMatchStatus(userID int) (*model.BlindMatchStatus,
         }, nil
     }
     
-    messages, err := s.matchRepo.GetBlindMessages(match.ID)
+    messages, err := s.matchRepo.GetBlindMessages(ctx, match.ID)
     if err != nil {
         return nil, err
     }
@@ -242,16 +321,16 @@ func (s *MatchService) GetBlindMatchStatus(userID int) (*model.BlindMatchStatus,
 }
 
 // GenerateDateTask - Date görevi oluştur
-func (s *MatchService) GenerateDateTask(userID, targetID int) (*model.DateTask, error) {
-    user1, err := s.userRepo.GetUserByID(userID)
+func (s *MatchService) GenerateDateTask(ctx context.Context, userID, targetID int) (*model.DateTask, error) {
+    user1, err := s.userRepo.GetUserByID(ctx, userID)
     if err != nil {
         return nil, err
     }
 
-    user2, err := s.userRepo.GetUserByID(targetID)
+    user2, err := s.userRepo.GetUserByID(ctx, targetID)
     if err != nil {
         return nil, err
     }
 
-    return s.aiService.GenerateDateTask(user1, user2)
+    return s.aiService.GenerateDateTask(ctx, user1, user2)
 } 
\ No newline at end of file
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"eros/match-service/model"
+	"eros/match-service/repository"
+	"eros/shared/moderation"
+	"eros/shared/server"
+	"eros/shared/sqldb"
+	"eros/shared/utils"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testUsersTable - Kullanıcılar user-service'e aittir; UserRepository'nin okuduğu sütunlar yeterli
+const testUsersTable = `
+	CREATE TABLE users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		email TEXT NOT NULL DEFAULT '',
+		bio TEXT NOT NULL DEFAULT '',
+		age INTEGER NOT NULL DEFAULT 30,
+		age_range TEXT NOT NULL DEFAULT '',
+		distance INTEGER NOT NULL DEFAULT 50,
+		seriousness INTEGER NOT NULL DEFAULT 5,
+		height INTEGER NOT NULL DEFAULT 0,
+		weight INTEGER NOT NULL DEFAULT 0,
+		smokes BOOLEAN NOT NULL DEFAULT FALSE,
+		drinks BOOLEAN NOT NULL DEFAULT FALSE,
+		job TEXT NOT NULL DEFAULT '',
+		job_category TEXT NOT NULL DEFAULT '',
+		education TEXT NOT NULL DEFAULT '',
+		hobbies TEXT NOT NULL DEFAULT '[]',
+		hobby_categories TEXT NOT NULL DEFAULT '[]',
+		verified_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`
+
+// newBlindTestService - Bellek içi SQLite üzerinde blind eşleşmesi kurulmuş servis
+func newBlindTestService(t *testing.T) (*MatchService, *repository.MatchRepository, int) {
+	t.Helper()
+	db, err := sqldb.Open(sqldb.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Bellek içi SQLite her bağlantıda ayrı bir veritabanıdır
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := repository.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec(testUsersTable); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO users (id, name) VALUES (1, 'Ayşe'), (2, 'Mehmet')`); err != nil {
+		t.Fatal(err)
+	}
+
+	matchRepo := repository.NewMatchRepository(db)
+	match := &model.Match{User1ID: 1, User2ID: 2, MatchType: "blind", Status: "active", CreatedAt: time.Now()}
+	if err := matchRepo.CreateMatch(context.Background(), match); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("OPENROUTER_API_KEY", "")
+	workers := server.NewWorkers()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		workers.Stop(ctx)
+	})
+	s := NewMatchService(matchRepo, repository.NewUserRepository(db), NewAIService(), DefaultIceBreakerPolicy(), utils.DefaultContactPolicy(), moderation.Default(), workers)
+	return s, matchRepo, match.ID
+}
+
+func TestSendBlindMessageModeration(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		wantErr error
+		stored  string // Boşsa mesaj saklanmamalı
+	}{
+		{name: "clean message", message: "Merhaba, nasılsın?", stored: "Merhaba, nasılsın?"},
+		{name: "profanity is masked", message: "siktir git", stored: moderation.Default().Evaluate("siktir git").Masked},
+		{name: "threat is blocked", message: "seni öldüreceğim", wantErr: ErrInappropriateContent},
+		{name: "scam is blocked", message: "bana para gönderir misin acil", wantErr: ErrInappropriateContent},
+		{name: "early contact info", message: "Numaram 0555 123 45 67", wantErr: ErrContactInfoNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, matchRepo, matchID := newBlindTestService(t)
+			ctx := context.Background()
+
+			sent, err := s.SendBlindMessage(ctx, matchID, 1, tt.message)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			messages, err := matchRepo.GetBlindMessages(ctx, matchID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.stored == "" {
+				if len(messages) != 0 {
+					t.Fatalf("rejected message was stored: %+v", messages)
+				}
+				return
+			}
+			if len(messages) != 1 || messages[0].Message != tt.stored || sent.Message != tt.stored {
+				t.Fatalf("stored %+v, returned %+v, want %q", messages, sent, tt.stored)
+			}
+			if tt.stored != tt.message && messages[0].Message == tt.message {
+				t.Fatal("masked message was stored verbatim")
+			}
+		})
+	}
+}
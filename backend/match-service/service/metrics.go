@@ -0,0 +1,17 @@
+// metrics.go - Eşleşme hunisi metrikleri
+package service
+
+import "eros/shared/metrics"
+
+var (
+	swipesTotal = metrics.NewCounter("swipes_total",
+		"Swipes by direction (right, left).", "direction")
+	matchesCreated = metrics.NewCounter("matches_created_total",
+		"Matches created by type (classic, blind).", "type")
+	blindMatchesEnded = metrics.NewCounter("blind_matches_ended_total",
+		"Blind dates that ended by outcome (completed, expired).", "outcome")
+	messagesSent = metrics.NewCounter("messages_sent_total",
+		"Messages stored by chat (blind). AI ice-breakers are not counted.", "chat")
+	messagesBlocked = metrics.NewCounter("messages_blocked_total",
+		"Messages rejected before storing by chat and reason.", "chat", "reason")
+)
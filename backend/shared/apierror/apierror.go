@@ -0,0 +1,202 @@
+// apierror.go - Servisler arası ortak hata modeli: tipli hatalar, durum kodları ve tek JSON zarfı
+package apierror
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Hata kodları (yanıttaki "code" alanı; istemciler mesaja değil koda bakmalı)
+const (
+	CodeBadRequest       = "bad_request"
+	CodeValidation       = "validation_failed"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeConflict         = "conflict"
+	CodePayloadTooLarge  = "payload_too_large"
+	CodeUnsupportedMedia = "unsupported_media_type"
+	CodeUnprocessable    = "unprocessable"
+	CodeRateLimited      = "rate_limited"
+	CodeInternal         = "internal"
+	CodeAIUnavailable    = "ai_unavailable"
+	CodeBadGateway       = "bad_gateway"
+	CodeUnavailable      = "unavailable"
+	CodeGatewayTimeout   = "gateway_timeout"
+)
+
+// Error - İstemciye dönecek hata
+// Message istemciye gösterilir; Err (sebep) yalnızca günlüğe ve iz span'ine yazılır.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// New - Durum kodu, hata kodu ve mesajla hata
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// Wrap - Sebebi saklayarak hata oluştur (errors.Is/As sebebe ulaşır)
+func Wrap(err error, status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message, Err: err}
+}
+
+// BadRequest - Okunamayan gövde, geçersiz sorgu parametresi (400)
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, CodeBadRequest, message)
+}
+
+// Unauthorized - Oturum yok ya da geçersiz (401)
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+// Forbidden - Oturum geçerli ama işleme izin yok (403)
+func Forbidden(message string) *Error {
+	return New(http.StatusForbidden, CodeForbidden, message)
+}
+
+// NotFound - Kayıt bulunamadı (404)
+func NotFound(message string) *Error {
+	return New(http.StatusNotFound, CodeNotFound, message)
+}
+
+// Conflict - Kayıt zaten var ya da durumu işleme uygun değil (409)
+func Conflict(message string) *Error {
+	return New(http.StatusConflict, CodeConflict, message)
+}
+
+// PayloadTooLarge - Gövde ya da dosya sınırı aşıldı (413)
+func PayloadTooLarge(message string) *Error {
+	return New(http.StatusRequestEntityTooLarge, CodePayloadTooLarge, message)
+}
+
+// UnsupportedMediaType - Desteklenmeyen dosya türü (415)
+func UnsupportedMediaType(message string) *Error {
+	return New(http.StatusUnsupportedMediaType, CodeUnsupportedMedia, message)
+}
+
+// Unprocessable - İstek geçerli ama içerik politikaya takıldı (422; örn. iletişim bilgisi, moderasyon)
+func Unprocessable(message string) *Error {
+	return New(http.StatusUnprocessableEntity, CodeUnprocessable, message)
+}
+
+// RateLimited - Çok fazla istek (429); Retry-After başlığını çağıran yazar
+func RateLimited(message string) *Error {
+	return New(http.StatusTooManyRequests, CodeRateLimited, message)
+}
+
+// Internal - Beklenmeyen hata (500); sebep istemciye gösterilmez
+func Internal(err error) *Error {
+	return Wrap(err, http.StatusInternalServerError, CodeInternal, "internal server error")
+}
+
+// AIUnavailable - OpenRouter çağrısı başarısız ya da devre açık (502)
+func AIUnavailable(err error) *Error {
+	return Wrap(err, http.StatusBadGateway, CodeAIUnavailable, "AI provider is unavailable, try again later")
+}
+
+// Unavailable - Özellik ya da bağımlılık şu an kullanılamıyor (503)
+func Unavailable(message string) *Error {
+	return New(http.StatusServiceUnavailable, CodeUnavailable, message)
+}
+
+// BadGateway - Upstream servise ulaşılamadı (502)
+func BadGateway(message string) *Error {
+	return New(http.StatusBadGateway, CodeBadGateway, message)
+}
+
+// GatewayTimeout - Upstream zamanında yanıt vermedi (504)
+func GatewayTimeout(message string) *Error {
+	return New(http.StatusGatewayTimeout, CodeGatewayTimeout, message)
+}
+
+// MethodNotAllowed - Yöntem desteklenmiyor (405)
+func MethodNotAllowed() *Error {
+	return New(http.StatusMethodNotAllowed, CodeMethodNotAllowed, "method not allowed")
+}
+
+// From - err'i yanıtlanabilir hataya çevir
+// *Error ve *ValidationError olduğu gibi kullanılır, sql.ErrNoRows 404 olur, diğerleri 500'dür.
+func From(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		// Tek alan hatasında mesaj doğrudan o alanınkidir
+		message := "validation failed"
+		if len(validation.Fields) == 1 {
+			message = validation.Fields[0].Message
+		}
+		return &Error{Status: http.StatusBadRequest, Code: CodeValidation, Message: message, Fields: validation.Fields, Err: err}
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return Wrap(err, http.StatusNotFound, CodeNotFound, "not found")
+	}
+	return Internal(err)
+}
+
+// Fallback - From gibi; tanınmayan hatalarda 500 mesajı message olur (sebep yine gizlenir)
+func Fallback(err error, message string) *Error {
+	apiErr := From(err)
+	if apiErr.Code == CodeInternal && apiErr.Err == err {
+		apiErr.Message = message
+	}
+	return apiErr
+}
+
+// FieldError - Alan bazlı doğrulama hatası
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError - Bir istekteki tüm alan hataları (400, "fields" ile döner)
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+// Validation - Tek alanlı doğrulama hatası
+func Validation(field, code, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Code: code, Message: message}}}
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Add - Alan hatası ekle
+func (e *ValidationError) Add(field, code, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Code: code, Message: message})
+}
+
+// OrNil - Hata yoksa nil döndür
+func (e *ValidationError) OrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
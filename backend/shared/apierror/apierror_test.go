@@ -0,0 +1,173 @@
+package apierror
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"eros/shared/logging"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFrom(t *testing.T) {
+	cause := errors.New("dial tcp: connection refused")
+	conflict := Conflict("already matched")
+
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{"api error", conflict, http.StatusConflict, CodeConflict, "already matched"},
+		{"wrapped api error", fmt.Errorf("swipe: %w", NotFound("user not found")), http.StatusNotFound, CodeNotFound, "user not found"},
+		{"no rows", sql.ErrNoRows, http.StatusNotFound, CodeNotFound, "not found"},
+		{"wrapped no rows", fmt.Errorf("get match: %w", sql.ErrNoRows), http.StatusNotFound, CodeNotFound, "not found"},
+		{"single field", Validation("age", "range", "age must be at least 18"), http.StatusBadRequest, CodeValidation, "age must be at least 18"},
+		{"unknown", cause, http.StatusInternalServerError, CodeInternal, "internal server error"},
+		{"rate limited", RateLimited("too many swipes"), http.StatusTooManyRequests, CodeRateLimited, "too many swipes"},
+		{"ai unavailable", AIUnavailable(cause), http.StatusBadGateway, CodeAIUnavailable, "AI provider is unavailable, try again later"},
+		{"unprocessable", Unprocessable("contact info is not allowed"), http.StatusUnprocessableEntity, CodeUnprocessable, "contact info is not allowed"},
+		{"payload too large", PayloadTooLarge("photo too large"), http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "photo too large"},
+		{"gateway timeout", GatewayTimeout("upstream timed out"), http.StatusGatewayTimeout, CodeGatewayTimeout, "upstream timed out"},
+		{"method not allowed", MethodNotAllowed(), http.StatusMethodNotAllowed, CodeMethodNotAllowed, "method not allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := From(tt.err)
+			if got.Status != tt.status || got.Code != tt.code || got.Message != tt.message {
+				t.Fatalf("From = %d %s %q, want %d %s %q", got.Status, got.Code, got.Message, tt.status, tt.code, tt.message)
+			}
+		})
+	}
+
+	// Sebep errors.Is ile bulunabilir
+	if err := AIUnavailable(cause); !errors.Is(err, cause) || err.Error() != "AI provider is unavailable, try again later: "+cause.Error() {
+		t.Fatalf("AIUnavailable = %v", err)
+	}
+	if From(conflict) != conflict {
+		t.Fatal("From copied an *Error instead of returning it")
+	}
+}
+
+func TestFallback(t *testing.T) {
+	cause := errors.New("constraint failed")
+	tests := []struct {
+		name    string
+		err     error
+		code    string
+		message string
+	}{
+		{"unknown error gets the message", cause, CodeInternal, "swipe processing failed"},
+		{"wrapped unknown error", fmt.Errorf("insert: %w", cause), CodeInternal, "swipe processing failed"},
+		{"typed error keeps its message", Forbidden("not your match"), CodeForbidden, "not your match"},
+		{"explicit internal keeps its message", Internal(cause), CodeInternal, "internal server error"},
+		{"no rows stays 404", sql.ErrNoRows, CodeNotFound, "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Fallback(tt.err, "swipe processing failed")
+			if got.Code != tt.code || got.Message != tt.message {
+				t.Fatalf("Fallback = %s %q, want %s %q", got.Code, got.Message, tt.code, tt.message)
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	v := &ValidationError{}
+	if v.OrNil() != nil {
+		t.Fatal("empty validation error is not nil")
+	}
+	v.Add("name", "required", "name is required")
+	v.Add("bio", "too_long", "bio is too long")
+
+	err := v.OrNil()
+	if err == nil || err.Error() != "validation failed: name: name is required; bio: bio is too long" {
+		t.Fatalf("err = %v", err)
+	}
+	got := From(fmt.Errorf("update profile: %w", err))
+	if got.Status != http.StatusBadRequest || got.Message != "validation failed" || len(got.Fields) != 2 || got.Fields[1].Field != "bio" {
+		t.Fatalf("From = %+v", got)
+	}
+}
+
+// writeEnvelope - Write'ın yanıtını ve zarfını döndür
+func writeEnvelope(t *testing.T, r *http.Request, err error) (*httptest.ResponseRecorder, Envelope) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	Write(rec, r, err)
+	var env Envelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("body %q: %v", rec.Body.String(), err)
+	}
+	return rec, env
+}
+
+func TestWrite(t *testing.T) {
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(logging.New(&logs, "test", slog.LevelInfo))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	req := httptest.NewRequest(http.MethodPost, "/api/swipe", nil)
+	req = req.WithContext(logging.WithRequestID(req.Context(), "req-42"))
+
+	// Doğrulama hatası alanlarıyla döner
+	v := &ValidationError{}
+	v.Add("direction", "invalid", "direction must be like or pass")
+	v.Add("target_id", "required", "target_id is required")
+	rec, env := writeEnvelope(t, req, v)
+	if rec.Code != http.StatusBadRequest || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("validation response = %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if env.Success || env.Code != CodeValidation || env.Error != "validation failed" || len(env.Fields) != 2 || env.RequestID != "req-42" {
+		t.Fatalf("envelope = %+v", env)
+	}
+	if logs.Len() != 0 {
+		t.Fatalf("4xx error was logged: %s", logs.String())
+	}
+
+	// 5xx sebebi istemciye gitmez, günlüğe yazılır
+	rec, env = writeEnvelope(t, req, errors.New("pq: password authentication failed for user eros"))
+	if rec.Code != http.StatusInternalServerError || env.Code != CodeInternal || env.Error != "internal server error" {
+		t.Fatalf("internal response = %d %+v", rec.Code, env)
+	}
+	if strings.Contains(rec.Body.String(), "pq:") || strings.Contains(rec.Body.String(), `"fields"`) {
+		t.Fatalf("internal response leaked details: %s", rec.Body.String())
+	}
+	if !strings.Contains(logs.String(), "pq: password authentication failed") || !strings.Contains(logs.String(), `"request_id":"req-42"`) {
+		t.Fatalf("cause was not logged: %s", logs.String())
+	}
+}
+
+func TestRouterHandlers(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.Handler
+		status  int
+		code    string
+	}{
+		{"not found", NotFoundHandler(), http.StatusNotFound, CodeNotFound},
+		{"method not allowed", MethodNotAllowedHandler(), http.StatusMethodNotAllowed, CodeMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/nope", nil))
+			var env Envelope
+			if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.status || env.Code != tt.code || env.RequestID != "" {
+				t.Fatalf("response = %d %+v", rec.Code, env)
+			}
+		})
+	}
+}
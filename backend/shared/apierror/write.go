@@ -0,0 +1,58 @@
+// write.go - Hata zarfının yazılması
+package apierror
+
+import (
+	"encoding/json"
+	"eros/shared/logging"
+	"eros/shared/tracing"
+	"log/slog"
+	"net/http"
+)
+
+// Envelope - Tüm servislerin hata yanıtı
+//
+//	{"success": false, "error": "match not found", "code": "not_found", "request_id": "..."}
+//	{"success": false, "error": "validation failed", "code": "validation_failed", "fields": [{"field": "name", "code": "required", "message": "..."}]}
+//
+// "error" eski istemcilerin okuduğu metin olarak kalır; makine için "code" ve "fields" kullanılır.
+type Envelope struct {
+	Success   bool         `json:"success"`
+	Error     string       `json:"error"`
+	Code      string       `json:"code"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// Write - err'i zarf olarak yaz
+// 5xx hataların sebebi istemciye gitmez; günlüğe ve isteğin span'ine yazılır.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	e := From(err)
+	if e.Status >= http.StatusInternalServerError && e.Err != nil {
+		slog.ErrorContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "code", e.Code, "error", e.Err)
+		tracing.FromContext(r.Context()).RecordError(e.Err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(Envelope{
+		Error:     e.Message,
+		Code:      e.Code,
+		Fields:    e.Fields,
+		RequestID: logging.RequestID(r.Context()),
+	})
+}
+
+// NotFoundHandler - Eşleşmeyen rotalar için (router.NotFoundHandler)
+func NotFoundHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, r, NotFound("route not found"))
+	})
+}
+
+// MethodNotAllowedHandler - Rota var ama yöntem yanlış (router.MethodNotAllowedHandler)
+func MethodNotAllowedHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, r, MethodNotAllowed())
+	})
+}
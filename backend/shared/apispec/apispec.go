@@ -0,0 +1,45 @@
+// apispec.go - OpenAPI ve AsyncAPI belgeleri (gateway'de sunulur, sözleşme testlerinde kullanılır)
+//
+// openapi.json gateway üzerinden erişilen HTTP uçlarını, asyncapi.json sohbet WebSocket
+// çerçevelerini tanımlar. Handler'lar değiştiğinde belgeler de güncellenmeli; her servisin
+// handler/contracttest paketi yanıtları bu belgelere göre doğrular.
+package apispec
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Belge dosyaları (gateway'de aynı adlarla sunulur)
+const (
+	OpenAPIFile  = "openapi.json"
+	AsyncAPIFile = "asyncapi.json"
+)
+
+//go:embed openapi.json asyncapi.json
+var files embed.FS
+
+// Load - Belgeyi JSON ağacı olarak oku
+func Load(name string) (map[string]any, error) {
+	data, err := files.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("apispec: parsing %s: %w", name, err)
+	}
+	return doc, nil
+}
+
+// Handler - Belgeleri /openapi.json ve /asyncapi.json yollarında sun
+func Handler() http.Handler {
+	fileServer := http.FileServer(http.FS(files))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Belge sürümle değişir; istemciler her açılışta yeniden doğrulasın
+		w.Header().Set("Cache-Control", "no-cache")
+		fileServer.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,50 @@
+package apispec
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		file, versionKey, section string
+	}{
+		{OpenAPIFile, "openapi", "paths"},
+		{AsyncAPIFile, "asyncapi", "channels"},
+	}
+	for _, tt := range tests {
+		doc, err := Load(tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, _ := doc[tt.versionKey].(string); v == "" {
+			t.Errorf("%s: missing %q version", tt.file, tt.versionKey)
+		}
+		if section, _ := doc[tt.section].(map[string]any); len(section) == 0 {
+			t.Errorf("%s: no %s", tt.file, tt.section)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	for _, file := range []string{OpenAPIFile, AsyncAPIFile} {
+		rec := httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/"+file, nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /%s: status %d", file, rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("GET /%s: Content-Type %q", file, ct)
+		}
+		if cc := rec.Header().Get("Cache-Control"); cc != "no-cache" {
+			t.Errorf("GET /%s: Cache-Control %q", file, cc)
+		}
+		var doc map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+			t.Errorf("GET /%s: %v", file, err)
+		}
+	}
+}
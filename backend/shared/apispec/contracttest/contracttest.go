@@ -0,0 +1,416 @@
+// contracttest.go - Handler'ların OpenAPI/AsyncAPI belgelerine uyumluluk testleri
+//
+// Her servisin handler/contracttest paketi kendi router'ını ve durumlarını bu paketle çalıştırır:
+//
+//	err := errors.Join(
+//		contracttest.CheckRoutes("chat", router),
+//		contracttest.CheckRequest("POST", "/api/messages/send", handler.SendMessageRequest{}),
+//		contracttest.Run("chat", router, []contracttest.Case{
+//			{Name: "send", Method: "POST", Path: "/api/messages/send", Body: `{...}`, Status: 200},
+//		}),
+//	)
+package contracttest
+
+import (
+	"encoding/json"
+	"eros/shared/apispec"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Case - Bir istek ve beklenen durum kodu
+// Durumlar sırayla aynı handler'a gönderilir; önceki durumların yazdığı kayıtlar sonrakilerce görülür.
+type Case struct {
+	Name   string
+	Method string
+	Path   string // Sorgu dizgisi dahil; belgedeki yol şablonuyla eşleşir
+	Body   string
+	Header map[string]string
+	Status int
+}
+
+// operation - Belgedeki bir HTTP işlemi
+type operation struct {
+	key     string // "POST /api/swipe"
+	service string // x-service
+	node    node
+}
+
+// operations - openapi.json'daki tüm işlemler (yol şablonları ile)
+func operations() (map[string]operation, error) {
+	all, err := loadDocs()
+	if err != nil {
+		return nil, err
+	}
+	paths, _ := all[apispec.OpenAPIFile]["paths"].(map[string]any)
+	ops := map[string]operation{}
+	for path, item := range paths {
+		methods, _ := item.(map[string]any)
+		for method, op := range methods {
+			obj, ok := op.(map[string]any)
+			if !ok || strings.HasPrefix(method, "x-") {
+				continue
+			}
+			key := strings.ToUpper(method) + " " + path
+			service, _ := obj["x-service"].(string)
+			ops[key] = operation{key: key, service: service, node: node{doc: apispec.OpenAPIFile, obj: obj}}
+		}
+	}
+	return ops, nil
+}
+
+// findOperation - İstek yolunu şablonlarla eşleştir; sabit parçası çok olan şablon önce gelir
+// (/api/messages/analyze, /api/messages/{match_id}'den önce)
+func findOperation(ops map[string]operation, method, path string) (operation, bool) {
+	best, bestParams := operation{}, -1
+	segments := strings.Split(path, "/")
+	for _, op := range ops {
+		opMethod, template, _ := strings.Cut(op.key, " ")
+		if opMethod != method {
+			continue
+		}
+		parts := strings.Split(template, "/")
+		if len(parts) != len(segments) {
+			continue
+		}
+		params, match := 0, true
+		for i, part := range parts {
+			if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+				params++
+				match = match && segments[i] != ""
+				continue
+			}
+			match = match && part == segments[i]
+		}
+		if match && (bestParams < 0 || params < bestParams) {
+			best, bestParams = op, params
+		}
+	}
+	return best, bestParams >= 0
+}
+
+// Run - Durumları handler'a gönder, yanıtları belgeye göre doğrula
+// service'e ait (x-service) her işlem en az bir durumla denenmelidir.
+func Run(service string, h http.Handler, cases []Case) error {
+	ops, err := operations()
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	covered := map[string]bool{}
+	for _, c := range cases {
+		op, err := runCase(ops, service, h, c)
+		if err != nil {
+			failures = append(failures, c.Name+": "+err.Error())
+		}
+		covered[op.key] = true
+	}
+
+	for _, op := range sortedOperations(ops, service) {
+		if !covered[op.key] {
+			failures = append(failures, op.key+": documented but no contract case exercises it")
+		}
+	}
+
+	if len(failures) > 0 {
+		return errors.New("contracttest: " + service + " API does not match the spec:\n\t" + strings.Join(failures, "\n\t"))
+	}
+	return nil
+}
+
+// runCase - Tek durumu çalıştır ve yanıtı doğrula
+func runCase(ops map[string]operation, service string, h http.Handler, c Case) (operation, error) {
+	path, _, _ := strings.Cut(c.Path, "?")
+	op, ok := findOperation(ops, c.Method, path)
+	if !ok {
+		return op, fmt.Errorf("%s %s is not documented", c.Method, path)
+	}
+	if op.service != service {
+		return op, fmt.Errorf("%s is documented for service %q", op.key, op.service)
+	}
+
+	req := httptest.NewRequest(c.Method, c.Path, strings.NewReader(c.Body))
+	if c.Body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range c.Header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if c.Status != 0 && rec.Code != c.Status {
+		return op, fmt.Errorf("status %d, want %d (body: %s)", rec.Code, c.Status, strings.TrimSpace(rec.Body.String()))
+	}
+	return op, checkResponse(op, rec)
+}
+
+// checkResponse - Durum kodu belgelenmiş mi, gövde şemaya uyuyor mu
+func checkResponse(op operation, rec *httptest.ResponseRecorder) error {
+	responses, _ := op.node.child("responses")
+	resp, ok := responses.child(strconv.Itoa(rec.Code))
+	if !ok {
+		if resp, ok = responses.child("default"); !ok {
+			return fmt.Errorf("status %d is not documented for %s (body: %s)", rec.Code, op.key, strings.TrimSpace(rec.Body.String()))
+		}
+	}
+	resp, err := resolve(resp)
+	if err != nil {
+		return err
+	}
+
+	content, ok := resp.child("content")
+	if !ok {
+		return nil
+	}
+	media, ok := content.child("application/json")
+	if !ok {
+		return nil
+	}
+	if mediaType, _, _ := mime.ParseMediaType(rec.Header().Get("Content-Type")); mediaType != "application/json" {
+		return fmt.Errorf("status %d: Content-Type is %q, want application/json", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	var body any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		return fmt.Errorf("status %d: body is not JSON: %v", rec.Code, err)
+	}
+	schema, _ := media.child("schema")
+	if errs := validate(schema, body, "body"); len(errs) > 0 {
+		return fmt.Errorf("status %d: %s", rec.Code, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// sortedOperations - Servise ait işlemler, anahtara göre sıralı
+func sortedOperations(ops map[string]operation, service string) []operation {
+	var list []operation
+	for _, op := range ops {
+		if op.service == service {
+			list = append(list, op)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].key < list[j].key })
+	return list
+}
+
+// muxParam - {key:.+} gibi desenli parametreleri {key} biçimine indir
+var muxParam = regexp.MustCompile(`\{([^}:]+):[^}]*\}`)
+
+// CheckRoutes - Router ile belge aynı rotaları mı tanımlıyor
+// Servisin belgelenmiş işlemleri router'da olmalı; belgelenmiş bir bölümdeki (/api/<bölüm>)
+// her rota da belgede olmalı. OPTIONS ve yöntem kısıtı olmayan rotalar (WebSocket) atlanır.
+func CheckRoutes(service string, router *mux.Router) error {
+	ops, err := operations()
+	if err != nil {
+		return err
+	}
+	documented := map[string]bool{}
+	areas := map[string]bool{}
+	for _, op := range sortedOperations(ops, service) {
+		documented[op.key] = true
+		_, path, _ := strings.Cut(op.key, " ")
+		areas[area(path)] = true
+	}
+
+	var failures []string
+	routed := map[string]bool{}
+	err = router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		template = muxParam.ReplaceAllString(template, "{$1}")
+		for _, method := range methods {
+			if method == http.MethodOptions {
+				continue
+			}
+			key := method + " " + template
+			routed[key] = true
+			if areas[area(template)] && !documented[key] {
+				failures = append(failures, key+": routed but not documented")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, op := range sortedOperations(ops, service) {
+		if !routed[op.key] {
+			failures = append(failures, op.key+": documented but not routed")
+		}
+	}
+
+	if len(failures) > 0 {
+		return errors.New("contracttest: " + service + " routes do not match the spec:\n\t" + strings.Join(failures, "\n\t"))
+	}
+	return nil
+}
+
+// area - Yolun bölümü: /api/auth/login → /api/auth
+func area(path string) string {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(parts) < 2 {
+		return path
+	}
+	return "/" + parts[0] + "/" + parts[1]
+}
+
+// CheckRequest - İstek gövdesi şeması handler'ın çözdüğü yapıyla aynı alanları mı tanımlıyor
+// v, handler'ın json.Decode ettiği yapının sıfır değeridir (örn. handler.LoginRequest{}).
+func CheckRequest(method, path string, v any) error {
+	ops, err := operations()
+	if err != nil {
+		return err
+	}
+	key := method + " " + path
+	op, ok := ops[key]
+	if !ok {
+		return fmt.Errorf("contracttest: %s is not documented", key)
+	}
+	schema, ok := op.node.child("requestBody")
+	if ok {
+		schema, ok = schema.child("content")
+	}
+	if ok {
+		schema, ok = schema.child("application/json")
+	}
+	if ok {
+		schema, ok = schema.child("schema")
+	}
+	if !ok {
+		return fmt.Errorf("contracttest: %s has no JSON request body", key)
+	}
+	schema, err = resolve(schema)
+	if err != nil {
+		return err
+	}
+	properties, _ := schema.child("properties")
+
+	var failures []string
+	fields := map[string]bool{}
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = true
+		prop, ok := properties.child(name)
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s: handler reads %q but it is not documented", key, name))
+			continue
+		}
+		prop, err := resolve(prop)
+		if err != nil {
+			return err
+		}
+		if want, _ := prop.obj["type"].(string); want != "" && want != schemaType(field.Type) {
+			failures = append(failures, fmt.Sprintf("%s: %q is documented as %s but decoded as %s", key, name, want, field.Type))
+		}
+	}
+	for name := range properties.obj {
+		if !fields[name] {
+			failures = append(failures, fmt.Sprintf("%s: %q is documented but the handler ignores it", key, name))
+		}
+	}
+
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return errors.New("contracttest: request body does not match the spec:\n\t" + strings.Join(failures, "\n\t"))
+	}
+	return nil
+}
+
+// schemaType - Go türünün JSON şema türü
+func schemaType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Pointer:
+		return schemaType(t.Elem())
+	}
+	return "object"
+}
+
+// CheckFrame - WebSocket çerçevesini asyncapi.json'daki kanal işlemine göre doğrula
+// operation "publish" (istemciden sunucuya) veya "subscribe" (sunucudan istemciye) olur.
+func CheckFrame(channel, operation string, frame []byte) error {
+	all, err := loadDocs()
+	if err != nil {
+		return err
+	}
+	root := node{doc: apispec.AsyncAPIFile, obj: all[apispec.AsyncAPIFile]}
+	channels, _ := root.child("channels")
+	ch, ok := channels.child(channel)
+	if !ok {
+		return fmt.Errorf("contracttest: channel %s is not documented", channel)
+	}
+	op, ok := ch.child(operation)
+	if !ok {
+		return fmt.Errorf("contracttest: %s %s is not documented", channel, operation)
+	}
+	message, _ := op.child("message")
+
+	// Mesaj seçenekleri: tek mesaj veya oneOf; çerçeve tam olarak birinin yüküne uymalı
+	var candidates []node
+	if variants, ok := message.obj["oneOf"].([]any); ok {
+		for _, v := range variants {
+			if obj, ok := v.(map[string]any); ok {
+				candidates = append(candidates, node{doc: message.doc, obj: obj})
+			}
+		}
+	} else {
+		candidates = append(candidates, message)
+	}
+
+	var value any
+	if err := json.Unmarshal(frame, &value); err != nil {
+		return fmt.Errorf("contracttest: %s %s frame is not JSON: %v", channel, operation, err)
+	}
+	var mismatches []string
+	for _, candidate := range candidates {
+		candidate, err := resolve(candidate)
+		if err != nil {
+			return err
+		}
+		payload, _ := candidate.child("payload")
+		errs := validate(payload, value, "frame")
+		if len(errs) == 0 {
+			return nil
+		}
+		name, _ := candidate.obj["name"].(string)
+		mismatches = append(mismatches, name+": "+strings.Join(errs, "; "))
+	}
+	return fmt.Errorf("contracttest: %s %s frame %s matches no documented message:\n\t%s", channel, operation, frame, strings.Join(mismatches, "\n\t"))
+}
@@ -0,0 +1,235 @@
+// schema.go - Belgelerdeki JSON şemalarına göre değer doğrulama
+//
+// OpenAPI 3.0 şema alt kümesi: $ref (belgeler arası dahil), type, nullable, properties,
+// required, additionalProperties, items, enum, oneOf, minimum/maximum, minLength ve
+// date-time biçimi. Sözleşme testleri katıdır: additionalProperties tanımlamayan bir
+// nesnede belgelenmemiş alan da sapma sayılır.
+package contracttest
+
+import (
+	"eros/shared/apispec"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// node - Belgedeki bir düğüm ve ait olduğu belge (göreli $ref'ler bu belgeye göre çözülür)
+type node struct {
+	doc string
+	obj map[string]any
+}
+
+var docs struct {
+	once sync.Once
+	all  map[string]map[string]any
+	err  error
+}
+
+// loadDocs - Belgeleri bir kez oku
+func loadDocs() (map[string]map[string]any, error) {
+	docs.once.Do(func() {
+		docs.all = map[string]map[string]any{}
+		for _, name := range []string{apispec.OpenAPIFile, apispec.AsyncAPIFile} {
+			doc, err := apispec.Load(name)
+			if err != nil {
+				docs.err = err
+				return
+			}
+			docs.all[name] = doc
+		}
+	})
+	return docs.all, docs.err
+}
+
+// resolve - $ref içeren düğümü hedefine çöz ("#/a/b" veya "openapi.json#/a/b")
+func resolve(n node) (node, error) {
+	for i := 0; i < 16; i++ {
+		ref, ok := n.obj["$ref"].(string)
+		if !ok {
+			return n, nil
+		}
+		doc, pointer, _ := strings.Cut(ref, "#")
+		if doc == "" {
+			doc = n.doc
+		}
+		all, err := loadDocs()
+		if err != nil {
+			return n, err
+		}
+		var cur any = all[doc]
+		if cur == nil {
+			return n, fmt.Errorf("unknown document in $ref %q", ref)
+		}
+		for _, part := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+			m, ok := cur.(map[string]any)
+			if !ok {
+				return n, fmt.Errorf("unresolvable $ref %q", ref)
+			}
+			cur = m[strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")]
+		}
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return n, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		n = node{doc: doc, obj: obj}
+	}
+	return n, fmt.Errorf("$ref chain too deep")
+}
+
+// child - Düğümün alt nesnesi (yoksa ok=false)
+func (n node) child(key string) (node, bool) {
+	obj, ok := n.obj[key].(map[string]any)
+	return node{doc: n.doc, obj: obj}, ok
+}
+
+// validate - value'yu şemaya göre doğrula; sapmalar "yol: açıklama" olarak döner
+func validate(schema node, value any, path string) []string {
+	schema, err := resolve(schema)
+	if err != nil {
+		return []string{path + ": " + err.Error()}
+	}
+
+	if value == nil {
+		if nullable, _ := schema.obj["nullable"].(bool); nullable || schema.obj["type"] == nil {
+			return nil
+		}
+		return []string{path + ": null is not allowed"}
+	}
+
+	if variants, ok := schema.obj["oneOf"].([]any); ok {
+		matched := 0
+		for _, v := range variants {
+			if obj, ok := v.(map[string]any); ok && len(validate(node{doc: schema.doc, obj: obj}, value, path)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return []string{fmt.Sprintf("%s: matches %d of the oneOf schemas, want exactly 1", path, matched)}
+		}
+		return nil
+	}
+
+	if enum, ok := schema.obj["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			found = found || reflect.DeepEqual(e, value)
+		}
+		if !found {
+			return []string{fmt.Sprintf("%s: %v is not one of %v", path, value, enum)}
+		}
+	}
+
+	typ, _ := schema.obj["type"].(string)
+	switch typ {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: %s, want object", path, jsonType(value))}
+		}
+		return validateObject(schema, obj, path)
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: %s, want array", path, jsonType(value))}
+		}
+		items, _ := schema.child("items")
+		var errs []string
+		for i, item := range arr {
+			errs = append(errs, validate(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return errs
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s: %s, want string", path, jsonType(value))}
+		}
+		if format, _ := schema.obj["format"].(string); format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				return []string{fmt.Sprintf("%s: %q is not a date-time", path, s)}
+			}
+		}
+		if min, ok := schema.obj["minLength"].(float64); ok && float64(len([]rune(s))) < min {
+			return []string{fmt.Sprintf("%s: shorter than %v", path, min)}
+		}
+	case "integer", "number":
+		f, ok := value.(float64)
+		if !ok {
+			return []string{fmt.Sprintf("%s: %s, want %s", path, jsonType(value), typ)}
+		}
+		if typ == "integer" && f != math.Trunc(f) {
+			return []string{fmt.Sprintf("%s: %v is not an integer", path, f)}
+		}
+		if min, ok := schema.obj["minimum"].(float64); ok && f < min {
+			return []string{fmt.Sprintf("%s: %v is less than %v", path, f, min)}
+		}
+		if max, ok := schema.obj["maximum"].(float64); ok && f > max {
+			return []string{fmt.Sprintf("%s: %v is greater than %v", path, f, max)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s: %s, want boolean", path, jsonType(value))}
+		}
+	}
+	return nil
+}
+
+// validateObject - Zorunlu, belgelenmiş ve belgelenmemiş alanları denetle
+func validateObject(schema node, obj map[string]any, path string) []string {
+	var errs []string
+	properties, _ := schema.child("properties")
+
+	if required, ok := schema.obj["required"].([]any); ok {
+		for _, r := range required {
+			if name, _ := r.(string); name != "" {
+				if _, present := obj[name]; !present {
+					errs = append(errs, fmt.Sprintf("%s.%s: required property is missing", path, name))
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if prop, ok := properties.child(name); ok {
+			errs = append(errs, validate(prop, obj[name], path+"."+name)...)
+			continue
+		}
+		switch extra := schema.obj["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				errs = append(errs, fmt.Sprintf("%s.%s: property is not documented", path, name))
+			}
+		case map[string]any:
+			errs = append(errs, validate(node{doc: schema.doc, obj: extra}, obj[name], path+"."+name)...)
+		default:
+			errs = append(errs, fmt.Sprintf("%s.%s: property is not documented", path, name))
+		}
+	}
+	return errs
+}
+
+// jsonType - Hata mesajları için JSON tür adı
+func jsonType(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
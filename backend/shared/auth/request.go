@@ -0,0 +1,20 @@
+// request.go - Gateway'in doğruladığı kullanıcının servislerde okunması
+package auth
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// UserIDHeader - Gateway'in auth: required rotalarda doğrulanmış kullanıcıyı ilettiği başlık
+// Gateway istemcinin gönderdiği başlığı siler; servisler kullanıcıyı yalnızca buradan almalıdır.
+const UserIDHeader = "X-User-ID"
+
+// RequestUserID - İsteği yapan doğrulanmış kullanıcı (başlık yoksa veya geçersizse ok false)
+func RequestUserID(r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.Header.Get(UserIDHeader))
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,143 @@
+// token.go - HMAC-SHA256 imzalı oturum jetonları (JWT, HS256)
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("auth: invalid token")
+	ErrExpiredToken = errors.New("auth: token expired")
+	ErrNoSecret     = errors.New("auth: JWT_SECRET is not set")
+)
+
+// Claims - Jetonun taşıdığı bilgiler
+type Claims struct {
+	UserID    int
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// jwtClaims - JWT gövdesi (RFC 7519 alan adları)
+type jwtClaims struct {
+	Sub string `json:"sub"`
+	Iat int64  `json:"iat"`
+	Exp int64  `json:"exp"`
+}
+
+// jwtHeader - Sadece HS256 üretilir ve kabul edilir
+const jwtHeader = `{"alg":"HS256","typ":"JWT"}`
+
+// Signer - Jeton üretir ve doğrular; user-service ve gateway aynı gizli anahtarı kullanır
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewSigner - ttl jetonların geçerlilik süresidir
+func NewSigner(secret []byte, ttl time.Duration) *Signer {
+	return &Signer{secret: secret, ttl: ttl}
+}
+
+// SignerFromEnv - JWT_SECRET ve JWT_TTL_HOURS (varsayılan 24) ile imzalayıcı
+func SignerFromEnv() (*Signer, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil, ErrNoSecret
+	}
+
+	ttl := 24 * time.Hour
+	if v := os.Getenv("JWT_TTL_HOURS"); v != "" {
+		if hours, err := strconv.ParseFloat(v, 64); err == nil && hours > 0 {
+			ttl = time.Duration(hours * float64(time.Hour))
+		}
+	}
+	return NewSigner([]byte(secret), ttl), nil
+}
+
+// Sign - Kullanıcı için jeton üret
+func (s *Signer) Sign(userID int, now time.Time) (string, error) {
+	body, err := json.Marshal(jwtClaims{
+		Sub: strconv.Itoa(userID),
+		Iat: now.Unix(),
+		Exp: now.Add(s.ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := encode([]byte(jwtHeader)) + "." + encode(body)
+	return unsigned + "." + encode(s.mac(unsigned)), nil
+}
+
+// Verify - İmzayı ve süreyi denetle
+func (s *Signer) Verify(token string, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrInvalidToken
+	}
+
+	unsigned := parts[0] + "." + parts[1]
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, s.mac(unsigned)) {
+		return Claims{}, ErrInvalidToken
+	}
+
+	// İmza doğru olsa da başlık HS256 değilse reddet (alg karışıklığı saldırıları)
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil || h.Alg != "HS256" {
+		return Claims{}, ErrInvalidToken
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	var c jwtClaims
+	if err := json.Unmarshal(body, &c); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	userID, err := strconv.Atoi(c.Sub)
+	if err != nil || userID <= 0 {
+		return Claims{}, ErrInvalidToken
+	}
+
+	claims := Claims{UserID: userID, IssuedAt: time.Unix(c.Iat, 0), ExpiresAt: time.Unix(c.Exp, 0)}
+	if !now.Before(claims.ExpiresAt) {
+		return Claims{}, ErrExpiredToken
+	}
+	return claims, nil
+}
+
+// BearerToken - "Authorization: Bearer <jeton>" başlığındaki jeton (yoksa boş)
+func BearerToken(header string) string {
+	const prefix = "Bearer "
+	if len(header) > len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+		return strings.TrimSpace(header[len(prefix):])
+	}
+	return ""
+}
+
+func (s *Signer) mac(unsigned string) []byte {
+	m := hmac.New(sha256.New, s.secret)
+	m.Write([]byte(unsigned))
+	return m.Sum(nil)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
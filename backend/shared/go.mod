@@ -1,7 +1,8 @@
 module eros/shared
 
-go 1.20
+go 1.21
 
 require (
 	github.com/gorilla/mux v1.8.0
-) 
\ No newline at end of file
+	github.com/mattn/go-sqlite3 v1.14.28
+)
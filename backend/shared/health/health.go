@@ -0,0 +1,152 @@
+// health.go - Servislerin canlılık (/healthz) ve hazırlık (/readyz) uçları
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"eros/shared/migrate"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Durumlar
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"    // Kritik olmayan bir bağımlılık (örn. AI) çalışmıyor
+	StatusDown     = "unavailable" // Kritik bir bağımlılık (örn. veritabanı) çalışmıyor
+)
+
+// CheckFunc - Bağımlılığı denetler; çalışmıyorsa hata döner
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name     string
+	critical bool
+	fn       CheckFunc
+}
+
+// Checker - Bir servisin bağımlılık kontrolleri
+type Checker struct {
+	service string
+	timeout time.Duration
+	checks  []check
+}
+
+// New - timeout her kontrolün en fazla süresidir
+func New(service string, timeout time.Duration) *Checker {
+	return &Checker{service: service, timeout: timeout}
+}
+
+// Add - Kontrol ekle; kritik kontrol başarısızsa servis hazır sayılmaz
+func (c *Checker) Add(name string, critical bool, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, critical: critical, fn: fn})
+}
+
+// Report - /readyz yanıtı
+type Report struct {
+	Service string                 `json:"service"`
+	Status  string                 `json:"status"`
+	Checks  map[string]CheckResult `json:"checks"`
+}
+
+// CheckResult - Tek bir kontrolün sonucu
+type CheckResult struct {
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Ready - Kritik kontrollerin hepsi geçti mi
+func (r Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// Run - Kontrolleri paralel çalıştır
+func (c *Checker) Run(ctx context.Context) Report {
+	report := Report{Service: c.service, Status: StatusOK, Checks: make(map[string]CheckResult, len(c.checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, chk := range c.checks {
+		wg.Add(1)
+		go func(chk check) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := chk.fn(ctx)
+			result := CheckResult{Status: StatusOK, Critical: chk.critical, LatencyMS: milliseconds(time.Since(start))}
+			if err != nil {
+				result.Status = StatusDegraded
+				if chk.critical {
+					result.Status = StatusDown
+				}
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[chk.name] = result
+			switch {
+			case result.Status == StatusDown:
+				report.Status = StatusDown
+			case result.Status == StatusDegraded && report.Status == StatusOK:
+				report.Status = StatusDegraded
+			}
+		}(chk)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// Live - /healthz: süreç ayakta ve istek kabul ediyor (bağımlılıklara bakılmaz)
+func (c *Checker) Live(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"service": c.service, "status": StatusOK})
+}
+
+// Ready - /readyz: kritik bağımlılıklardan biri çalışmıyorsa 503
+func (c *Checker) Ready(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+// Ping - Veritabanı bağlantısı
+func Ping(db interface {
+	PingContext(ctx context.Context) error
+}) CheckFunc {
+	return db.PingContext
+}
+
+// Migrations - Bekleyen şema göçü kalmamış olmalı
+func Migrations(m *migrate.Migrator) CheckFunc {
+	return func(ctx context.Context) error {
+		pending, err := m.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d pending migrations", pending)
+		}
+		return nil
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,163 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"eros/shared/migrate"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func pass(ctx context.Context) error { return nil }
+
+func fail(ctx context.Context) error { return errors.New("connection refused") }
+
+func TestRunAggregation(t *testing.T) {
+	type dep struct {
+		name     string
+		critical bool
+		fn       CheckFunc
+	}
+	tests := []struct {
+		name   string
+		checks []dep
+		want   string
+		ready  bool
+	}{
+		{"no checks", nil, StatusOK, true},
+		{"all pass", []dep{{"db", true, pass}, {"ai", false, pass}}, StatusOK, true},
+		{"optional fails", []dep{{"db", true, pass}, {"ai", false, fail}}, StatusDegraded, true},
+		{"critical fails", []dep{{"db", true, fail}, {"ai", false, pass}}, StatusDown, false},
+		{"both fail", []dep{{"db", true, fail}, {"ai", false, fail}}, StatusDown, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New("user-service", time.Second)
+			for _, d := range tt.checks {
+				c.Add(d.name, d.critical, d.fn)
+			}
+			report := c.Run(context.Background())
+			if report.Service != "user-service" || report.Status != tt.want || report.Ready() != tt.ready {
+				t.Fatalf("report = %+v, want status %q ready %v", report, tt.want, tt.ready)
+			}
+			if len(report.Checks) != len(tt.checks) {
+				t.Fatalf("got %d check results, want %d", len(report.Checks), len(tt.checks))
+			}
+		})
+	}
+}
+
+func TestRunReportsFailures(t *testing.T) {
+	c := New("match-service", 20*time.Millisecond)
+	c.Add("db", true, pass)
+	c.Add("ai", false, fail)
+	// Süreyi aşan kontrol bağlamın iptaliyle hata döner
+	c.Add("cache", true, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := c.Run(context.Background())
+	if report.Status != StatusDown {
+		t.Fatalf("status = %q, want %q", report.Status, StatusDown)
+	}
+
+	tests := []struct {
+		name     string
+		status   string
+		critical bool
+		err      string
+	}{
+		{"db", StatusOK, true, ""},
+		{"ai", StatusDegraded, false, "connection refused"},
+		{"cache", StatusDown, true, context.DeadlineExceeded.Error()},
+	}
+	for _, tt := range tests {
+		got, ok := report.Checks[tt.name]
+		if !ok {
+			t.Fatalf("no result for %q", tt.name)
+		}
+		if got.Status != tt.status || got.Critical != tt.critical || got.Error != tt.err {
+			t.Errorf("%s = %+v, want status %q critical %v error %q", tt.name, got, tt.status, tt.critical, tt.err)
+		}
+	}
+	if report.Checks["cache"].LatencyMS < 20 {
+		t.Errorf("cache latency = %vms, want at least the timeout", report.Checks["cache"].LatencyMS)
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	c := New("chat-service", time.Second)
+	c.Add("db", true, fail)
+
+	// Canlılık bağımlılıklara bakmaz
+	rec := httptest.NewRecorder()
+	c.Live(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"status":"ok"`) {
+		t.Fatalf("live = %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	c.Ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("ready with failing db = %d, want 503", rec.Code)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != StatusDown || report.Checks["db"].Error != "connection refused" {
+		t.Fatalf("report = %+v", report)
+	}
+
+	// Kritik olmayan hata hazırlığı bozmaz
+	c = New("chat-service", time.Second)
+	c.Add("ai", false, fail)
+	rec = httptest.NewRecorder()
+	c.Ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"status":"degraded"`) {
+		t.Fatalf("ready with failing ai = %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMigrationsCheck(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "health.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := migrate.New(db, []migrate.Migration{
+		{Version: 1, Name: "one", Up: migrate.SQL(`CREATE TABLE one (id INTEGER)`), Down: migrate.SQL(`DROP TABLE one`)},
+	}, migrate.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := Migrations(m)
+	if err := check(ctx); err == nil || !strings.Contains(err.Error(), "1 pending") {
+		t.Fatalf("check before migrating = %v, want 1 pending", err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := check(ctx); err != nil {
+		t.Fatalf("check after migrating = %v", err)
+	}
+
+	if err := Ping(db)(ctx); err != nil {
+		t.Fatalf("ping = %v", err)
+	}
+}
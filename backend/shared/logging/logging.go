@@ -0,0 +1,101 @@
+// logging.go - Yapılandırılmış JSON günlükleri (log/slog), istek kimliği ve hassas veri maskeleme
+package logging
+
+import (
+	"context"
+	"eros/shared/tracing"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Setup - Varsayılan slog günlükçüsünü kur (stdout'a JSON, seviye LOG_LEVEL)
+// slog.SetDefault sonrası log paketiyle yazılan satırlar da aynı biçimde ve maskelenerek yazılır.
+func Setup(service string) {
+	slog.SetDefault(New(os.Stdout, service, levelFromEnv()))
+}
+
+// New - w'ya JSON yazan, bağlamdaki istek kimliğini ekleyen ve hassas alanları maskeleyen günlükçü
+func New(w io.Writer, service string, level slog.Level) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level, ReplaceAttr: redact})
+	return slog.New(contextHandler{handler}).With("service", service)
+}
+
+// levelFromEnv - LOG_LEVEL: debug, info (varsayılan), warn, error
+func levelFromEnv() slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(os.Getenv("LOG_LEVEL"))); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// contextHandler - Kayda bağlamdaki istek ve iz kimliğini ekler
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := tracing.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID.String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// secretKeys - Değeri hiç yazılmayan alanlar (mesaj gövdeleri, şifreler, jetonlar)
+var secretKeys = map[string]bool{
+	"message":  true,
+	"body":     true,
+	"content":  true,
+	"prompt":   true,
+	"password": true,
+	"token":    true,
+}
+
+var emailPattern = regexp.MustCompile(`([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+// redact - Gizli alanları sil, metinlerdeki e-posta adreslerini maskele
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.MessageKey {
+		return slog.String(a.Key, MaskEmails(a.Value.String()))
+	}
+	if secretKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, "[redacted]")
+	}
+	if a.Value.Kind() == slog.KindString {
+		return slog.String(a.Key, MaskEmails(a.Value.String()))
+	}
+	if a.Value.Kind() == slog.KindAny {
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, MaskEmails(err.Error()))
+		}
+	}
+	return a
+}
+
+// MaskEmails - "ayse.yilmaz@example.com" → "a***@example.com"
+func MaskEmails(s string) string {
+	if !strings.Contains(s, "@") {
+		return s
+	}
+	return emailPattern.ReplaceAllString(s, "$1***@$2")
+}
+
+// Fatal - Hata kaydı yaz ve süreci sonlandır (log.Fatal karşılığı)
+func Fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,170 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"eros/shared/tracing"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// logLine - Günlükçünün yazdığı tek satır
+func logLine(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("log output %q: %v", buf.String(), err)
+	}
+	buf.Reset()
+	return line
+}
+
+func TestRedaction(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value any
+		want  any
+	}{
+		{"password", "password", "hunter2", "[redacted]"},
+		{"token key is case insensitive", "Token", "eyJhbGciOi", "[redacted]"},
+		{"message body", "message", "selam, numaram 0555", "[redacted]"},
+		{"prompt", "prompt", "kullanıcı profili...", "[redacted]"},
+		{"non-string secret", "body", 42, "[redacted]"},
+		{"email in string", "to", "ayse.yilmaz@example.com", "a***@example.com"},
+		{"email in error", "error", errors.New("smtp: rejected mehmet@eros.app"), "smtp: rejected m***@eros.app"},
+		{"plain string kept", "status", "active", "active"},
+		{"number kept", "user_id", 7, float64(7)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			New(&buf, "user-service", slog.LevelInfo).Info("event", tt.key, tt.value)
+			line := logLine(t, &buf)
+			if got := line[tt.key]; got != tt.want {
+				t.Fatalf("%s = %v, want %v", tt.key, got, tt.want)
+			}
+			if line["service"] != "user-service" {
+				t.Fatalf("service = %v", line["service"])
+			}
+		})
+	}
+}
+
+func TestRedactionInMessageAndGroups(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "user-service", slog.LevelInfo)
+
+	logger.Info("verification mail sent to zeynep@example.com")
+	if got := logLine(t, &buf)["msg"]; got != "verification mail sent to z***@example.com" {
+		t.Fatalf("msg = %v", got)
+	}
+
+	// Gruplar ve With ile eklenen alanlar da maskelenir
+	logger.With("password", "secret").WithGroup("user").Info("login", "email", "ali@example.com", "token", "abc")
+	line := logLine(t, &buf)
+	if line["password"] != "[redacted]" {
+		t.Fatalf("password = %v", line["password"])
+	}
+	user, _ := line["user"].(map[string]any)
+	if user["email"] != "a***@example.com" || user["token"] != "[redacted]" {
+		t.Fatalf("user group = %v", user)
+	}
+}
+
+func TestMaskEmails(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"no address here", "no address here"},
+		{"a@b.co", "a***@b.co"},
+		{"from x.y+tag@mail.example.org to q@eros.app", "from x***@mail.example.org to q***@eros.app"},
+		{"@handle is not an email", "@handle is not an email"},
+	}
+	for _, tt := range tests {
+		if got := MaskEmails(tt.in); got != tt.want {
+			t.Errorf("MaskEmails(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestContextIDs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "chat-service", slog.LevelDebug)
+
+	h := http.Header{}
+	h.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := WithRequestID(tracing.Extract(context.Background(), h), "req-1")
+
+	logger.DebugContext(ctx, "handled")
+	line := logLine(t, &buf)
+	if line["request_id"] != "req-1" || line["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("line = %v, want request and trace ids", line)
+	}
+
+	logger.Info("no context")
+	line = logLine(t, &buf)
+	if _, ok := line["request_id"]; ok {
+		t.Fatalf("line without context has request_id: %v", line)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(New(&buf, "api-gateway", slog.LevelInfo))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	var seen string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+		if r.Header.Get(RequestIDHeader) != seen {
+			t.Errorf("request header = %q, context = %q", r.Header.Get(RequestIDHeader), seen)
+		}
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("upstream down"))
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"valid id is kept", "abc-123_x.y", true},
+		{"missing id is generated", "", false},
+		{"unsafe id is replaced", "bad id\nforged=1", false},
+		{"overlong id is replaced", strings.Repeat("a", 129), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/users/7", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(RequestIDHeader)
+			if id != seen {
+				t.Fatalf("response id = %q, handler saw %q", id, seen)
+			}
+			if tt.keep && id != tt.header {
+				t.Fatalf("id = %q, want %q", id, tt.header)
+			}
+			if !tt.keep && (id == tt.header || len(id) != 32) {
+				t.Fatalf("id = %q, want a new 32 hex id", id)
+			}
+
+			line := logLine(t, &buf)
+			if line["msg"] != "request" || line["level"] != "ERROR" || line["status"] != float64(http.StatusBadGateway) ||
+				line["bytes"] != float64(len("upstream down")) || line["path"] != "/api/users/7" || line["request_id"] != id {
+				t.Fatalf("access log = %v", line)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// request.go - İstek kimliği (X-Request-ID) ve erişim günlüğü
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"eros/shared/server"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader - Gateway'in ürettiği ya da istemciden aldığı ve servislere ilettiği kimlik
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID - Bağlama istek kimliği ekle (arka plan işlerine taşımak için de kullanılır)
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID - Bağlamdaki istek kimliği (yoksa boş)
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID - 128 bit rastgele kimlik
+func NewRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID - Dışarıdan gelen kimlik günlüklere yazılabilir mi (kısa, yazdırılabilir ASCII)
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// Middleware - İstek kimliğini al ya da üret, bağlama ve yanıta ekle, erişim günlüğü yaz
+// Kimlik isteğin başlığına da yazılır; gateway'in vekil ettiği istekler onu upstream'e taşır.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = NewRequestID()
+		}
+		r.Header.Set(RequestIDHeader, id)
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx := WithRequestID(r.Context(), id)
+		rec := server.NewResponseRecorder(w)
+		start := time.Now()
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		level := slog.LevelInfo
+		if rec.Status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(ctx, level, "request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.Status),
+			slog.Int64("bytes", rec.Bytes),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("remote_addr", r.RemoteAddr),
+		)
+	})
+}
@@ -0,0 +1,27 @@
+// http.go - HTTP istek süresi ve durum kodu histogramı
+package metrics
+
+import (
+	"eros/shared/server"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var httpDuration = NewHistogram("http_request_duration_seconds",
+	"HTTP request latency by route template, method and status code.",
+	DefBuckets, "route", "method", "status")
+
+// Middleware - router.Use ile eklenir; rota etiketi mux şablonudur (/api/users/{id}), yol değil
+// WebSocket'e yükseltilen istekler bağlantı ömrünü ölçeceği için sayılmaz.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := server.NewResponseRecorder(w)
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+		if rec.Hijacked {
+			return
+		}
+		httpDuration.Observe(time.Since(start).Seconds(), server.RouteTemplate(r), r.Method, strconv.Itoa(rec.Status))
+	})
+}
@@ -0,0 +1,357 @@
+// metrics.go - Prometheus metin biçiminde sayaç, gösterge ve histogramlar
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets - HTTP istekleri için varsayılan histogram sınırları (saniye)
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Registry - /metrics ucunda yayınlanan metrik aileleri
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]family
+}
+
+// family - Tek bir metrik adı ve onun etiketli serileri
+type family interface {
+	write(w *bufio.Writer)
+}
+
+// NewRegistry - Boş kayıt defteri
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]family)}
+}
+
+// Default - Paket düzeyindeki New* fonksiyonlarının kullandığı kayıt defteri
+var Default = NewRegistry()
+
+func (r *Registry) register(name string, f family) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.families[name]; ok {
+		panic("metrics: duplicate metric " + name)
+	}
+	r.families[name] = f
+}
+
+// WriteTo - Tüm metrikleri Prometheus metin biçiminde (0.0.4) yaz
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	families := r.families
+	r.mu.Unlock()
+	sort.Strings(names)
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, name := range names {
+		families[name].write(bw)
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// Handler - Kayıt defterini yayınlayan /metrics ucu
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		r.WriteTo(w)
+	})
+}
+
+// Handler - Default kayıt defterinin /metrics ucu
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// desc - Metriğin adı, açıklaması ve etiket adları
+type desc struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (d desc) header(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, escapeHelp(d.help), d.name, d.kind)
+}
+
+// key - Etiket değerlerinin seri anahtarı (sayı uyuşmazlığı programlama hatasıdır)
+func (d desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", d.name, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// maxSeries - Bir metrikte tutulan en fazla etiket kombinasyonu
+// Etiket değerleri istemciden gelebilir (HTTP metodu, kaydırma yönü); sınır dolunca yeni
+// kombinasyonlar tek bir taşma serisinde toplanır, böylece bellek ve /metrics çıktısı büyümez.
+const maxSeries = 500
+
+// overflowValue - Taşma serisinin tüm etiketlerinin değeri
+const overflowValue = "_overflow"
+
+// lookup - values serisini bul ya da oluştur; sınır dolduysa taşma serisini döndür (kilit çağıranda)
+func lookup[T any](m map[string]*T, d desc, values []string, create func(values []string) *T) *T {
+	k := d.key(values)
+	if s, ok := m[k]; ok {
+		return s
+	}
+	if len(m) >= maxSeries {
+		values = make([]string, len(d.labels))
+		for i := range values {
+			values[i] = overflowValue
+		}
+		k = d.key(values)
+		if s, ok := m[k]; ok {
+			return s
+		}
+	} else {
+		values = append([]string(nil), values...)
+	}
+	s := create(values)
+	m[k] = s
+	return s
+}
+
+// labelPairs - {a="x",b="y"} (extra varsa sona eklenir, örn. le="0.5")
+func (d desc) labelPairs(values []string, extra ...string) string {
+	if len(d.labels) == 0 && len(extra) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, label := range d.labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(label)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(values[i]))
+		b.WriteByte('"')
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		b.WriteString(extra[i])
+		b.WriteString(`="`)
+		b.WriteString(extra[i+1])
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// series - Bir etiket kombinasyonunun değeri
+type series struct {
+	values []string
+	value  float64
+}
+
+// valueVec - Sayaç ve göstergelerin ortak seri tablosu
+type valueVec struct {
+	desc
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+func (v *valueVec) add(delta float64, values []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	lookup(v.series, v.desc, values, newSeries).value += delta
+}
+
+func (v *valueVec) set(value float64, values []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	lookup(v.series, v.desc, values, newSeries).value = value
+}
+
+func newSeries(values []string) *series {
+	return &series{values: values}
+}
+
+func (v *valueVec) write(w *bufio.Writer) {
+	v.header(w)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, k := range sortedKeys(v.series) {
+		s := v.series[k]
+		fmt.Fprintf(w, "%s%s %s\n", v.name, v.labelPairs(s.values), formatFloat(s.value))
+	}
+}
+
+// Counter - Yalnızca artan sayaç
+type Counter struct {
+	valueVec
+}
+
+// NewCounter - Default kayıt defterinde sayaç (labels: etiket adları)
+func NewCounter(name, help string, labels ...string) *Counter {
+	return Default.NewCounter(name, help, labels...)
+}
+
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{valueVec{desc: desc{name, help, "counter", labels}, series: make(map[string]*series)}}
+	r.register(name, c)
+	return c
+}
+
+// Inc - Etiket değerleri verilen seriyi 1 artır
+func (c *Counter) Inc(values ...string) {
+	c.add(1, values)
+}
+
+// Add - Seriyi v kadar artır (negatif değerler yok sayılır)
+func (c *Counter) Add(v float64, values ...string) {
+	if v < 0 {
+		return
+	}
+	c.add(v, values)
+}
+
+// Gauge - Artıp azalabilen değer
+type Gauge struct {
+	valueVec
+}
+
+// NewGauge - Default kayıt defterinde gösterge
+func NewGauge(name, help string, labels ...string) *Gauge {
+	return Default.NewGauge(name, help, labels...)
+}
+
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{valueVec{desc: desc{name, help, "gauge", labels}, series: make(map[string]*series)}}
+	r.register(name, g)
+	return g
+}
+
+func (g *Gauge) Set(v float64, values ...string) {
+	g.set(v, values)
+}
+
+func (g *Gauge) Add(v float64, values ...string) {
+	g.add(v, values)
+}
+
+func (g *Gauge) Inc(values ...string) {
+	g.add(1, values)
+}
+
+func (g *Gauge) Dec(values ...string) {
+	g.add(-1, values)
+}
+
+// Histogram - Gözlemlerin kova dağılımı, toplamı ve sayısı
+type Histogram struct {
+	desc
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64 // Kova başına (kümülatif değil)
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram - Default kayıt defterinde histogram (buckets artan sırada)
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return Default.NewHistogram(name, help, buckets, labels...)
+}
+
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+	h := &Histogram{desc: desc{name, help, "histogram", labels}, buckets: b, series: make(map[string]*histogramSeries)}
+	r.register(name, h)
+	return h
+}
+
+// Observe - Gözlem ekle (süreler saniye cinsinden)
+func (h *Histogram) Observe(v float64, values ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := lookup(h.series, h.desc, values, func(values []string) *histogramSeries {
+		return &histogramSeries{values: values, counts: make([]uint64, len(h.buckets))}
+	})
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.sum += v
+	s.count++
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.header(w)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, k := range sortedKeys(h.series) {
+		s := h.series[k]
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(s.values, "le", formatFloat(upper)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(s.values, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelPairs(s.values), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelPairs(s.values), s.count)
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// exposition - Kayıt defterinin metin çıktısı
+func exposition(t *testing.T, r *Registry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	n, err := r.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatalf("WriteTo reported %d bytes, wrote %d", n, buf.Len())
+	}
+	return buf.String()
+}
+
+func TestExposition(t *testing.T) {
+	r := NewRegistry()
+	swipes := r.NewCounter("swipes_total", "Swipes by direction.", "direction")
+	sockets := r.NewGauge("websocket_connections", "Open WebSocket connections.")
+	latency := r.NewHistogram("ai_request_duration_seconds", "AI latency.\nPer task.", []float64{1, 0.1, 0.5}, "task")
+
+	swipes.Inc("like")
+	swipes.Inc("like")
+	swipes.Add(3, "pass")
+	swipes.Add(-1, "pass") // Sayaç azalmaz
+	sockets.Inc()
+	sockets.Inc()
+	sockets.Dec()
+	latency.Observe(0.05, `profile "matching"`)
+	latency.Observe(0.3, `profile "matching"`)
+	latency.Observe(2, `profile "matching"`)
+
+	want := `# HELP ai_request_duration_seconds AI latency.\nPer task.
+# TYPE ai_request_duration_seconds histogram
+ai_request_duration_seconds_bucket{task="profile \"matching\"",le="0.1"} 1
+ai_request_duration_seconds_bucket{task="profile \"matching\"",le="0.5"} 2
+ai_request_duration_seconds_bucket{task="profile \"matching\"",le="1"} 2
+ai_request_duration_seconds_bucket{task="profile \"matching\"",le="+Inf"} 3
+ai_request_duration_seconds_sum{task="profile \"matching\""} 2.35
+ai_request_duration_seconds_count{task="profile \"matching\""} 3
+# HELP swipes_total Swipes by direction.
+# TYPE swipes_total counter
+swipes_total{direction="like"} 2
+swipes_total{direction="pass"} 3
+# HELP websocket_connections Open WebSocket connections.
+# TYPE websocket_connections gauge
+websocket_connections 1
+`
+	if got := exposition(t, r); got != want {
+		t.Fatalf("exposition =\n%s\nwant\n%s", got, want)
+	}
+
+	sockets.Set(7)
+	if got := exposition(t, r); !strings.Contains(got, "\nwebsocket_connections 7\n") {
+		t.Fatalf("gauge after Set:\n%s", got)
+	}
+}
+
+func TestCardinalityGuard(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounter("requests_total", "Requests.", "method", "status")
+	latency := r.NewHistogram("request_duration_seconds", "Latency.", []float64{1}, "method")
+
+	// İstemcinin uydurduğu metotlar sınırı doldurur
+	for i := 0; i < maxSeries+100; i++ {
+		method := fmt.Sprintf("M%d", i)
+		requests.Inc(method, "200")
+		latency.Observe(0.5, method)
+	}
+	// Var olan seriler güncellenmeye devam eder
+	requests.Inc("M0", "200")
+
+	if n := len(requests.series); n != maxSeries+1 {
+		t.Fatalf("counter has %d series, want %d plus one overflow series", n, maxSeries)
+	}
+	if n := len(latency.series); n != maxSeries+1 {
+		t.Fatalf("histogram has %d series, want %d plus one overflow series", n, maxSeries)
+	}
+
+	out := exposition(t, r)
+	for _, line := range []string{
+		`requests_total{method="M0",status="200"} 2`,
+		`requests_total{method="_overflow",status="_overflow"} 100`,
+		`request_duration_seconds_count{method="_overflow"} 100`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("exposition is missing %s", line)
+		}
+	}
+	if strings.Contains(out, `method="M`+fmt.Sprint(maxSeries)+`"`) {
+		t.Error("a series beyond the limit was exported")
+	}
+}
+
+func TestMisuse(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(r *Registry)
+		want string
+	}{
+		{"duplicate name", func(r *Registry) {
+			r.NewCounter("x_total", "X.")
+			r.NewGauge("x_total", "X.")
+		}, "duplicate metric x_total"},
+		{"label count", func(r *Registry) {
+			r.NewCounter("y_total", "Y.", "a", "b").Inc("only-one")
+		}, "y_total expects 2 label values, got 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if p := recover(); p == nil || !strings.Contains(fmt.Sprint(p), tt.want) {
+					t.Fatalf("panic = %v, want %q", p, tt.want)
+				}
+			}()
+			tt.fn(NewRegistry())
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("up_total", "Up.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "up_total 1\n") {
+		t.Fatalf("body = %s", rec.Body.String())
+	}
+}
+
+func TestMiddlewareUsesRouteTemplate(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(Middleware)
+	router.HandleFunc("/api/metrics-test/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	for _, id := range []string{"1", "2", "3"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/metrics-test/"+id, nil))
+	}
+
+	out := exposition(t, Default)
+	if !strings.Contains(out, `http_request_duration_seconds_count{route="/api/metrics-test/{id}",method="GET",status="404"} 3`) {
+		t.Fatalf("exposition has no series for the route template:\n%s", out)
+	}
+	if strings.Contains(out, "/api/metrics-test/1") {
+		t.Fatal("raw path leaked into a label")
+	}
+}
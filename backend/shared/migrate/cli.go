@@ -0,0 +1,118 @@
+// cli.go - Servislerin "migrate" alt komutu
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// Usage - Alt komut kullanımı
+const Usage = `usage: <service> migrate <command>
+
+commands:
+  up [version]   apply pending migrations (up to and including version)
+  down [steps]   roll back the last applied migrations (default 1)
+  status         list migrations and whether they are applied`
+
+// Command - "migrate" alt komutunu çalıştır (args alt komuttan sonraki argümanlardır)
+func Command(ctx context.Context, m *Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command\n%s", Usage)
+	}
+
+	switch args[0] {
+	case "up":
+		target, err := optionalInt(args[1:], 0)
+		if err != nil {
+			return err
+		}
+		applied, err := m.UpTo(ctx, target)
+		for _, mig := range applied {
+			fmt.Fprintf(out, "applied  %d %s\n", mig.Version, mig.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+		return err
+
+	case "down":
+		steps, err := optionalInt(args[1:], 1)
+		if err != nil {
+			return err
+		}
+		rolledBack, err := m.Down(ctx, steps)
+		for _, mig := range rolledBack {
+			fmt.Fprintf(out, "rolled back  %d %s\n", mig.Version, mig.Name)
+		}
+		if err == nil && len(rolledBack) == 0 {
+			fmt.Fprintln(out, "nothing to roll back")
+		}
+		return err
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state, at := "pending", ""
+			if s.Applied {
+				state, at = "applied", s.AppliedAt.Local().Format(time.RFC3339)
+			}
+			if s.Unknown {
+				state = "unknown"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, at)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], Usage)
+	}
+}
+
+// AutoMigrateFromEnv - AUTO_MIGRATE=false değilse servisler açılışta göçleri uygular
+func AutoMigrateFromEnv() bool {
+	return os.Getenv("AUTO_MIGRATE") != "false"
+}
+
+// Startup - Servis açılışında göçleri uygula
+// auto false ise göç uygulanmaz; bekleyen göç varsa servis eski şemayla açılmasın diye hata döner.
+func Startup(ctx context.Context, m *Migrator, auto bool) error {
+	if !auto {
+		pending, err := m.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d pending migrations; run \"migrate up\" or set AUTO_MIGRATE=true", pending)
+		}
+		return nil
+	}
+
+	applied, err := m.Up(ctx)
+	for _, mig := range applied {
+		slog.InfoContext(ctx, "applied migration", "version", mig.Version, "name", mig.Name)
+	}
+	return err
+}
+
+// optionalInt - İsteğe bağlı pozitif tam sayı argümanı
+func optionalInt(args []string, fallback int) (int, error) {
+	if len(args) == 0 {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid argument %q: expected a positive number", args[0])
+	}
+	return n, nil
+}
@@ -0,0 +1,464 @@
+// migrate.go - Numaralı, geri alınabilir şema göç (migration) çalıştırıcısı
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"eros/shared/sqldb"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrLocked         = errors.New("migrate: could not acquire the migration lock")
+	ErrIrreversible   = errors.New("migrate: migration cannot be rolled back")
+	ErrUnknownVersion = errors.New("migrate: database has migrations this build does not know")
+)
+
+// Migration - Tek bir şema değişikliği
+// Up ve Down kendi işlemlerinde (transaction) çalışır; versiyon kaydı aynı işlemde yazılır,
+// böylece yarım kalan bir göç kayıt bırakmaz. Down nil ise göç geri alınamaz.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *sql.Tx) error
+	Down    func(tx *sql.Tx) error
+}
+
+// SQL - Sırayla çalıştırılan SQL ifadelerinden göç adımı oluştur
+func SQL(statements ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, stmt := range statements {
+			if _, err := tx.Exec(stmt); err != nil {
+				return fmt.Errorf("%w\n%s", err, strings.TrimSpace(stmt))
+			}
+		}
+		return nil
+	}
+}
+
+// Options - Çalıştırıcı ayarları (sıfır değerler varsayılanları kullanır)
+type Options struct {
+	Dialect     sqldb.Dialect // Varsayılan SQLite
+	Table       string        // Varsayılan "schema_migrations"; aynı veritabanını paylaşan servisler farklı tablo kullanmalı
+	LegacyTable string        // Table boşken bu tablodaki kayıtlar bu göçlerle eşleşiyorsa devralınır (bkz. legacyVersions)
+	LockTimeout time.Duration // Kilidi beklerken vazgeçme süresi, varsayılan 30s
+	StaleLock   time.Duration // Bu süreden eski kilit çökmüş bir süreçten kalmış sayılır, varsayılan 10m
+}
+
+// Status - Bir göçün uygulanma durumu
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Unknown   bool // Veritabanında kayıtlı ama bu sürümde tanımlı değil
+}
+
+// Migrator - Bir veritabanının göçlerini yönetir
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	opts       Options
+	owner      string
+}
+
+// New - Göçleri doğrula (pozitif, benzersiz versiyonlar) ve çalıştırıcıyı oluştur
+func New(db *sql.DB, migrations []Migration, opts Options) (*Migrator, error) {
+	if opts.Dialect.Name == "" {
+		opts.Dialect = sqldb.SQLite
+	}
+	if opts.Table == "" {
+		opts.Table = "schema_migrations"
+	}
+	if opts.LockTimeout <= 0 {
+		opts.LockTimeout = 30 * time.Second
+	}
+	if opts.StaleLock <= 0 {
+		opts.StaleLock = 10 * time.Minute
+	}
+
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i, m := range sorted {
+		if m.Version <= 0 {
+			return nil, fmt.Errorf("migrate: invalid version %d (%s)", m.Version, m.Name)
+		}
+		if m.Up == nil {
+			return nil, fmt.Errorf("migrate: migration %d (%s) has no Up step", m.Version, m.Name)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("migrate: duplicate version %d", m.Version)
+		}
+	}
+
+	host, _ := os.Hostname()
+	return &Migrator{
+		db:         db,
+		migrations: sorted,
+		opts:       opts,
+		owner:      fmt.Sprintf("%s:%d", host, os.Getpid()),
+	}, nil
+}
+
+// Up - Bekleyen tüm göçleri uygula
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.UpTo(ctx, 0)
+}
+
+// UpTo - target versiyonuna kadar (dahil) bekleyen göçleri uygula; 0 en son versiyon demektir
+func (m *Migrator) UpTo(ctx context.Context, target int) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(done map[int]time.Time) error {
+		if unknown := m.unknownVersions(done); len(unknown) > 0 {
+			return fmt.Errorf("%w: %v", ErrUnknownVersion, unknown)
+		}
+
+		for _, mig := range m.migrations {
+			if target > 0 && mig.Version > target {
+				break
+			}
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, mig, true); err != nil {
+				return err
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down - Son uygulanan steps kadar göçü geri al
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var rolledBack []Migration
+	err := m.withLock(ctx, func(done map[int]time.Time) error {
+		if unknown := m.unknownVersions(done); len(unknown) > 0 {
+			return fmt.Errorf("%w: %v", ErrUnknownVersion, unknown)
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := done[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == nil {
+				return fmt.Errorf("%w: %d (%s)", ErrIrreversible, mig.Version, mig.Name)
+			}
+			if err := m.apply(ctx, mig, false); err != nil {
+				return err
+			}
+			rolledBack = append(rolledBack, mig)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Status - Tanımlı ve veritabanında kayıtlı tüm göçler (versiyon sırasıyla)
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureTables(ctx); err != nil {
+		return nil, err
+	}
+	done, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(done) == 0 {
+		// Kilit alınmadığı için devralma burada yazılmaz, ilk Up/Down'da kalıcı olur
+		legacy, err := m.legacyVersions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range legacy {
+			done[row.version] = row.appliedAt
+		}
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if at, ok := done[mig.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = &at
+		}
+		statuses = append(statuses, s)
+	}
+	for _, version := range m.unknownVersions(done) {
+		at := done[version]
+		statuses = append(statuses, Status{Version: version, Applied: true, AppliedAt: &at, Unknown: true})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+
+	return statuses, nil
+}
+
+// Pending - Uygulanmamış göç sayısı
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, s := range statuses {
+		if !s.Applied {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// apply - Göç adımını ve versiyon kaydını tek işlemde çalıştır
+func (m *Migrator) apply(ctx context.Context, mig Migration, up bool) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	step, record := mig.Down, m.rebind(`DELETE FROM `+m.opts.Table+` WHERE version = ?`)
+	args := []interface{}{mig.Version}
+	direction := "down"
+	if up {
+		step, record = mig.Up, m.rebind(`INSERT INTO `+m.opts.Table+` (version, name, applied_at) VALUES (?, ?, ?)`)
+		args = append(args, mig.Name, time.Now().UTC())
+		direction = "up"
+	}
+
+	if err := step(tx); err != nil {
+		return fmt.Errorf("migrate: %s %d (%s): %w", direction, mig.Version, mig.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("migrate: recording %s %d (%s): %w", direction, mig.Version, mig.Name, err)
+	}
+	return tx.Commit()
+}
+
+// withLock - Kilidi al, uygulanmış versiyonları oku ve fn'i çalıştır
+// Aynı veritabanını kullanan birden çok örnek aynı anda başlarsa göçleri sadece biri uygular.
+func (m *Migrator) withLock(ctx context.Context, fn func(done map[int]time.Time) error) error {
+	if err := m.ensureTables(ctx); err != nil {
+		return err
+	}
+	if err := m.lock(ctx); err != nil {
+		return err
+	}
+	defer m.unlock()
+
+	done, err := m.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+	if len(done) == 0 {
+		if done, err = m.adoptLegacy(ctx); err != nil {
+			return err
+		}
+	}
+	return fn(done)
+}
+
+func (m *Migrator) lockTable() string {
+	return m.opts.Table + "_lock"
+}
+
+// ensureTables - Versiyon ve kilit tablolarını oluştur
+func (m *Migrator) ensureTables(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS `+m.opts.Table+` (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS `+m.lockTable()+` (
+			id INTEGER PRIMARY KEY,
+			owner TEXT NOT NULL,
+			locked_at TIMESTAMP NOT NULL
+		)
+	`)
+	return err
+}
+
+// lock - Tek satırlık kilit kaydını ekle; başkası tutuyorsa bekle
+// Kilidi tutan süreç çökerse kayıt StaleLock süresinden sonra devralınır.
+func (m *Migrator) lock(ctx context.Context) error {
+	deadline := time.Now().Add(m.opts.LockTimeout)
+	insert := m.rebind(`INSERT INTO ` + m.lockTable() + ` (id, owner, locked_at) VALUES (1, ?, ?)`)
+
+	for {
+		_, err := m.db.ExecContext(ctx, insert, m.owner, time.Now().UTC())
+		if err == nil {
+			return nil
+		}
+
+		var owner string
+		var lockedAt time.Time
+		scanErr := m.db.QueryRowContext(ctx, `SELECT owner, locked_at FROM `+m.lockTable()+` WHERE id = 1`).Scan(&owner, &lockedAt)
+		switch {
+		case errors.Is(scanErr, sql.ErrNoRows):
+			// Kilit bu arada bırakıldı; ekleme başka bir sebeple başarısız olduysa sonsuza dek denenmez
+			if time.Now().After(deadline) {
+				return fmt.Errorf("%w: %v", ErrLocked, err)
+			}
+		case scanErr != nil:
+			return fmt.Errorf("migrate: reading lock: %w (insert: %v)", scanErr, err)
+		case time.Since(lockedAt) > m.opts.StaleLock:
+			m.db.ExecContext(ctx, m.rebind(`DELETE FROM `+m.lockTable()+` WHERE id = 1 AND owner = ?`), owner)
+			continue
+		case time.Now().After(deadline):
+			return fmt.Errorf("%w (held by %s since %s)", ErrLocked, owner, lockedAt.Format(time.RFC3339))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+func (m *Migrator) unlock() {
+	m.db.Exec(m.rebind(`DELETE FROM `+m.lockTable()+` WHERE id = 1 AND owner = ?`), m.owner)
+}
+
+// appliedVersions - Kayıtlı versiyonlar ve uygulanma zamanları
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int]time.Time, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM `+m.opts.Table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		done[version] = at
+	}
+	return done, rows.Err()
+}
+
+// unknownVersions - Veritabanında olup bu sürümde tanımlı olmayan versiyonlar
+func (m *Migrator) unknownVersions(done map[int]time.Time) []int {
+	known := make(map[int]bool, len(m.migrations))
+	for _, mig := range m.migrations {
+		known[mig.Version] = true
+	}
+
+	var unknown []int
+	for version := range done {
+		if !known[version] {
+			unknown = append(unknown, version)
+		}
+	}
+	sort.Ints(unknown)
+	return unknown
+}
+
+// rebind - "?" parametrelerini lehçenin biçimine çevir
+func (m *Migrator) rebind(query string) string {
+	return m.opts.Dialect.Rebind(query)
+}
+
+// legacyRow - Eski ortak tablodaki bir kayıt
+type legacyRow struct {
+	version   int
+	name      string
+	appliedAt time.Time
+}
+
+// legacyVersions - LegacyTable bu çalıştırıcıya aitse kayıtları
+// Servisler eskiden tek "schema_migrations" tablosunu paylaşıyordu. Tablo ancak her kaydın
+// versiyonu ve adı bu çalıştırıcının bir göçüyle eşleşiyorsa devralınır; başka bir servisin
+// kayıtlarını da içeren ortak tablo hiç devralınmaz ve göçler baştan (baseline tekrar
+// çalıştırılabilir olduğu için güvenle) uygulanır.
+func (m *Migrator) legacyVersions(ctx context.Context) ([]legacyRow, error) {
+	if m.opts.LegacyTable == "" || m.opts.LegacyTable == m.opts.Table {
+		return nil, nil
+	}
+	exists, err := m.tableExists(ctx, m.opts.LegacyTable)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	names := make(map[int]string, len(m.migrations))
+	for _, mig := range m.migrations {
+		names[mig.Version] = mig.Name
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, name, applied_at FROM `+m.opts.LegacyTable)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var legacy []legacyRow
+	for rows.Next() {
+		var row legacyRow
+		if err := rows.Scan(&row.version, &row.name, &row.appliedAt); err != nil {
+			return nil, err
+		}
+		if name, ok := names[row.version]; !ok || name != row.name {
+			return nil, rows.Close()
+		}
+		legacy = append(legacy, row)
+	}
+	return legacy, rows.Err()
+}
+
+// adoptLegacy - Eşleşen eski kayıtları Table'a kopyala (kilit altında, Table boşken bir kez)
+func (m *Migrator) adoptLegacy(ctx context.Context) (map[int]time.Time, error) {
+	legacy, err := m.legacyVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[int]time.Time, len(legacy))
+	if len(legacy) == 0 {
+		return done, nil
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	insert := m.rebind(`INSERT INTO ` + m.opts.Table + ` (version, name, applied_at) VALUES (?, ?, ?)`)
+	for _, row := range legacy {
+		if _, err := tx.ExecContext(ctx, insert, row.version, row.name, row.appliedAt); err != nil {
+			return nil, fmt.Errorf("migrate: adopting %d (%s) from %s: %w", row.version, row.name, m.opts.LegacyTable, err)
+		}
+		done[row.version] = row.appliedAt
+	}
+	return done, tx.Commit()
+}
+
+// tableExists - Tablo veritabanında var mı
+func (m *Migrator) tableExists(ctx context.Context, table string) (bool, error) {
+	query := `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`
+	if m.opts.Dialect.Name == sqldb.Postgres.Name {
+		query = `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = ?`
+	}
+
+	var n int
+	if err := m.db.QueryRowContext(ctx, m.rebind(query), table).Scan(&n); err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
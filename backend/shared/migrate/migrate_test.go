@@ -0,0 +1,392 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// testDB - Geçici dizinde dosya tabanlı SQLite (eşzamanlı bağlantılar aynı veritabanını görür)
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "migrate.db")+"?_busy_timeout=5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// testMigrations - Her adımı tablo oluşturup silen üç göç
+func testMigrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "one", Up: SQL(`CREATE TABLE one (id INTEGER)`), Down: SQL(`DROP TABLE one`)},
+		{Version: 2, Name: "two", Up: SQL(`CREATE TABLE two (id INTEGER)`), Down: SQL(`DROP TABLE two`)},
+		{Version: 3, Name: "three", Up: SQL(`CREATE TABLE three (id INTEGER)`), Down: SQL(`DROP TABLE three`)},
+	}
+}
+
+func newMigrator(t *testing.T, db *sql.DB, migrations []Migration, opts Options) *Migrator {
+	t.Helper()
+	m, err := New(db, migrations, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// versions - Göçlerin versiyon numaraları
+func versions(migrations []Migration) []int {
+	out := make([]int, len(migrations))
+	for i, mig := range migrations {
+		out[i] = mig.Version
+	}
+	return out
+}
+
+func hasTable(t *testing.T, db *sql.DB, table string) bool {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n > 0
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNewRejectsInvalidMigrations(t *testing.T) {
+	db := testDB(t)
+	tests := []struct {
+		name       string
+		migrations []Migration
+	}{
+		{name: "zero version", migrations: []Migration{{Version: 0, Name: "zero", Up: SQL()}}},
+		{name: "missing up", migrations: []Migration{{Version: 1, Name: "one"}}},
+		{name: "duplicate version", migrations: []Migration{{Version: 1, Name: "a", Up: SQL()}, {Version: 1, Name: "b", Up: SQL()}}},
+	}
+	for _, tt := range tests {
+		if _, err := New(db, tt.migrations, Options{}); err == nil {
+			t.Errorf("%s: New succeeded", tt.name)
+		}
+	}
+}
+
+func TestUpToAndDown(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	m := newMigrator(t, db, testMigrations(), Options{})
+
+	applied, err := m.UpTo(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(applied); !equalInts(got, []int{1, 2}) {
+		t.Fatalf("UpTo(2) applied %v, want [1 2]", got)
+	}
+	if hasTable(t, db, "three") {
+		t.Fatal("UpTo(2) applied migration 3")
+	}
+	if pending, err := m.Pending(ctx); err != nil || pending != 1 {
+		t.Fatalf("pending = %d, err = %v; want 1", pending, err)
+	}
+
+	applied, err = m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(applied); !equalInts(got, []int{3}) {
+		t.Fatalf("Up applied %v, want [3]", got)
+	}
+	if applied, err := m.Up(ctx); err != nil || len(applied) != 0 {
+		t.Fatalf("second Up applied %v, err = %v; want nothing", versions(applied), err)
+	}
+
+	// Geri alma en son uygulanandan başlar
+	rolledBack, err := m.Down(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(rolledBack); !equalInts(got, []int{3, 2}) {
+		t.Fatalf("Down(2) rolled back %v, want [3 2]", got)
+	}
+	if !hasTable(t, db, "one") || hasTable(t, db, "two") || hasTable(t, db, "three") {
+		t.Fatal("Down(2) left the wrong tables")
+	}
+
+	// Fazla adım istenirse yalnızca uygulanmış olanlar geri alınır
+	rolledBack, err = m.Down(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(rolledBack); !equalInts(got, []int{1}) {
+		t.Fatalf("Down(10) rolled back %v, want [1]", got)
+	}
+	if pending, err := m.Pending(ctx); err != nil || pending != 3 {
+		t.Fatalf("pending = %d, err = %v; want 3", pending, err)
+	}
+}
+
+func TestFailedMigrationIsNotRecorded(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	migrations := append(testMigrations()[:1], Migration{
+		Version: 2,
+		Name:    "broken",
+		Up:      SQL(`CREATE TABLE two (id INTEGER)`, `NOT SQL`),
+	})
+	m := newMigrator(t, db, migrations, Options{})
+
+	applied, err := m.Up(ctx)
+	if err == nil {
+		t.Fatal("Up succeeded with a broken migration")
+	}
+	if got := versions(applied); !equalInts(got, []int{1}) {
+		t.Fatalf("applied %v, want [1]", got)
+	}
+	// Başarısız göçün işlemi geri alınır; yarım şema kalmaz
+	if hasTable(t, db, "two") {
+		t.Fatal("broken migration left its table behind")
+	}
+	if pending, err := m.Pending(ctx); err != nil || pending != 1 {
+		t.Fatalf("pending = %d, err = %v; want 1", pending, err)
+	}
+}
+
+func TestDownIrreversible(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	migrations := testMigrations()
+	migrations[2].Down = nil
+	m := newMigrator(t, db, migrations, Options{})
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Down(ctx, 1); !errors.Is(err, ErrIrreversible) {
+		t.Fatalf("err = %v, want ErrIrreversible", err)
+	}
+	if !hasTable(t, db, "three") {
+		t.Fatal("irreversible migration was rolled back")
+	}
+}
+
+func TestUnknownRecordedVersion(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	// Daha yeni bir sürüm 3. göçü uygulamış; bu sürüm yalnızca 1 ve 2'yi biliyor
+	if _, err := newMigrator(t, db, testMigrations(), Options{}).Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	m := newMigrator(t, db, testMigrations()[:2], Options{})
+
+	if _, err := m.Up(ctx); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("Up err = %v, want ErrUnknownVersion", err)
+	}
+	if _, err := m.Down(ctx, 1); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("Down err = %v, want ErrUnknownVersion", err)
+	}
+	if !hasTable(t, db, "two") {
+		t.Fatal("Down rolled back a migration despite the unknown version")
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 3 {
+		t.Fatalf("status lists %d migrations, want 3", len(statuses))
+	}
+	last := statuses[2]
+	if last.Version != 3 || !last.Unknown || !last.Applied {
+		t.Fatalf("status = %+v, want version 3 applied and unknown", last)
+	}
+	for _, s := range statuses[:2] {
+		if s.Unknown || !s.Applied {
+			t.Errorf("status = %+v, want applied and known", s)
+		}
+	}
+}
+
+func TestLockHeldByAnotherProcess(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	m := newMigrator(t, db, testMigrations(), Options{LockTimeout: 300 * time.Millisecond})
+
+	if err := m.ensureTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO schema_migrations_lock (id, owner, locked_at) VALUES (1, 'other:1', ?)`, time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Up(ctx); !errors.Is(err, ErrLocked) {
+		t.Fatalf("err = %v, want ErrLocked", err)
+	}
+	if hasTable(t, db, "one") {
+		t.Fatal("migration applied without the lock")
+	}
+
+	// Başkasının kilidi bırakılmaz
+	var owner string
+	if err := db.QueryRow(`SELECT owner FROM schema_migrations_lock WHERE id = 1`).Scan(&owner); err != nil || owner != "other:1" {
+		t.Fatalf("lock owner = %q, err = %v; want other:1", owner, err)
+	}
+}
+
+func TestStaleLockIsTakenOver(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	m := newMigrator(t, db, testMigrations(), Options{LockTimeout: time.Second, StaleLock: time.Minute})
+
+	if err := m.ensureTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Çökmüş bir süreçten kalan kilit
+	if _, err := db.Exec(`INSERT INTO schema_migrations_lock (id, owner, locked_at) VALUES (1, 'crashed:1', ?)`, time.Now().UTC().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(applied) != 3 {
+		t.Fatalf("applied %v, want all 3", versions(applied))
+	}
+	// Kilit iş bitince bırakılır
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM schema_migrations_lock`).Scan(&n); err != nil || n != 0 {
+		t.Fatalf("lock rows = %d, err = %v; want 0", n, err)
+	}
+}
+
+func TestConcurrentUpAppliesOnce(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	runs := map[int]int{}
+	counted := func(mig Migration) Migration {
+		up := mig.Up
+		mig.Up = func(tx *sql.Tx) error {
+			mu.Lock()
+			runs[mig.Version]++
+			mu.Unlock()
+			return up(tx)
+		}
+		return mig
+	}
+	var migrations []Migration
+	for _, mig := range testMigrations() {
+		migrations = append(migrations, counted(mig))
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		m := newMigrator(t, db, migrations, Options{LockTimeout: 10 * time.Second})
+		m.owner = fmt.Sprintf("%s#%d", m.owner, i) // Aynı süreçteki örnekler farklı sahip olsun
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := m.Up(ctx)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, mig := range migrations {
+		if runs[mig.Version] != 1 {
+			t.Errorf("migration %d ran %d times, want 1", mig.Version, runs[mig.Version])
+		}
+	}
+}
+
+// legacyTable - Eski ortak tabloyu verilen kayıtlarla oluştur
+func legacyTable(t *testing.T, db *sql.DB, rows map[int]string) {
+	t.Helper()
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	for version, name := range rows {
+		if _, err := db.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`, version, name, time.Now().UTC()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLegacyTableAdoption(t *testing.T) {
+	tests := []struct {
+		name        string
+		legacy      map[int]string
+		wantApplied []int
+	}{
+		{name: "matching rows are adopted", legacy: map[int]string{1: "one", 2: "two"}, wantApplied: []int{3}},
+		{name: "name mismatch is ignored", legacy: map[int]string{1: "one", 2: "matches_status"}, wantApplied: []int{1, 2, 3}},
+		{name: "other service versions are ignored", legacy: map[int]string{1: "one", 7: "seven"}, wantApplied: []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testDB(t)
+			ctx := context.Background()
+			legacyTable(t, db, tt.legacy)
+			// Devralınan göçlerin tabloları eski çalıştırıcı tarafından oluşturulmuştu
+			for _, mig := range testMigrations() {
+				if tt.legacy[mig.Version] == mig.Name {
+					if _, err := db.Exec(`CREATE TABLE ` + mig.Name + ` (id INTEGER)`); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			migrations := testMigrations()
+			for i := range migrations {
+				// Devralınmayan tablolar baştan oluşturulabilsin
+				migrations[i].Up = SQL(`CREATE TABLE IF NOT EXISTS ` + migrations[i].Name + ` (id INTEGER)`)
+			}
+			m := newMigrator(t, db, migrations, Options{Table: "svc_schema_migrations", LegacyTable: "schema_migrations"})
+
+			applied, err := m.Up(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := versions(applied); !equalInts(got, tt.wantApplied) {
+				t.Fatalf("applied %v, want %v", got, tt.wantApplied)
+			}
+
+			var recorded int
+			if err := db.QueryRow(`SELECT COUNT(*) FROM svc_schema_migrations`).Scan(&recorded); err != nil || recorded != 3 {
+				t.Fatalf("recorded = %d, err = %v; want 3", recorded, err)
+			}
+			// Eski tablo olduğu gibi kalır (başka servisler hâlâ okuyor olabilir)
+			var legacy int
+			if err := db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&legacy); err != nil || legacy != len(tt.legacy) {
+				t.Fatalf("legacy rows = %d, err = %v; want %d", legacy, err, len(tt.legacy))
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// corpus_test.go - Moderasyon motorunun beklenen davranışını belgeleyen örnek korpus
+package moderation
+
+// corpusCase - Korpus örneği
+type corpusCase struct {
+	Lang string   // "tr" veya "en"
+	Text string   // Değerlendirilecek metin
+	Want Severity // Beklenen aksiyon
+}
+
+// corpusFailure - Beklenen aksiyonla eşleşmeyen örnek
+type corpusFailure struct {
+	Case corpusCase
+	Got  Decision
+}
+
+// corpus - Türkçe ve İngilizce örnek korpus
+var corpus = []corpusCase{
+	// Masum mesajlar (eski anahtar kelime listesine takılanlar dahil)
+	{Lang: "tr", Text: "Merhaba! Nasılsın? Bugün hava çok güzel, birlikte bir şeyler yapalım mı?", Want: SeverityAllow},
+	{Lang: "tr", Text: "Bu akşam yemeğe gidelim, para benden", Want: SeverityAllow},
+	{Lang: "tr", Text: "Topu vur, gol olsun!", Want: SeverityAllow},
+	{Lang: "tr", Text: "Konser bomba gibiydi", Want: SeverityAllow},
+	{Lang: "tr", Text: "Ben gay bir erkeğim ve ciddi ilişki arıyorum", Want: SeverityAllow},
+	{Lang: "tr", Text: "Seni eve götürebilirim", Want: SeverityAllow},
+	{Lang: "tr", Text: "Seksen yaşında bir dedem var", Want: SeverityAllow},
+	{Lang: "tr", Text: "Amina ile tanıştın mı?", Want: SeverityAllow},
+	{Lang: "tr", Text: "Fotoğraf çekmeyi çok seviyorum", Want: SeverityAllow},
+	{Lang: "en", Text: "I'm free this weekend, want to grab coffee?", Want: SeverityAllow},
+	{Lang: "en", Text: "I got tickets for the concert", Want: SeverityAllow},
+	{Lang: "en", Text: "That class was a killer, let's assess it later", Want: SeverityAllow},
+	{Lang: "en", Text: "Nice pic! Where was it taken?", Want: SeverityAllow},
+	{Lang: "en", Text: "I love shiitake mushrooms and cocktails", Want: SeverityAllow},
+	{Lang: "en", Text: "Money can't buy happiness", Want: SeverityAllow},
+	{Lang: "en", Text: "I'm a proud gay man", Want: SeverityAllow},
+
+	// Küfür: maskelenir
+	{Lang: "tr", Text: "siktir git", Want: SeverityMask},
+	{Lang: "tr", Text: "SİKTİR", Want: SeverityMask},
+	{Lang: "tr", Text: "orospunun çocuğu", Want: SeverityMask},
+	{Lang: "tr", Text: "0r0spu", Want: SeverityMask},
+	{Lang: "tr", Text: "amına koyayım", Want: SeverityMask},
+	{Lang: "tr", Text: "AMINA", Want: SeverityMask},
+	{Lang: "tr", Text: "yavsak herif", Want: SeverityMask},
+	{Lang: "en", Text: "fuck this", Want: SeverityMask},
+	{Lang: "en", Text: "f.u.c.k you", Want: SeverityMask},
+	{Lang: "en", Text: "f u c k", Want: SeverityMask},
+	{Lang: "en", Text: "fuuuuuck", Want: SeverityMask},
+	{Lang: "en", Text: "sh!t happens", Want: SeverityMask},
+	{Lang: "en", Text: "$h1t", Want: SeverityMask},
+	{Lang: "en", Text: "you @ss", Want: SeverityMask},
+	{Lang: "en", Text: "f\u200buck", Want: SeverityMask},
+
+	// Hafif hakaret: uyarı
+	{Lang: "tr", Text: "ne kadar salaksın", Want: SeverityWarn},
+	{Lang: "en", Text: "that was stupid", Want: SeverityWarn},
+	{Lang: "tr", Text: "Annem bana dün para gönderdi, çok sevindim", Want: SeverityWarn},
+	{Lang: "tr", Text: "Bunu satın al, pişman olmazsın", Want: SeverityWarn},
+	{Lang: "en", Text: "Buy now and get 50% off", Want: SeverityWarn},
+
+	// Engellenenler
+	{Lang: "tr", Text: "seni öldüreceğim", Want: SeverityBlock},
+	{Lang: "tr", Text: "SENİ ÖLDÜRÜRÜM", Want: SeverityBlock},
+	{Lang: "tr", Text: "seni oldururum", Want: SeverityBlock},
+	{Lang: "tr", Text: "bana para gönderir misin acil", Want: SeverityBlock},
+	{Lang: "tr", Text: "Harika bir yatırım fırsatı var", Want: SeverityBlock},
+	{Lang: "tr", Text: "ibne", Want: SeverityBlock},
+	{Lang: "en", Text: "I will kill you", Want: SeverityBlock},
+	{Lang: "en", Text: "just kys", Want: SeverityBlock},
+	{Lang: "en", Text: "send nudes", Want: SeverityBlock},
+	{Lang: "en", Text: "Please send me money for the flight", Want: SeverityBlock},
+	{Lang: "en", Text: "Great investment opportunity in crypto", Want: SeverityBlock},
+}
+
+// checkCorpus - Motoru korpusa karşı çalıştır ve beklenmeyen sonuçları döndür
+func checkCorpus(e *Engine, cases []corpusCase) []corpusFailure {
+	var failures []corpusFailure
+	for _, c := range cases {
+		decision := e.Evaluate(c.Text)
+		if decision.Action != c.Want {
+			failures = append(failures, corpusFailure{Case: c, Got: decision})
+		}
+	}
+	return failures
+}
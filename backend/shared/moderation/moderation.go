@@ -0,0 +1,330 @@
+// moderation.go - İçerik moderasyon motoru
+package moderation
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Severity - Bir eşleşmenin ciddiyeti ve uygulanacak aksiyon
+type Severity int
+
+const (
+	SeverityAllow Severity = iota // Mesaj olduğu gibi iletilir
+	SeverityWarn                  // Mesaj iletilir, kullanıcı uyarılır
+	SeverityMask                  // Eşleşen kısım maskelenerek iletilir
+	SeverityBlock                 // Mesaj engellenir
+)
+
+var severityNames = map[Severity]string{
+	SeverityAllow: "allow",
+	SeverityWarn:  "warn",
+	SeverityMask:  "mask",
+	SeverityBlock: "block",
+}
+
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// MarshalText - JSON'da "block" gibi isimlerle yazılır
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText - "allow", "warn", "mask", "block" değerlerini okur
+func (s *Severity) UnmarshalText(text []byte) error {
+	parsed, ok := ParseSeverity(string(text))
+	if !ok {
+		return fmt.Errorf("unknown severity: %s", text)
+	}
+	*s = parsed
+	return nil
+}
+
+// ParseSeverity - "allow", "warn", "mask", "block" değerlerini çözümle
+func ParseSeverity(name string) (Severity, bool) {
+	for s, n := range severityNames {
+		if n == strings.ToLower(strings.TrimSpace(name)) {
+			return s, true
+		}
+	}
+	return SeverityAllow, false
+}
+
+// Category - İçerik kategorisi
+type Category string
+
+const (
+	CategoryProfanity  Category = "profanity"
+	CategoryHate       Category = "hate"
+	CategoryViolence   Category = "violence"
+	CategoryHarassment Category = "harassment"
+	CategorySexual     Category = "sexual"
+	CategorySpam       Category = "spam"
+)
+
+// Rule - Moderasyon kuralı
+// Phrase boşlukla ayrılmış bir veya daha fazla kelimeden oluşur; "*" ile biten
+// kelimeler önek olarak eşleşir ("orospu*" → "orospunun").
+type Rule struct {
+	Phrase   string
+	Category Category
+	Severity Severity
+}
+
+// Match - Metinde bulunan bir kural eşleşmesi
+type Match struct {
+	Rule     Rule     `json:"-"`
+	Category Category `json:"category"`
+	Severity Severity `json:"severity"` // Kategori politikası uygulanmış ciddiyet
+	Text     string   `json:"text"`     // Orijinal metindeki eşleşen kısım
+	Start    int      `json:"start"`
+	End      int      `json:"end"`
+}
+
+// Decision - Moderasyon kararı
+type Decision struct {
+	Action  Severity `json:"action"`
+	Matches []Match  `json:"matches,omitempty"`
+	Masked  string   `json:"masked"` // Mask kararında maskelenmiş metin, diğer durumlarda orijinal metin
+}
+
+// Allowed - Mesaj iletilebilir mi
+func (d Decision) Allowed() bool {
+	return d.Action != SeverityBlock
+}
+
+// Categories - Karardaki kategoriler (tekrarsız, ciddiyete göre sıralı değil)
+func (d Decision) Categories() []Category {
+	seen := make(map[Category]bool)
+	var categories []Category
+	for _, m := range d.Matches {
+		if !seen[m.Category] {
+			seen[m.Category] = true
+			categories = append(categories, m.Category)
+		}
+	}
+	return categories
+}
+
+// TopCategory - Kararı belirleyen en ciddi eşleşmenin kategorisi
+func (d Decision) TopCategory() Category {
+	var top Category
+	severity := SeverityAllow
+	for _, m := range d.Matches {
+		if m.Severity > severity {
+			severity = m.Severity
+			top = m.Category
+		}
+	}
+	return top
+}
+
+// Config - Motor yapılandırması
+type Config struct {
+	Rules     []Rule
+	Allowlist []string // Hiçbir kurala takılmayacak kelimeler ("*" ile biten önekler)
+	// Policies - Kategori bazında ciddiyet; tanımlıysa kuralın kendi ciddiyetinin yerine geçer.
+	// SeverityAllow kategoriyi tamamen kapatır.
+	Policies map[Category]Severity
+}
+
+// Engine - Moderasyon motoru
+type Engine struct {
+	rules     []compiledRule
+	allowlist []pattern
+	policies  map[Category]Severity
+}
+
+type pattern struct {
+	folded string
+	long   string
+	lower  string
+	prefix bool
+}
+
+type compiledRule struct {
+	rule  Rule
+	words []pattern
+}
+
+// New - Yapılandırmadan motor oluştur
+func New(cfg Config) *Engine {
+	e := &Engine{policies: make(map[Category]Severity)}
+
+	for _, rule := range cfg.Rules {
+		var words []pattern
+		for _, w := range strings.Fields(rule.Phrase) {
+			words = append(words, compilePattern(w))
+		}
+		if len(words) > 0 {
+			e.rules = append(e.rules, compiledRule{rule: rule, words: words})
+		}
+	}
+
+	for _, w := range cfg.Allowlist {
+		e.allowlist = append(e.allowlist, compilePattern(w))
+	}
+
+	for category, severity := range cfg.Policies {
+		e.policies[category] = severity
+	}
+
+	return e
+}
+
+var (
+	defaultEngine     *Engine
+	defaultEngineOnce sync.Once
+)
+
+// Default - Varsayılan kurallarla paylaşılan motor
+func Default() *Engine {
+	defaultEngineOnce.Do(func() {
+		defaultEngine = New(DefaultConfig())
+	})
+	return defaultEngine
+}
+
+// WithPolicies - Aynı kurallarla, verilen kategori politikaları eklenmiş yeni bir motor döndür
+func (e *Engine) WithPolicies(policies map[Category]Severity) *Engine {
+	clone := &Engine{
+		rules:     e.rules,
+		allowlist: e.allowlist,
+		policies:  make(map[Category]Severity),
+	}
+	for category, severity := range e.policies {
+		clone.policies[category] = severity
+	}
+	for category, severity := range policies {
+		clone.policies[category] = severity
+	}
+	return clone
+}
+
+// Evaluate - Metni değerlendir ve moderasyon kararı üret
+func (e *Engine) Evaluate(text string) Decision {
+	tokens := tokenize(text)
+
+	allowed := make([]bool, len(tokens))
+	for i, t := range tokens {
+		allowed[i] = e.isAllowlisted(t)
+	}
+
+	decision := Decision{Action: SeverityAllow, Masked: text}
+
+	for _, cr := range e.rules {
+		severity := cr.rule.Severity
+		if override, ok := e.policies[cr.rule.Category]; ok {
+			severity = override
+		}
+		if severity == SeverityAllow {
+			continue
+		}
+
+		for i := 0; i+len(cr.words) <= len(tokens); i++ {
+			if !matchesAt(cr.words, tokens, allowed, i) {
+				continue
+			}
+
+			last := tokens[i+len(cr.words)-1]
+			decision.Matches = append(decision.Matches, Match{
+				Rule:     cr.rule,
+				Category: cr.rule.Category,
+				Severity: severity,
+				Text:     text[tokens[i].start:last.end],
+				Start:    tokens[i].start,
+				End:      last.end,
+			})
+			if severity > decision.Action {
+				decision.Action = severity
+			}
+		}
+	}
+
+	if decision.Action == SeverityMask {
+		decision.Masked = mask(text, decision.Matches)
+	}
+
+	return decision
+}
+
+// isAllowlisted - Kelime izin listesinde mi (orijinal yazımla karşılaştırılır)
+func (e *Engine) isAllowlisted(t token) bool {
+	for _, p := range e.allowlist {
+		if p.prefix && strings.HasPrefix(t.lower, p.lower) {
+			return true
+		}
+		if !p.prefix && t.lower == p.lower {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAt - Kural kelimeleri i. tokendan itibaren eşleşiyor mu
+func matchesAt(words []pattern, tokens []token, allowed []bool, i int) bool {
+	for j, w := range words {
+		t := tokens[i+j]
+		if allowed[i+j] {
+			return false
+		}
+		if w.prefix {
+			if !strings.HasPrefix(t.folded, w.folded) {
+				return false
+			}
+			continue
+		}
+		// "ass" kuralı "as" kelimesine takılmasın diye çift harfler korunmalı
+		if t.folded != w.folded || len(t.long) < len(w.long) {
+			return false
+		}
+	}
+	return true
+}
+
+// compilePattern - Kural veya izin listesi kelimesini normalize et
+func compilePattern(word string) pattern {
+	prefix := strings.HasSuffix(word, "*")
+	word = strings.TrimSuffix(word, "*")
+	lower := turkishLower(word)
+	folded := fold(lower)
+	return pattern{
+		folded: collapseRepeats(folded, 1),
+		long:   collapseRepeats(folded, 2),
+		lower:  lower,
+		prefix: prefix,
+	}
+}
+
+// mask - Mask ciddiyetindeki eşleşmeleri yıldızla
+func mask(text string, matches []Match) string {
+	runes := []rune(text)
+	masked := make([]bool, len(runes))
+
+	for _, m := range matches {
+		if m.Severity != SeverityMask {
+			continue
+		}
+		pos := 0
+		for i, r := range text {
+			if i >= m.Start && i < m.End && !unicode.IsSpace(r) {
+				masked[pos] = true
+			}
+			pos++
+		}
+	}
+
+	for i := range runes {
+		if masked[i] {
+			runes[i] = '*'
+		}
+	}
+	return string(runes)
+}
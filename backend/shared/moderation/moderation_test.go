@@ -0,0 +1,42 @@
+package moderation
+
+import "testing"
+
+func TestCorpus(t *testing.T) {
+	for _, f := range checkCorpus(Default(), corpus) {
+		t.Errorf("[%s] %q: got %s (%v), want %s", f.Case.Lang, f.Case.Text, f.Got.Action, f.Got.Categories(), f.Case.Want)
+	}
+}
+
+func TestCorpusCoversEverySeverity(t *testing.T) {
+	seen := map[Severity]bool{}
+	langs := map[string]bool{}
+	for _, c := range corpus {
+		seen[c.Want] = true
+		langs[c.Lang] = true
+	}
+	for _, s := range []Severity{SeverityAllow, SeverityWarn, SeverityMask, SeverityBlock} {
+		if !seen[s] {
+			t.Errorf("corpus has no case expecting %s", s)
+		}
+	}
+	for _, lang := range []string{"tr", "en"} {
+		if !langs[lang] {
+			t.Errorf("corpus has no %s cases", lang)
+		}
+	}
+}
+
+func TestCheckCorpusReportsMismatches(t *testing.T) {
+	cases := []corpusCase{
+		{Lang: "en", Text: "I'm free this weekend", Want: SeverityBlock},
+		{Lang: "en", Text: "send nudes", Want: SeverityBlock},
+	}
+	failures := checkCorpus(Default(), cases)
+	if len(failures) != 1 || failures[0].Case != cases[0] {
+		t.Fatalf("failures = %+v, want only the first case", failures)
+	}
+	if failures[0].Got.Action != SeverityAllow {
+		t.Errorf("got %s, want %s", failures[0].Got.Action, SeverityAllow)
+	}
+}
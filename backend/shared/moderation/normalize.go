@@ -0,0 +1,185 @@
+// normalize.go - Moderasyon için metin normalizasyonu ve tokenizasyon
+package moderation
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token - Orijinal metindeki bir kelime ve normalize edilmiş halleri
+type token struct {
+	start, end int    // Orijinal metindeki byte aralığı
+	lower      string // Türkçe kurallarıyla küçük harfe çevrilmiş hali
+	folded     string // Aksan/leet dönüştürülmüş, tekrarlar tek harfe indirilmiş hali
+	long       string // Aksan/leet dönüştürülmüş, tekrarlar en fazla iki harfe indirilmiş hali
+}
+
+// foldMap - Türkçe ve yaygın Latin aksanları, Kiril benzeri harfler
+var foldMap = map[rune]rune{
+	'ç': 'c', 'ğ': 'g', 'ı': 'i', 'ö': 'o', 'ş': 's', 'ü': 'u',
+	'â': 'a', 'î': 'i', 'û': 'u',
+	'á': 'a', 'à': 'a', 'ä': 'a', 'ã': 'a', 'å': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o',
+	'ú': 'u', 'ù': 'u',
+	'ñ': 'n', 'ý': 'y',
+	// Kiril harfleri (homoglif)
+	'а': 'a', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'у': 'y', 'х': 'x', 'і': 'i', 'к': 'k',
+}
+
+// leetMap - Leetspeak karakterleri
+var leetMap = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't',
+	'@': 'a', '$': 's', '!': 'i', '|': 'i',
+}
+
+// isWordRune - Kelimenin parçası sayılan karakterler
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '@' || r == '$'
+}
+
+// isInnerRune - Sadece iki harf arasında kelimeye dahil edilen karakterler ("sh!t")
+func isInnerRune(r rune) bool {
+	return r == '!' || r == '|'
+}
+
+// tokenize - Metni kelimelere ayır ve her kelimeyi normalize et
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	var b strings.Builder
+
+	flush := func(end int) {
+		if start >= 0 && b.Len() > 0 {
+			tokens = append(tokens, newToken(start, end, b.String()))
+		}
+		start = -1
+		b.Reset()
+	}
+
+	for i, r := range text {
+		switch {
+		case isWordRune(r):
+			if start < 0 {
+				start = i
+			}
+			b.WriteRune(r)
+		case start >= 0 && unicode.Is(unicode.Cf, r):
+			// Sıfır genişlikli karakterler kelimeyi bölmez
+		case start >= 0 && isInnerRune(r) && nextIsLetter(text, i+utf8.RuneLen(r)):
+			b.WriteRune(r)
+		default:
+			flush(i)
+		}
+	}
+	flush(len(text))
+
+	return mergeSpelledOut(text, tokens)
+}
+
+// nextIsLetter - Verilen konumdaki karakter harf mi
+func nextIsLetter(text string, i int) bool {
+	if i >= len(text) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return unicode.IsLetter(r)
+}
+
+// newToken - Ham kelimeden token oluştur
+func newToken(start, end int, raw string) token {
+	lower := turkishLower(raw)
+	folded := fold(lower)
+	return token{
+		start:  start,
+		end:    end,
+		lower:  lower,
+		folded: collapseRepeats(folded, 1),
+		long:   collapseRepeats(folded, 2),
+	}
+}
+
+// mergeSpelledOut - "f.u.c.k" veya "f u c k" gibi harf harf yazılmış kelimeleri birleştir
+func mergeSpelledOut(text string, tokens []token) []token {
+	const minRun = 3
+	const maxGap = 3
+
+	var merged []token
+	for i := 0; i < len(tokens); {
+		j := i
+		for j < len(tokens) && utf8.RuneCountInString(tokens[j].lower) == 1 {
+			if j > i && tokens[j].start-tokens[j-1].end > maxGap {
+				break
+			}
+			j++
+		}
+
+		if j-i >= minRun {
+			var raw strings.Builder
+			for _, t := range tokens[i:j] {
+				raw.WriteString(text[t.start:t.end])
+			}
+			merged = append(merged, newToken(tokens[i].start, tokens[j-1].end, raw.String()))
+			i = j
+			continue
+		}
+
+		merged = append(merged, tokens[i])
+		i++
+	}
+
+	return merged
+}
+
+// turkishLower - Türkçe büyük/küçük harf kurallarıyla küçük harfe çevir (İ→i, I→ı)
+func turkishLower(s string) string {
+	return strings.ToLowerSpecial(unicode.TurkishCase, s)
+}
+
+// fold - Aksanları kaldır, leetspeak karakterlerini harfe çevir
+func fold(s string) string {
+	hasLetter := strings.IndexFunc(s, unicode.IsLetter) >= 0
+
+	var b strings.Builder
+	for _, r := range s {
+		if f, ok := foldMap[r]; ok {
+			r = f
+		} else if l, ok := leetMap[r]; ok && hasLetter {
+			// Sadece harf içeren kelimelerde (sayılar olduğu gibi kalır)
+			r = l
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// collapseRepeats - Art arda tekrar eden karakterleri en fazla max adede indir ("fuuuck" → "fuck")
+func collapseRepeats(s string, max int) string {
+	var b strings.Builder
+	var prev rune
+	count := 0
+	for _, r := range s {
+		if r == prev {
+			count++
+		} else {
+			prev = r
+			count = 1
+		}
+		if count <= max {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Normalize - Metni moderasyon karşılaştırmasında kullanılan biçime getir
+func Normalize(text string) string {
+	tokens := tokenize(text)
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.folded
+	}
+	return strings.Join(words, " ")
+}
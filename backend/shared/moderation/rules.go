@@ -0,0 +1,146 @@
+// rules.go - Varsayılan moderasyon kuralları (Türkçe ve İngilizce)
+package moderation
+
+// DefaultRules - Varsayılan kural listesi
+// Tek başına masum olan kelimeler ("para", "free", "vur", "bomba") kural değildir;
+// sadece zararlı kullanımları ifade olarak eşleşir ("para gönder", "seni öldür*").
+var DefaultRules = []Rule{
+	// Türkçe küfürler
+	{Phrase: "amk", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "amq", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "aq", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "amına*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "amcık*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "orospu*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "oç", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "piç", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "göt", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "götveren*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "siktir*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "sikerim", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "sikeyim", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "sikik*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "yarrak*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "yavşak*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "pezevenk*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "kaltak*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "sürtük*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "fahişe*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "kancık*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "gerizekalı*", Category: CategoryProfanity, Severity: SeverityWarn},
+	{Phrase: "salak*", Category: CategoryProfanity, Severity: SeverityWarn},
+	{Phrase: "aptal*", Category: CategoryProfanity, Severity: SeverityWarn},
+
+	// İngilizce küfürler
+	{Phrase: "fuck*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "motherfuck*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "wtf", Category: CategoryProfanity, Severity: SeverityWarn},
+	{Phrase: "shit*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "bullshit*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "bitch*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "ass", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "asshole*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "dumbass", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "dick", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "cock", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "pussy", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "cunt*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "whore*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "slut*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "bastard*", Category: CategoryProfanity, Severity: SeverityMask},
+	{Phrase: "idiot*", Category: CategoryProfanity, Severity: SeverityWarn},
+	{Phrase: "stupid", Category: CategoryProfanity, Severity: SeverityWarn},
+
+	// Nefret söylemi
+	{Phrase: "ibne*", Category: CategoryHate, Severity: SeverityBlock},
+	{Phrase: "faggot*", Category: CategoryHate, Severity: SeverityBlock},
+	{Phrase: "nigger*", Category: CategoryHate, Severity: SeverityBlock},
+	{Phrase: "nigga*", Category: CategoryHate, Severity: SeverityBlock},
+	{Phrase: "retard*", Category: CategoryHate, Severity: SeverityMask},
+
+	// Şiddet ve tehdit
+	{Phrase: "seni öldür*", Category: CategoryViolence, Severity: SeverityBlock},
+	{Phrase: "öldürürüm", Category: CategoryViolence, Severity: SeverityBlock},
+	{Phrase: "öldüreceğim", Category: CategoryViolence, Severity: SeverityBlock},
+	{Phrase: "gebertirim", Category: CategoryViolence, Severity: SeverityBlock},
+	{Phrase: "geberteceğim", Category: CategoryViolence, Severity: SeverityBlock},
+	{Phrase: "seni vururum", Category: CategoryViolence, Severity: SeverityBlock},
+	{Phrase: "seni bıçakla*", Category: CategoryViolence, Severity: SeverityBlock},
+	{Phrase: "kill you*", Category: CategoryViolence, Severity: SeverityBlock},
+	{Phrase: "kys", Category: CategoryViolence, Severity: SeverityBlock},
+	{Phrase: "shoot you", Category: CategoryViolence, Severity: SeverityBlock},
+	{Phrase: "stab you", Category: CategoryViolence, Severity: SeverityBlock},
+
+	// Taciz ve şantaj
+	{Phrase: "tecavüz*", Category: CategoryHarassment, Severity: SeverityBlock},
+	{Phrase: "rape*", Category: CategoryHarassment, Severity: SeverityBlock},
+	{Phrase: "şantaj*", Category: CategoryHarassment, Severity: SeverityWarn},
+	{Phrase: "blackmail*", Category: CategoryHarassment, Severity: SeverityWarn},
+	{Phrase: "send nudes", Category: CategoryHarassment, Severity: SeverityBlock},
+	{Phrase: "send me nudes", Category: CategoryHarassment, Severity: SeverityBlock},
+	{Phrase: "çıplak foto*", Category: CategoryHarassment, Severity: SeverityBlock},
+
+	// Müstehcen içerik
+	{Phrase: "porn*", Category: CategorySexual, Severity: SeverityMask},
+	{Phrase: "seks*", Category: CategorySexual, Severity: SeverityWarn},
+	{Phrase: "sex", Category: CategorySexual, Severity: SeverityWarn},
+	{Phrase: "sexy", Category: CategorySexual, Severity: SeverityWarn},
+	{Phrase: "nude*", Category: CategorySexual, Severity: SeverityWarn},
+	{Phrase: "naked", Category: CategorySexual, Severity: SeverityWarn},
+	{Phrase: "çıplak", Category: CategorySexual, Severity: SeverityWarn},
+	{Phrase: "penis*", Category: CategorySexual, Severity: SeverityWarn},
+	{Phrase: "vajina*", Category: CategorySexual, Severity: SeverityWarn},
+	{Phrase: "mastürbasyon*", Category: CategorySexual, Severity: SeverityWarn},
+	{Phrase: "masturbat*", Category: CategorySexual, Severity: SeverityWarn},
+
+	// Spam ve dolandırıcılık
+	{Phrase: "satın al", Category: CategorySpam, Severity: SeverityWarn},
+	{Phrase: "satın alın", Category: CategorySpam, Severity: SeverityWarn},
+	{Phrase: "buraya tıkla*", Category: CategorySpam, Severity: SeverityWarn},
+	{Phrase: "linke tıkla*", Category: CategorySpam, Severity: SeverityWarn},
+	{Phrase: "para gönder*", Category: CategorySpam, Severity: SeverityWarn},
+	{Phrase: "para yatır*", Category: CategorySpam, Severity: SeverityWarn},
+	{Phrase: "bana para gönder*", Category: CategorySpam, Severity: SeverityBlock},
+	{Phrase: "bana para yolla*", Category: CategorySpam, Severity: SeverityBlock},
+	{Phrase: "bana para at", Category: CategorySpam, Severity: SeverityBlock},
+	{Phrase: "bana para atar*", Category: CategorySpam, Severity: SeverityBlock},
+	{Phrase: "yatırım fırsatı", Category: CategorySpam, Severity: SeverityBlock},
+	{Phrase: "kripto yatırım*", Category: CategorySpam, Severity: SeverityBlock},
+	{Phrase: "bitcoin gönder*", Category: CategorySpam, Severity: SeverityBlock},
+	{Phrase: "hediye kart*", Category: CategorySpam, Severity: SeverityWarn},
+	{Phrase: "buy now", Category: CategorySpam, Severity: SeverityWarn},
+	{Phrase: "click here", Category: CategorySpam, Severity: SeverityWarn},
+	{Phrase: "click the link", Category: CategorySpam, Severity: SeverityWarn},
+	{Phrase: "send money", Category: CategorySpam, Severity: SeverityWarn},
+	{Phrase: "send me money", Category: CategorySpam, Severity: SeverityBlock},
+	{Phrase: "wire me money", Category: CategorySpam, Severity: SeverityBlock},
+	{Phrase: "send bitcoin", Category: CategorySpam, Severity: SeverityBlock},
+	{Phrase: "send crypto", Category: CategorySpam, Severity: SeverityBlock},
+	{Phrase: "crypto invest*", Category: CategorySpam, Severity: SeverityBlock},
+	{Phrase: "investment opportunity", Category: CategorySpam, Severity: SeverityBlock},
+	{Phrase: "gift card*", Category: CategorySpam, Severity: SeverityWarn},
+}
+
+// DefaultAllowlist - Kurallara takılan ama masum olan kelimeler
+var DefaultAllowlist = []string{
+	"got",                 // "göt" ile aynı normalize edilir
+	"pic",                 // "piç" ile aynı normalize edilir
+	"amina",               // İsim; "amına" yazımı yine yakalanır
+	"shiitake", "shitake", // "shit*"
+	"rapeseed*",  // "rape*"
+	"retardant*", // "retard*"
+	"seksen*",    // "seks*" (sayı)
+	"penisilin*", // "penis*"
+}
+
+// DefaultPolicies - Varsayılan kategori politikaları (boş: kuralların kendi ciddiyeti geçerli)
+var DefaultPolicies = map[Category]Severity{}
+
+// DefaultConfig - Varsayılan yapılandırma
+func DefaultConfig() Config {
+	return Config{
+		Rules:     DefaultRules,
+		Allowlist: DefaultAllowlist,
+		Policies:  DefaultPolicies,
+	}
+}
@@ -0,0 +1,69 @@
+// recorder.go - Middleware'ler için yanıt durumu ve boyutu kaydı
+package server
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// ResponseRecorder - Yanıtın durum kodunu ve boyutunu kaydeden ResponseWriter
+// WebSocket yükseltmeleri için Hijack, http.ResponseController için Unwrap sağlar.
+type ResponseRecorder struct {
+	http.ResponseWriter
+	Status   int
+	Bytes    int64
+	Hijacked bool // Bağlantı ele geçirildi (WebSocket); Status anlamsızdır
+
+	wroteHeader bool
+}
+
+// NewResponseRecorder - Durum kodu yazılmazsa 200 kabul edilir
+func NewResponseRecorder(w http.ResponseWriter) *ResponseRecorder {
+	return &ResponseRecorder{ResponseWriter: w, Status: http.StatusOK}
+}
+
+func (r *ResponseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.Status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *ResponseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.Bytes += int64(n)
+	return n, err
+}
+
+func (r *ResponseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil {
+		r.Hijacked = true
+		r.Status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+func (r *ResponseRecorder) Flush() {
+	http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+func (r *ResponseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// RouteTemplate - İsteğin eşleştiği mux rota şablonu (/api/users/{id}); eşleşme yoksa "unmatched"
+// Metrik etiketlerinde ve span adlarında yol yerine kullanılır, böylece kimlikler ayrı seri açmaz.
+func RouteTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			return tpl
+		}
+	}
+	return "unmatched"
+}
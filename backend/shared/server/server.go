@@ -0,0 +1,140 @@
+// server.go - Zaman aşımlı HTTP sunucusu ve sinyalle kontrollü kapanış
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Config - Sunucu zaman aşımları
+type Config struct {
+	Addr              string
+	ReadHeaderTimeout time.Duration // İstek başlıkları (slowloris)
+	ReadTimeout       time.Duration // Gövde dahil isteğin tamamı (fotoğraf yüklemeleri)
+	WriteTimeout      time.Duration // Yanıtın tamamı (AI analizi gibi uzun istekler dahil)
+	IdleTimeout       time.Duration // Keep-alive bağlantılarında sonraki istek
+	ShutdownTimeout   time.Duration // Kapanışta isteklerin ve arka plan işlerinin bitmesi için
+}
+
+// DefaultConfig - addr için varsayılan zaman aşımları
+func DefaultConfig(addr string) Config {
+	return Config{
+		Addr:              addr,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       60 * time.Second,
+		WriteTimeout:      90 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		ShutdownTimeout:   30 * time.Second,
+	}
+}
+
+// ConfigFromEnv - DefaultConfig, SHUTDOWN_TIMEOUT_SECONDS ile ezilebilir
+func ConfigFromEnv(addr string) Config {
+	cfg := DefaultConfig(addr)
+	if v, err := strconv.Atoi(os.Getenv("SHUTDOWN_TIMEOUT_SECONDS")); err == nil && v > 0 {
+		cfg.ShutdownTimeout = time.Duration(v) * time.Second
+	}
+	return cfg
+}
+
+// Server - HTTP sunucusu, arka plan işleri ve kapanış adımları
+type Server struct {
+	// Workers - Servisin arka plan işleri; kapanışta bitmeleri beklenir
+	Workers *Workers
+
+	name   string
+	config Config
+	http   *http.Server
+
+	mu         sync.Mutex
+	onShutdown []func(ctx context.Context)
+}
+
+// New - name günlüklerde kullanılır; workers nil ise yeni bir grup oluşturulur
+// Servisler arka plan işlerini başlatan bileşenleri router'dan önce kurduğu için grup dışarıdan verilebilir.
+func New(name string, handler http.Handler, cfg Config, workers *Workers) *Server {
+	if workers == nil {
+		workers = NewWorkers()
+	}
+	return &Server{
+		Workers: workers,
+		name:    name,
+		config:  cfg,
+		http: &http.Server{
+			Addr:              cfg.Addr,
+			Handler:           handler,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			ReadTimeout:       cfg.ReadTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+		},
+	}
+}
+
+// OnShutdown - Kapanışta, HTTP istekleri boşaltılmadan önce çalışacak adım
+// http.Server.Shutdown ele geçirilmiş (hijack) bağlantıları kapatmaz; WebSocket'ler burada kapatılır.
+func (s *Server) OnShutdown(fn func(ctx context.Context)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onShutdown = append(s.onShutdown, fn)
+}
+
+// Run - SIGINT/SIGTERM gelene kadar hizmet ver, sonra kontrollü kapan
+func (s *Server) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, 1)
+	go func() {
+		slog.Info("server starting", "name", s.name, "addr", s.config.Addr)
+		errc <- s.http.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		s.Workers.Stop(context.Background())
+		return err
+	case <-ctx.Done():
+	}
+	stop() // İkinci sinyal süreci hemen sonlandırır
+
+	slog.Info("server shutting down", "name", s.name, "timeout", s.config.ShutdownTimeout.String())
+	return s.Shutdown(context.Background())
+}
+
+// Shutdown - Kapanış adımlarını çalıştır, istekleri boşalt ve arka plan işlerini bekle
+func (s *Server) Shutdown(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.ShutdownTimeout)
+	defer cancel()
+
+	s.mu.Lock()
+	hooks := s.onShutdown
+	s.mu.Unlock()
+	for _, fn := range hooks {
+		fn(ctx)
+	}
+
+	var errs []error
+	if err := s.http.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := s.Workers.Stop(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		slog.Error("server shutdown incomplete", "name", s.name, "error", err)
+	} else {
+		slog.Info("server stopped", "name", s.name)
+	}
+	return err
+}
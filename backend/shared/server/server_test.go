@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// events - Kapanış adımlarının sırası
+type events struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *events) add(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.list = append(e.list, name)
+}
+
+func (e *events) get() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.list...)
+}
+
+// serve - Sunucuyu rastgele bir portta başlat, adresini döndür
+func serve(t *testing.T, s *Server) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.http.Serve(ln)
+	return "http://" + ln.Addr().String()
+}
+
+func TestShutdownOrder(t *testing.T) {
+	var ev events
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		ev.add("request")
+		io.WriteString(w, "done")
+	})
+
+	cfg := DefaultConfig("")
+	cfg.ShutdownTimeout = 5 * time.Second
+	s := New("test", handler, cfg, nil)
+	url := serve(t, s)
+
+	// Kapanış adımı isteklerden önce çalışır; süren isteği o serbest bırakır
+	s.OnShutdown(func(ctx context.Context) {
+		ev.add("hook")
+		close(release)
+	})
+	// Arka plan işi ancak istekler boşaldıktan sonra iptal edilir
+	s.Workers.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		ev.add("worker")
+	})
+
+	type result struct {
+		body string
+		err  error
+	}
+	resc := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			resc <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		resc <- result{body: string(body), err: err}
+	}()
+	<-started
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown = %v", err)
+	}
+	if res := <-resc; res.err != nil || res.body != "done" {
+		t.Fatalf("in-flight request = %q, %v; want it to complete", res.body, res.err)
+	}
+	if got := strings.Join(ev.get(), ","); got != "hook,request,worker" {
+		t.Fatalf("shutdown order = %s, want hook,request,worker", got)
+	}
+
+	if _, err := http.Get(url); err == nil {
+		t.Fatal("server accepted a request after shutdown")
+	}
+}
+
+func TestShutdownDrainTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	cfg := DefaultConfig("")
+	cfg.ShutdownTimeout = 50 * time.Millisecond
+	s := New("test", handler, cfg, nil)
+	url := serve(t, s)
+
+	// Bağlamı dinlemeyen iş kapanış süresini aşar
+	s.Workers.Go(func(ctx context.Context) {
+		<-release
+	})
+	go http.Get(url)
+	<-started
+
+	start := time.Now()
+	err := s.Shutdown(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("shutdown took %s, want it bounded by the timeout", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("shutdown = %v, want a deadline error", err)
+	}
+	if !strings.Contains(err.Error(), "1 background workers still running") {
+		t.Fatalf("shutdown = %v, want the stuck worker count", err)
+	}
+}
+
+func TestWorkersStop(t *testing.T) {
+	w := NewWorkers()
+	done := make(chan struct{})
+	w.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		close(done)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := w.Stop(ctx); err != nil {
+		t.Fatalf("stop = %v", err)
+	}
+	select {
+	case <-done:
+	default:
+		t.Fatal("stop returned before the worker finished")
+	}
+	if w.Context().Err() == nil {
+		t.Fatal("worker context was not cancelled")
+	}
+
+	// nil grup işi yine de çalıştırır
+	var nilWorkers *Workers
+	ran := make(chan struct{})
+	nilWorkers.Go(func(ctx context.Context) { close(ran) })
+	<-ran
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		env  string
+		want time.Duration
+	}{
+		{"", 30 * time.Second},
+		{"5", 5 * time.Second},
+		{"0", 30 * time.Second},
+		{"abc", 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Setenv("SHUTDOWN_TIMEOUT_SECONDS", tt.env)
+		if got := ConfigFromEnv(":8080").ShutdownTimeout; got != tt.want {
+			t.Errorf("SHUTDOWN_TIMEOUT_SECONDS=%q: timeout = %s, want %s", tt.env, got, tt.want)
+		}
+	}
+}
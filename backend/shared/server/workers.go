@@ -0,0 +1,68 @@
+// workers.go - Kapanışta beklenen arka plan işleri
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Workers - Arka plan goroutine'lerini izler
+// İşlere verilen ctx kapanış başladığında iptal edilir: döngüler çıkmalı, sırada bekleyen
+// işler başlamamalı. Başlamış tek seferlik işler (AI analizi, e-posta) bitirilebilir; Stop
+// bunları kapanış süresi dolana kadar bekler.
+type Workers struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running atomic.Int64
+}
+
+// NewWorkers - Boş iş grubu
+func NewWorkers() *Workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Workers{ctx: ctx, cancel: cancel}
+}
+
+// Go - fn'i arka planda çalıştır (nil Workers ile de çalışır, ama beklenmez)
+func (w *Workers) Go(fn func(ctx context.Context)) {
+	if w == nil {
+		go fn(context.Background())
+		return
+	}
+
+	w.wg.Add(1)
+	w.running.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer w.running.Add(-1)
+		fn(w.ctx)
+	}()
+}
+
+// Context - Kapanış başladığında iptal edilen bağlam
+func (w *Workers) Context() context.Context {
+	if w == nil {
+		return context.Background()
+	}
+	return w.ctx
+}
+
+// Stop - Bağlamı iptal et ve işlerin bitmesini ctx süresince bekle
+func (w *Workers) Stop(ctx context.Context) error {
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("server: %d background workers still running: %w", w.running.Load(), ctx.Err())
+	}
+}
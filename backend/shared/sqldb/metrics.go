@@ -0,0 +1,58 @@
+// metrics.go - Sorgu süreleri ve hataları
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"eros/shared/metrics"
+	"eros/shared/tracing"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	queryDuration = metrics.NewHistogram("db_query_duration_seconds",
+		"Database query latency by statement type (select, insert, update, delete, other).",
+		[]float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}, "operation")
+	queryErrors = metrics.NewCounter("db_query_errors_total",
+		"Failed database queries by statement type (sql.ErrNoRows is not an error).", "operation")
+)
+
+// track - Sorgu öncesi çağrılır; dönen fonksiyon sorgunun süresini ve hatasını kaydeder
+// ctx'te bir span varsa sorgu onun altında "db.select" gibi bir span olur; kök span açılmaz.
+// QueryRow'da row.Err() kullanılır; sql.ErrNoRows ancak Scan'de döner ve hata sayılmaz.
+func track(ctx context.Context, dialect Dialect, query string) (context.Context, func(error)) {
+	op := operation(query)
+	var span *tracing.Span
+	if tracing.FromContext(ctx) != nil {
+		ctx, span = tracing.StartClient(ctx, "db."+op)
+		span.SetAttr("db.system", dialect.Name)
+		span.SetAttr("db.operation", op)
+		span.SetAttr("db.statement", strings.Join(strings.Fields(query), " "))
+	}
+
+	start := time.Now()
+	return ctx, func(err error) {
+		queryDuration.Observe(time.Since(start).Seconds(), op)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			queryErrors.Inc(op)
+			span.RecordError(err)
+		}
+		span.End()
+	}
+}
+
+// operation - Sorgunun ilk anahtar kelimesi (etiket sayısı sınırlı kalsın diye bilinenler dışı "other")
+func operation(query string) string {
+	query = strings.TrimSpace(query)
+	end := strings.IndexAny(query, " \t\r\n(")
+	if end < 0 {
+		end = len(query)
+	}
+	switch op := strings.ToLower(query[:end]); op {
+	case "select", "insert", "update", "delete":
+		return op
+	}
+	return "other"
+}
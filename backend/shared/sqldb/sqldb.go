@@ -0,0 +1,236 @@
+// sqldb.go - SQLite ve PostgreSQL için ortak veritabanı katmanı
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Dialect - Veritabanına özgü sürücü adı ve parametre biçimi
+type Dialect struct {
+	Name        string
+	Driver      string // database/sql sürücü adı; sürücüyü servis kendisi import eder
+	placeholder func(n int) string
+}
+
+var (
+	SQLite   = Dialect{Name: "sqlite", Driver: "sqlite3", placeholder: func(int) string { return "?" }}
+	Postgres = Dialect{Name: "postgres", Driver: "postgres", placeholder: func(n int) string { return "$" + strconv.Itoa(n) }}
+)
+
+// DialectByName - "sqlite" veya "postgres" (boş değer SQLite)
+func DialectByName(name string) (Dialect, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "sqlite", "sqlite3":
+		return SQLite, nil
+	case "postgres", "postgresql", "pgx":
+		return Postgres, nil
+	}
+	return Dialect{}, fmt.Errorf("sqldb: unknown database driver %q", name)
+}
+
+// Rebind - Sorgudaki "?" parametrelerini lehçenin biçimine çevir
+// Tırnak içindeki soru işaretlerine dokunulmaz.
+func (d Dialect) Rebind(query string) string {
+	if d.placeholder == nil || d.Name == SQLite.Name {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	inQuote := false
+	for _, r := range query {
+		switch {
+		case r == '\'':
+			inQuote = !inQuote
+		case r == '?' && !inQuote:
+			n++
+			b.WriteString(d.placeholder(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// DB - Sorguları lehçeye göre yeniden yazan *sql.DB
+// Depolar sorgularını "?" ile yazar; PostgreSQL'de bunlar $1, $2, ... olur.
+type DB struct {
+	*sql.DB
+	Dialect Dialect
+}
+
+// Open - Bağlantıyı aç ve doğrula
+func Open(dialect Dialect, dsn string) (*DB, error) {
+	db, err := sql.Open(dialect.Driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqldb: connecting to %s: %w", dialect.Name, err)
+	}
+	return Wrap(db, dialect), nil
+}
+
+// Wrap - Açık bir bağlantıyı sarmala
+func Wrap(db *sql.DB, dialect Dialect) *DB {
+	return &DB{DB: db, Dialect: dialect}
+}
+
+// FromEnv - DB_DRIVER ("sqlite" varsayılan veya "postgres") ile bağlantı aç
+// SQLite için DB_PATH (yoksa defaultPath), PostgreSQL için DATABASE_URL kullanılır.
+func FromEnv(defaultPath string) (*DB, error) {
+	dialect, err := DialectByName(os.Getenv("DB_DRIVER"))
+	if err != nil {
+		return nil, err
+	}
+
+	dsn := os.Getenv("DB_PATH")
+	if dsn == "" {
+		dsn = defaultPath
+	}
+	if dialect.Name == Postgres.Name {
+		dsn = os.Getenv("DATABASE_URL")
+		if dsn == "" {
+			return nil, fmt.Errorf("sqldb: DATABASE_URL is required when DB_DRIVER=%s", dialect.Name)
+		}
+	}
+
+	return Open(dialect, dsn)
+}
+
+// IsPostgres - Bağlantı PostgreSQL mi
+func (db *DB) IsPostgres() bool {
+	return db.Dialect.Name == Postgres.Name
+}
+
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	ctx, done := track(context.Background(), db.Dialect, query)
+	res, err := db.DB.ExecContext(ctx, db.Dialect.Rebind(query), args...)
+	done(err)
+	return res, err
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, done := track(ctx, db.Dialect, query)
+	res, err := db.DB.ExecContext(ctx, db.Dialect.Rebind(query), args...)
+	done(err)
+	return res, err
+}
+
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, done := track(context.Background(), db.Dialect, query)
+	rows, err := db.DB.QueryContext(ctx, db.Dialect.Rebind(query), args...)
+	done(err)
+	return rows, err
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, done := track(ctx, db.Dialect, query)
+	rows, err := db.DB.QueryContext(ctx, db.Dialect.Rebind(query), args...)
+	done(err)
+	return rows, err
+}
+
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	ctx, done := track(context.Background(), db.Dialect, query)
+	row := db.DB.QueryRowContext(ctx, db.Dialect.Rebind(query), args...)
+	done(row.Err())
+	return row
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, done := track(ctx, db.Dialect, query)
+	row := db.DB.QueryRowContext(ctx, db.Dialect.Rebind(query), args...)
+	done(row.Err())
+	return row
+}
+
+// InsertID - INSERT'i çalıştır ve oluşan satırın id'sini döndür
+// LastInsertId PostgreSQL'de desteklenmediği için her iki lehçede de RETURNING kullanılır.
+func (db *DB) InsertID(query string, args ...interface{}) (int64, error) {
+	return db.InsertIDContext(context.Background(), query, args...)
+}
+
+func (db *DB) InsertIDContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	var id int64
+	err := db.QueryRowContext(ctx, strings.TrimSpace(query)+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+func (db *DB) Begin() (*Tx, error) {
+	return db.BeginTx(context.Background(), nil)
+}
+
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, Dialect: db.Dialect}, nil
+}
+
+// Tx - Sorguları lehçeye göre yeniden yazan *sql.Tx
+type Tx struct {
+	*sql.Tx
+	Dialect Dialect
+}
+
+// InsertID - DB.InsertID'nin işlem içindeki karşılığı
+func (tx *Tx) InsertID(query string, args ...interface{}) (int64, error) {
+	return tx.InsertIDContext(context.Background(), query, args...)
+}
+
+func (tx *Tx) InsertIDContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	var id int64
+	err := tx.QueryRowContext(ctx, strings.TrimSpace(query)+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+func (tx *Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	ctx, done := track(context.Background(), tx.Dialect, query)
+	res, err := tx.Tx.ExecContext(ctx, tx.Dialect.Rebind(query), args...)
+	done(err)
+	return res, err
+}
+
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, done := track(ctx, tx.Dialect, query)
+	res, err := tx.Tx.ExecContext(ctx, tx.Dialect.Rebind(query), args...)
+	done(err)
+	return res, err
+}
+
+func (tx *Tx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, done := track(context.Background(), tx.Dialect, query)
+	rows, err := tx.Tx.QueryContext(ctx, tx.Dialect.Rebind(query), args...)
+	done(err)
+	return rows, err
+}
+
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, done := track(ctx, tx.Dialect, query)
+	rows, err := tx.Tx.QueryContext(ctx, tx.Dialect.Rebind(query), args...)
+	done(err)
+	return rows, err
+}
+
+func (tx *Tx) QueryRow(query string, args ...interface{}) *sql.Row {
+	ctx, done := track(context.Background(), tx.Dialect, query)
+	row := tx.Tx.QueryRowContext(ctx, tx.Dialect.Rebind(query), args...)
+	done(row.Err())
+	return row
+}
+
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, done := track(ctx, tx.Dialect, query)
+	row := tx.Tx.QueryRowContext(ctx, tx.Dialect.Rebind(query), args...)
+	done(row.Err())
+	return row
+}
@@ -0,0 +1,34 @@
+package sqldb
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		query   string
+		want    string
+	}{
+		{SQLite, `SELECT * FROM users WHERE id = ? AND email = ?`, `SELECT * FROM users WHERE id = ? AND email = ?`},
+		{Postgres, `SELECT * FROM users WHERE id = ? AND email = ?`, `SELECT * FROM users WHERE id = $1 AND email = $2`},
+		{Postgres, `UPDATE users SET bio = 'neden?' WHERE id = ?`, `UPDATE users SET bio = 'neden?' WHERE id = $1`},
+		{Postgres, `INSERT INTO t (a, b) VALUES (?, 'it''s ?'), (?, ?)`, `INSERT INTO t (a, b) VALUES ($1, 'it''s ?'), ($2, $3)`},
+		{Postgres, `SELECT 1`, `SELECT 1`},
+	}
+	for _, tt := range tests {
+		if got := tt.dialect.Rebind(tt.query); got != tt.want {
+			t.Errorf("%s.Rebind(%q) = %q, want %q", tt.dialect.Name, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestDialectByName(t *testing.T) {
+	for name, want := range map[string]Dialect{"": SQLite, "sqlite3": SQLite, " Postgres ": Postgres, "pgx": Postgres} {
+		got, err := DialectByName(name)
+		if err != nil || got.Name != want.Name {
+			t.Errorf("DialectByName(%q) = %s, %v; want %s", name, got.Name, err, want.Name)
+		}
+	}
+	if _, err := DialectByName("mysql"); err == nil {
+		t.Error("DialectByName accepted mysql")
+	}
+}
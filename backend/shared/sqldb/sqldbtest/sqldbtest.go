@@ -0,0 +1,89 @@
+// sqldbtest.go - Testlerin PostgreSQL veritabanı (TEST_DATABASE_URL)
+package sqldbtest
+
+import (
+	"eros/shared/sqldb"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ErrNotTestDatabase - Veritabanı test veritabanı olarak işaretlenmemiş; şema silinmez
+var ErrNotTestDatabase = errors.New("refusing to reset a database that is not marked as a test database: use a database whose name contains \"test\" or set TEST_DATABASE_ALLOW_RESET=1")
+
+// schemaName - Paket başına şema adı (go test paketleri paralel çalıştırır)
+var schemaName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// URL - TEST_DATABASE_URL (tanımlı değilse boş; PostgreSQL testleri atlanır)
+func URL() string {
+	return os.Getenv("TEST_DATABASE_URL")
+}
+
+// OpenPostgres - TEST_DATABASE_URL'ye bağlan ve schema'yı boş olarak yeniden oluştur
+// Bağlantının search_path'i schema'dır; böylece aynı veritabanını kullanan test paketleri
+// birbirinin tablolarına dokunmaz. Veritabanının adı "test" içermiyorsa ve
+// TEST_DATABASE_ALLOW_RESET=1 verilmemişse hiçbir şey silinmez, ErrNotTestDatabase döner.
+func OpenPostgres(schema string) (*sqldb.DB, error) {
+	dsn := URL()
+	if dsn == "" {
+		return nil, errors.New("TEST_DATABASE_URL is not set")
+	}
+	if !schemaName.MatchString(schema) {
+		return nil, fmt.Errorf("invalid schema name %q", schema)
+	}
+	dsn, err := withSearchPath(dsn, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sqldb.Open(sqldb.Postgres, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	var database string
+	if err := db.QueryRow(`SELECT current_database()`).Scan(&database); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if !resetAllowed(database, os.Getenv("TEST_DATABASE_ALLOW_RESET")) {
+		db.Close()
+		return nil, fmt.Errorf("%w (database %q)", ErrNotTestDatabase, database)
+	}
+
+	if _, err := db.Exec(`DROP SCHEMA IF EXISTS ` + schema + ` CASCADE; CREATE SCHEMA ` + schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// resetAllowed - Adında "test" geçen veritabanları veya açık onay
+func resetAllowed(database, allowReset string) bool {
+	if strings.Contains(strings.ToLower(database), "test") {
+		return true
+	}
+	switch strings.ToLower(allowReset) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
+// withSearchPath - DSN'e search_path çalışma parametresini ekle (URL veya key=value biçimi)
+func withSearchPath(dsn, schema string) (string, error) {
+	if !strings.Contains(dsn, "://") {
+		return dsn + " search_path=" + schema, nil
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", fmt.Errorf("invalid TEST_DATABASE_URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
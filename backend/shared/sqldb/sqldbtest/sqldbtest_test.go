@@ -0,0 +1,42 @@
+package sqldbtest
+
+import "testing"
+
+func TestResetAllowed(t *testing.T) {
+	tests := []struct {
+		database, allowReset string
+		want                 bool
+	}{
+		{"eros_test", "", true},
+		{"TestDB", "", true},
+		{"eros", "", false},
+		{"postgres", "", false},
+		{"postgres", "0", false},
+		{"postgres", "1", true},
+		{"postgres", "true", true},
+	}
+	for _, tt := range tests {
+		if got := resetAllowed(tt.database, tt.allowReset); got != tt.want {
+			t.Errorf("resetAllowed(%q, %q) = %v, want %v", tt.database, tt.allowReset, got, tt.want)
+		}
+	}
+}
+
+func TestWithSearchPath(t *testing.T) {
+	tests := []struct {
+		dsn, want string
+	}{
+		{"postgres://u:p@localhost:5433/eros_test?sslmode=disable", "postgres://u:p@localhost:5433/eros_test?search_path=store_test&sslmode=disable"},
+		{"postgres://localhost/eros_test", "postgres://localhost/eros_test?search_path=store_test"},
+		{"host=localhost dbname=eros_test sslmode=disable", "host=localhost dbname=eros_test sslmode=disable search_path=store_test"},
+	}
+	for _, tt := range tests {
+		got, err := withSearchPath(tt.dsn, "store_test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("withSearchPath(%q) = %q, want %q", tt.dsn, got, tt.want)
+		}
+	}
+}
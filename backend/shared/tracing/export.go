@@ -0,0 +1,180 @@
+// export.go - Span aktarıcıları: OTLP/HTTP (JSON) ve stdout
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Exporter - Bitmiş span'leri bir arka uca gönderir
+type Exporter interface {
+	Export(ctx context.Context, service string, spans []*Span) error
+}
+
+// OTLPExporter - Span'leri OTLP/HTTP JSON ile bir toplayıcıya (OpenTelemetry Collector, Jaeger, Tempo) gönderir
+type OTLPExporter struct {
+	Endpoint string            // Örn. http://localhost:4318/v1/traces
+	Headers  map[string]string // Örn. kimlik doğrulama
+	client   *http.Client
+}
+
+// NewOTLPExporter - endpoint tam /v1/traces adresidir
+func NewOTLPExporter(endpoint string, headers map[string]string) *OTLPExporter {
+	return &OTLPExporter{Endpoint: endpoint, Headers: headers, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (e *OTLPExporter) Export(ctx context.Context, service string, spans []*Span) error {
+	body, err := json.Marshal(otlpRequest(service, spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("tracing: exporting %d spans: %w", len(spans), err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("tracing: collector answered %s", resp.Status)
+	}
+	return nil
+}
+
+// StdoutExporter - Her span'i bir JSON satırı olarak yazar (yerel geliştirme)
+type StdoutExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	return &StdoutExporter{w: w}
+}
+
+func (e *StdoutExporter) Export(ctx context.Context, service string, spans []*Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	enc := json.NewEncoder(e.w)
+	for _, s := range spans {
+		line := map[string]any{
+			"time":        s.start.Format(time.RFC3339Nano),
+			"service":     service,
+			"span":        s.name,
+			"trace_id":    s.context.TraceID.String(),
+			"span_id":     s.context.SpanID.String(),
+			"duration_ms": float64(s.end.Sub(s.start).Microseconds()) / 1000,
+		}
+		if s.parent.IsValid() {
+			line["parent_span_id"] = s.parent.String()
+		}
+		if s.status.Code == statusError {
+			line["error"] = s.status.Message
+		}
+		if len(s.attributes) > 0 {
+			attrs := make(map[string]any, len(s.attributes))
+			for _, a := range s.attributes {
+				attrs[a.Key] = a.Value
+			}
+			line["attributes"] = attrs
+		}
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// otlpRequest - ExportTraceServiceRequest'in JSON karşılığı
+// https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+func otlpRequest(service string, spans []*Span) map[string]any {
+	out := make([]map[string]any, 0, len(spans))
+	for _, s := range spans {
+		span := map[string]any{
+			"traceId":           s.context.TraceID.String(),
+			"spanId":            s.context.SpanID.String(),
+			"name":              s.name,
+			"kind":              int(s.kind),
+			"startTimeUnixNano": strconv.FormatInt(s.start.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(s.end.UnixNano(), 10),
+			"attributes":        otlpAttributes(s.attributes),
+		}
+		if s.parent.IsValid() {
+			span["parentSpanId"] = s.parent.String()
+		}
+		if s.status.Code != 0 {
+			span["status"] = map[string]any{"code": s.status.Code, "message": s.status.Message}
+		}
+		out = append(out, span)
+	}
+
+	return map[string]any{
+		"resourceSpans": []map[string]any{{
+			"resource": map[string]any{
+				"attributes": otlpAttributes([]Attribute{{Key: "service.name", Value: service}}),
+			},
+			"scopeSpans": []map[string]any{{
+				"scope": map[string]any{"name": "eros/shared/tracing"},
+				"spans": out,
+			}},
+		}},
+	}
+}
+
+func otlpAttributes(attrs []Attribute) []map[string]any {
+	out := make([]map[string]any, 0, len(attrs))
+	for _, a := range attrs {
+		out = append(out, map[string]any{"key": a.Key, "value": otlpValue(a.Value)})
+	}
+	return out
+}
+
+func otlpValue(v any) map[string]any {
+	switch v := v.(type) {
+	case string:
+		return map[string]any{"stringValue": v}
+	case bool:
+		return map[string]any{"boolValue": v}
+	case int:
+		return map[string]any{"intValue": strconv.Itoa(v)}
+	case int64:
+		return map[string]any{"intValue": strconv.FormatInt(v, 10)}
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return map[string]any{"stringValue": strconv.FormatFloat(v, 'g', -1, 64)}
+		}
+		return map[string]any{"doubleValue": v}
+	}
+	return map[string]any{"stringValue": fmt.Sprint(v)}
+}
+
+// parseHeaders - OTEL_EXPORTER_OTLP_HEADERS biçimi: "anahtar1=değer1,anahtar2=değer2"
+func parseHeaders(v string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return headers
+}
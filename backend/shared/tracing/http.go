@@ -0,0 +1,82 @@
+// http.go - Gelen istekler için sunucu, giden istekler için istemci span'leri
+package tracing
+
+import (
+	"eros/shared/server"
+	"net/http"
+	"strconv"
+)
+
+// untraced - Sağlık kontrolleri ve metrik kazıma sık çağrılır; iz açmazlar
+var untraced = map[string]bool{"/healthz": true, "/readyz": true, "/health": true, "/metrics": true}
+
+// Middleware - router.Use ile eklenir; gelen traceparent'ı okur ve "GET /api/users/{id}" adlı sunucu span'i açar
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := server.RouteTemplate(r)
+		if untraced[route] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := Extract(r.Context(), r.Header)
+		ctx, span := start(ctx, r.Method+" "+route, KindServer)
+		if span == nil {
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+		defer span.End()
+
+		span.SetAttr("http.request.method", r.Method)
+		span.SetAttr("http.route", route)
+		span.SetAttr("url.path", r.URL.Path)
+
+		rec := server.NewResponseRecorder(w)
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		span.SetAttr("http.response.status_code", rec.Status)
+		if rec.Status >= http.StatusInternalServerError {
+			span.SetError(http.StatusText(rec.Status))
+		}
+	})
+}
+
+// Transport - Giden isteklere istemci span'i açar ve traceparent başlığını ekler
+// Gateway'in upstream'lere vekil ettiği isteklerde iz bu sayede servislerde devam eder.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx, span := start(r.Context(), r.Method+" "+r.URL.Host, KindClient)
+	if span == nil {
+		return t.base.RoundTrip(r)
+	}
+	defer span.End()
+
+	span.SetAttr("http.request.method", r.Method)
+	span.SetAttr("server.address", r.URL.Host)
+	span.SetAttr("url.path", r.URL.Path)
+
+	// RoundTripper isteği değiştirmemeli; başlık kopyaya yazılır
+	out := r.Clone(ctx)
+	Inject(ctx, out.Header)
+
+	resp, err := t.base.RoundTrip(out)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttr("http.response.status_code", resp.StatusCode)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetError("upstream answered " + strconv.Itoa(resp.StatusCode))
+	}
+	return resp, nil
+}
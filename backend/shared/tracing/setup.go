@@ -0,0 +1,75 @@
+// setup.go - Ortam değişkenlerinden izleme kurulumu (OpenTelemetry SDK değişken adlarıyla)
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Setup - OTEL_TRACES_EXPORTER'a göre izlemeyi aç
+//
+//	OTEL_TRACES_EXPORTER                 none (varsayılan), otlp, console (stdout)
+//	OTEL_EXPORTER_OTLP_ENDPOINT          Toplayıcı adresi, varsayılan http://localhost:4318 (/v1/traces eklenir)
+//	OTEL_EXPORTER_OTLP_TRACES_ENDPOINT   Tam adres (ENDPOINT'i ezer)
+//	OTEL_EXPORTER_OTLP_HEADERS           anahtar=değer,... (örn. kimlik doğrulama)
+//	OTEL_TRACES_SAMPLER_ARG              Kök izlerin örnekleme oranı 0..1 (varsayılan 1)
+//	OTEL_SERVICE_NAME                    service yerine kullanılacak ad
+func Setup(service string) error {
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		service = name
+	}
+
+	var exporter Exporter
+	switch kind := strings.ToLower(os.Getenv("OTEL_TRACES_EXPORTER")); kind {
+	case "", "none":
+		SetProvider(nil)
+		return nil
+	case "otlp":
+		exporter = NewOTLPExporter(otlpEndpoint(), parseHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")))
+	case "console", "stdout":
+		exporter = NewStdoutExporter(os.Stdout)
+	default:
+		return fmt.Errorf("tracing: unknown OTEL_TRACES_EXPORTER %q", kind)
+	}
+
+	ratio := 1.0
+	if v := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); v != "" {
+		r, err := strconv.ParseFloat(v, 64)
+		if err != nil || r < 0 || r > 1 {
+			return fmt.Errorf("tracing: invalid OTEL_TRACES_SAMPLER_ARG %q", v)
+		}
+		ratio = r
+	}
+
+	SetProvider(NewProvider(service, exporter, ratio))
+	return nil
+}
+
+func otlpEndpoint() string {
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); v != "" {
+		return v
+	}
+	base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if base == "" {
+		base = "http://localhost:4318"
+	}
+	return strings.TrimRight(base, "/") + "/v1/traces"
+}
+
+// Shutdown - Kuyruktaki span'leri gönder (süreç kapanırken, HTTP istekleri boşaltıldıktan sonra)
+func Shutdown(ctx context.Context) error {
+	p := provider.Swap(nil)
+	if p == nil {
+		return nil
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+	return p.Shutdown(ctx)
+}
@@ -0,0 +1,317 @@
+// trace.go - Dağıtık izleme: span'ler, W3C trace context ve OTLP'ye aktarım
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TraceID - 16 baytlık iz kimliği
+type TraceID [16]byte
+
+// SpanID - 8 baytlık span kimliği
+type SpanID [8]byte
+
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+func (s SpanID) String() string  { return hex.EncodeToString(s[:]) }
+
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+func (s SpanID) IsValid() bool  { return s != SpanID{} }
+
+// SpanKind - OTLP span türü
+type SpanKind int
+
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+// SpanContext - Süreçler arasında taşınan kimlikler
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+	Remote  bool // Başka bir süreçten (traceparent başlığından) geldi
+}
+
+// IsValid - İz ve span kimliği dolu mu
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Span - Zamanlanmış bir işlem
+// nil *Span üzerinde tüm metotlar güvenlidir; izleme kapalıyken Start nil döner.
+type Span struct {
+	provider *Provider
+	context  SpanContext
+	parent   SpanID
+	name     string
+	kind     SpanKind
+	start    time.Time
+
+	mu         sync.Mutex
+	end        time.Time
+	attributes []Attribute
+	status     Status
+	ended      bool
+}
+
+// Attribute - Span'e eklenen anahtar/değer
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Status - Span sonucu (OTLP: 0 unset, 1 ok, 2 error)
+type Status struct {
+	Code    int
+	Message string
+}
+
+const statusError = 2
+
+type spanKey struct{}
+type remoteKey struct{}
+
+// Start - ctx'teki span'in (ya da gelen traceparent'ın) altında yeni bir span başlat
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return start(ctx, name, KindInternal)
+}
+
+// StartClient - Başka bir sisteme (veritabanı, OpenRouter) yapılan çağrı için span başlat
+func StartClient(ctx context.Context, name string) (context.Context, *Span) {
+	return start(ctx, name, KindClient)
+}
+
+func start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	p := provider.Load()
+	if p == nil {
+		return ctx, nil
+	}
+
+	parent := SpanContextFromContext(ctx)
+	sc := SpanContext{SpanID: newSpanID()}
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID
+		sc.Sampled = parent.Sampled
+	} else {
+		sc.TraceID = newTraceID()
+		sc.Sampled = p.sample(sc.TraceID)
+	}
+
+	span := &Span{provider: p, context: sc, name: name, kind: kind, start: time.Now()}
+	if parent.IsValid() {
+		span.parent = parent.SpanID
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// FromContext - ctx'teki span (yoksa nil)
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// SpanContextFromContext - ctx'teki span'in ya da uzak üst span'in kimlikleri
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span := FromContext(ctx); span != nil {
+		return span.context
+	}
+	sc, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return sc
+}
+
+// SpanContext - Span'in kimlikleri
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.context
+}
+
+// SetAttr - Özellik ekle (string, bool, int/int64, float64; diğerleri metne çevrilir)
+func (s *Span) SetAttr(key string, value any) {
+	if s == nil || !s.context.Sampled {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.attributes = append(s.attributes, Attribute{Key: key, Value: value})
+	}
+}
+
+// RecordError - Span'i hatalı işaretle (nil hata yok sayılır)
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil || !s.context.Sampled {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.status = Status{Code: statusError, Message: err.Error()}
+	}
+}
+
+// SetError - Hata değeri olmadan span'i hatalı işaretle (örn. 5xx yanıt)
+func (s *Span) SetError(message string) {
+	s.RecordError(errors.New(message))
+}
+
+// End - Span'i bitir ve örneklendiyse aktarım kuyruğuna ekle
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	if s.context.Sampled {
+		s.provider.enqueue(s)
+	}
+}
+
+// Provider - Örnekleme ve aktarım
+type Provider struct {
+	service  string
+	exporter Exporter
+	ratio    float64
+
+	queue   chan *Span
+	done    chan struct{}
+	flushed chan struct{}
+	dropped atomic.Int64
+}
+
+var provider atomic.Pointer[Provider]
+
+const (
+	queueSize     = 2048
+	batchSize     = 512
+	flushInterval = 5 * time.Second
+)
+
+// NewProvider - Span'leri exporter'a toplu gönderen sağlayıcı (ratio: kök izlerin örnekleme oranı)
+func NewProvider(service string, exporter Exporter, ratio float64) *Provider {
+	p := &Provider{
+		service:  service,
+		exporter: exporter,
+		ratio:    ratio,
+		queue:    make(chan *Span, queueSize),
+		done:     make(chan struct{}),
+		flushed:  make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// SetProvider - Start'ın kullandığı sağlayıcıyı değiştir (nil izlemeyi kapatır)
+func SetProvider(p *Provider) {
+	provider.Store(p)
+}
+
+// sample - Kök iz örneklensin mi (iz kimliğine göre, süreçler arasında tutarlı)
+func (p *Provider) sample(id TraceID) bool {
+	switch {
+	case p.ratio >= 1:
+		return true
+	case p.ratio <= 0:
+		return false
+	}
+	return float64(binary.BigEndian.Uint64(id[8:])>>11)/(1<<53) < p.ratio
+}
+
+// enqueue - Kuyruk doluysa span düşürülür (istekler aktarım yüzünden beklemez)
+func (p *Provider) enqueue(s *Span) {
+	select {
+	case p.queue <- s:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+func (p *Provider) run() {
+	defer close(p.flushed)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := p.exporter.Export(ctx, p.service, batch); err != nil {
+			slog.Warn("span export failed", "spans", len(batch), "error", err)
+		}
+		cancel()
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case s := <-p.queue:
+			batch = append(batch, s)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-p.done:
+			for {
+				select {
+				case s := <-p.queue:
+					batch = append(batch, s)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// Shutdown - Kuyruktaki span'leri gönder ve dur
+func (p *Provider) Shutdown(ctx context.Context) error {
+	close(p.done)
+	select {
+	case <-p.flushed:
+	case <-ctx.Done():
+		return fmt.Errorf("tracing: flushing spans: %w", ctx.Err())
+	}
+	if n := p.dropped.Load(); n > 0 {
+		return fmt.Errorf("tracing: %d spans dropped (queue full)", n)
+	}
+	return nil
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+	return id
+}
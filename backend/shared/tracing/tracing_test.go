@@ -0,0 +1,263 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// recordingExporter - Aktarılan span'leri saklar
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+func (e *recordingExporter) Export(ctx context.Context, service string, spans []*Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+// withProvider - Test süresince ratio oranıyla örnekleyen sağlayıcı kur
+// Dönen fonksiyon kuyruğu boşaltır ve aktarılan span'leri verir.
+func withProvider(t *testing.T, ratio float64) func() []*Span {
+	t.Helper()
+	exporter := &recordingExporter{}
+	p := NewProvider("test", exporter, ratio)
+	SetProvider(p)
+	t.Cleanup(func() { SetProvider(nil) })
+	return func() []*Span {
+		SetProvider(nil)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := p.Shutdown(ctx); err != nil {
+			t.Fatal(err)
+		}
+		exporter.mu.Lock()
+		defer exporter.mu.Unlock()
+		return exporter.spans
+	}
+}
+
+// spanNamed - Adı verilen span
+func spanNamed(t *testing.T, spans []*Span, name string) *Span {
+	t.Helper()
+	for _, s := range spans {
+		if s.name == name {
+			return s
+		}
+	}
+	t.Fatalf("no span named %q among %d spans", name, len(spans))
+	return nil
+}
+
+func (s *Span) attr(key string) any {
+	for _, a := range s.attributes {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return nil
+}
+
+func TestParseTraceparent(t *testing.T) {
+	const traceID, spanID = "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	tests := []struct {
+		name    string
+		header  string
+		ok      bool
+		sampled bool
+	}{
+		{"sampled", "00-" + traceID + "-" + spanID + "-01", true, true},
+		{"not sampled", "00-" + traceID + "-" + spanID + "-00", true, false},
+		{"surrounding spaces", " 00-" + traceID + "-" + spanID + "-01 ", true, true},
+		{"future version with extra field", "01-" + traceID + "-" + spanID + "-01-extra", true, true},
+		{"version 00 with extra field", "00-" + traceID + "-" + spanID + "-01-extra", false, false},
+		{"forbidden version ff", "ff-" + traceID + "-" + spanID + "-01", false, false},
+		{"zero trace id", "00-" + strings.Repeat("0", 32) + "-" + spanID + "-01", false, false},
+		{"zero span id", "00-" + traceID + "-" + strings.Repeat("0", 16) + "-01", false, false},
+		{"short trace id", "00-" + traceID[:30] + "-" + spanID + "-01", false, false},
+		{"not hex", "00-" + strings.Repeat("z", 32) + "-" + spanID + "-01", false, false},
+		{"bad flags", "00-" + traceID + "-" + spanID + "-zz", false, false},
+		{"empty", "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, ok := parseTraceparent(tt.header)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if sc.TraceID.String() != traceID || sc.SpanID.String() != spanID || sc.Sampled != tt.sampled || !sc.Remote {
+				t.Fatalf("span context = %+v", sc)
+			}
+		})
+	}
+}
+
+func TestInjectExtractRoundTrip(t *testing.T) {
+	for _, sampled := range []bool{true, false} {
+		in := http.Header{}
+		in.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+		if sampled {
+			in.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		}
+
+		ctx := Extract(context.Background(), in)
+		out := http.Header{}
+		Inject(ctx, out)
+		if got := out.Get(TraceparentHeader); got != in.Get(TraceparentHeader) {
+			t.Fatalf("round trip = %q, want %q", got, in.Get(TraceparentHeader))
+		}
+	}
+
+	// Geçersiz başlık bağlamı değiştirmez, Inject de bir şey yazmaz
+	in := http.Header{}
+	in.Set(TraceparentHeader, "garbage")
+	ctx := Extract(context.Background(), in)
+	if SpanContextFromContext(ctx).IsValid() {
+		t.Fatal("invalid header produced a span context")
+	}
+	out := http.Header{}
+	Inject(ctx, out)
+	if _, ok := out[http.CanonicalHeaderKey(TraceparentHeader)]; ok {
+		t.Fatal("Inject wrote a header without a span context")
+	}
+}
+
+func TestStartDisabled(t *testing.T) {
+	SetProvider(nil)
+	ctx, span := Start(context.Background(), "noop")
+	if span != nil || FromContext(ctx) != nil {
+		t.Fatal("Start created a span without a provider")
+	}
+	// nil span üzerindeki çağrılar güvenlidir
+	span.SetAttr("k", "v")
+	span.RecordError(context.Canceled)
+	span.End()
+}
+
+func TestChildSpans(t *testing.T) {
+	export := withProvider(t, 1)
+
+	ctx, root := Start(context.Background(), "root")
+	_, child := StartClient(ctx, "db.query")
+	child.SetAttr("db.operation", "SELECT")
+	child.RecordError(context.DeadlineExceeded)
+	child.End()
+	root.End()
+	root.End() // İkinci End yok sayılır
+
+	spans := export()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	r, c := spanNamed(t, spans, "root"), spanNamed(t, spans, "db.query")
+	if r.parent.IsValid() || r.kind != KindInternal {
+		t.Fatalf("root = %+v", r)
+	}
+	if c.context.TraceID != r.context.TraceID || c.parent != r.context.SpanID || c.kind != KindClient {
+		t.Fatalf("child is not linked to root: child %+v, root %+v", c.context, r.context)
+	}
+	if c.attr("db.operation") != "SELECT" || c.status.Code != statusError || c.status.Message != context.DeadlineExceeded.Error() {
+		t.Fatalf("child = attrs %v status %+v", c.attributes, c.status)
+	}
+}
+
+func TestUnsampledTraceIsNotExported(t *testing.T) {
+	export := withProvider(t, 0)
+
+	// Örneklenmemiş kök iz alt span'lere ve giden başlığa taşınır
+	ctx, root := Start(context.Background(), "root")
+	ctx, child := Start(ctx, "child")
+	h := http.Header{}
+	Inject(ctx, h)
+	child.End()
+	root.End()
+
+	if !strings.HasSuffix(h.Get(TraceparentHeader), "-00") {
+		t.Fatalf("traceparent = %q, want the unsampled flag", h.Get(TraceparentHeader))
+	}
+	if spans := export(); len(spans) != 0 {
+		t.Fatalf("exported %d unsampled spans", len(spans))
+	}
+}
+
+func TestPropagationThroughHTTP(t *testing.T) {
+	export := withProvider(t, 1)
+
+	// Upstream servis: gelen traceparent'ı sunucu span'ine bağlar
+	var upstreamSpan SpanContext
+	router := mux.NewRouter()
+	router.Use(Middleware)
+	router.HandleFunc("/api/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		upstreamSpan = SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	router.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+	upstream := httptest.NewServer(router)
+	defer upstream.Close()
+
+	// Gateway: gelen isteğin izinde Transport ile upstream'i çağırır
+	const incoming = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	client := &http.Client{Transport: Transport(nil)}
+	h := http.Header{}
+	h.Set(TraceparentHeader, incoming)
+	ctx, gateway := Start(Extract(context.Background(), h), "gateway")
+
+	for _, path := range []string{"/api/users/7", "/healthz"} {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if req.Header.Get(TraceparentHeader) != "" {
+			t.Fatal("Transport modified the caller's request")
+		}
+	}
+	gateway.End()
+
+	spans := export()
+	g := spanNamed(t, spans, "gateway")
+	call := spanNamed(t, spans, "GET "+strings.TrimPrefix(upstream.URL, "http://"))
+	server := spanNamed(t, spans, "GET /api/users/{id}")
+
+	if g.context.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || g.parent.String() != "00f067aa0ba902b7" {
+		t.Fatalf("gateway span did not continue the incoming trace: %+v parent %s", g.context, g.parent)
+	}
+	if call.parent != g.context.SpanID || call.kind != KindClient {
+		t.Fatalf("client span parent = %s, want %s", call.parent, g.context.SpanID)
+	}
+	if server.parent != call.context.SpanID || server.context.TraceID != g.context.TraceID || server.kind != KindServer {
+		t.Fatalf("server span %+v parent %s, want child of client span %s", server.context, server.parent, call.context.SpanID)
+	}
+	if upstreamSpan != server.context {
+		t.Fatalf("handler context = %+v, want the server span", upstreamSpan)
+	}
+	if server.attr("http.route") != "/api/users/{id}" || server.attr("http.response.status_code") != http.StatusInternalServerError || server.status.Code != statusError {
+		t.Fatalf("server span attrs %v status %+v", server.attributes, server.status)
+	}
+	if call.status.Code != statusError {
+		t.Fatalf("client span status = %+v, want error for a 5xx answer", call.status)
+	}
+
+	// Sağlık kontrolü sunucu span'i açmaz
+	for _, s := range spans {
+		if s.name == "GET /healthz" {
+			t.Fatal("health check was traced")
+		}
+	}
+	if len(spans) != 4 {
+		t.Fatalf("exported %d spans, want gateway, two client calls and one server span", len(spans))
+	}
+}
@@ -0,0 +1,21 @@
+// ai_metrics.go - OpenRouter çağrılarının süre, hata ve token sayaçları
+package utils
+
+import "eros/shared/metrics"
+
+// AI görevleri (metrik etiketi)
+const (
+	TaskChatAnalysis   = "chat_analysis"
+	TaskDateSuggestion = "date_suggestion"
+	TaskIceBreaker     = "ice_breaker"
+)
+
+var (
+	aiDuration = metrics.NewHistogram("ai_request_duration_seconds",
+		"OpenRouter call latency by task and model.",
+		[]float64{.25, .5, 1, 2.5, 5, 10, 20, 30, 60}, "task", "model")
+	aiRequests = metrics.NewCounter("ai_requests_total",
+		"OpenRouter calls by task and result (ok, error, circuit_open).", "task", "result")
+	aiTokens = metrics.NewCounter("ai_tokens_total",
+		"Tokens reported by OpenRouter by task and kind (prompt, completion).", "task", "kind")
+)
@@ -0,0 +1,86 @@
+// circuit.go - Dış servis çağrıları için devre kesici (circuit breaker)
+package utils
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen - Devre açıkken çağrı yapılmadan dönülür
+var ErrCircuitOpen = errors.New("circuit open: provider is failing, try again later")
+
+// Devre durumları
+const (
+	CircuitClosed   = "closed"    // Çağrılar normal
+	CircuitOpen     = "open"      // Ardışık hatalar sonrası çağrılar bekletiliyor
+	CircuitHalfOpen = "half_open" // Bekleme bitti, deneme çağrısına izin veriliyor
+)
+
+// CircuitBreaker - Threshold ardışık hatada açılır, Cooldown sonra tek deneme çağrısına izin verir;
+// deneme başarılıysa kapanır, başarısızsa yeniden açılır.
+type CircuitBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	lastError string
+}
+
+// NewCircuitBreaker - threshold ardışık hata, cooldown bekleme süresi
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{Threshold: threshold, Cooldown: cooldown}
+}
+
+// Allow - Çağrı yapılabilir mi
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.Threshold {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < b.Cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// Record - Çağrının sonucunu işle
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if err == nil {
+		b.failures = 0
+		b.lastError = ""
+		return
+	}
+
+	b.failures++
+	b.lastError = strings.TrimSpace(err.Error())
+	if b.failures >= b.Threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+// State - Anlık durum ve son hata (sağlık kontrolleri için)
+func (b *CircuitBreaker) State() (string, string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.failures < b.Threshold:
+		return CircuitClosed, b.lastError
+	case b.probing || time.Since(b.openedAt) >= b.Cooldown:
+		return CircuitHalfOpen, b.lastError
+	default:
+		return CircuitOpen, b.lastError
+	}
+}
@@ -0,0 +1,199 @@
+// contact_detector.go - İletişim bilgisi ve platform dışına yönlendirme tespiti
+package utils
+
+import (
+	"math/big"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ContactKind - Tespit edilen iletişim bilgisi türü
+type ContactKind string
+
+const (
+	ContactPhone        ContactKind = "phone"
+	ContactEmail        ContactKind = "email"
+	ContactSocialHandle ContactKind = "social_handle"
+	ContactURL          ContactKind = "url"
+	ContactIBAN         ContactKind = "iban"
+	ContactCryptoWallet ContactKind = "crypto_wallet"
+)
+
+// ContactMatch - Metinde bulunan iletişim bilgisi
+type ContactMatch struct {
+	Kind  ContactKind `json:"kind"`
+	Text  string      `json:"text"`
+	Start int         `json:"start"`
+	End   int         `json:"end"`
+}
+
+type contactPattern struct {
+	kind     ContactKind
+	re       *regexp.Regexp
+	validate func(string) bool
+}
+
+// contactPatterns - Öncelik sırasına göre (önce gelen eşleşme çakışanları ezer)
+var contactPatterns = []contactPattern{
+	{kind: ContactIBAN, re: regexp.MustCompile(`(?i)\b[a-z]{2}\d{2}(?:[ -]?[a-z0-9]{4}){2,7}(?:[ -]?[a-z0-9]{1,4})?\b`), validate: validIBAN},
+	{kind: ContactCryptoWallet, re: regexp.MustCompile(`\b0x[a-fA-F0-9]{40}\b`)},
+	{kind: ContactCryptoWallet, re: regexp.MustCompile(`\bbc1[a-z0-9]{25,59}\b`)},
+	{kind: ContactCryptoWallet, re: regexp.MustCompile(`\b[13][a-km-zA-HJ-NP-Z1-9]{25,34}\b`), validate: hasLetterAndDigit},
+	{kind: ContactCryptoWallet, re: regexp.MustCompile(`\bT[1-9A-HJ-NP-Za-km-z]{33}\b`), validate: hasLetterAndDigit},
+	{kind: ContactEmail, re: regexp.MustCompile(`(?i)[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`)},
+	{kind: ContactEmail, re: regexp.MustCompile(`(?i)\b[a-z0-9._%+-]{2,}\s*(?:\[at\]|\(at\)|\bat\b|@)\s*(?:gmail|hotmail|outlook|yahoo|icloud|yandex|protonmail)\b`)},
+	{kind: ContactURL, re: regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s]+`)},
+	// Çıplak alan adı: yaygın uzantılar tek başına, kelimeye de benzeyenler ("you.me") yalnızca yol ile
+	{kind: ContactURL, re: regexp.MustCompile(`(?i)\b[a-z0-9-]+(?:\.[a-z0-9-]+)*\.(?:com|net|org|io|xyz)(?:\.tr)?(?:/[^\s]*)?\b`)},
+	{kind: ContactURL, re: regexp.MustCompile(`(?i)\b[a-z0-9-]+(?:\.[a-z0-9-]+)*\.(?:me|co|app|ly|gg|link|site|online|tr)/[^\s]+`)},
+	{kind: ContactSocialHandle, re: regexp.MustCompile(`(?i)\b(?:insta(?:gram)?|ig|snap(?:chat)?|sc|telegram|tg|tiktok|twitter|onlyfans|discord|kik|facebook|fb)m?\s*(?:[:=]\s*@?|\s+@)[a-z0-9_.]{3,30}`)},
+	// "my ig is ayse92", "instam ayse.92": kullanıcı adı rakam, "_" veya "." içermeli
+	{kind: ContactSocialHandle, re: regexp.MustCompile(`(?i)\b(?:insta(?:gram)?|ig|snap(?:chat)?|sc|telegram|tg|tiktok|twitter|discord|kik|facebook|fb)(?:m|ım|im|dan|den|tan|ten)?\s+(?:(?:is|hesabım|hesabim|adım|adim|id|username)\s+)?[a-z0-9_.]*[._0-9][a-z0-9_.]*`), validate: validHandle},
+	{kind: ContactSocialHandle, re: regexp.MustCompile(`(?:^|[^\w@.])(@[A-Za-z0-9_.]{3,30})`)},
+	// Telefon: yalnızca telefon biçimleri; tarih, saat ve düz sayılar eşleşmez
+	// TR cep (+90/90/0 önekli veya öneksiz operatör kodu 50x, 53x, 54x, 55x, 561)
+	{kind: ContactPhone, re: regexp.MustCompile(`(?:^|[^\w+])((?:(?:\+|00)?90[\s.-]?|0[\s.-]?)?\(?5(?:0[1-7]|[34]\d|5[1-9]|61)\)?[\s.-]?\d{3}[\s.-]?\d{2}[\s.-]?\d{2})\b`), validate: validPhone},
+	// TR sabit hat (alan kodu 2xx-4xx, önek zorunlu)
+	{kind: ContactPhone, re: regexp.MustCompile(`(?:^|[^\w+])((?:(?:\+|00)?90[\s.-]?|\(?0)\(?[2-4]\d{2}\)?[\s.-]?\d{3}[\s.-]?\d{2}[\s.-]?\d{2})\b`), validate: validPhone},
+	// Uluslararası (+ veya 00 önekli)
+	{kind: ContactPhone, re: regexp.MustCompile(`(?:^|[^\w+])((?:\+|00)[1-9][\d\s().-]{6,}\d)`), validate: validPhone},
+	// Kuzey Amerika 3-3-4 gruplama ("415-555-0132", "(415) 555 0132")
+	{kind: ContactPhone, re: regexp.MustCompile(`(?:^|[^\w+])(\(?[2-9]\d{2}\)?[\s.-]\d{3}[\s.-]\d{4})\b`), validate: validPhone},
+}
+
+// DetectContactInfo - Metindeki telefon, e-posta, sosyal medya, URL, IBAN ve cüzdan adreslerini bul
+func DetectContactInfo(text string) []ContactMatch {
+	var matches []ContactMatch
+	taken := make([]bool, len(text))
+
+	for _, p := range contactPatterns {
+		for _, loc := range p.re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			// Grup varsa eşleşme sadece grubun kendisidir (önündeki ayraç hariç)
+			if len(loc) > 2 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			candidate := text[start:end]
+			if p.validate != nil && !p.validate(candidate) {
+				continue
+			}
+			if overlaps(taken, start, end) {
+				continue
+			}
+			for i := start; i < end; i++ {
+				taken[i] = true
+			}
+			matches = append(matches, ContactMatch{Kind: p.kind, Text: candidate, Start: start, End: end})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	return matches
+}
+
+// ContainsContactInfo - Metinde iletişim bilgisi var mı
+func ContainsContactInfo(text string) bool {
+	return len(DetectContactInfo(text)) > 0
+}
+
+func overlaps(taken []bool, start, end int) bool {
+	for i := start; i < end; i++ {
+		if taken[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// dateShape - Yıl içeren tarih ("2024-01-15", "15.01.2024")
+var dateShape = regexp.MustCompile(`\b(?:\d{4}[./-]\d{1,2}[./-]\d{1,2}|\d{1,2}[./-]\d{1,2}[./-]\d{4})\b`)
+
+// validPhone - TR cep/sabit ve uluslararası numaralar (10-15 hane, "+" ile 8-15 hane)
+func validPhone(candidate string) bool {
+	if dateShape.MatchString(candidate) {
+		return false
+	}
+	digits := 0
+	for _, r := range candidate {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	if strings.HasPrefix(candidate, "+") {
+		return digits >= 8 && digits <= 15
+	}
+	return digits >= 10 && digits <= 15
+}
+
+// validHandle - Kullanıcı adı en az 3 karakter olmalı ve harf içermeli ("ig 100" sayı değildir)
+func validHandle(candidate string) bool {
+	fields := strings.Fields(candidate)
+	handle := fields[len(fields)-1]
+	return len(handle) >= 3 && strings.IndexFunc(handle, unicode.IsLetter) >= 0
+}
+
+// validIBAN - ISO 13616 mod-97 kontrolü
+func validIBAN(candidate string) bool {
+	iban := strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(candidate))
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	if strings.HasPrefix(iban, "TR") && len(iban) != 26 {
+		return false
+	}
+
+	rearranged := iban[4:] + iban[:4]
+	var numeric strings.Builder
+	for _, r := range rearranged {
+		switch {
+		case r >= '0' && r <= '9':
+			numeric.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			numeric.WriteString(strconv.Itoa(int(r-'A') + 10))
+		default:
+			return false
+		}
+	}
+
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	if !ok {
+		return false
+	}
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// hasLetterAndDigit - Base58 adres adayları sıradan kelime olmasın
+func hasLetterAndDigit(candidate string) bool {
+	return strings.IndexFunc(candidate, unicode.IsLetter) >= 0 && strings.IndexFunc(candidate[1:], unicode.IsDigit) >= 0
+}
+
+// ContactPolicy - Sohbette iletişim bilgisi paylaşım politikası
+// Her iki taraf da en az MinMessagesEach mesaj gönderene kadar iletişim bilgisi engellenir.
+type ContactPolicy struct {
+	MinMessagesEach int
+}
+
+// DefaultContactPolicy - Varsayılan politika
+func DefaultContactPolicy() ContactPolicy {
+	return ContactPolicy{MinMessagesEach: 10}
+}
+
+// ContactPolicyFromEnv - Politikayı ortam değişkenlerinden oku
+func ContactPolicyFromEnv() ContactPolicy {
+	policy := DefaultContactPolicy()
+	if v := os.Getenv("CONTACT_INFO_MIN_MESSAGES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			policy.MinMessagesEach = n
+		}
+	}
+	return policy
+}
+
+// Allows - Gönderen ve karşı tarafın mesaj sayısına göre iletişim bilgisi paylaşılabilir mi
+func (p ContactPolicy) Allows(senderMessages, otherMessages int) bool {
+	return senderMessages >= p.MinMessagesEach && otherMessages >= p.MinMessagesEach
+}
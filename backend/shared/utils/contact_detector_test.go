@@ -0,0 +1,100 @@
+package utils
+
+import "testing"
+
+func TestDetectContactInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want ContactKind // boş: iletişim bilgisi yok
+	}{
+		// Telefon (TR)
+		{"tr mobile", "Numaram 0555 123 45 67", ContactPhone},
+		{"tr mobile compact", "beni ara 05551234567", ContactPhone},
+		{"tr mobile +90", "+90 532 123 45 67 yaz", ContactPhone},
+		{"tr mobile without prefix", "555 123 45 67 whatsapp", ContactPhone},
+		{"tr mobile parentheses", "0 (544) 123-45-67", ContactPhone},
+		{"tr landline", "evi ara 0212 555 12 34", ContactPhone},
+		// Telefon (EN)
+		{"international", "call me on +44 20 7946 0958", ContactPhone},
+		{"international 00", "my number is 0044 20 7946 0958", ContactPhone},
+		{"us grouped", "text me at (415) 555-0132", ContactPhone},
+		{"us dashed", "415-555-0132 is my cell", ContactPhone},
+
+		// Diğer türler
+		{"email", "mailim ayse92@gmail.com", ContactEmail},
+		{"email obfuscated", "ayse92 at gmail", ContactEmail},
+		{"url", "check https://example.org/me", ContactURL},
+		{"bare domain", "profilim ayse.com'da", ContactURL},
+		{"link with path", "t.me/ayse92 yaz", ContactURL},
+		{"handle with colon", "Instagram: @ayse.eros", ContactSocialHandle},
+		{"handle with is", "my ig is ayse92", ContactSocialHandle},
+		{"handle tr", "instam ayse_92 ekle", ContactSocialHandle},
+		{"handle snapchat", "add my snap ayse.92", ContactSocialHandle},
+		{"bare handle", "beni @ayse_eros olarak bul", ContactSocialHandle},
+		{"iban", "IBAN TR33 0006 1005 1978 6457 8413 26", ContactIBAN},
+		{"wallet", "0x52908400098527886E0F7030069857D2E4169EE7", ContactCryptoWallet},
+
+		// Tarih, saat ve sayılar (TR)
+		{"tr date and time", "Tarih 2024-01-15 10:30", ""},
+		{"tr dotted date", "15.01.2024 cumartesi buluşalım mı", ""},
+		{"tr time", "saat 21.30'da kahve?", ""},
+		{"tr price", "bilet 1.250 TL, 3 kişi 3750 TL", ""},
+		{"tr year", "2019'dan beri İstanbul'dayım", ""},
+		// Tarih, saat ve sayılar (EN)
+		{"number list", "3 5 7 9 11 13 15 17", ""},
+		{"large number", "my score is 1000000000", ""},
+		{"en date", "see you on 01/15/2024 at 7:30 pm", ""},
+		{"height and age", "I'm 28, 1.75 m and run 10 km", ""},
+		{"missing space", "see you.me too", ""},
+		{"sentence", "I love hiking and coffee", ""},
+		{"instagram mention", "I'm on instagram a lot", ""},
+		{"ig with number", "ig 100 takipçim var", ""},
+		{"tr sentence", "Kitap okumayı ve yürüyüşü severim", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := DetectContactInfo(tt.text)
+			if tt.want == "" {
+				if len(matches) > 0 {
+					t.Errorf("DetectContactInfo(%q) = %+v, want none", tt.text, matches)
+				}
+				return
+			}
+			if len(matches) != 1 || matches[0].Kind != tt.want {
+				t.Errorf("DetectContactInfo(%q) = %+v, want one %s", tt.text, matches, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectContactInfoPosition(t *testing.T) {
+	text := "Numaram 0555 123 45 67, yaz bana"
+	matches := DetectContactInfo(text)
+	if len(matches) != 1 {
+		t.Fatalf("got %+v, want one match", matches)
+	}
+	m := matches[0]
+	if m.Text != "0555 123 45 67" || text[m.Start:m.End] != m.Text {
+		t.Errorf("match = %+v, want exactly the number", m)
+	}
+}
+
+func TestContactPolicyAllows(t *testing.T) {
+	policy := ContactPolicy{MinMessagesEach: 10}
+	tests := []struct {
+		sender, other int
+		want          bool
+	}{
+		{0, 0, false},
+		{10, 9, false},
+		{9, 10, false},
+		{10, 10, true},
+	}
+	for _, tt := range tests {
+		if got := policy.Allows(tt.sender, tt.other); got != tt.want {
+			t.Errorf("Allows(%d, %d) = %v, want %v", tt.sender, tt.other, got, tt.want)
+		}
+	}
+}
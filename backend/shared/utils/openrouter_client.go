@@ -3,17 +3,25 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"eros/shared/apierror"
+	"eros/shared/logging"
+	"eros/shared/tracing"
 	"eros/shared/types"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
+	"time"
 )
 
 type OpenRouterClient struct {
 	BaseURL string
 	Keys    map[string]string // model -> key
+	Breaker *CircuitBreaker   // nil ise devre kesici yok
 }
 
 type OpenRouterRequest struct {
@@ -51,11 +59,30 @@ func NewOpenRouterClientFromEnv() *OpenRouterClient {
 			"google/gemma-3-27b-it:free":  apiKey,
 			"google/gemma-3n-e4b-it:free": apiKey,
 		},
+		Breaker: NewCircuitBreaker(5, 30*time.Second),
 	}
 }
 
+// Health - Sağlık kontrolü: anahtar tanımlı mı, devre açık mı (API'ye istek atmaz)
+func (c *OpenRouterClient) Health(ctx context.Context) error {
+	configured := false
+	for _, key := range c.Keys {
+		configured = configured || key != ""
+	}
+	if !configured {
+		return errors.New("OPENROUTER_API_KEY is not set")
+	}
+	if c.Breaker == nil {
+		return nil
+	}
+	if state, lastErr := c.Breaker.State(); state == CircuitOpen {
+		return fmt.Errorf("circuit %s: %s", state, lastErr)
+	}
+	return nil
+}
+
 // ChatAnalysis - Sohbet analizi (mistralai/mistral-7b-instruct)
-func (c *OpenRouterClient) ChatAnalysis(conversation string) (string, error) {
+func (c *OpenRouterClient) ChatAnalysis(ctx context.Context, conversation string) (string, error) {
 	prompt := fmt.Sprintf(`
     Bu sohbeti analiz et ve şu bilgileri çıkar:
     
@@ -76,11 +103,11 @@ func (c *OpenRouterClient) ChatAnalysis(conversation string) (string, error) {
     }
     `, conversation)
 
-	return c.callAPI(types.ModelChatAnalysis, prompt, 0.7)
+	return c.callAPI(ctx, TaskChatAnalysis, types.ModelChatAnalysis, prompt, 0.7)
 }
 
 // DateSuggestion - Date önerisi (google/gemma-7b-it)
-func (c *OpenRouterClient) DateSuggestion(user1, user2 map[string]interface{}) (string, error) {
+func (c *OpenRouterClient) DateSuggestion(ctx context.Context, user1, user2 map[string]interface{}) (string, error) {
 	prompt := fmt.Sprintf(`
     İki kişi için İstanbul'da eğlenceli bir date önerisi oluştur:
     
@@ -99,17 +126,16 @@ func (c *OpenRouterClient) DateSuggestion(user1, user2 map[string]interface{}) (
     }
     `, user1, user2)
 
-	return c.callAPI(types.ModelDateSuggestion, prompt, 0.8)
+	return c.callAPI(ctx, TaskDateSuggestion, types.ModelDateSuggestion, prompt, 0.8)
 }
 
-// SecurityFilter - Keyword-based güvenlik filtresi
+// SecurityFilter - Moderasyon motoru tabanlı güvenlik filtresi
 func (c *OpenRouterClient) SecurityFilter(message string) (bool, error) {
-	// Yeni keyword-based filter kullan
 	return SecurityFilter(message), nil
 }
 
 // IceBreaker - Buz kırıcı mesaj (huggingfaceh4/zephyr-7b-beta)
-func (c *OpenRouterClient) IceBreaker(user1, user2 map[string]interface{}) (string, error) {
+func (c *OpenRouterClient) IceBreaker(ctx context.Context, user1, user2 map[string]interface{}) (string, error) {
 	prompt := fmt.Sprintf(`
     İki kişi arasında doğal ve samimi bir buz kırıcı mesaj oluştur:
     
@@ -124,7 +150,7 @@ func (c *OpenRouterClient) IceBreaker(user1, user2 map[string]interface{}) (stri
     - Türkçe yaz
     `, user1, user2)
 
-	return c.callAPI(types.ModelIceBreaker, prompt, 0.9)
+	return c.callAPI(ctx, TaskIceBreaker, types.ModelIceBreaker, prompt, 0.9)
 }
 
 // ProfileMatching - Profil eşleştirme (Yeni algoritma)
@@ -134,12 +160,53 @@ func (c *OpenRouterClient) ProfileMatching(user1, user2 map[string]interface{})
 	return score, nil
 }
 
-// callAPI - Genel API çağrısı
-func (c *OpenRouterClient) callAPI(model, prompt string, temperature float64) (string, error) {
+// callAPI - Genel API çağrısı (devre açıksa istek atılmaz); task metrik etiketidir
+// Hatalar apierror.AIUnavailable ile sarılır (devre açıkken 503), handler'lar olduğu gibi yazabilir.
+func (c *OpenRouterClient) callAPI(ctx context.Context, task, model, prompt string, temperature float64) (string, error) {
+	ctx, span := tracing.StartClient(ctx, "openrouter."+task)
+	defer span.End()
+	span.SetAttr("ai.task", task)
+	span.SetAttr("ai.model", model)
+
 	apiKey := c.Keys[model]
 	if apiKey == "" {
-		return "", fmt.Errorf("API key for model %s not found", model)
+		err := fmt.Errorf("API key for model %s not found", model)
+		span.RecordError(err)
+		return "", apierror.AIUnavailable(err)
+	}
+	if c.Breaker != nil && !c.Breaker.Allow() {
+		aiRequests.Inc(task, "circuit_open")
+		span.RecordError(ErrCircuitOpen)
+		return "", apierror.Wrap(ErrCircuitOpen, http.StatusServiceUnavailable, apierror.CodeAIUnavailable, "AI provider is temporarily disabled, try again later")
 	}
+
+	start := time.Now()
+	content, usage, err := c.send(ctx, apiKey, model, prompt, temperature)
+	if c.Breaker != nil {
+		c.Breaker.Record(err)
+	}
+	elapsed := time.Since(start)
+	aiDuration.Observe(elapsed.Seconds(), task, model)
+	aiTokens.Add(float64(usage.PromptTokens), task, "prompt")
+	aiTokens.Add(float64(usage.CompletionTokens), task, "completion")
+	span.SetAttr("ai.usage.prompt_tokens", usage.PromptTokens)
+	span.SetAttr("ai.usage.completion_tokens", usage.CompletionTokens)
+	span.RecordError(err)
+
+	// İstem ve yanıt metni günlüğe yazılmaz
+	attrs := []any{"task", task, "model", model, "duration_ms", float64(elapsed.Microseconds()) / 1000}
+	if err != nil {
+		aiRequests.Inc(task, "error")
+		slog.WarnContext(ctx, "openrouter call failed", append(attrs, "error", err)...)
+		return "", apierror.AIUnavailable(err)
+	}
+	aiRequests.Inc(task, "ok")
+	slog.InfoContext(ctx, "openrouter call", append(attrs, "total_tokens", usage.TotalTokens)...)
+	return content, nil
+}
+
+// send - İsteği gönder, ilk yanıtı ve token kullanımını döndür
+func (c *OpenRouterClient) send(ctx context.Context, apiKey, model, prompt string, temperature float64) (string, Usage, error) {
 	request := OpenRouterRequest{
 		Model: model,
 		Messages: []Message{
@@ -154,43 +221,47 @@ func (c *OpenRouterClient) callAPI(model, prompt string, temperature float64) (s
 
 	jsonData, err := json.Marshal(request)
 	if err != nil {
-		return "", fmt.Errorf("request marshal error: %v", err)
+		return "", Usage{}, fmt.Errorf("request marshal error: %v", err)
 	}
 
-	req, err := http.NewRequest("POST", c.BaseURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("request creation error: %v", err)
+		return "", Usage{}, fmt.Errorf("request creation error: %v", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+apiKey)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("HTTP-Referer", "https://eros-app.com")
 	req.Header.Set("X-Title", "EROS Dating App")
+	// İsteği başlatan API çağrısıyla ilişkilendirmek için
+	if id := logging.RequestID(ctx); id != "" {
+		req.Header.Set(logging.RequestIDHeader, id)
+	}
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("API call error: %v", err)
+		return "", Usage{}, fmt.Errorf("API call error: %v", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("response read error: %v", err)
+		return "", Usage{}, fmt.Errorf("response read error: %v", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API error: %s - %s", resp.Status, string(body))
+		return "", Usage{}, fmt.Errorf("API error: %s - %s", resp.Status, string(body))
 	}
 
 	var response OpenRouterResponse
 	if err := json.Unmarshal(body, &response); err != nil {
-		return "", fmt.Errorf("response unmarshal error: %v", err)
+		return "", Usage{}, fmt.Errorf("response unmarshal error: %v", err)
 	}
 
 	if len(response.Choices) == 0 {
-		return "", fmt.Errorf("no response from API")
+		return "", Usage{}, fmt.Errorf("no response from API")
 	}
 
-	return response.Choices[0].Message.Content, nil
+	return response.Choices[0].Message.Content, response.Usage, nil
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"eros/shared/utils"
 	"fmt"
 
@@ -11,6 +12,7 @@ func main() {
 	godotenv.Load()
 
 	client := utils.NewOpenRouterClientFromEnv()
+	ctx := context.Background()
 
 	fmt.Println("🤖 TÜM AI SERVİSLERİ TEST")
 	fmt.Println("==================================================")
@@ -46,7 +48,7 @@ func main() {
 
 	// 1. Chat Analysis
 	fmt.Println("\n1️⃣ CHAT ANALYSIS")
-	analysis, err := client.ChatAnalysis(testMessage)
+	analysis, err := client.ChatAnalysis(ctx, testMessage)
 	if err != nil {
 		fmt.Printf("❌ Hata: %v\n", err)
 	} else {
@@ -55,7 +57,7 @@ func main() {
 
 	// 2. Date Suggestion
 	fmt.Println("\n2️⃣ DATE SUGGESTION")
-	suggestion, err := client.DateSuggestion(user1, user2)
+	suggestion, err := client.DateSuggestion(ctx, user1, user2)
 	if err != nil {
 		fmt.Printf("❌ Hata: %v\n", err)
 	} else {
@@ -64,7 +66,7 @@ func main() {
 
 	// 3. Ice Breaker
 	fmt.Println("\n3️⃣ ICE BREAKER")
-	iceBreaker, err := client.IceBreaker(user1, user2)
+	iceBreaker, err := client.IceBreaker(ctx, user1, user2)
 	if err != nil {
 		fmt.Printf("❌ Hata: %v\n", err)
 	} else {
@@ -0,0 +1,162 @@
+// admin.go - Fotoğraf incelemeleri, hesap askıya alma ve giriş kayıtları (admin)
+package handler
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"eros/shared/apierror"
+	"eros/user-service/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type AdminHandler struct {
+	userService *service.UserService
+	loginGuard  *service.LoginGuard
+	adminToken  string
+}
+
+func NewAdminHandler(userService *service.UserService, loginGuard *service.LoginGuard, adminToken string) *AdminHandler {
+	return &AdminHandler{
+		userService: userService,
+		loginGuard:  loginGuard,
+		adminToken:  adminToken,
+	}
+}
+
+// RequireAdmin - X-Admin-Token başlığını doğrulayan middleware
+// ADMIN_TOKEN tanımlı değilse admin endpoint'leri kapalıdır.
+func (h *AdminHandler) RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Admin-Token")
+		if h.adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+			apierror.Write(w, r, apierror.Forbidden("Forbidden"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// ListPhotoReviews - Kopya fotoğraf incelemelerini listele (?status=pending&limit=)
+func (h *AdminHandler) ListPhotoReviews(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 0
+	if v := query.Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			limit = n
+		}
+	}
+
+	reviews, err := h.userService.ListPhotoReviews(query.Get("status"), limit)
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to list photo reviews"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reviews)
+}
+
+// ResolvePhotoReview - İncelemeyi sonuçlandır ({"decision":"approve"|"reject","reviewer":"..."})
+func (h *AdminHandler) ResolvePhotoReview(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	reviewID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid review ID"))
+		return
+	}
+
+	var request struct {
+		Decision string `json:"decision"`
+		Reviewer string `json:"reviewer"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+	if request.Reviewer == "" {
+		request.Reviewer = "admin"
+	}
+
+	review, err := h.userService.ResolvePhotoReview(r.Context(), reviewID, request.Decision, request.Reviewer)
+	if errors.Is(err, service.ErrReviewNotFound) {
+		apierror.Write(w, r, apierror.NotFound("Photo review not found"))
+		return
+	}
+	if errors.Is(err, service.ErrReviewResolved) {
+		apierror.Write(w, r, apierror.Conflict(err.Error()))
+		return
+	}
+	if errors.Is(err, service.ErrInvalidReviewDecision) {
+		apierror.Write(w, r, apierror.BadRequest(err.Error()))
+		return
+	}
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to resolve photo review"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// SuspendUser - Hesabı askıya al; fotoğrafları başka hesaplarda yeniden kullanılamaz
+func (h *AdminHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
+		return
+	}
+
+	err = h.userService.SuspendUser(r.Context(), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		apierror.Write(w, r, apierror.NotFound("User not found"))
+		return
+	}
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to suspend user"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"user_id": userID,
+	})
+}
+
+// ListLoginEvents - Kullanıcının son giriş denemeleri (?limit=, varsayılan 50)
+func (h *AdminHandler) ListLoginEvents(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			limit = n
+		}
+	}
+
+	events, err := h.loginGuard.ListUserEvents(userID, limit)
+	if errors.Is(err, sql.ErrNoRows) {
+		apierror.Write(w, r, apierror.NotFound("User not found"))
+		return
+	}
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to list login events"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
@@ -3,22 +3,33 @@ package handler
 
 import (
 	"encoding/json"
+	"eros/shared/apierror"
+	"eros/shared/auth"
 	"eros/user-service/model"
 	"eros/user-service/service"
-	"fmt"
-	"log"
+	"errors"
+	"log/slog"
+	"math"
+	"net"
 	"net/http"
+	"net/mail"
+	"net/netip"
+	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthHandler struct {
-	userService *service.UserService
+	userService    *service.UserService
+	accountService *service.AccountService
+	loginGuard     *service.LoginGuard
+	tokens         *auth.Signer // nil ise girişte oturum jetonu verilmez
 }
 
-func NewAuthHandler(userService *service.UserService) *AuthHandler {
-	return &AuthHandler{userService: userService}
+func NewAuthHandler(userService *service.UserService, accountService *service.AccountService, loginGuard *service.LoginGuard, tokens *auth.Signer) *AuthHandler {
+	return &AuthHandler{userService: userService, accountService: accountService, loginGuard: loginGuard, tokens: tokens}
 }
 
 // RegisterRequest - Kayıt isteği
@@ -53,29 +64,45 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// EmailRequest - Doğrulama e-postasını yeniden gönderme ve şifremi unuttum isteği
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+// VerifyEmailRequest - E-posta doğrulama isteği
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// ResetPasswordRequest - Şifre sıfırlama isteği
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
 // Register - Kullanıcı kaydı
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apierror.Write(w, r, apierror.MethodNotAllowed())
 		return
 	}
 
 	var req RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
 		return
 	}
 
 	// Validasyonlar
-	if err := h.validateRegistration(req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if errs := h.validateRegistration(req); len(errs.Fields) > 0 {
+		apierror.Write(w, r, errs)
 		return
 	}
 
 	// Şifre hash'leme
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		http.Error(w, "Password hashing failed", http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Fallback(err, "Password hashing failed"))
 		return
 	}
 
@@ -99,97 +126,119 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		UpdatedAt:       time.Now(),
 	}
 
+	// Alan hataları 400, e-posta çakışması 409 olarak döner
 	if err := h.userService.CreateUser(user); err != nil {
-		log.Println("[CreateUser ERROR]", err)
-		http.Error(w, "User creation failed", http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Fallback(err, "User creation failed"))
 		return
 	}
 
+	// Hesap e-posta doğrulanana kadar giriş yapamaz; gönderim hatası kaydı engellemez
+	if err := h.accountService.SendVerificationEmail(r.Context(), user); err != nil {
+		slog.ErrorContext(r.Context(), "failed to send verification email", "user_id", user.ID, "error", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message": "User registered successfully",
+		"message": "User registered successfully, please verify your email",
 		"user_id": user.ID,
 	})
 }
 
-// validateRegistration - Kayıt validasyonu
-func (h *AuthHandler) validateRegistration(req RegisterRequest) error {
+// validateRegistration - Kayıt validasyonu (tüm alan hataları birlikte döner)
+func (h *AuthHandler) validateRegistration(req RegisterRequest) *service.ValidationError {
+	errs := &service.ValidationError{}
+
 	// Temel alanlar
-	if req.Name == "" || req.Email == "" || req.Password == "" {
-		return fmt.Errorf("name, email and password are required")
-	}
+	validateEmail(errs, req.Email)
+	validatePassword(errs, req.Password)
 
-	if len(req.Password) < 6 {
-		return fmt.Errorf("password must be at least 6 characters")
-	}
+	// İsim, biyografi ve meslek (moderasyon dahil)
+	h.userService.ValidateProfile(errs, &model.User{Name: req.Name, Bio: req.Bio, Job: req.Job})
 
 	// Yaş kontrolü
 	if req.Age < 18 || req.Age > 100 {
-		return fmt.Errorf("age must be between 18-100")
+		errs.Add("age", service.CodeOutOfRange, "age must be between 18-100")
 	}
 
 	// Ciddiyet seviyesi kontrolü
 	if req.Seriousness < 1 || req.Seriousness > 10 {
-		return fmt.Errorf("seriousness level must be between 1-10")
+		errs.Add("seriousness", service.CodeOutOfRange, "seriousness level must be between 1-10")
 	}
 
 	// Boy kontrolü
 	if req.Height < 140 || req.Height > 220 {
-		return fmt.Errorf("height must be between 140-220 cm")
+		errs.Add("height", service.CodeOutOfRange, "height must be between 140-220 cm")
 	}
 
 	// Kilo kontrolü
 	if req.Weight < 40 || req.Weight > 200 {
-		return fmt.Errorf("weight must be between 40-200 kg")
+		errs.Add("weight", service.CodeOutOfRange, "weight must be between 40-200 kg")
 	}
 
 	// Hobi kontrolü
 	if len(req.Hobbies) == 0 {
-		return fmt.Errorf("at least one hobby must be selected")
+		errs.Add("hobbies", service.CodeRequired, "at least one hobby must be selected")
 	}
 
 	if len(req.Hobbies) > 10 {
-		return fmt.Errorf("maximum 10 hobbies can be selected")
+		errs.Add("hobbies", service.CodeTooLong, "maximum 10 hobbies can be selected")
 	}
 
 	// Hobi kategorileri kontrolü
 	if len(req.HobbyCategories) == 0 {
-		return fmt.Errorf("at least one hobby category must be selected")
+		errs.Add("hobby_categories", service.CodeRequired, "at least one hobby category must be selected")
 	}
 
-	return nil
+	return errs
+}
+
+// validateEmail - E-posta adresi biçimi ("Ad <adres>" gibi görünen adlar kabul edilmez)
+func validateEmail(errs *service.ValidationError, email string) {
+	if email == "" {
+		errs.Add("email", service.CodeRequired, "email is required")
+		return
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > 254 {
+		errs.Add("email", service.CodeInvalidFormat, "email must be a valid email address")
+	}
+}
+
+// validatePassword - Şifre kuralları
+func validatePassword(errs *service.ValidationError, password string) {
+	if password == "" {
+		errs.Add("password", service.CodeRequired, "password is required")
+	} else if len(password) < 6 {
+		errs.Add("password", service.CodeTooShort, "password must be at least 6 characters")
+	}
 }
 
 // SimpleRegister - Basit kullanıcı kaydı (sadece temel bilgiler)
 func (h *AuthHandler) SimpleRegister(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apierror.Write(w, r, apierror.MethodNotAllowed())
 		return
 	}
 
 	var req SimpleRegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "error": "Invalid request body"})
-		return
-	}
-
-	if req.Name == "" || req.Email == "" || req.Password == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "error": "name, email and password are required"})
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
 		return
 	}
 
-	if len(req.Password) < 6 {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "error": "password must be at least 6 characters"})
+	errs := &service.ValidationError{}
+	validateEmail(errs, req.Email)
+	validatePassword(errs, req.Password)
+	h.userService.ValidateProfile(errs, &model.User{Name: req.Name})
+	if len(errs.Fields) > 0 {
+		apierror.Write(w, r, errs)
 		return
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "error": "Password hashing failed"})
+		apierror.Write(w, r, apierror.Fallback(err, "Password hashing failed"))
 		return
 	}
 
@@ -214,11 +263,15 @@ func (h *AuthHandler) SimpleRegister(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.userService.CreateUser(user); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "error": "User creation failed"})
+		apierror.Write(w, r, apierror.Fallback(err, "User creation failed"))
 		return
 	}
 
+	if err := h.accountService.SendVerificationEmail(r.Context(), user); err != nil {
+		slog.ErrorContext(r.Context(), "failed to send verification email", "user_id", user.ID, "error", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
@@ -229,35 +282,177 @@ func (h *AuthHandler) SimpleRegister(w http.ResponseWriter, r *http.Request) {
 // Login - Kullanıcı girişi
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apierror.Write(w, r, apierror.MethodNotAllowed())
 		return
 	}
 
 	var req LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "error": "Invalid request body"})
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	attempt := service.LoginAttempt{Email: req.Email, IP: clientIP(r), UserAgent: r.UserAgent()}
+	wait, err := h.loginGuard.Check(r.Context(), attempt)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "login guard check failed", "error", err)
+		apierror.Write(w, r, apierror.Fallback(err, "Login failed"))
+		return
+	}
+	if wait > 0 {
+		retryAfter := int(math.Ceil(wait.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		apierror.Write(w, r, apierror.RateLimited("Too many login attempts, try again later"))
 		return
 	}
 
 	user, err := h.userService.AuthenticateUser(req.Email, req.Password)
+	h.loginGuard.RecordResult(r.Context(), attempt, user, err)
+	if errors.Is(err, service.ErrEmailNotVerified) {
+		apierror.Write(w, r, apierror.Forbidden("Email not verified"))
+		return
+	}
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "error": "Invalid credentials"})
+		apierror.Write(w, r, apierror.Unauthorized("Invalid credentials"))
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]interface{}{
+	response := map[string]interface{}{
 		"success": true,
 		"user":    user,
-	})
+	}
+	// Oturum jetonu gateway'de WebSocket gibi kimlik isteyen uçlarda kullanılır
+	if h.tokens != nil {
+		token, err := h.tokens.Sign(user.ID, time.Now())
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to sign session token", "user_id", user.ID, "error", err)
+			apierror.Write(w, r, apierror.Fallback(err, "Login failed"))
+			return
+		}
+		response["token"] = token
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// VerifyEmail - E-postadaki bağlantının jetonuyla hesabı etkinleştir
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	err := h.accountService.VerifyEmail(req.Token)
+	if errors.Is(err, service.ErrInvalidToken) {
+		apierror.Write(w, r, apierror.BadRequest(err.Error()))
+		return
+	}
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Email verification failed"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+}
+
+// ResendVerification - Doğrulama e-postasını yeniden gönder
+// Adres kayıtlı olsun olmasın aynı yanıt döner.
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var req EmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if err := h.accountService.ResendVerificationEmail(r.Context(), req.Email); err != nil {
+		slog.ErrorContext(r.Context(), "failed to resend verification email", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+}
+
+// ForgotPassword - Şifre sıfırlama bağlantısı iste
+// Adres kayıtlı olsun olmasın aynı yanıt döner.
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req EmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if err := h.accountService.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		slog.ErrorContext(r.Context(), "failed to send password reset email", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+}
+
+// ResetPassword - Sıfırlama jetonuyla yeni şifre belirle
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	errs := &service.ValidationError{}
+	validatePassword(errs, req.Password)
+	if len(errs.Fields) > 0 {
+		apierror.Write(w, r, errs)
+		return
+	}
+
+	err := h.accountService.ResetPassword(r.Context(), req.Token, req.Password)
+	if errors.Is(err, service.ErrInvalidToken) {
+		apierror.Write(w, r, apierror.BadRequest(err.Error()))
+		return
+	}
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Password reset failed"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+}
+
+// clientIP - İsteği yapan istemcinin adresi
+// X-Forwarded-For sadece doğrudan bağlantı yerel ağdan (API gateway) geldiğinde dikkate alınır;
+// gateway istemci adresini listenin sonuna eklediği için son eleman kullanılır.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	peer, err := netip.ParseAddr(host)
+	if err != nil || !(peer.IsLoopback() || peer.IsPrivate()) {
+		return host
+	}
+
+	forwarded := r.Header.Get("X-Forwarded-For")
+	if forwarded == "" {
+		return host
+	}
+	parts := strings.Split(forwarded, ",")
+	if addr, err := netip.ParseAddr(strings.TrimSpace(parts[len(parts)-1])); err == nil {
+		return addr.String()
+	}
+	return host
 }
 
 // GetHobbyCategories - Hobi kategorilerini getir
 func (h *AuthHandler) GetHobbyCategories(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apierror.Write(w, r, apierror.MethodNotAllowed())
 		return
 	}
 
@@ -308,6 +503,7 @@ func (h *AuthHandler) GetHobbyCategories(w http.ResponseWriter, r *http.Request)
 		},
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(hobbyCategories)
 }
@@ -315,7 +511,7 @@ func (h *AuthHandler) GetHobbyCategories(w http.ResponseWriter, r *http.Request)
 // GetEducationLevels - Eğitim seviyelerini getir
 func (h *AuthHandler) GetEducationLevels(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apierror.Write(w, r, apierror.MethodNotAllowed())
 		return
 	}
 
@@ -330,6 +526,7 @@ func (h *AuthHandler) GetEducationLevels(w http.ResponseWriter, r *http.Request)
 		"Diğer",
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(educationLevels)
 }
@@ -337,7 +534,7 @@ func (h *AuthHandler) GetEducationLevels(w http.ResponseWriter, r *http.Request)
 // GetJobCategories - Meslek kategorilerini getir
 func (h *AuthHandler) GetJobCategories(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apierror.Write(w, r, apierror.MethodNotAllowed())
 		return
 	}
 
@@ -378,6 +575,7 @@ func (h *AuthHandler) GetJobCategories(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(jobCategories)
 }
@@ -0,0 +1,239 @@
+// contract.go - User servisi handler'larının OpenAPI belgesine uyumluluk testleri
+//
+// Kullanım (servisin test dosyasında):
+//
+//	if err := contracttest.Test(); err != nil {
+//		t.Fatal(err)
+//	}
+package contracttest
+
+import (
+	"context"
+	"eros/shared/apispec/contracttest"
+	"eros/shared/auth"
+	"eros/shared/moderation"
+	"eros/shared/server"
+	"eros/shared/sqldb"
+	"eros/user-service/handler"
+	"eros/user-service/mail"
+	"eros/user-service/model"
+	"eros/user-service/repository"
+	"eros/user-service/service"
+	"eros/user-service/storage"
+	"eros/user-service/verification"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// serviceName - openapi.json'daki x-service değeri
+const serviceName = "user"
+
+// maxPhotoBytes - Yükleme sınırı (auth uçları fotoğraf almaz, varsayılan yeterli)
+const maxPhotoBytes = 10 << 20
+
+// Test - Rotaları, istek gövdelerini ve auth uçlarının yanıtlarını belgeyle karşılaştır
+// Bellek içi SQLite ve geçici bir fotoğraf dizini kullanır; e-postalar gönderilmez.
+func Test() error {
+	env, err := newEnv()
+	if err != nil {
+		return err
+	}
+	defer env.close()
+
+	return errors.Join(
+		contracttest.CheckRoutes(serviceName, env.router),
+		checkRequests(),
+		env.run(),
+	)
+}
+
+// checkRequests - Handler'ların çözdüğü yapılar belgedeki istek şemalarıyla aynı mı
+func checkRequests() error {
+	return errors.Join(
+		contracttest.CheckRequest("POST", "/api/auth/register", handler.RegisterRequest{}),
+		contracttest.CheckRequest("POST", "/api/auth/simple-register", handler.SimpleRegisterRequest{}),
+		contracttest.CheckRequest("POST", "/api/auth/login", handler.LoginRequest{}),
+		contracttest.CheckRequest("POST", "/api/auth/verify-email", handler.VerifyEmailRequest{}),
+		contracttest.CheckRequest("POST", "/api/auth/resend-verification", handler.EmailRequest{}),
+		contracttest.CheckRequest("POST", "/api/auth/forgot-password", handler.EmailRequest{}),
+		contracttest.CheckRequest("POST", "/api/auth/reset-password", handler.ResetPasswordRequest{}),
+	)
+}
+
+// env - Gerçek servis ve repository'lerle kurulmuş router
+type env struct {
+	db       *sqldb.DB
+	photoDir string
+	workers  *server.Workers
+	router   *mux.Router
+	account  *service.AccountService
+	users    *service.UserService
+	mailer   *captureMailer
+}
+
+func newEnv() (*env, error) {
+	db, err := sqldb.Open(sqldb.SQLite, ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	// Bellek içi SQLite her bağlantıda ayrı bir veritabanıdır
+	db.SetMaxOpenConns(1)
+	if err := repository.InitDatabase(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	photoDir, err := os.MkdirTemp("", "eros-contract-photos-")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	photoStore, err := storage.NewLocalStore(photoDir)
+	if err != nil {
+		db.Close()
+		os.RemoveAll(photoDir)
+		return nil, err
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	photoRepo := repository.NewPhotoRepository(db)
+	reviewRepo := repository.NewPhotoReviewRepository(db)
+	urlSigner := storage.NewURLSigner([]byte("contract-test"), "/api/photos/file/", time.Hour)
+	mailer := &captureMailer{}
+	workers := server.NewWorkers()
+
+	userService := service.NewUserService(userRepo, photoRepo, reviewRepo, photoStore, urlSigner, service.NewProfileValidator(moderation.Default()), service.DefaultDuplicatePolicy(), workers)
+	accountPolicy := service.DefaultAccountPolicy()
+	accountService := service.NewAccountService(userRepo, repository.NewTokenRepository(db), mailer, accountPolicy)
+	loginGuard := service.NewLoginGuard(repository.NewLoginRepository(db), userRepo, service.NewMailNewDeviceNotifier(mailer, accountPolicy.BaseURL), service.DefaultLoginPolicy(), workers)
+	verificationService := service.NewVerificationService(userRepo, photoRepo, repository.NewVerificationRepository(db), photoStore, verification.NewFakeVerifier(), service.DefaultVerificationPolicy())
+
+	router := mux.NewRouter()
+	handler.Handlers{
+		Auth:         handler.NewAuthHandler(userService, accountService, loginGuard, auth.NewSigner([]byte("contract-test"), time.Hour)),
+		Photos:       handler.NewPhotosHandler(userService, maxPhotoBytes),
+		Profile:      handler.NewProfileHandler(userService),
+		Admin:        handler.NewAdminHandler(userService, loginGuard, "contract-test"),
+		Verification: handler.NewVerificationHandler(verificationService, maxPhotoBytes),
+	}.Register(router)
+
+	return &env{
+		db:       db,
+		photoDir: photoDir,
+		workers:  workers,
+		router:   router,
+		account:  accountService,
+		users:    userService,
+		mailer:   mailer,
+	}, nil
+}
+
+func (e *env) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	e.workers.Stop(ctx)
+	e.db.Close()
+	os.RemoveAll(e.photoDir)
+}
+
+// run - Auth akışı: kayıt, doğrulanmamış giriş, e-posta doğrulama, giriş, şifre sıfırlama
+func (e *env) run() error {
+	// Doğrulama ve sıfırlama jetonları yalnızca e-postada gelir; hesabı önceden aç ve jetonları yakala
+	const email, password = "ayse@example.com", "gizli-sifre"
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		return err
+	}
+	user := &model.User{
+		Name:        "Ayşe",
+		Email:       email,
+		Password:    string(hash),
+		Seriousness: 5,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := e.users.CreateUser(user); err != nil {
+		return fmt.Errorf("contracttest: seeding user: %w", err)
+	}
+	ctx := context.Background()
+	if err := e.account.SendVerificationEmail(ctx, user); err != nil {
+		return err
+	}
+	verifyToken, err := e.mailer.lastToken(email)
+	if err != nil {
+		return err
+	}
+	if err := e.account.RequestPasswordReset(ctx, email); err != nil {
+		return err
+	}
+	resetToken, err := e.mailer.lastToken(email)
+	if err != nil {
+		return err
+	}
+
+	register := `{"name":"Mehmet","email":"mehmet@example.com","password":"gizli-sifre","bio":"Kitap ve doğa","age":29,` +
+		`"seriousness":7,"height":180,"weight":75,"smokes":false,"drinks":true,"job":"Mühendis",` +
+		`"job_category":"Teknoloji","education":"Lisans","hobbies":["Yürüyüş"],"hobby_categories":["Spor"]}`
+
+	return contracttest.Run(serviceName, e.router, []contracttest.Case{
+		{Name: "register", Method: "POST", Path: "/api/auth/register", Body: register, Status: http.StatusCreated},
+		{Name: "register duplicate", Method: "POST", Path: "/api/auth/register", Body: register, Status: http.StatusConflict},
+		{Name: "register invalid", Method: "POST", Path: "/api/auth/register", Body: `{"email":"yanlis"}`, Status: http.StatusBadRequest},
+		{Name: "register malformed", Method: "POST", Path: "/api/auth/register", Body: `{`, Status: http.StatusBadRequest},
+		{Name: "simple register", Method: "POST", Path: "/api/auth/simple-register", Body: `{"name":"Zeynep","email":"zeynep@example.com","password":"gizli-sifre"}`, Status: http.StatusCreated},
+		{Name: "simple register duplicate", Method: "POST", Path: "/api/auth/simple-register", Body: `{"name":"Zeynep","email":"zeynep@example.com","password":"gizli-sifre"}`, Status: http.StatusConflict},
+		{Name: "simple register invalid", Method: "POST", Path: "/api/auth/simple-register", Body: `{"name":"","email":"","password":"123"}`, Status: http.StatusBadRequest},
+		{Name: "login unverified", Method: "POST", Path: "/api/auth/login", Body: `{"email":"` + email + `","password":"` + password + `"}`, Status: http.StatusForbidden},
+		{Name: "verify email", Method: "POST", Path: "/api/auth/verify-email", Body: `{"token":"` + verifyToken + `"}`, Status: http.StatusOK},
+		{Name: "verify email reused token", Method: "POST", Path: "/api/auth/verify-email", Body: `{"token":"` + verifyToken + `"}`, Status: http.StatusBadRequest},
+		{Name: "login", Method: "POST", Path: "/api/auth/login", Body: `{"email":"` + email + `","password":"` + password + `"}`, Status: http.StatusOK},
+		{Name: "login wrong password", Method: "POST", Path: "/api/auth/login", Body: `{"email":"` + email + `","password":"yanlis-sifre"}`, Status: http.StatusUnauthorized},
+		{Name: "login malformed", Method: "POST", Path: "/api/auth/login", Body: `{`, Status: http.StatusBadRequest},
+		{Name: "reset password", Method: "POST", Path: "/api/auth/reset-password", Body: `{"token":"` + resetToken + `","password":"yeni-sifre"}`, Status: http.StatusOK},
+		{Name: "reset password invalid token", Method: "POST", Path: "/api/auth/reset-password", Body: `{"token":"gecersiz","password":"yeni-sifre"}`, Status: http.StatusBadRequest},
+		{Name: "reset password short", Method: "POST", Path: "/api/auth/reset-password", Body: `{"token":"gecersiz","password":"123"}`, Status: http.StatusBadRequest},
+		{Name: "forgot password", Method: "POST", Path: "/api/auth/forgot-password", Body: `{"email":"` + email + `"}`, Status: http.StatusAccepted},
+		{Name: "forgot password unknown", Method: "POST", Path: "/api/auth/forgot-password", Body: `{"email":"yok@example.com"}`, Status: http.StatusAccepted},
+		{Name: "forgot password malformed", Method: "POST", Path: "/api/auth/forgot-password", Body: `{`, Status: http.StatusBadRequest},
+		{Name: "resend verification", Method: "POST", Path: "/api/auth/resend-verification", Body: `{"email":"zeynep@example.com"}`, Status: http.StatusAccepted},
+		{Name: "resend verification malformed", Method: "POST", Path: "/api/auth/resend-verification", Body: `{`, Status: http.StatusBadRequest},
+	})
+}
+
+// captureMailer - Gönderilen e-postaları bellekte tutar
+type captureMailer struct {
+	mu   sync.Mutex
+	sent []mail.Message
+}
+
+func (m *captureMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+var tokenParam = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+// lastToken - Adrese gönderilen son e-postadaki bağlantının jetonu
+func (m *captureMailer) lastToken(to string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.sent) - 1; i >= 0; i-- {
+		if m.sent[i].To != to {
+			continue
+		}
+		if match := tokenParam.FindStringSubmatch(m.sent[i].Body); match != nil {
+			return match[1], nil
+		}
+	}
+	return "", fmt.Errorf("contracttest: no email with a token sent to %s", to)
+}
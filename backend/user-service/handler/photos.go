@@ -2,104 +2,252 @@
 package handler
 
 import (
+    "encoding/base64"
     "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "mime"
     "net/http"
+    "eros/shared/apierror"
+    "eros/shared/auth"
     "eros/user-service/model"
     "eros/user-service/service"
+    "eros/user-service/storage"
     "strconv"
+    "strings"
+
+    "github.com/gorilla/mux"
 )
 
 type PhotosHandler struct {
-    userService *service.UserService
+    userService   *service.UserService
+    maxPhotoBytes int64
 }
 
-func NewPhotosHandler(userService *service.UserService) *PhotosHandler {
-    return &PhotosHandler{userService: userService}
+func NewPhotosHandler(userService *service.UserService, maxPhotoBytes int64) *PhotosHandler {
+    return &PhotosHandler{userService: userService, maxPhotoBytes: maxPhotoBytes}
 }
 
-// UploadPhotoRequest - Fotoğraf yükleme isteği
+// UploadPhotoRequest - Fotoğraf yükleme isteği (JSON; multipart form tercih edilir)
+// Yeni fotoğraf her zaman sıranın sonuna eklenir; sıra ReorderPhotos ile değişir.
 type UploadPhotoRequest struct {
-    UserID    int    `json:"user_id"`
     ImageData string `json:"image_data"` // Base64 encoded image
-    OrderIndex int   `json:"order_index"`
 }
 
 // ReorderPhotosRequest - Fotoğraf sıralama isteği
 type ReorderPhotosRequest struct {
-    UserID int   `json:"user_id"`
     PhotoIDs []int `json:"photo_ids"` // Yeni sıralama
 }
 
+var errPhotoTooLarge = errors.New("photo is too large")
+
+// requireUser - Gateway'in doğruladığı kullanıcı (X-User-ID); yoksa 401 yazar
+// Fotoğrafı değiştiren uçlar kullanıcıyı istemcinin gönderdiği user_id'den asla almaz.
+func requireUser(w http.ResponseWriter, r *http.Request) (int, bool) {
+    userID, ok := auth.RequestUserID(r)
+    if !ok {
+        apierror.Write(w, r, apierror.Unauthorized("Authentication required"))
+    }
+    return userID, ok
+}
+
 // UploadPhoto - Fotoğraf yükleme (maksimum 6 fotoğraf)
+// multipart/form-data: "photo" dosyası. Eski istemciler için base64 "image_data" içeren
+// JSON da kabul edilir. Fotoğraf oturumdaki kullanıcıya eklenir.
 func (h *PhotosHandler) UploadPhoto(w http.ResponseWriter, r *http.Request) {
     if r.Method != http.MethodPost {
-        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        apierror.Write(w, r, apierror.MethodNotAllowed())
+        return
+    }
+    userID, ok := requireUser(w, r)
+    if !ok {
         return
     }
 
-    var req UploadPhotoRequest
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        http.Error(w, "Invalid request body", http.StatusBadRequest)
+    // Base64 ve multipart ek yükü için gövde sınırı dosya sınırından geniş tutulur
+    r.Body = http.MaxBytesReader(w, r.Body, h.maxPhotoBytes*2+1<<20)
+
+    data, err := h.readUpload(r)
+    var maxBytesErr *http.MaxBytesError
+    if errors.Is(err, errPhotoTooLarge) || errors.As(err, &maxBytesErr) {
+        apierror.Write(w, r, apierror.PayloadTooLarge(fmt.Sprintf("Photo must be at most %d bytes", h.maxPhotoBytes)))
+        return
+    }
+    if err != nil {
+        apierror.Write(w, r, apierror.BadRequest(err.Error()))
         return
     }
 
     // Fotoğraf sayısı kontrolü
-    photoCount, err := h.userService.GetUserPhotoCount(req.UserID)
+    photoCount, err := h.userService.GetUserPhotoCount(userID)
     if err != nil {
-        http.Error(w, "Failed to get photo count", http.StatusInternalServerError)
+        apierror.Write(w, r, apierror.Fallback(err, "Failed to get photo count"))
+        return
+    }
+
+    if photoCount >= service.MaxPhotosPerUser {
+        apierror.Write(w, r, apierror.BadRequest("Maximum 6 photos allowed"))
         return
     }
 
-    if photoCount >= 6 {
-        http.Error(w, "Maximum 6 photos allowed", http.StatusBadRequest)
+    // Görüntüyü çöz ve kalite skorunu hesapla (bozuk dosyalar depolanmadan reddedilir)
+    aiScore, phash, err := h.userService.AnalyzePhoto(data)
+    if errors.Is(err, service.ErrInvalidImage) {
+        apierror.Write(w, r, apierror.UnsupportedMediaType("Only valid JPEG, PNG and WebP images are allowed"))
+        return
+    }
+    if errors.Is(err, service.ErrImageTooLarge) {
+        apierror.Write(w, r, apierror.PayloadTooLarge("Image dimensions are too large"))
+        return
+    }
+    if err != nil {
+        apierror.Write(w, r, apierror.Fallback(err, "Photo analysis failed"))
         return
     }
 
-    // AI analizi için fotoğrafı işle
-    aiScore, err := h.userService.AnalyzePhoto(req.ImageData)
+    // Dosyayı depolamaya yaz (içerik türü koklanarak doğrulanır)
+    key, err := h.userService.SavePhotoFile(r.Context(), userID, data)
+    if errors.Is(err, storage.ErrUnsupportedType) {
+        apierror.Write(w, r, apierror.UnsupportedMediaType("Only JPEG, PNG and WebP images are allowed"))
+        return
+    }
     if err != nil {
-        http.Error(w, "Photo analysis failed", http.StatusInternalServerError)
+        apierror.Write(w, r, apierror.Fallback(err, "Failed to store photo"))
         return
     }
 
     photo := &model.Photo{
-        UserID:    req.UserID,
-        URL:       req.ImageData, // Gerçek uygulamada cloud storage'a yüklenir
-        OrderIndex: req.OrderIndex,
+        UserID:    userID,
+        URL:       key, // Orijinalin depolama anahtarı; işlendikten sonra tam boy varyant olur
         AIScore:   aiScore,
-        IsVerified: true, // TODO: AI ile doğrulama
+        IsVerified: false, // Selfie doğrulamasında yüzle eşleşirse doğrulanır
+        Status:    model.PhotoStatusPending,
+        PHash:     phash,
     }
 
-    if err := h.userService.AddPhoto(photo); err != nil {
-        http.Error(w, "Failed to save photo", http.StatusInternalServerError)
+    if err := h.userService.AddPhoto(r.Context(), photo); err != nil {
+        h.userService.DeletePhotoFile(r.Context(), key)
+        if errors.Is(err, service.ErrPhotoLimitReached) {
+            apierror.Write(w, r, apierror.BadRequest("Maximum 6 photos allowed"))
+            return
+        }
+        if errors.Is(err, service.ErrPhotoBlocked) {
+            apierror.Write(w, r, apierror.Unprocessable("This photo cannot be used"))
+            return
+        }
+        apierror.Write(w, r, apierror.Fallback(err, "Failed to save photo"))
         return
     }
 
+    // Varyantlar, EXIF temizliği ve algısal özet arka planda üretilir
+    h.userService.QueuePhotoProcessing(r.Context(), photo.ID)
+
     w.WriteHeader(http.StatusCreated)
     json.NewEncoder(w).Encode(map[string]interface{}{
         "message": "Photo uploaded successfully",
         "photo_id": photo.ID,
+        "order_index": photo.OrderIndex,
+        "is_primary": photo.IsPrimary,
+        "is_verified": photo.IsVerified,
+        "status": photo.Status,
         "ai_score": photo.AIScore,
     })
 }
 
+// readUpload - Multipart veya JSON isteğinden fotoğraf verisini oku
+func (h *PhotosHandler) readUpload(r *http.Request) ([]byte, error) {
+    mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+    if mediaType == "multipart/form-data" {
+        // 1MB'tan büyük parçalar geçici dosyaya yazılır
+        if err := r.ParseMultipartForm(1 << 20); err != nil {
+            return nil, err
+        }
+
+        file, _, err := r.FormFile("photo")
+        if err != nil {
+            return nil, errors.New("photo file is required")
+        }
+        defer file.Close()
+
+        data, err := io.ReadAll(io.LimitReader(file, h.maxPhotoBytes+1))
+        if err != nil {
+            return nil, err
+        }
+        if int64(len(data)) > h.maxPhotoBytes {
+            return nil, errPhotoTooLarge
+        }
+        return data, nil
+    }
+
+    var req UploadPhotoRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        return nil, err
+    }
+
+    // "data:image/png;base64,..." önekini at
+    encoded := req.ImageData
+    if i := strings.Index(encoded, ","); strings.HasPrefix(encoded, "data:") && i >= 0 {
+        encoded = encoded[i+1:]
+    }
+    data, err := base64.StdEncoding.DecodeString(encoded)
+    if err != nil {
+        return nil, errors.New("image_data must be base64 encoded")
+    }
+    if int64(len(data)) > h.maxPhotoBytes {
+        return nil, errPhotoTooLarge
+    }
+    return data, nil
+}
+
+// ServePhotoFile - İmzalı ve süreli adresle fotoğraf dosyasını indir
+func (h *PhotosHandler) ServePhotoFile(w http.ResponseWriter, r *http.Request) {
+    key := mux.Vars(r)["key"]
+    query := r.URL.Query()
+
+    if !h.userService.VerifyPhotoURL(key, query.Get("expires"), query.Get("sig")) {
+        apierror.Write(w, r, apierror.Forbidden("Invalid or expired link"))
+        return
+    }
+
+    body, info, err := h.userService.OpenPhotoFile(r.Context(), key)
+    if errors.Is(err, storage.ErrNotFound) {
+        apierror.Write(w, r, apierror.NotFound("Photo not found"))
+        return
+    }
+    if err != nil {
+        apierror.Write(w, r, apierror.Fallback(err, "Failed to read photo"))
+        return
+    }
+    defer body.Close()
+
+    w.Header().Set("Content-Type", info.ContentType)
+    if info.Size > 0 {
+        w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+    }
+    w.Header().Set("Cache-Control", "private, max-age=300")
+    w.Header().Set("X-Content-Type-Options", "nosniff")
+    w.WriteHeader(http.StatusOK)
+    io.Copy(w, body)
+}
+
 // GetUserPhotos - Kullanıcının fotoğraflarını getir
 func (h *PhotosHandler) GetUserPhotos(w http.ResponseWriter, r *http.Request) {
     if r.Method != http.MethodGet {
-        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        apierror.Write(w, r, apierror.MethodNotAllowed())
         return
     }
 
     userIDStr := r.URL.Query().Get("user_id")
     userID, err := strconv.Atoi(userIDStr)
     if err != nil {
-        http.Error(w, "Invalid user ID", http.StatusBadRequest)
+        apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
         return
     }
 
     photos, err := h.userService.GetUserPhotos(userID)
     if err != nil {
-        http.Error(w, "Failed to get photos", http.StatusInternalServerError)
+        apierror.Write(w, r, apierror.Fallback(err, "Failed to get photos"))
         return
     }
 
@@ -107,21 +255,34 @@ func (h *PhotosHandler) GetUserPhotos(w http.ResponseWriter, r *http.Request) {
     json.NewEncoder(w).Encode(photos)
 }
 
-// ReorderPhotos - Fotoğraf sıralamasını değiştir
+// ReorderPhotos - Oturumdaki kullanıcının fotoğraf sıralamasını değiştir
 func (h *PhotosHandler) ReorderPhotos(w http.ResponseWriter, r *http.Request) {
     if r.Method != http.MethodPut {
-        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        apierror.Write(w, r, apierror.MethodNotAllowed())
+        return
+    }
+    userID, ok := requireUser(w, r)
+    if !ok {
         return
     }
 
     var req ReorderPhotosRequest
     if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
         return
     }
 
-    if err := h.userService.ReorderPhotos(req.UserID, req.PhotoIDs); err != nil {
-        http.Error(w, "Failed to reorder photos", http.StatusInternalServerError)
+    err := h.userService.ReorderPhotos(userID, req.PhotoIDs)
+    if errors.Is(err, service.ErrPhotoNotFound) {
+        apierror.Write(w, r, apierror.NotFound("Photo not found"))
+        return
+    }
+    if errors.Is(err, service.ErrInvalidPhotoOrder) {
+        apierror.Write(w, r, apierror.BadRequest(err.Error()))
+        return
+    }
+    if err != nil {
+        apierror.Write(w, r, apierror.Fallback(err, "Failed to reorder photos"))
         return
     }
 
@@ -131,22 +292,32 @@ func (h *PhotosHandler) ReorderPhotos(w http.ResponseWriter, r *http.Request) {
     })
 }
 
-// DeletePhoto - Fotoğraf sil
+// DeletePhoto - Oturumdaki kullanıcının fotoğrafını sil
 func (h *PhotosHandler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
     if r.Method != http.MethodDelete {
-        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        apierror.Write(w, r, apierror.MethodNotAllowed())
+        return
+    }
+    userID, ok := requireUser(w, r)
+    if !ok {
         return
     }
 
     photoIDStr := r.URL.Query().Get("photo_id")
     photoID, err := strconv.Atoi(photoIDStr)
     if err != nil {
-        http.Error(w, "Invalid photo ID", http.StatusBadRequest)
+        apierror.Write(w, r, apierror.BadRequest("Invalid photo ID"))
         return
     }
 
-    if err := h.userService.DeletePhoto(photoID); err != nil {
-        http.Error(w, "Failed to delete photo", http.StatusInternalServerError)
+    // Sadece kullanıcının kendi fotoğrafı silinebilir; başkasınınki bulunamadı sayılır
+    err = h.userService.DeletePhoto(r.Context(), userID, photoID)
+    if errors.Is(err, service.ErrPhotoNotFound) {
+        apierror.Write(w, r, apierror.NotFound("Photo not found"))
+        return
+    }
+    if err != nil {
+        apierror.Write(w, r, apierror.Fallback(err, "Failed to delete photo"))
         return
     }
 
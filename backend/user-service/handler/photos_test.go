@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"eros/shared/moderation"
+	"eros/shared/server"
+	"eros/shared/sqldb"
+	"eros/user-service/model"
+	"eros/user-service/repository"
+	"eros/user-service/service"
+	"eros/user-service/storage"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// photoEnv - Gerçek servis ve bellek içi SQLite ile fotoğraf uçları
+type photoEnv struct {
+	db     *sqldb.DB
+	users  *service.UserService
+	router *mux.Router
+}
+
+func newPhotoEnv(t *testing.T) *photoEnv {
+	t.Helper()
+	db, err := sqldb.Open(sqldb.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Bellek içi SQLite her bağlantıda ayrı bir veritabanıdır
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if err := repository.InitDatabase(db); err != nil {
+		t.Fatal(err)
+	}
+
+	photoStore, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	workers := server.NewWorkers()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		workers.Stop(ctx)
+	})
+
+	photoRepo := repository.NewPhotoRepository(db)
+	reviewRepo := repository.NewPhotoReviewRepository(db)
+	urlSigner := storage.NewURLSigner([]byte("test"), "/api/photos/file/", time.Hour)
+	users := service.NewUserService(repository.NewUserRepository(db), photoRepo, reviewRepo, photoStore, urlSigner,
+		service.NewProfileValidator(moderation.Default()), service.DefaultDuplicatePolicy(), workers)
+
+	photos := NewPhotosHandler(users, 1<<20)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/photos/upload", photos.UploadPhoto).Methods("POST")
+	router.HandleFunc("/api/photos/reorder", photos.ReorderPhotos).Methods("PUT")
+	router.HandleFunc("/api/photos", photos.DeletePhoto).Methods("DELETE")
+	return &photoEnv{db: db, users: users, router: router}
+}
+
+// createUser - Fotoğraf testleri için en küçük kullanıcı kaydı
+func (e *photoEnv) createUser(t *testing.T, email string) int {
+	t.Helper()
+	id, err := e.db.InsertID(`INSERT INTO users (name, email, password) VALUES (?, ?, ?)`, "Ayşe", email, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int(id)
+}
+
+// addPhotos - Kullanıcıya n fotoğraf ekle, ID'leri sırayla döndür
+func (e *photoEnv) addPhotos(t *testing.T, userID, n int) []int {
+	t.Helper()
+	var ids []int
+	for i := 0; i < n; i++ {
+		p := &model.Photo{UserID: userID, URL: "photos/x.jpg", Status: model.PhotoStatusReady}
+		if err := e.users.AddPhoto(context.Background(), p); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+// photoIDs - Kullanıcının fotoğrafları (mevcut sırayla)
+func (e *photoEnv) photoIDs(t *testing.T, userID int) []int {
+	t.Helper()
+	photos, err := e.users.GetUserPhotos(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	for _, p := range photos {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+// do - İsteği router'a gönder; userID 0 ise X-User-ID eklenmez
+func (e *photoEnv) do(req *http.Request, userID int) *httptest.ResponseRecorder {
+	if userID != 0 {
+		req.Header.Set("X-User-ID", strconv.Itoa(userID))
+	}
+	rec := httptest.NewRecorder()
+	e.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPhotoEndpointsRequireGatewayUser(t *testing.T) {
+	env := newPhotoEnv(t)
+	owner := env.createUser(t, "owner@example.com")
+	ids := env.addPhotos(t, owner, 2)
+
+	// İstemcinin gönderdiği user_id kimlik yerine geçmez
+	requests := []*http.Request{
+		httptest.NewRequest("DELETE", "/api/photos?photo_id="+strconv.Itoa(ids[0])+"&user_id="+strconv.Itoa(owner), nil),
+		httptest.NewRequest("PUT", "/api/photos/reorder", strings.NewReader(`{"user_id": `+strconv.Itoa(owner)+`, "photo_ids": [`+strconv.Itoa(ids[1])+`, `+strconv.Itoa(ids[0])+`]}`)),
+		httptest.NewRequest("POST", "/api/photos/upload", strings.NewReader(`{"user_id": `+strconv.Itoa(owner)+`, "image_data": ""}`)),
+	}
+	for _, req := range requests {
+		if rec := env.do(req, 0); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s without X-User-ID: status %d, want 401", req.Method, req.URL.Path, rec.Code)
+		}
+	}
+	if got := env.photoIDs(t, owner); len(got) != 2 || got[0] != ids[0] {
+		t.Fatalf("owner's photos changed: %v", got)
+	}
+}
+
+func TestPhotoEndpointsRejectOtherUsersPhotos(t *testing.T) {
+	env := newPhotoEnv(t)
+	owner := env.createUser(t, "owner@example.com")
+	attacker := env.createUser(t, "attacker@example.com")
+	ids := env.addPhotos(t, owner, 2)
+	own := env.addPhotos(t, attacker, 1)
+
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{"delete", httptest.NewRequest("DELETE", "/api/photos?photo_id="+strconv.Itoa(ids[0])+"&user_id="+strconv.Itoa(owner), nil)},
+		{"reorder owner's photos", httptest.NewRequest("PUT", "/api/photos/reorder", strings.NewReader(`{"user_id": `+strconv.Itoa(owner)+`, "photo_ids": [`+strconv.Itoa(ids[1])+`, `+strconv.Itoa(ids[0])+`]}`))},
+		{"reorder mixing in owner's photo", httptest.NewRequest("PUT", "/api/photos/reorder", strings.NewReader(`{"photo_ids": [`+strconv.Itoa(ids[0])+`]}`))},
+	}
+	for _, tt := range tests {
+		if rec := env.do(tt.req, attacker); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status %d, want 404", tt.name, rec.Code)
+		}
+	}
+
+	if got := env.photoIDs(t, owner); len(got) != 2 || got[0] != ids[0] || got[1] != ids[1] {
+		t.Fatalf("owner's photos = %v, want %v unchanged", got, ids)
+	}
+	if got := env.photoIDs(t, attacker); len(got) != 1 || got[0] != own[0] {
+		t.Fatalf("attacker's photos = %v, want %v", got, own)
+	}
+
+	// Kendi fotoğrafı ise silinebilir
+	if rec := env.do(httptest.NewRequest("DELETE", "/api/photos?photo_id="+strconv.Itoa(own[0]), nil), attacker); rec.Code != http.StatusOK {
+		t.Fatalf("deleting own photo: status %d", rec.Code)
+	}
+}
+
+func TestUploadPhotoIgnoresFormUserID(t *testing.T) {
+	env := newPhotoEnv(t)
+	owner := env.createUser(t, "owner@example.com")
+	uploader := env.createUser(t, "uploader@example.com")
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("user_id", strconv.Itoa(owner))
+	part, err := form.CreateFormFile("photo", "photo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			img.Set(x, y, color.RGBA{uint8(x * 4), uint8(y * 4), 128, 255})
+		}
+	}
+	if err := png.Encode(part, img); err != nil {
+		t.Fatal(err)
+	}
+	form.Close()
+
+	req := httptest.NewRequest("POST", "/api/photos/upload", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if rec := env.do(req, uploader); rec.Code != http.StatusCreated {
+		t.Fatalf("upload: status %d: %s", rec.Code, rec.Body)
+	}
+
+	if got := env.photoIDs(t, owner); len(got) != 0 {
+		t.Errorf("photo was added to the user named in the form: %v", got)
+	}
+	if got := env.photoIDs(t, uploader); len(got) != 1 {
+		t.Errorf("uploader has %d photos, want 1", len(got))
+	}
+}
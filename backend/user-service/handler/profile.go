@@ -3,6 +3,7 @@ package handler
 
 import (
 	"encoding/json"
+	"eros/shared/apierror"
 	"eros/user-service/model"
 	"eros/user-service/service"
 	"net/http"
@@ -26,13 +27,13 @@ func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
 		return
 	}
 
 	user, err := h.userService.GetUserByID(userID)
 	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+		apierror.Write(w, r, apierror.NotFound("User not found"))
 		return
 	}
 
@@ -45,19 +46,19 @@ func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
 		return
 	}
 
-	var user model.User
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	user.ID = userID
-	if err := h.userService.UpdateUser(&user); err != nil {
-		http.Error(w, "Failed to update user", http.StatusInternalServerError)
+	// Gövde kayıtlı profilin üzerine çözülür; gönderilmeyen alanlar değişmez
+	_, err = h.userService.UpdateUser(userID, func(user *model.User) error {
+		if err := json.NewDecoder(r.Body).Decode(user); err != nil {
+			return apierror.BadRequest("Invalid request body")
+		}
+		return nil
+	})
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to update user"))
 		return
 	}
 
@@ -70,12 +71,12 @@ func (h *ProfileHandler) DeleteProfile(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
 		return
 	}
 
 	if err := h.userService.DeleteUser(userID); err != nil {
-		http.Error(w, "Failed to delete user", http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to delete user"))
 		return
 	}
 
@@ -88,13 +89,13 @@ func (h *ProfileHandler) GetUserPreferences(w http.ResponseWriter, r *http.Reque
 	vars := mux.Vars(r)
 	userID, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
 		return
 	}
 
 	user, err := h.userService.GetUserByID(userID)
 	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+		apierror.Write(w, r, apierror.NotFound("User not found"))
 		return
 	}
 
@@ -114,38 +115,34 @@ func (h *ProfileHandler) UpdateUserPreferences(w http.ResponseWriter, r *http.Re
 	vars := mux.Vars(r)
 	userID, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
 		return
 	}
 
 	var preferences map[string]interface{}
 	if err := json.NewDecoder(r.Body).Decode(&preferences); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	user, err := h.userService.GetUserByID(userID)
-	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
 		return
 	}
 
 	// Tercihleri güncelle
-	if ageRange, ok := preferences["age_range"].(string); ok {
-		user.AgeRange = ageRange
-	}
-	if distance, ok := preferences["distance"].(int); ok {
-		user.Distance = distance
-	}
-	if seriousness, ok := preferences["seriousness"].(float64); ok {
-		user.Seriousness = int(seriousness)
-	}
-	if hobbies, ok := preferences["hobbies"].([]string); ok {
-		user.Hobbies = hobbies
-	}
-
-	if err := h.userService.UpdateUser(user); err != nil {
-		http.Error(w, "Failed to update preferences", http.StatusInternalServerError)
+	_, err = h.userService.UpdateUser(userID, func(user *model.User) error {
+		if ageRange, ok := preferences["age_range"].(string); ok {
+			user.AgeRange = ageRange
+		}
+		if distance, ok := preferences["distance"].(int); ok {
+			user.Distance = distance
+		}
+		if seriousness, ok := preferences["seriousness"].(float64); ok {
+			user.Seriousness = int(seriousness)
+		}
+		if hobbies, ok := preferences["hobbies"].([]string); ok {
+			user.Hobbies = hobbies
+		}
+		return nil
+	})
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to update preferences"))
 		return
 	}
 
@@ -0,0 +1,54 @@
+// routes.go - API rotaları (main ve sözleşme testleri aynı tabloyu kullanır)
+package handler
+
+import "github.com/gorilla/mux"
+
+// Handlers - Rotalara bağlanan handler'lar
+type Handlers struct {
+	Auth         *AuthHandler
+	Photos       *PhotosHandler
+	Profile      *ProfileHandler
+	Admin        *AdminHandler
+	Verification *VerificationHandler
+}
+
+// Register - API rotalarını router'a ekle
+func (h Handlers) Register(router *mux.Router) {
+	// Auth routes
+	router.HandleFunc("/api/auth/register", h.Auth.Register).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/simple-register", h.Auth.SimpleRegister).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/login", h.Auth.Login).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/verify-email", h.Auth.VerifyEmail).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/resend-verification", h.Auth.ResendVerification).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/forgot-password", h.Auth.ForgotPassword).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/reset-password", h.Auth.ResetPassword).Methods("POST", "OPTIONS")
+
+	// Form data routes
+	router.HandleFunc("/api/form/hobby-categories", h.Auth.GetHobbyCategories).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/form/education-levels", h.Auth.GetEducationLevels).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/form/job-categories", h.Auth.GetJobCategories).Methods("GET", "OPTIONS")
+
+	// Photo routes
+	router.HandleFunc("/api/photos/upload", h.Photos.UploadPhoto).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/photos/file/{key:.+}", h.Photos.ServePhotoFile).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/photos", h.Photos.GetUserPhotos).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/photos/reorder", h.Photos.ReorderPhotos).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/api/photos", h.Photos.DeletePhoto).Methods("DELETE", "OPTIONS")
+
+	// Selfie verification routes
+	router.HandleFunc("/api/verification/challenge", h.Verification.StartChallenge).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/verification/selfie", h.Verification.SubmitSelfie).Methods("POST", "OPTIONS")
+
+	// User routes
+	router.HandleFunc("/api/users/{id}", h.Profile.GetProfile).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/users/{id}", h.Profile.UpdateProfile).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/api/users/{id}", h.Profile.DeleteProfile).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/api/users/{id}/preferences", h.Profile.GetUserPreferences).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/users/{id}/preferences", h.Profile.UpdateUserPreferences).Methods("PUT", "OPTIONS")
+
+	// Admin routes (kopya fotoğraf incelemesi, hesap askıya alma, giriş kayıtları)
+	router.HandleFunc("/api/admin/photos/reviews", h.Admin.RequireAdmin(h.Admin.ListPhotoReviews)).Methods("GET")
+	router.HandleFunc("/api/admin/photos/reviews/{id}/resolve", h.Admin.RequireAdmin(h.Admin.ResolvePhotoReview)).Methods("POST")
+	router.HandleFunc("/api/admin/users/{id}/suspend", h.Admin.RequireAdmin(h.Admin.SuspendUser)).Methods("POST")
+	router.HandleFunc("/api/admin/users/{id}/logins", h.Admin.RequireAdmin(h.Admin.ListLoginEvents)).Methods("GET")
+}
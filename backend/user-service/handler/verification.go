@@ -0,0 +1,134 @@
+// verification.go - Selfie ile profil doğrulama
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"eros/shared/apierror"
+	"eros/user-service/service"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+type VerificationHandler struct {
+	verificationService *service.VerificationService
+	maxPhotoBytes       int64
+}
+
+func NewVerificationHandler(verificationService *service.VerificationService, maxPhotoBytes int64) *VerificationHandler {
+	return &VerificationHandler{verificationService: verificationService, maxPhotoBytes: maxPhotoBytes}
+}
+
+// StartChallenge - Rastgele poz meydan okuması al ({"user_id": 1})
+func (h *VerificationHandler) StartChallenge(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID int `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	challenge, err := h.verificationService.StartChallenge(req.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		apierror.Write(w, r, apierror.NotFound("User not found"))
+		return
+	}
+	if errors.Is(err, service.ErrVerificationDisabled) {
+		apierror.Write(w, r, apierror.Unavailable(err.Error()))
+		return
+	}
+	if errors.Is(err, service.ErrNoPhotosToVerify) {
+		apierror.Write(w, r, apierror.BadRequest(err.Error()))
+		return
+	}
+	if errors.Is(err, service.ErrTooManyChallenges) {
+		apierror.Write(w, r, apierror.RateLimited(err.Error()))
+		return
+	}
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to start verification"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(challenge)
+}
+
+// SubmitSelfie - Poz selfie'sini gönder
+// multipart/form-data: "selfie" dosyası, "user_id" ve "challenge_id" alanları.
+func (h *VerificationHandler) SubmitSelfie(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxPhotoBytes+1<<20)
+
+	// 1MB'tan büyük parçalar geçici dosyaya yazılır
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			apierror.Write(w, r, apierror.PayloadTooLarge(fmt.Sprintf("Selfie must be at most %d bytes", h.maxPhotoBytes)))
+			return
+		}
+		apierror.Write(w, r, apierror.BadRequest("Expected multipart form"))
+		return
+	}
+
+	userID, err := strconv.Atoi(r.FormValue("user_id"))
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid user ID"))
+		return
+	}
+	challengeID, err := strconv.Atoi(r.FormValue("challenge_id"))
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid challenge ID"))
+		return
+	}
+
+	file, _, err := r.FormFile("selfie")
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest("selfie file is required"))
+		return
+	}
+	defer file.Close()
+
+	selfie, err := io.ReadAll(io.LimitReader(file, h.maxPhotoBytes+1))
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Failed to read selfie"))
+		return
+	}
+	if int64(len(selfie)) > h.maxPhotoBytes {
+		apierror.Write(w, r, apierror.PayloadTooLarge(fmt.Sprintf("Selfie must be at most %d bytes", h.maxPhotoBytes)))
+		return
+	}
+
+	outcome, err := h.verificationService.SubmitSelfie(r.Context(), userID, challengeID, selfie)
+	if errors.Is(err, service.ErrVerificationDisabled) {
+		apierror.Write(w, r, apierror.Unavailable(err.Error()))
+		return
+	}
+	if errors.Is(err, service.ErrChallengeNotFound) {
+		apierror.Write(w, r, apierror.NotFound(err.Error()))
+		return
+	}
+	if errors.Is(err, service.ErrChallengeUsed) || errors.Is(err, service.ErrChallengeExpired) {
+		apierror.Write(w, r, apierror.Conflict(err.Error()))
+		return
+	}
+	if errors.Is(err, service.ErrNoPhotosToVerify) {
+		apierror.Write(w, r, apierror.BadRequest(err.Error()))
+		return
+	}
+	if errors.Is(err, service.ErrNoFace) {
+		apierror.Write(w, r, apierror.Unprocessable(err.Error()))
+		return
+	}
+	if err != nil {
+		apierror.Write(w, r, apierror.Fallback(err, "Failed to verify selfie"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outcome)
+}
@@ -0,0 +1,211 @@
+// imaging.go - Yüklenen fotoğrafların çözülmesi, düzeltilmesi ve boyutlandırılması
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	ErrInvalidImage  = errors.New("invalid or unsupported image")
+	ErrImageTooLarge = errors.New("image dimensions are too large")
+)
+
+// maxPixels - Çözülecek en büyük görüntü (sıkıştırma bombalarına karşı)
+const maxPixels = 40 * 1000 * 1000
+
+// jpegQuality - Üretilen varyantların JPEG kalitesi
+const jpegQuality = 85
+
+// originalQuality - Temizlenmiş orijinalin JPEG kalitesi (varyantlar bundan üretilir)
+const originalQuality = 95
+
+// Variant - Üretilecek boyut
+// Crop true ise görüntü ortadan kırpılarak tam Width x Height olur,
+// değilse oranı korunarak bu kutuya sığdırılır. Görüntü hiçbir zaman büyütülmez.
+type Variant struct {
+	Name   string
+	Width  int
+	Height int
+	Crop   bool
+}
+
+// DefaultVariants - Küçük resim, kart ve tam boy
+var DefaultVariants = []Variant{
+	{Name: "thumb", Width: 256, Height: 256, Crop: true},
+	{Name: "card", Width: 640, Height: 960},
+	{Name: "full", Width: 1600, Height: 1600},
+}
+
+// Result - İşlenmiş fotoğraf
+type Result struct {
+	Format   string            // Yüklenen dosyanın biçimi: jpeg, png, webp
+	Width    int               // Yönlendirme düzeltilmiş genişlik
+	Height   int               // Yönlendirme düzeltilmiş yükseklik
+	Variants map[string][]byte // Varyant adı → JPEG (EXIF dahil hiçbir metadata taşımaz)
+	PHash    uint64            // Algısal özet
+}
+
+// Process - Görüntüyü çöz, EXIF yönlendirmesini uygula ve varyantları üret
+// Varyantlar yeniden kodlandığı için GPS dahil tüm EXIF verisi atılmış olur.
+func Process(data []byte, variants []Variant) (*Result, error) {
+	flat, format, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Format:   format,
+		Width:    flat.Bounds().Dx(),
+		Height:   flat.Bounds().Dy(),
+		Variants: make(map[string][]byte, len(variants)),
+		PHash:    PHash(flat),
+	}
+
+	for _, v := range variants {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resize(flat, v), &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		result.Variants[v.Name] = buf.Bytes()
+	}
+
+	return result, nil
+}
+
+// StripMetadata - Görüntüyü metadata'sız JPEG olarak yeniden kodla
+// Yükleme depoya yazılmadan önce çağrılır: EXIF (GPS dahil), XMP ve PNG/WebP metin
+// blokları atılır, EXIF yönlendirmesi piksellere uygulanır.
+func StripMetadata(data []byte) ([]byte, error) {
+	flat, _, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: originalQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decode - Görüntüyü doğrula, çöz, yönlendirmesini düzelt ve beyaz zemine oturt
+func decode(data []byte) (*image.RGBA, string, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if format != "jpeg" && format != "png" && format != "webp" {
+		return nil, "", fmt.Errorf("%w: %s", ErrInvalidImage, format)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, "", ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+	return flatten(img), format, nil
+}
+
+// flatten - Şeffaf alanları beyaz zemine oturt (JPEG alfa kanalı taşımaz)
+func flatten(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	return dst
+}
+
+// resize - Varyant boyutuna sığdır veya kırp
+func resize(img *image.RGBA, v Variant) *image.RGBA {
+	src := img.Bounds()
+	w, h := src.Dx(), src.Dy()
+
+	if v.Crop {
+		// Hedef oranda en büyük orta bölge
+		cropW, cropH := w, w*v.Height/v.Width
+		if cropH > h {
+			cropW, cropH = h*v.Width/v.Height, h
+		}
+		x0, y0 := (w-cropW)/2, (h-cropH)/2
+		src = image.Rect(x0, y0, x0+cropW, y0+cropH)
+		w, h = cropW, cropH
+	}
+
+	dstW, dstH := w, h
+	switch {
+	case dstW <= v.Width && dstH <= v.Height:
+		// Küçük görüntü büyütülmez
+	case v.Crop:
+		// Kırpılan bölge zaten hedef oranda; yuvarlama hatası boyutu kaydırmasın
+		dstW, dstH = v.Width, v.Height
+	case dstW*v.Height > dstH*v.Width:
+		dstW, dstH = v.Width, (h*v.Width+w/2)/w
+	default:
+		dstW, dstH = (w*v.Height+h/2)/h, v.Height
+	}
+	if dstW < 1 {
+		dstW = 1
+	}
+	if dstH < 1 {
+		dstH = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Src, nil)
+	return dst
+}
+
+// orient - EXIF yönlendirme değerine göre görüntüyü döndür/çevir
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // Yatay çevir
+				dx, dy = w-1-x, y
+			case 3: // 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // Dikey çevir
+				dx, dy = x, h-1-y
+			case 5: // Transpoze
+				dx, dy = y, x
+			case 6: // Saat yönünde 90°
+				dx, dy = h-1-y, x
+			case 7: // Ters transpoze
+				dx, dy = h-1-y, w-1-x
+			case 8: // Saat yönünün tersine 90°
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+
+	return dst
+}
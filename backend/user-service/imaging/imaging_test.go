@@ -0,0 +1,223 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// halves - Sol yarısı (vertical) veya üst yarısı açık, diğer yarısı koyu görüntü
+func halves(w, h int, vertical bool) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			bright := y < h/2
+			if vertical {
+				bright = x < w/2
+			}
+			if bright {
+				img.Set(x, y, color.RGBA{R: 240, G: 240, B: 240, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{R: 15, G: 15, B: 15, A: 255})
+			}
+		}
+	}
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withOrientation - JPEG'in SOI'sinden sonra yönlendirme etiketli bir APP1 Exif bölümü ekle
+func withOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2A\x00\x00\x00\x08")              // Big endian, IFD0 8. baytta
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)             // Tek etiket
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)        // Orientation
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)             // SHORT
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)             // Adet
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)   // Değer
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)                     // Dolgu ve sonraki IFD yok
+	tiff = append(tiff, []byte("GPS 41.0082 N 28.9784 E")...) // Atılması gereken konum
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+// bright - Pikselin açık tarafa ait olup olmadığı (JPEG kaybı payıyla)
+func bright(img image.Image, x, y int) bool {
+	r, _, _, _ := img.At(x, y).RGBA()
+	return r>>8 > 128
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	plain := encodeJPEG(t, halves(40, 20, true))
+	if got := jpegOrientation(plain); got != 1 {
+		t.Fatalf("orientation without exif = %d, want 1", got)
+	}
+
+	tests := []struct {
+		orientation uint16
+		topBright   bool // 90° döndürülünce sol yarı üste veya alta gelir
+	}{
+		{6, true},
+		{8, false},
+	}
+	for _, tt := range tests {
+		data := withOrientation(plain, tt.orientation)
+		if got := jpegOrientation(data); got != int(tt.orientation) {
+			t.Fatalf("jpegOrientation = %d, want %d", got, tt.orientation)
+		}
+
+		img, format, err := decode(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if format != "jpeg" {
+			t.Fatalf("format = %q", format)
+		}
+		if b := img.Bounds(); b.Dx() != 20 || b.Dy() != 40 {
+			t.Fatalf("orientation %d: size %dx%d, want 20x40", tt.orientation, b.Dx(), b.Dy())
+		}
+		if bright(img, 10, 5) != tt.topBright || bright(img, 10, 35) == tt.topBright {
+			t.Errorf("orientation %d: top bright = %v, want %v", tt.orientation, bright(img, 10, 5), tt.topBright)
+		}
+	}
+}
+
+func TestStripMetadata(t *testing.T) {
+	data := withOrientation(encodeJPEG(t, halves(40, 20, true)), 6)
+
+	clean, err := StripMetadata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(clean, []byte("Exif")) || bytes.Contains(clean, []byte("GPS")) {
+		t.Fatal("stripped image still carries exif")
+	}
+	if got := jpegOrientation(clean); got != 1 {
+		t.Fatalf("orientation after strip = %d, want 1", got)
+	}
+	// Yönlendirme piksellere uygulanmış olmalı
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(clean))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 20 || cfg.Height != 40 {
+		t.Fatalf("size %dx%d, want 20x40", cfg.Width, cfg.Height)
+	}
+
+	// PNG metin blokları da atılır
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, halves(16, 16, false)); err != nil {
+		t.Fatal(err)
+	}
+	if clean, err := StripMetadata(buf.Bytes()); err != nil || formatOf(clean) != "jpeg" {
+		t.Fatalf("png strip: format %q, err %v", formatOf(clean), err)
+	}
+
+	if _, err := StripMetadata([]byte("not an image")); err == nil {
+		t.Fatal("invalid data was accepted")
+	}
+}
+
+// formatOf - Çıktının biçimi (image.DecodeConfig ile)
+func formatOf(data []byte) string {
+	_, format, _ := image.DecodeConfig(bytes.NewReader(data))
+	return format
+}
+
+func TestResize(t *testing.T) {
+	tests := []struct {
+		name         string
+		w, h         int
+		variant      Variant
+		wantW, wantH int
+	}{
+		{"crop to square", 1000, 500, Variant{Width: 256, Height: 256, Crop: true}, 256, 256},
+		{"crop portrait", 1000, 1000, Variant{Width: 200, Height: 300, Crop: true}, 200, 300},
+		{"fit landscape", 1000, 500, Variant{Width: 640, Height: 960}, 640, 320},
+		{"fit portrait", 500, 2000, Variant{Width: 640, Height: 960}, 240, 960},
+		{"never upscale", 100, 50, Variant{Width: 640, Height: 960}, 100, 50},
+		{"small crop keeps size", 100, 50, Variant{Width: 256, Height: 256, Crop: true}, 50, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resize(halves(tt.w, tt.h, true), tt.variant).Bounds()
+			if got.Dx() != tt.wantW || got.Dy() != tt.wantH {
+				t.Fatalf("size %dx%d, want %dx%d", got.Dx(), got.Dy(), tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestProcessVariants(t *testing.T) {
+	result, err := Process(encodeJPEG(t, halves(1200, 800, true)), DefaultVariants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Format != "jpeg" || result.Width != 1200 || result.Height != 800 {
+		t.Fatalf("result = %s %dx%d", result.Format, result.Width, result.Height)
+	}
+
+	want := map[string][2]int{"thumb": {256, 256}, "card": {640, 427}, "full": {1200, 800}}
+	for name, size := range want {
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(result.Variants[name]))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if cfg.Width != size[0] || cfg.Height != size[1] {
+			t.Errorf("%s: %dx%d, want %dx%d", name, cfg.Width, cfg.Height, size[0], size[1])
+		}
+	}
+}
+
+func TestPHash(t *testing.T) {
+	original := halves(400, 300, true)
+	hash := PHash(original)
+
+	// Küçültülmüş ve yeniden sıkıştırılmış kopya neredeyse aynı özeti verir
+	small := resize(original, Variant{Width: 120, Height: 120})
+	copyImg, err := jpeg.Decode(bytes.NewReader(encodeJPEG(t, small)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := HammingDistance(hash, PHash(copyImg)); d > 4 {
+		t.Errorf("resized copy distance = %d, want <= 4", d)
+	}
+
+	// Farklı bir görüntü uzak düşer
+	if d := HammingDistance(hash, PHash(halves(400, 300, false))); d < 10 {
+		t.Errorf("different image distance = %d, want >= 10", d)
+	}
+
+	if d := HammingDistance(0, 0xFF); d != 8 {
+		t.Errorf("HammingDistance(0, 0xFF) = %d, want 8", d)
+	}
+
+	s := FormatHash(hash)
+	if len(s) != 16 {
+		t.Fatalf("FormatHash = %q, want 16 hex digits", s)
+	}
+	if parsed, err := ParseHash(s); err != nil || parsed != hash {
+		t.Fatalf("ParseHash(%q) = %x, %v; want %x", s, parsed, err, hash)
+	}
+	if _, err := ParseHash("zz"); err == nil {
+		t.Fatal("invalid hash was parsed")
+	}
+}
@@ -0,0 +1,76 @@
+// phash.go - DCT tabanlı algısal özet (perceptual hash)
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	phashSize   = 32 // Özet için küçültülen görüntü boyutu
+	phashLowDim = 8  // Kullanılan düşük frekanslı DCT katsayıları (8x8 = 64 bit)
+)
+
+// PHash - Görüntünün 64 bitlik algısal özeti
+// Yeniden boyutlandırma, sıkıştırma ve hafif renk değişikliklerinde büyük ölçüde aynı kalır.
+func PHash(img image.Image) uint64 {
+	gray := image.NewGray(image.Rect(0, 0, phashSize, phashSize))
+	draw.BiLinear.Scale(gray, gray.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var pixels [phashSize][phashSize]float64
+	for y := 0; y < phashSize; y++ {
+		for x := 0; x < phashSize; x++ {
+			pixels[y][x] = float64(gray.GrayAt(x, y).Y)
+		}
+	}
+
+	// Sadece sol üst 8x8 DCT katsayılarını hesapla
+	var coeffs [phashLowDim * phashLowDim]float64
+	for v := 0; v < phashLowDim; v++ {
+		for u := 0; u < phashLowDim; u++ {
+			sum := 0.0
+			for y := 0; y < phashSize; y++ {
+				cy := math.Cos(float64(2*y+1) * float64(v) * math.Pi / (2 * phashSize))
+				for x := 0; x < phashSize; x++ {
+					sum += pixels[y][x] * cy * math.Cos(float64(2*x+1)*float64(u)*math.Pi/(2*phashSize))
+				}
+			}
+			coeffs[v*phashLowDim+u] = sum
+		}
+	}
+
+	// Medyan DC bileşeni hariç hesaplanır (DC tüm parlaklığı taşır)
+	sorted := make([]float64, len(coeffs)-1)
+	copy(sorted, coeffs[1:])
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+
+	var hash uint64
+	for i, c := range coeffs {
+		if c > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// HammingDistance - İki özet arasındaki farklı bit sayısı (0 = aynı görüntü)
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// FormatHash - Özeti veritabanında saklanan 16 haneli hex biçimine çevir
+func FormatHash(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+// ParseHash - 16 haneli hex özeti çöz
+func ParseHash(s string) (uint64, error) {
+	return strconv.ParseUint(s, 16, 64)
+}
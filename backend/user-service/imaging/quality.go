@@ -0,0 +1,109 @@
+// quality.go - Fotoğraf kalite skoru (çözünürlük, pozlama, kontrast, netlik)
+package imaging
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	qualityRefSide   = 1080 // Bu kısa kenar ve üstü tam çözünürlük puanı alır
+	qualitySampleMax = 512  // Analiz bu boyuta küçültülmüş kopya üzerinde yapılır
+	sharpnessRef     = 300  // Laplace varyansı bu değerde 0.5 netlik puanı verir
+)
+
+// Analysis - Yükleme sırasında hesaplanan değerler
+type Analysis struct {
+	Score float64 // 0-1 arası kalite skoru
+	PHash uint64  // Algısal özet (işlenmiş varyantlarla aynı yönlendirmeden)
+}
+
+// Analyze - Fotoğrafı çöz, kalite skorunu ve algısal özeti hesapla
+func Analyze(data []byte) (*Analysis, error) {
+	img, _, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Analysis{Score: QualityScore(img), PHash: PHash(img)}, nil
+}
+
+// QualityScore - Çözünürlük, pozlama, kontrast ve netliğin ağırlıklı ortalaması
+func QualityScore(img image.Image) float64 {
+	b := img.Bounds()
+	shortSide := b.Dx()
+	if b.Dy() < shortSide {
+		shortSide = b.Dy()
+	}
+	resolution := math.Min(1, float64(shortSide)/qualityRefSide)
+
+	gray := sample(img)
+	mean, stddev := luminanceStats(gray)
+	exposure := 1 - math.Abs(mean-128)/128
+	contrast := math.Min(1, stddev/64)
+
+	variance := laplacianVariance(gray)
+	sharpness := variance / (variance + sharpnessRef)
+
+	score := 0.3*resolution + 0.2*exposure + 0.2*contrast + 0.3*sharpness
+	return math.Round(score*100) / 100
+}
+
+// sample - Analiz için gri tonlamalı küçük kopya
+func sample(img image.Image) *image.Gray {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > qualitySampleMax || h > qualitySampleMax {
+		if w > h {
+			w, h = qualitySampleMax, h*qualitySampleMax/w
+		} else {
+			w, h = w*qualitySampleMax/h, qualitySampleMax
+		}
+	}
+	if w < 3 {
+		w = 3
+	}
+	if h < 3 {
+		h = 3
+	}
+
+	gray := image.NewGray(image.Rect(0, 0, w, h))
+	draw.BiLinear.Scale(gray, gray.Bounds(), img, b, draw.Src, nil)
+	return gray
+}
+
+func luminanceStats(gray *image.Gray) (mean, stddev float64) {
+	n := float64(len(gray.Pix))
+	for _, p := range gray.Pix {
+		mean += float64(p)
+	}
+	mean /= n
+
+	for _, p := range gray.Pix {
+		d := float64(p) - mean
+		stddev += d * d
+	}
+	return mean, math.Sqrt(stddev / n)
+}
+
+// laplacianVariance - Kenar yanıtının varyansı; bulanık fotoğraflarda düşüktür
+func laplacianVariance(gray *image.Gray) float64 {
+	b := gray.Bounds()
+	var sum, sumSq, n float64
+	for y := b.Min.Y + 1; y < b.Max.Y-1; y++ {
+		for x := b.Min.X + 1; x < b.Max.X-1; x++ {
+			v := 4*float64(gray.GrayAt(x, y).Y) -
+				float64(gray.GrayAt(x-1, y).Y) - float64(gray.GrayAt(x+1, y).Y) -
+				float64(gray.GrayAt(x, y-1).Y) - float64(gray.GrayAt(x, y+1).Y)
+			sum += v
+			sumSq += v * v
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	mean := sum / n
+	return sumSq/n - mean*mean
+}
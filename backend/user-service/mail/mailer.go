@@ -0,0 +1,50 @@
+// mailer.go - E-posta gönderim arayüzü ve yapılandırması
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Message - Düz metin e-posta
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer - E-postaların gönderildiği yer
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// FromEnv - MAILER değişkenine göre gönderici oluştur ("smtp", "file" veya "log")
+// Tanımlı değilse e-postalar standart hata çıkışına yazılır.
+func FromEnv() (Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "EROS <no-reply@eros.local>"
+	}
+
+	switch backend := os.Getenv("MAILER"); backend {
+	case "", "log":
+		return NewLogMailer(), nil
+	case "file":
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = "./mail"
+		}
+		return NewFileMailer(dir, from)
+	case "smtp":
+		return NewSMTPMailer(SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		})
+	default:
+		return nil, fmt.Errorf("unknown MAILER %q", backend)
+	}
+}
@@ -0,0 +1,58 @@
+// sink.go - Yerel geliştirme için e-postayı dosyaya veya loga yazan göndericiler
+package mail
+
+import (
+	"context"
+	"fmt"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileMailer - Her mesajı dizine .eml dosyası olarak yazar (e-posta istemcisiyle açılabilir)
+type FileMailer struct {
+	dir  string
+	from *netmail.Address
+}
+
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	fromAddr, err := netmail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAIL_FROM: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileMailer{dir: dir, from: fromAddr}, nil
+}
+
+// Send - Mesajı {zaman}_{alıcı}.eml dosyasına yaz
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	to, err := netmail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %v", err)
+	}
+
+	now := time.Now()
+	// Adresin yerel kısmı "/" içerebilir
+	recipient := strings.NewReplacer("/", "_", "\\", "_").Replace(to.Address)
+	name := fmt.Sprintf("%s_%s.eml", now.Format("20060102T150405.000000000"), recipient)
+	return os.WriteFile(filepath.Join(m.dir, name), buildMessage(m.from, to, msg, now), 0o644)
+}
+
+// LogMailer - Mesajı standart hata çıkışına yazar (yalnızca yerel geliştirme)
+// Gövde bağlantı ve jeton içerdiği için yapılandırılmış günlüklerin dışında tutulur;
+// orada gövdeler maskelenir. Üretimde MAILER=smtp kullanılmalı.
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// Send - Alıcı, konu ve gövdeyi yaz
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	fmt.Fprintf(os.Stderr, "[mail] to=%s subject=%q\n%s\n", msg.To, msg.Subject, msg.Body)
+	return nil
+}
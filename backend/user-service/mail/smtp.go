@@ -0,0 +1,97 @@
+// smtp.go - SMTP ile e-posta gönderimi
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPConfig - SMTP sunucu ayarları
+type SMTPConfig struct {
+	Host     string
+	Port     string // Varsayılan 587 (sunucu destekliyorsa STARTTLS kullanılır)
+	Username string // Boşsa kimlik doğrulama yapılmaz
+	Password string
+	From     string // "Ad <adres>" veya sadece adres
+}
+
+// SMTPMailer - net/smtp ile gönderen Mailer
+type SMTPMailer struct {
+	cfg  SMTPConfig
+	from *netmail.Address
+}
+
+func NewSMTPMailer(cfg SMTPConfig) (*SMTPMailer, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("SMTP_HOST is required")
+	}
+	if cfg.Port == "" {
+		cfg.Port = "587"
+	}
+
+	from, err := netmail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAIL_FROM: %v", err)
+	}
+
+	return &SMTPMailer{cfg: cfg, from: from}, nil
+}
+
+// Send - Mesajı gönder
+// net/smtp bağlam desteklemez; iptal sadece gönderim başlamadan önce dikkate alınır.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	to, err := netmail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %v", err)
+	}
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, m.cfg.Port)
+	return smtp.SendMail(addr, auth, m.from.Address, []string{to.Address}, buildMessage(m.from, to, msg, time.Now()))
+}
+
+// buildMessage - RFC 5322 başlıklarıyla UTF-8 düz metin mesaj
+func buildMessage(from, to *netmail.Address, msg Message, now time.Time) []byte {
+	var buf bytes.Buffer
+
+	id := make([]byte, 16)
+	rand.Read(id)
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+
+	// SMTP satır sonları CRLF olmalıdır
+	body := strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n")
+	buf.WriteString(body)
+	if !strings.HasSuffix(body, "\r\n") {
+		buf.WriteString("\r\n")
+	}
+
+	return buf.Bytes()
+}
@@ -2,12 +2,27 @@
 package main
 
 import (
+	"context"
+	"eros/shared/apierror"
+	"eros/shared/auth"
+	"eros/shared/health"
+	"eros/shared/logging"
+	"eros/shared/metrics"
+	"eros/shared/migrate"
+	"eros/shared/moderation"
+	"eros/shared/server"
+	"eros/shared/sqldb"
+	"eros/shared/tracing"
 	"eros/user-service/handler"
+	"eros/user-service/mail"
 	"eros/user-service/repository"
 	"eros/user-service/service"
-	"log"
+	"eros/user-service/storage"
+	"eros/user-service/verification"
+	"log/slog"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
@@ -15,71 +30,135 @@ import (
 
 func main() {
 	// .env dosyasını yükle
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using default values")
+	envErr := godotenv.Load()
+
+	// JSON günlükler (LOG_LEVEL .env'den de okunabilir)
+	logging.Setup("user-service")
+	if envErr != nil {
+		slog.Info("no .env file found, using default values")
 	}
 
-	// SQLite veritabanını başlat
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "./eros.db"
+	// Dağıtık izleme (OTEL_TRACES_EXPORTER=otlp|console; varsayılan kapalı)
+	if err := tracing.Setup("user-service"); err != nil {
+		logging.Fatal("failed to initialize tracing", err)
 	}
 
-	db, err := repository.NewSQLiteDB(dbPath)
+	// Veritabanını başlat (DB_DRIVER=sqlite için DB_PATH, DB_DRIVER=postgres için DATABASE_URL)
+	db, err := sqldb.FromEnv("./eros.db")
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		logging.Fatal("failed to connect to database", err)
 	}
 	defer db.Close()
 
-	// Veritabanı tablolarını oluştur
-	if err := repository.InitDatabase(db); err != nil {
-		log.Fatal("Failed to initialize database:", err)
+	migrator, err := repository.NewMigrator(db)
+	if err != nil {
+		logging.Fatal("failed to load migrations", err)
+	}
+
+	// "go run . migrate up|down|status" - göçleri elle yönet ve çık
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate.Command(context.Background(), migrator, os.Args[2:], os.Stdout); err != nil {
+			logging.Fatal("migrate command failed", err)
+		}
+		return
+	}
+
+	// Bekleyen şema göçlerini uygula (AUTO_MIGRATE=false ise sadece kontrol et)
+	if err := migrate.Startup(context.Background(), migrator, migrate.AutoMigrateFromEnv()); err != nil {
+		logging.Fatal("failed to migrate database", err)
 	}
 
 	// Repository'leri oluştur
 	userRepo := repository.NewUserRepository(db)
 	photoRepo := repository.NewPhotoRepository(db)
+	reviewRepo := repository.NewPhotoReviewRepository(db)
+	verificationRepo := repository.NewVerificationRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	loginRepo := repository.NewLoginRepository(db)
+
+	// Fotoğraf depolaması (PHOTO_STORAGE=local|s3)
+	photoStore, err := storage.FromEnv()
+	if err != nil {
+		logging.Fatal("failed to initialize photo storage", err)
+	}
+	urlSigner := storage.URLSignerFromEnv("/api/photos/file/")
+
+	// Selfie doğrulaması (FACE_VERIFIER=http, tanımlı değilse kapalı; fake yalnızca testler için)
+	faceVerifier, err := verification.FromEnv()
+	if err != nil {
+		logging.Fatal("failed to initialize face verifier", err)
+	}
+	if _, ok := faceVerifier.(*verification.FakeVerifier); ok {
+		slog.Warn("FACE_VERIFIER=fake accepts a profile photo as the selfie; use it only in tests")
+	}
+
+	// E-posta gönderimi (MAILER=smtp|file|log)
+	mailer, err := mail.FromEnv()
+	if err != nil {
+		logging.Fatal("failed to initialize mailer", err)
+	}
+
+	// Arka plan işleri (fotoğraf işleme, bildirimler); kapanışta bitmeleri beklenir
+	workers := server.NewWorkers()
 
 	// Service'leri oluştur
-	userService := service.NewUserService(userRepo, photoRepo)
+	userService := service.NewUserService(userRepo, photoRepo, reviewRepo, photoStore, urlSigner, service.NewProfileValidator(moderation.Default()), service.DuplicatePolicyFromEnv(), workers)
+
+	accountPolicy := service.AccountPolicyFromEnv()
+	accountService := service.NewAccountService(userRepo, tokenRepo, mailer, accountPolicy)
+	loginGuard := service.NewLoginGuard(loginRepo, userRepo, service.NewMailNewDeviceNotifier(mailer, accountPolicy.BaseURL), service.LoginPolicyFromEnv(), workers)
+	verificationService := service.NewVerificationService(userRepo, photoRepo, verificationRepo, photoStore, faceVerifier, service.VerificationPolicyFromEnv())
+
+	// Yarım kalmış fotoğraf işlemelerini sürdür
+	if err := userService.ResumePhotoProcessing(); err != nil {
+		slog.Error("failed to resume photo processing", "error", err)
+	}
 
 	// Handler'ları oluştur
-	authHandler := handler.NewAuthHandler(userService)
-	photosHandler := handler.NewPhotosHandler(userService)
+	// Oturum jetonları (JWT_SECRET gateway ile aynı olmalı)
+	tokenSigner, err := auth.SignerFromEnv()
+	if err != nil {
+		slog.Warn("JWT_SECRET not set, login will not issue session tokens")
+	}
+	authHandler := handler.NewAuthHandler(userService, accountService, loginGuard, tokenSigner)
+	photosHandler := handler.NewPhotosHandler(userService, storage.MaxPhotoBytesFromEnv())
 	profileHandler := handler.NewProfileHandler(userService)
+	adminHandler := handler.NewAdminHandler(userService, loginGuard, os.Getenv("ADMIN_TOKEN"))
+	verificationHandler := handler.NewVerificationHandler(verificationService, storage.MaxPhotoBytesFromEnv())
 
 	// Router'ı oluştur
 	router := mux.NewRouter()
-
-	// Auth routes
-	router.HandleFunc("/api/auth/register", authHandler.Register).Methods("POST", "OPTIONS")
-	router.HandleFunc("/api/auth/simple-register", authHandler.SimpleRegister).Methods("POST", "OPTIONS")
-	router.HandleFunc("/api/auth/login", authHandler.Login).Methods("POST", "OPTIONS")
-
-	// Form data routes
-	router.HandleFunc("/api/form/hobby-categories", authHandler.GetHobbyCategories).Methods("GET", "OPTIONS")
-	router.HandleFunc("/api/form/education-levels", authHandler.GetEducationLevels).Methods("GET", "OPTIONS")
-	router.HandleFunc("/api/form/job-categories", authHandler.GetJobCategories).Methods("GET", "OPTIONS")
-
-	// Photo routes
-	router.HandleFunc("/api/photos/upload", photosHandler.UploadPhoto).Methods("POST", "OPTIONS")
-	router.HandleFunc("/api/photos", photosHandler.GetUserPhotos).Methods("GET", "OPTIONS")
-	router.HandleFunc("/api/photos/reorder", photosHandler.ReorderPhotos).Methods("PUT", "OPTIONS")
-	router.HandleFunc("/api/photos", photosHandler.DeletePhoto).Methods("DELETE", "OPTIONS")
-
-	// User routes
-	router.HandleFunc("/api/users/{id}", profileHandler.GetProfile).Methods("GET", "OPTIONS")
-	router.HandleFunc("/api/users/{id}", profileHandler.UpdateProfile).Methods("PUT", "OPTIONS")
-	router.HandleFunc("/api/users/{id}", profileHandler.DeleteProfile).Methods("DELETE", "OPTIONS")
-	router.HandleFunc("/api/users/{id}/preferences", profileHandler.GetUserPreferences).Methods("GET", "OPTIONS")
-	router.HandleFunc("/api/users/{id}/preferences", profileHandler.UpdateUserPreferences).Methods("PUT", "OPTIONS")
+	// Eşleşmeyen rota ve yöntemler de ortak JSON hata zarfıyla döner
+	router.NotFoundHandler = apierror.NotFoundHandler()
+	router.MethodNotAllowedHandler = apierror.MethodNotAllowedHandler()
+
+	// API routes
+	handler.Handlers{
+		Auth:         authHandler,
+		Photos:       photosHandler,
+		Profile:      profileHandler,
+		Admin:        adminHandler,
+		Verification: verificationHandler,
+	}.Register(router)
+
+	// Canlılık ve hazırlık (veritabanı, şema göçleri)
+	checker := health.New("user-service", 2*time.Second)
+	checker.Add("database", true, health.Ping(db))
+	checker.Add("migrations", true, health.Migrations(migrator))
+	router.HandleFunc("/healthz", checker.Live).Methods("GET")
+	router.HandleFunc("/readyz", checker.Ready).Methods("GET")
+
+	// Prometheus metrikleri (istek süreleri rota şablonu, yöntem ve durum koduna göre)
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+	router.Use(metrics.Middleware)
+	router.Use(tracing.Middleware)
 
 	// CORS middleware (en üste, route'lardan hemen sonra)
 	router.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Admin-Token")
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
 				return
@@ -94,6 +173,14 @@ func main() {
 		port = "8081"
 	}
 
-	log.Printf("User Service starting on port %s", port)
-	log.Fatal(http.ListenAndServe(":"+port, router))
+	srv := server.New("User Service", logging.Middleware(router), server.ConfigFromEnv(":"+port), workers)
+	runErr := srv.Run()
+
+	// İstekler boşaltıldıktan sonra kuyruktaki span'leri gönder
+	if err := tracing.Shutdown(context.Background()); err != nil {
+		slog.Warn("failed to flush spans", "error", err)
+	}
+	if runErr != nil {
+		logging.Fatal("server failed", runErr)
+	}
 }
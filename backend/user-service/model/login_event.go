@@ -0,0 +1,25 @@
+// login_event.go - Giriş denetim kaydı modeli
+package model
+
+import "time"
+
+// LoginEvent - Tek bir giriş denemesinin denetim kaydı
+type LoginEvent struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int       `json:"user_id" db:"user_id"` // Bilinmeyen e-postalarda 0
+	Email     string    `json:"email" db:"email"`     // Küçük harfe çevrilmiş
+	IP        string    `json:"ip" db:"ip"`
+	UserAgent string    `json:"user_agent" db:"user_agent"`
+	Outcome   string    `json:"outcome" db:"outcome"`       // LoginOutcome*
+	NewDevice bool      `json:"new_device" db:"new_device"` // Başarılı girişte daha önce görülmemiş cihaz
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Giriş sonuçları
+const (
+	LoginOutcomeSuccess          = "success"
+	LoginOutcomeInvalidPassword  = "invalid_password"
+	LoginOutcomeUnknownEmail     = "unknown_email"
+	LoginOutcomeEmailNotVerified = "email_not_verified" // Şifre doğru, e-posta doğrulanmamış
+	LoginOutcomeLocked           = "locked"             // Kilit süresinde gelen deneme (şifre kontrol edilmedi)
+)
@@ -11,5 +11,16 @@ type Photo struct {
     OrderIndex  int       `json:"order_index" db:"order_index"` // 1-6 arası sıralama
     AIScore     float64   `json:"ai_score" db:"ai_score"`       // AI tarafından verilen kalite skoru
     IsVerified  bool      `json:"is_verified" db:"is_verified"` // Kullanıcının kendisi olup olmadığı
+    Status      string    `json:"status" db:"status"`           // İşleme durumu (PhotoStatus*)
+    Variants    map[string]string `json:"variants,omitempty" db:"-"` // thumb/card/full imzalı adresleri
+    PHash       string    `json:"-" db:"phash"`                 // Algısal özet (16 haneli hex)
     CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
+
+// Fotoğraf işleme durumları
+const (
+    PhotoStatusPending    = "pending"    // Yüklendi, işlenmeyi bekliyor
+    PhotoStatusProcessing = "processing" // Varyantlar üretiliyor
+    PhotoStatusReady      = "ready"      // Varyantlar hazır, orijinal silindi
+    PhotoStatusFailed     = "failed"     // İşlenemedi; dosyaları silindi, limite sayılmaz
+)
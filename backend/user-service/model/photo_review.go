@@ -0,0 +1,33 @@
+// photo_review.go - Kopya ve çalıntı fotoğraf inceleme modeli
+package model
+
+import "time"
+
+// PhotoReview - Başka bir hesabın fotoğrafına çok benzediği için incelemeye alınan yükleme
+type PhotoReview struct {
+	ID             int        `json:"id" db:"id"`
+	PhotoID        int        `json:"photo_id" db:"photo_id"` // Engellenen yüklemelerde 0
+	UserID         int        `json:"user_id" db:"user_id"`
+	MatchedPhotoID int        `json:"matched_photo_id" db:"matched_photo_id"`
+	MatchedUserID  int        `json:"matched_user_id" db:"matched_user_id"`
+	Distance       int        `json:"distance" db:"distance"` // Algısal özetler arası Hamming mesafesi
+	Reason         string     `json:"reason" db:"reason"`     // PhotoReviewReason*
+	Status         string     `json:"status" db:"status"`     // PhotoReviewStatus*
+	ReviewedBy     string     `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// İnceleme sebepleri
+const (
+	PhotoReviewReasonDuplicate        = "duplicate"         // Başka bir kullanıcının fotoğrafına benziyor
+	PhotoReviewReasonSuspendedAccount = "suspended_account" // Askıya alınmış hesabın fotoğrafı
+)
+
+// İnceleme durumları
+const (
+	PhotoReviewStatusPending  = "pending"  // Admin kararı bekleniyor
+	PhotoReviewStatusApproved = "approved" // Fotoğraf kullanıcıya ait, doğrulandı
+	PhotoReviewStatusRejected = "rejected" // Fotoğraf silindi
+	PhotoReviewStatusBlocked  = "blocked"  // Yükleme otomatik engellendi
+)
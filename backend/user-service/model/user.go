@@ -4,27 +4,30 @@ package model
 import "time"
 
 type User struct {
-	ID              int       `json:"id" db:"id"`
-	Name            string    `json:"name" db:"name"`
-	Email           string    `json:"email" db:"email"`
-	Password        string    `json:"-" db:"password"`
-	Bio             string    `json:"bio" db:"bio"`
-	Age             int       `json:"age" db:"age"`
-	AgeRange        string    `json:"age_range" db:"age_range"`
-	Distance        int       `json:"distance" db:"distance"`
-	Seriousness     int       `json:"seriousness" db:"seriousness"`
-	Height          int       `json:"height" db:"height"` // cm cinsinden
-	Weight          int       `json:"weight" db:"weight"` // kg cinsinden
-	Smokes          bool      `json:"smokes" db:"smokes"`
-	Drinks          bool      `json:"drinks" db:"drinks"`
-	Job             string    `json:"job" db:"job"`
-	JobCategory     string    `json:"job_category" db:"job_category"`
-	Education       string    `json:"education" db:"education"`
-	Hobbies         []string  `json:"hobbies" db:"hobbies"`
-	HobbyCategories []string  `json:"hobby_categories" db:"hobby_categories"`
-	Photos          []Photo   `json:"photos"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	ID              int        `json:"id" db:"id"`
+	Name            string     `json:"name" db:"name"`
+	Email           string     `json:"email" db:"email"`
+	Password        string     `json:"-" db:"password"`
+	Bio             string     `json:"bio" db:"bio"`
+	Age             int        `json:"age" db:"age"`
+	AgeRange        string     `json:"age_range" db:"age_range"`
+	Distance        int        `json:"distance" db:"distance"`
+	Seriousness     int        `json:"seriousness" db:"seriousness"`
+	Height          int        `json:"height" db:"height"` // cm cinsinden
+	Weight          int        `json:"weight" db:"weight"` // kg cinsinden
+	Smokes          bool       `json:"smokes" db:"smokes"`
+	Drinks          bool       `json:"drinks" db:"drinks"`
+	Job             string     `json:"job" db:"job"`
+	JobCategory     string     `json:"job_category" db:"job_category"`
+	Education       string     `json:"education" db:"education"`
+	Hobbies         []string   `json:"hobbies" db:"hobbies"`
+	HobbyCategories []string   `json:"hobby_categories" db:"hobby_categories"`
+	Photos          []Photo    `json:"photos"`
+	EmailVerified   bool       `json:"email_verified" db:"-"` // E-posta doğrulama bağlantısına tıklandı (email_verified_at dolu)
+	IsVerified      bool       `json:"is_verified" db:"-"`    // Selfie doğrulamasından geçti (verified_at dolu)
+	VerifiedAt      *time.Time `json:"verified_at,omitempty" db:"verified_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 type UserPreferences struct {
@@ -0,0 +1,25 @@
+// verification.go - Selfie doğrulama meydan okuma modeli
+package model
+
+import "time"
+
+// VerificationChallenge - Kullanıcıya verilen tek kullanımlık poz meydan okuması
+type VerificationChallenge struct {
+	ID          int        `json:"id" db:"id"`
+	UserID      int        `json:"user_id" db:"user_id"`
+	Pose        string     `json:"pose" db:"pose"`
+	Instruction string     `json:"instruction" db:"-"`
+	Status      string     `json:"status" db:"status"`         // VerificationStatus*
+	Similarity  float64    `json:"similarity" db:"similarity"` // En yüksek yüz benzerliği
+	ExpiresAt   time.Time  `json:"expires_at" db:"expires_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Meydan okuma durumları
+const (
+	VerificationStatusPending = "pending" // Selfie bekleniyor
+	VerificationStatusPassed  = "passed"  // Poz ve yüz eşleşti
+	VerificationStatusFailed  = "failed"  // Poz veya yüz eşleşmedi
+	VerificationStatusExpired = "expired" // Süresinde selfie gelmedi
+)
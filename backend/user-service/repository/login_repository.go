@@ -0,0 +1,142 @@
+// login_repository.go - Giriş denetim kayıtları ve tanınan cihazlar veritabanı işlemleri
+package repository
+
+import (
+	"database/sql"
+	"eros/shared/sqldb"
+	"eros/user-service/model"
+	"time"
+)
+
+type LoginRepository struct {
+	db *sqldb.DB
+}
+
+func NewLoginRepository(db *sqldb.DB) *LoginRepository {
+	return &LoginRepository{db: db}
+}
+
+// RecordEvent - Giriş denemesini kaydet
+func (r *LoginRepository) RecordEvent(event *model.LoginEvent) error {
+	id, err := r.db.InsertID(`
+		INSERT INTO login_events (user_id, email, ip, user_agent, outcome, new_device, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, event.UserID, event.Email, event.IP, event.UserAgent, event.Outcome, event.NewDevice, event.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	event.ID = int(id)
+	return nil
+}
+
+// EmailFailuresSince - E-posta için son başarılı girişten ve since'ten sonraki başarısız denemeler
+// Sayıyla birlikte en son başarısız denemenin zamanını döner.
+func (r *LoginRepository) EmailFailuresSince(email string, since time.Time) (int, time.Time, error) {
+	var lastSuccess time.Time
+	err := r.db.QueryRow(`
+		SELECT created_at FROM login_events WHERE email = ? AND outcome = ?
+		ORDER BY created_at DESC LIMIT 1
+	`, email, model.LoginOutcomeSuccess).Scan(&lastSuccess)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, time.Time{}, err
+	}
+	if lastSuccess.After(since) {
+		since = lastSuccess
+	}
+
+	return r.failuresSince("email", email, since)
+}
+
+// IPFailuresSince - IP adresinden since'ten sonraki başarısız denemeler
+// Başarılı giriş IP sayacını sıfırlamaz (saldırgan kendi hesabıyla sayacı temizleyemez).
+func (r *LoginRepository) IPFailuresSince(ip string, since time.Time) (int, time.Time, error) {
+	return r.failuresSince("ip", ip, since)
+}
+
+// failuresSince - column sabit bir kolon adıdır (email veya ip), kullanıcı girdisi değildir
+func (r *LoginRepository) failuresSince(column, value string, since time.Time) (int, time.Time, error) {
+	rows, err := r.db.Query(`
+		SELECT created_at FROM login_events
+		WHERE `+column+` = ? AND outcome IN (?, ?) AND created_at > ?
+		ORDER BY created_at DESC
+	`, value, model.LoginOutcomeInvalidPassword, model.LoginOutcomeUnknownEmail, since)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	defer rows.Close()
+
+	var count int
+	var latest time.Time
+	for rows.Next() {
+		var at time.Time
+		if err := rows.Scan(&at); err != nil {
+			return 0, time.Time{}, err
+		}
+		if count == 0 {
+			latest = at
+		}
+		count++
+	}
+
+	return count, latest, rows.Err()
+}
+
+// ListUserEvents - Kullanıcının son giriş kayıtları (en yeni önce)
+// Hesabın e-postasıyla yapılan ama hesapla eşleşmeyen (ör. farklı büyük/küçük harfli) denemeler de listelenir.
+func (r *LoginRepository) ListUserEvents(userID int, email string, limit int) ([]model.LoginEvent, error) {
+	rows, err := r.db.Query(`
+		SELECT id, user_id, email, ip, user_agent, outcome, new_device, created_at
+		FROM login_events WHERE user_id = ? OR email = ?
+		ORDER BY created_at DESC, id DESC LIMIT ?
+	`, userID, email, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []model.LoginEvent{}
+	for rows.Next() {
+		var event model.LoginEvent
+		if err := rows.Scan(&event.ID, &event.UserID, &event.Email, &event.IP, &event.UserAgent,
+			&event.Outcome, &event.NewDevice, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// TouchDevice - Cihazı kullanıcının tanınan cihazlarına ekle veya son görülme zamanını güncelle
+// Cihaz ilk kez görülüyorsa true döner.
+func (r *LoginRepository) TouchDevice(userID int, deviceHash, userAgent, ip string, at time.Time) (bool, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO known_devices (user_id, device_hash, user_agent, last_ip, first_seen_at, last_seen_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, device_hash) DO NOTHING
+	`, userID, deviceHash, userAgent, ip, at, at)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n > 0 {
+		return true, nil
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE known_devices SET last_ip = ?, last_seen_at = ? WHERE user_id = ? AND device_hash = ?
+	`, ip, at, userID, deviceHash)
+	return false, err
+}
+
+// CountDevices - Kullanıcının tanınan cihaz sayısı
+func (r *LoginRepository) CountDevices(userID int) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM known_devices WHERE user_id = ?`, userID).Scan(&count)
+	return count, err
+}
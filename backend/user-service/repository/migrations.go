@@ -0,0 +1,398 @@
+// migrations.go - User service şema göçleri
+package repository
+
+import (
+	"database/sql"
+	"eros/shared/migrate"
+	"eros/shared/sqldb"
+)
+
+// userMigrations - Yeni göçler listenin sonuna, bir sonraki versiyon numarasıyla eklenir
+// Her göçün PostgreSQL karşılığı postgres.go'da aynı versiyon numarasıyla eklenir.
+var userMigrations = []migrate.Migration{
+	{
+		Version: 1,
+		Name:    "baseline",
+		Up:      baselineSchema,
+		Down:    migrate.SQL(`DROP TABLE IF EXISTS user_preferences`, `DROP TABLE IF EXISTS photos`, `DROP TABLE IF EXISTS users`),
+	},
+	{
+		Version: 2,
+		Name:    "user_preferences_categories",
+		Up: migrate.SQL(
+			`ALTER TABLE user_preferences ADD COLUMN preferred_job_categories TEXT DEFAULT '[]'`,
+			`ALTER TABLE user_preferences ADD COLUMN preferred_hobby_categories TEXT DEFAULT '[]'`,
+		),
+		Down: migrate.SQL(
+			`ALTER TABLE user_preferences DROP COLUMN preferred_hobby_categories`,
+			`ALTER TABLE user_preferences DROP COLUMN preferred_job_categories`,
+		),
+	}, {
+		Version: 3,
+		Name:    "photos_unique_order",
+		Up:      migrate.SQL(photosUniqueOrder...),
+		Down:    migrate.SQL(`DROP INDEX IF EXISTS idx_photos_user_order`),
+	},
+	{
+		Version: 4,
+		Name:    "clear_stale_verified_at",
+		Up:      clearStaleVerifiedAtSQLite,
+		Down:    migrate.SQL(), // Yalnızca veri düzeltmesi; geri alınacak şema yok
+	},
+	{
+		Version: 5,
+		Name:    "photo_processing",
+		Up:      photoProcessingSchema,
+		Down: migrate.SQL(
+			`ALTER TABLE photos DROP COLUMN phash`,
+			`ALTER TABLE photos DROP COLUMN card_url`,
+			`ALTER TABLE photos DROP COLUMN thumb_url`,
+			`ALTER TABLE photos DROP COLUMN status`,
+		),
+	},
+	{
+		Version: 6,
+		Name:    "photo_reviews",
+		Up:      photoReviewsSchema,
+		Down: migrate.SQL(
+			`DROP TABLE IF EXISTS blocked_photo_hashes`,
+			`DROP TABLE IF EXISTS photo_reviews`,
+			`ALTER TABLE users DROP COLUMN suspended_at`,
+		),
+	},
+	{
+		Version: 7,
+		Name:    "selfie_verification",
+		Up:      selfieVerificationSchema,
+		Down: migrate.SQL(
+			`DROP TABLE IF EXISTS verification_challenges`,
+			`ALTER TABLE users DROP COLUMN verified_at`,
+		),
+	},
+	{
+		Version: 8,
+		Name:    "email_verification",
+		Up:      emailVerificationSchema,
+		Down: migrate.SQL(
+			`DROP TABLE IF EXISTS auth_tokens`,
+			`ALTER TABLE users DROP COLUMN email_verified_at`,
+		),
+	},
+	{
+		Version: 9,
+		Name:    "login_audit",
+		Up:      loginAuditSchema,
+		Down:    migrate.SQL(`DROP TABLE IF EXISTS known_devices`, `DROP TABLE IF EXISTS login_events`),
+	},
+	{
+		Version: 10,
+		Name:    "photo_hash_bands",
+		Up:      photoHashBandsSchema,
+		Down:    migrate.SQL(dropHashBands(false)...),
+	},
+}
+
+// photosUniqueOrder - Kullanıcı başına sıra numarası tekil olsun (her iki lehçede aynı)
+// Eşzamanlı yüklemelerin bıraktığı tekrarlı numaralar önce 1..n olarak yeniden sıralanır.
+var photosUniqueOrder = []string{
+	`CREATE TEMP TABLE photo_order AS
+		SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY order_index, id) AS n FROM photos`,
+	`UPDATE photos SET order_index = (SELECT n FROM photo_order WHERE photo_order.id = photos.id)`,
+	`DROP TABLE photo_order`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_photos_user_order ON photos (user_id, order_index)`,
+}
+
+// clearStaleVerifiedAt - Doğrulanmış fotoğrafı kalmamış kullanıcıların rozetini kaldır
+// DeletePhoto bunu artık kendisi yapar; göç önceden silinmiş fotoğrafların bıraktığı rozetler içindir.
+// Boş veritabanlarında rozet kolonu sonraki "selfie_verification" göçüyle gelir; temizlenecek rozet yoktur.
+const clearStaleVerifiedAt = `
+	UPDATE users SET verified_at = NULL
+	WHERE verified_at IS NOT NULL
+	AND NOT EXISTS (SELECT 1 FROM photos WHERE photos.user_id = users.id AND photos.is_verified = TRUE)`
+
+// clearStaleVerifiedAtSQLite - Rozet kolonu varsa eski rozetleri temizle
+func clearStaleVerifiedAtSQLite(tx *sql.Tx) error {
+	exists, err := hasColumn(tx, "users", "verified_at")
+	if err != nil || !exists {
+		return err
+	}
+	_, err = tx.Exec(clearStaleVerifiedAt)
+	return err
+}
+
+// NewMigrator - User service veritabanının göç çalıştırıcısı (lehçeye göre SQLite veya PostgreSQL göçleri)
+func NewMigrator(db *sqldb.DB) (*migrate.Migrator, error) {
+	migrations := userMigrations
+	if db.IsPostgres() {
+		migrations = postgresMigrations
+	}
+	// Servisler aynı veritabanını paylaşabilir; her biri kendi göç tablosunu kullanır
+	return migrate.New(db.DB, migrations, migrate.Options{
+		Dialect:     db.Dialect,
+		Table:       "user_schema_migrations",
+		LegacyTable: "schema_migrations",
+	})
+}
+
+// baselineSchema - Göç sisteminden ve profil özelliklerinden önceki şema
+// Eski veritabanlarında tablolar zaten vardır; her adım tekrar çalıştırılabilir olduğu için
+// hem boş hem eski veritabanları aynı şemaya ulaşır. Sonraki özelliklerin tablo ve kolonları
+// kendi göçlerindedir; eski baseline'ı uygulamış veritabanlarında o göçler değişiklik yapmaz.
+func baselineSchema(tx *sql.Tx) error {
+	// Users tablosu
+	_, err := tx.Exec(`
+        CREATE TABLE IF NOT EXISTS users (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            name TEXT NOT NULL,
+            email TEXT UNIQUE NOT NULL,
+            password TEXT NOT NULL,
+            bio TEXT,
+            age INTEGER,
+            age_range TEXT,
+            distance INTEGER DEFAULT 50,
+            seriousness INTEGER DEFAULT 5,
+            height INTEGER,
+            weight INTEGER,
+            smokes BOOLEAN DEFAULT FALSE,
+            drinks BOOLEAN DEFAULT FALSE,
+            job TEXT,
+            job_category TEXT,
+            education TEXT,
+            hobbies TEXT, -- JSON string olarak saklanacak
+            hobby_categories TEXT, -- JSON string olarak saklanacak
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	// Photos tablosu
+	_, err = tx.Exec(`
+        CREATE TABLE IF NOT EXISTS photos (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user_id INTEGER NOT NULL,
+            url TEXT NOT NULL,
+            is_primary BOOLEAN DEFAULT FALSE,
+            order_index INTEGER DEFAULT 0,
+            ai_score REAL DEFAULT 0.0,
+            is_verified BOOLEAN DEFAULT FALSE,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (user_id) REFERENCES users (id)
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	// User preferences tablosu
+	_, err = tx.Exec(`
+        CREATE TABLE IF NOT EXISTS user_preferences (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user_id INTEGER UNIQUE NOT NULL,
+            min_age INTEGER DEFAULT 18,
+            max_age INTEGER DEFAULT 100,
+            min_height INTEGER DEFAULT 150,
+            max_height INTEGER DEFAULT 200,
+            accepts_smokers BOOLEAN DEFAULT TRUE,
+            accepts_drinkers BOOLEAN DEFAULT TRUE,
+            min_seriousness INTEGER DEFAULT 1,
+            max_seriousness INTEGER DEFAULT 10,
+            FOREIGN KEY (user_id) REFERENCES users (id)
+        )
+    `)
+	return err
+}
+
+// photoProcessingSchema - Fotoğraf işleme durumu, varyantlar ve algısal özet
+func photoProcessingSchema(tx *sql.Tx) error {
+	photoColumns := []struct{ name, definition string }{
+		{"status", "TEXT DEFAULT 'ready'"},
+		{"thumb_url", "TEXT"},
+		{"card_url", "TEXT"},
+		{"phash", "TEXT"},
+	}
+	for _, c := range photoColumns {
+		if err := ensureColumn(tx, "photos", c.name, c.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// photoReviewsSchema - Kopya fotoğraf incelemeleri ve askıya alınan hesapların özetleri
+func photoReviewsSchema(tx *sql.Tx) error {
+	// Askıya alınan hesapların fotoğrafları başka hesaplarda kullanılamaz
+	if err := ensureColumn(tx, "users", "suspended_at", "DATETIME"); err != nil {
+		return err
+	}
+
+	// Kopya / çalıntı fotoğraf inceleme kuyruğu
+	_, err := tx.Exec(`
+        CREATE TABLE IF NOT EXISTS photo_reviews (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            photo_id INTEGER NOT NULL, -- Engellenen yüklemelerde 0 (fotoğraf kaydedilmedi)
+            user_id INTEGER NOT NULL,
+            matched_photo_id INTEGER NOT NULL,
+            matched_user_id INTEGER NOT NULL,
+            distance INTEGER NOT NULL,
+            reason TEXT NOT NULL,
+            status TEXT NOT NULL,
+            reviewed_by TEXT,
+            reviewed_at DATETIME,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_photo_reviews_status ON photo_reviews (status, created_at)`)
+	if err != nil {
+		return err
+	}
+
+	// Askıya alınan hesapların fotoğraf özetleri (fotoğraflar silinse de kalır)
+	_, err = tx.Exec(`
+        CREATE TABLE IF NOT EXISTS blocked_photo_hashes (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            phash TEXT UNIQUE NOT NULL,
+            user_id INTEGER NOT NULL,
+            photo_id INTEGER NOT NULL,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )
+    `)
+	return err
+}
+
+// selfieVerificationSchema - Doğrulama rozeti ve poz meydan okumaları
+func selfieVerificationSchema(tx *sql.Tx) error {
+	// Selfie doğrulama rozeti (match-service "sadece doğrulanmışlar" filtresi bu kolonu okur)
+	if err := ensureColumn(tx, "users", "verified_at", "DATETIME"); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(`
+        CREATE TABLE IF NOT EXISTS verification_challenges (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user_id INTEGER NOT NULL,
+            pose TEXT NOT NULL,
+            status TEXT NOT NULL,
+            similarity REAL DEFAULT 0.0,
+            expires_at DATETIME NOT NULL,
+            completed_at DATETIME,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (user_id) REFERENCES users (id)
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_verification_challenges_user ON verification_challenges (user_id, created_at)`)
+	return err
+}
+
+// emailVerificationSchema - E-posta doğrulaması ve tek kullanımlık jetonlar
+func emailVerificationSchema(tx *sql.Tx) error {
+	// Bu kolondan önce açılmış hesaplar doğrulanmış sayılır
+	emailVerifiedColumn, err := hasColumn(tx, "users", "email_verified_at")
+	if err != nil {
+		return err
+	}
+	if !emailVerifiedColumn {
+		if _, err := tx.Exec(`ALTER TABLE users ADD COLUMN email_verified_at DATETIME`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE users SET email_verified_at = created_at`); err != nil {
+			return err
+		}
+	}
+
+	// E-posta doğrulama ve şifre sıfırlama jetonları (sadece SHA-256 özeti saklanır)
+	_, err = tx.Exec(`
+        CREATE TABLE IF NOT EXISTS auth_tokens (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user_id INTEGER NOT NULL,
+            purpose TEXT NOT NULL,
+            token_hash TEXT UNIQUE NOT NULL,
+            expires_at DATETIME NOT NULL,
+            used_at DATETIME,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (user_id) REFERENCES users (id)
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_auth_tokens_user ON auth_tokens (user_id, purpose, created_at)`)
+	return err
+}
+
+// loginAuditSchema - Giriş denetim kayıtları ve bilinen cihazlar
+func loginAuditSchema(tx *sql.Tx) error {
+	// Kaba kuvvet kilidi bu tablodan hesaplanır
+	_, err := tx.Exec(`
+        CREATE TABLE IF NOT EXISTS login_events (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user_id INTEGER NOT NULL DEFAULT 0, -- Bilinmeyen e-postalarda 0
+            email TEXT NOT NULL,
+            ip TEXT NOT NULL,
+            user_agent TEXT NOT NULL,
+            outcome TEXT NOT NULL,
+            new_device BOOLEAN DEFAULT FALSE,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	loginIndexes := []string{
+		`CREATE INDEX IF NOT EXISTS idx_login_events_email ON login_events (email, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_login_events_ip ON login_events (ip, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_login_events_user ON login_events (user_id, created_at)`,
+	}
+	for _, stmt := range loginIndexes {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	// Başarılı girişlerin yapıldığı cihazlar (yeni cihaz bildirimi için)
+	_, err = tx.Exec(`
+        CREATE TABLE IF NOT EXISTS known_devices (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user_id INTEGER NOT NULL,
+            device_hash TEXT NOT NULL,
+            user_agent TEXT NOT NULL,
+            last_ip TEXT NOT NULL,
+            first_seen_at DATETIME NOT NULL,
+            last_seen_at DATETIME NOT NULL,
+            UNIQUE (user_id, device_hash),
+            FOREIGN KEY (user_id) REFERENCES users (id)
+        )
+    `)
+	return err
+}
+
+// photoHashBandsSchema - Kopya taraması için algısal özet bantları (bkz. phash_index.go)
+func photoHashBandsSchema(tx *sql.Tx) error {
+	for _, table := range []string{"photos", "blocked_photo_hashes"} {
+		for _, column := range bandColumns {
+			if err := ensureColumn(tx, table, column, "INTEGER"); err != nil {
+				return err
+			}
+		}
+	}
+	if err := migrate.SQL(hashBandIndexes()...)(tx); err != nil {
+		return err
+	}
+
+	if err := backfillHashBands(tx, "photos"); err != nil {
+		return err
+	}
+	return backfillHashBands(tx, "blocked_photo_hashes")
+}
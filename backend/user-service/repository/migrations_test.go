@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"eros/shared/migrate"
+	"eros/shared/sqldb"
+	"path/filepath"
+	"testing"
+)
+
+// otherService - Aynı veritabanını paylaşan, versiyonları user-service ile çakışan başka bir servis
+// Varsayılan "schema_migrations" tablosunu kullanır: user-service de onu kullansaydı çakışırdı.
+func otherService(t *testing.T, db *sqldb.DB) *migrate.Migrator {
+	t.Helper()
+	m, err := migrate.New(db.DB, []migrate.Migration{
+		{Version: 1, Name: "baseline", Up: migrate.SQL(`CREATE TABLE IF NOT EXISTS matches (id INTEGER PRIMARY KEY)`)},
+		{Version: 2, Name: "matches_status", Up: migrate.SQL(`ALTER TABLE matches ADD COLUMN status TEXT`)},
+	}, migrate.Options{Dialect: db.Dialect})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// assertAllApplied - Tanımlı her göç uygulanmış, bilinmeyen kayıt yok
+func assertAllApplied(t *testing.T, name string, m *migrate.Migrator) {
+	t.Helper()
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if !s.Applied || s.Unknown {
+			t.Errorf("%s: migration %d (%s) applied=%v unknown=%v", name, s.Version, s.Name, s.Applied, s.Unknown)
+		}
+	}
+}
+
+func TestMigratorsShareDatabase(t *testing.T) {
+	for _, userFirst := range []bool{true, false} {
+		db, err := sqldb.Open(sqldb.SQLite, filepath.Join(t.TempDir(), "eros.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		users, err := NewMigrator(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		other := otherService(t, db)
+		migrators := []*migrate.Migrator{users, other}
+		if !userFirst {
+			migrators[0], migrators[1] = migrators[1], migrators[0]
+		}
+		for _, m := range migrators {
+			if _, err := m.Up(context.Background()); err != nil {
+				t.Fatalf("userFirst=%v: %v", userFirst, err)
+			}
+		}
+
+		assertAllApplied(t, "user-service", users)
+		assertAllApplied(t, "other service", other)
+		for _, table := range []string{"users", "photos", "matches"} {
+			var n int
+			if err := db.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n); err != nil {
+				t.Errorf("userFirst=%v: table %s: %v", userFirst, table, err)
+			}
+		}
+	}
+}
+
+func TestMigratorAdoptsLegacyTable(t *testing.T) {
+	db, err := sqldb.Open(sqldb.SQLite, filepath.Join(t.TempDir(), "users.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	// Önceki sürüm göçleri ortak "schema_migrations" tablosuna yazıyordu
+	legacy, err := migrate.New(db.DB, userMigrations[:2], migrate.Options{Dialect: db.Dialect})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := legacy.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending, err := m.Pending(ctx); err != nil || pending != len(userMigrations)-2 {
+		t.Fatalf("pending = %d, err = %v; want only the migrations added since", pending, err)
+	}
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Eski kayıtlar devralındı: ALTER TABLE içeren 2. göç tekrar çalışmadı
+	if len(applied) != len(userMigrations)-2 || applied[0].Version != 3 {
+		t.Fatalf("applied %v, want versions from 3", applied)
+	}
+	assertAllApplied(t, "user-service", m)
+}
+
+func TestMigratorIgnoresSharedLegacyTable(t *testing.T) {
+	db, err := sqldb.Open(sqldb.SQLite, filepath.Join(t.TempDir(), "eros.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	// Başka bir servis ortak tabloya kendi 1. ve 2. göçünü yazmış
+	legacy, err := migrate.New(db.DB, []migrate.Migration{
+		{Version: 1, Name: "baseline", Up: migrate.SQL(`CREATE TABLE matches (id INTEGER PRIMARY KEY)`)},
+		{Version: 2, Name: "matches_status", Up: migrate.SQL(`ALTER TABLE matches ADD COLUMN status TEXT`)},
+	}, migrate.Options{Dialect: db.Dialect})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := legacy.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(userMigrations) {
+		t.Fatalf("applied %d migrations, want all %d", len(applied), len(userMigrations))
+	}
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&n); err != nil {
+		t.Fatalf("users table missing: %v", err)
+	}
+}
+
+// userTables - Göç tabloları dışındaki tablolar
+func userTables(t *testing.T, db *sqldb.DB) []string {
+	t.Helper()
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE '%schema_migrations%' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		tables = append(tables, name)
+	}
+	return tables
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	db, err := sqldb.Open(sqldb.SQLite, filepath.Join(t.TempDir(), "users.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Her özellik göçü tek tek geri alınıp tekrar uygulanabilir
+	for i := len(userMigrations) - 1; i > 0; i-- {
+		version := userMigrations[i].Version
+		if _, err := m.Down(ctx, 1); err != nil {
+			t.Fatalf("down %d: %v", version, err)
+		}
+		if _, err := m.UpTo(ctx, version); err != nil {
+			t.Fatalf("up to %d: %v", version, err)
+		}
+		if _, err := m.Down(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := m.Down(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if tables := userTables(t, db); len(tables) != 0 {
+		t.Fatalf("tables left after rolling back everything: %v", tables)
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("up after full rollback: %v", err)
+	}
+	assertAllApplied(t, "user-service", m)
+}
+
+func TestFeatureMigrationsOnOldBaseline(t *testing.T) {
+	db, err := sqldb.Open(sqldb.SQLite, filepath.Join(t.TempDir(), "users.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	// Önceki sürümlerin baseline'ı özellik tablolarını da oluşturuyordu; o veritabanlarında
+	// yalnızca 1-4 kayıtlıdır ve sonraki göçler mevcut şemada hatasız çalışmalıdır.
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`DELETE FROM user_schema_migrations WHERE version > 4`); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(userMigrations)-4 {
+		t.Fatalf("applied %d migrations, want %d", len(applied), len(userMigrations)-4)
+	}
+	assertAllApplied(t, "user-service", m)
+}
@@ -0,0 +1,138 @@
+// phash_index.go - Algısal özetlerin bant indeksi (kopya taramasında aday süzme)
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// phashBands - 64 bitlik özet dört adet 16 bitlik banda bölünür
+// İki özetin mesafesi d ise bantlardan en az birinin mesafesi d/4'ü geçmez (güvercin yuvası);
+// aday sorgusu her bantta bu yarıçap içindeki değerleri indeksten arar. Kesin mesafeyi servis hesaplar.
+const phashBands = 4
+
+// maxBandRadius - Bant indeksinin kullanıldığı en büyük yarıçap
+// Yarıçap 2'de bant başına 137 değer aranır; daha büyük eşiklerde (> 11 bit) tüm özetler taranır.
+const maxBandRadius = 2
+
+// bandColumns - photos ve blocked_photo_hashes tablolarındaki bant kolonları
+var bandColumns = [phashBands]string{"phash_band0", "phash_band1", "phash_band2", "phash_band3"}
+
+// hashBands - Hex özeti bantlarına ayır; özet boş veya bozuksa bantlar NULL kalır
+func hashBands(phash string) [phashBands]sql.NullInt64 {
+	var bands [phashBands]sql.NullInt64
+	hash, err := strconv.ParseUint(phash, 16, 64)
+	if err != nil {
+		return bands
+	}
+	for i := range bands {
+		bands[i] = sql.NullInt64{Int64: int64(hash >> (16 * i) & 0xFFFF), Valid: true}
+	}
+	return bands
+}
+
+// bandFilter - Özete maxDistance içinde olabilecek satırları seçen WHERE parçası
+// ok=false ise eşik bant indeksi için fazla büyüktür veya özet bozuktur; çağıran tümünü tarar.
+func bandFilter(phash string, maxDistance int) (clause string, args []interface{}, ok bool) {
+	radius := maxDistance / phashBands
+	bands := hashBands(phash)
+	if maxDistance < 0 || radius > maxBandRadius || !bands[0].Valid {
+		return "", nil, false
+	}
+
+	clauses := make([]string, 0, phashBands)
+	for i, band := range bands {
+		values := bandNeighbors(uint16(band.Int64), radius)
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(values)), ",")
+		clauses = append(clauses, bandColumns[i]+" IN ("+placeholders+")")
+		for _, v := range values {
+			args = append(args, int64(v))
+		}
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args, true
+}
+
+// bandNeighbors - 16 bitlik değere en fazla radius (≤ 2) bit uzaklıktaki değerler
+func bandNeighbors(value uint16, radius int) []uint16 {
+	values := []uint16{value}
+	for i := 0; i < 16 && radius >= 1; i++ {
+		values = append(values, value^1<<i)
+		for j := i + 1; j < 16 && radius >= 2; j++ {
+			values = append(values, value^1<<i^1<<j)
+		}
+	}
+	return values
+}
+
+// hashBandIndexes - Bant kolonlarının indeksleri (her iki lehçede aynı)
+func hashBandIndexes() []string {
+	var statements []string
+	for _, table := range []string{"photos", "blocked_photo_hashes"} {
+		for _, column := range bandColumns {
+			statements = append(statements, fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_%s ON %s (%s)`, table, column, table, column))
+		}
+	}
+	return statements
+}
+
+// dropHashBands - Bant indekslerini ve kolonlarını kaldıran ifadeler
+// ifExists PostgreSQL'in DROP COLUMN IF EXISTS söz dizimi içindir.
+func dropHashBands(ifExists bool) []string {
+	var statements []string
+	for _, table := range []string{"photos", "blocked_photo_hashes"} {
+		for _, column := range bandColumns {
+			statements = append(statements, fmt.Sprintf(`DROP INDEX IF EXISTS idx_%s_%s`, table, column))
+		}
+	}
+	for _, table := range []string{"photos", "blocked_photo_hashes"} {
+		for i := len(bandColumns) - 1; i >= 0; i-- {
+			if ifExists {
+				statements = append(statements, fmt.Sprintf(`ALTER TABLE %s DROP COLUMN IF EXISTS %s`, table, bandColumns[i]))
+			} else {
+				statements = append(statements, fmt.Sprintf(`ALTER TABLE %s DROP COLUMN %s`, table, bandColumns[i]))
+			}
+		}
+	}
+	return statements
+}
+
+// backfillHashBands - Özeti olan mevcut satırların bantlarını doldur
+// Değerler yalnızca tamsayı olduğu için ifade her iki lehçede de yer tutucusuz yazılır.
+func backfillHashBands(tx *sql.Tx, table string) error {
+	rows, err := tx.Query(`SELECT id, phash FROM ` + table + ` WHERE phash IS NOT NULL AND phash != ''`)
+	if err != nil {
+		return err
+	}
+	type hashedRow struct {
+		id    int64
+		phash string
+	}
+	var hashed []hashedRow
+	for rows.Next() {
+		var r hashedRow
+		if err := rows.Scan(&r.id, &r.phash); err != nil {
+			rows.Close()
+			return err
+		}
+		hashed = append(hashed, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, r := range hashed {
+		bands := hashBands(r.phash)
+		if !bands[0].Valid {
+			continue
+		}
+		_, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET phash_band0 = %d, phash_band1 = %d, phash_band2 = %d, phash_band3 = %d WHERE id = %d`,
+			table, bands[0].Int64, bands[1].Int64, bands[2].Int64, bands[3].Int64, r.id))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
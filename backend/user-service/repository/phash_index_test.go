@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"fmt"
+	"math/bits"
+	"testing"
+	"time"
+)
+
+func TestBandNeighbors(t *testing.T) {
+	for radius, want := range []int{1, 17, 137} {
+		values := bandNeighbors(0xBEEF, radius)
+		if len(values) != want {
+			t.Fatalf("radius %d: %d values, want %d", radius, len(values), want)
+		}
+		seen := make(map[uint16]bool)
+		for _, v := range values {
+			if seen[v] {
+				t.Fatalf("radius %d: %04x listed twice", radius, v)
+			}
+			seen[v] = true
+			if d := bits.OnesCount16(v ^ 0xBEEF); d > radius {
+				t.Fatalf("radius %d: %04x is %d bits away", radius, v, d)
+			}
+		}
+	}
+}
+
+func TestBandFilter(t *testing.T) {
+	if _, _, ok := bandFilter("not-a-hash", 8); ok {
+		t.Error("invalid hash produced a filter")
+	}
+	if _, _, ok := bandFilter("00000000000000ff", 12); ok {
+		t.Error("threshold above the band radius must fall back to a full scan")
+	}
+	if _, args, ok := bandFilter("00000000000000ff", 8); !ok || len(args) != 4*137 {
+		t.Errorf("threshold 8: ok = %v, %d args; want 4 bands of 137 values", ok, len(args))
+	}
+}
+
+func TestGetHashCandidates(t *testing.T) {
+	db := newFileDB(t)
+	photos := NewPhotoRepository(db)
+	reviews := NewPhotoReviewRepository(db)
+	uploader := createTestUser(t, db, "uploader@example.com")
+	owner := createTestUser(t, db, "owner@example.com")
+
+	const query = uint64(0x0123456789ABCDEF)
+	add := func(userID int, hash uint64) int {
+		t.Helper()
+		p := &Photo{UserID: userID, URL: "photos/x.jpg", Status: "ready", PHash: fmt.Sprintf("%016x", hash), CreatedAt: time.Now()}
+		if err := photos.AddPhoto(p, 6); err != nil {
+			t.Fatal(err)
+		}
+		return p.ID
+	}
+	// 8 bit fark her banda ikişer bit: hiçbir bant yarıçap 2 dışına çıkmaz
+	near := add(owner, query^0x0003000300030003)
+	add(owner, ^query)   // Her bit farklı
+	add(uploader, query) // Kendi fotoğrafı
+	processed := add(owner, 0)
+	if err := photos.SaveProcessed(processed, "photos/full.jpg", "photos/thumb.jpg", "photos/card.jpg", fmt.Sprintf("%016x", query^1<<63)); err != nil {
+		t.Fatal(err)
+	}
+
+	candidates, err := photos.GetHashCandidates(fmt.Sprintf("%016x", query), 8, uploader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	for _, p := range candidates {
+		ids = append(ids, p.ID)
+	}
+	if len(ids) != 2 || ids[0] != near || ids[1] != processed {
+		t.Fatalf("candidates = %v, want [%d %d]", ids, near, processed)
+	}
+
+	// Eşik indeks için çok büyükse diğer kullanıcıların tüm özetleri döner
+	all, err := photos.GetHashCandidates(fmt.Sprintf("%016x", query), 16, uploader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("full scan returned %d photos, want 3", len(all))
+	}
+
+	for _, hash := range []uint64{query ^ 0xF, ^query} {
+		if err := reviews.BlockHash(BlockedHash{PHash: fmt.Sprintf("%016x", hash), UserID: owner, PhotoID: near}, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	blocked, err := reviews.GetBlockedHashCandidates(fmt.Sprintf("%016x", query), 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocked) != 1 || blocked[0].PHash != fmt.Sprintf("%016x", query^0xF) {
+		t.Fatalf("blocked candidates = %+v", blocked)
+	}
+}
+
+func TestHashBandsBackfill(t *testing.T) {
+	db := newFileDB(t)
+	userID := createTestUser(t, db, "ayse@example.com")
+
+	// Bant kolonlarından önce yazılmış özetler
+	if _, err := db.Exec(`INSERT INTO photos (user_id, url, order_index, phash) VALUES (?, 'photos/a.jpg', 1, '0123456789abcdef'), (?, 'photos/b.jpg', 2, 'bozuk')`, userID, userID); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	if err := backfillHashBands(tx.Tx, "photos"); err != nil {
+		t.Fatal(err)
+	}
+
+	var band0, band3 int64
+	if err := tx.QueryRow(`SELECT phash_band0, phash_band3 FROM photos WHERE url = 'photos/a.jpg'`).Scan(&band0, &band3); err != nil {
+		t.Fatal(err)
+	}
+	if band0 != 0xCDEF || band3 != 0x0123 {
+		t.Fatalf("bands = %04x..%04x, want cdef..0123", band0, band3)
+	}
+	var broken int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM photos WHERE url = 'photos/b.jpg' AND phash_band0 IS NULL`).Scan(&broken); err != nil {
+		t.Fatal(err)
+	}
+	if broken != 1 {
+		t.Fatal("a malformed hash got bands")
+	}
+}
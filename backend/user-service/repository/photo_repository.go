@@ -2,9 +2,17 @@ package repository
 
 import (
 	"database/sql"
+	"eros/shared/sqldb"
+	"errors"
 	"time"
 )
 
+var (
+	ErrPhotoNotFound     = errors.New("photo not found")
+	ErrInvalidPhotoOrder = errors.New("photo order must list each of the user's photos exactly once")
+	ErrPhotoLimitReached = errors.New("photo limit reached")
+)
+
 type Photo struct {
 	ID         int
 	UserID     int
@@ -13,43 +21,328 @@ type Photo struct {
 	OrderIndex int
 	AIScore    float64
 	IsVerified bool
+	Status     string
+	ThumbURL   string
+	CardURL    string
+	PHash      string
 	CreatedAt  time.Time
 }
 
+// photoColumns - SELECT sorgularında scanPhoto ile aynı sırada kullanılan kolonlar
+const photoColumns = `id, user_id, url, is_primary, order_index, ai_score, is_verified, status, thumb_url, card_url, phash, created_at`
+
 type PhotoRepository struct {
-	db *sql.DB
+	db *sqldb.DB
 }
 
-func NewPhotoRepository(db *sql.DB) *PhotoRepository {
+func NewPhotoRepository(db *sqldb.DB) *PhotoRepository {
 	return &PhotoRepository{db: db}
 }
 
-func (r *PhotoRepository) AddPhoto(photo *Photo) error {
-	_, err := r.db.Exec(`
-		INSERT INTO photos (user_id, url, is_primary, order_index, ai_score, is_verified, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`, photo.UserID, photo.URL, photo.IsPrimary, photo.OrderIndex, photo.AIScore, photo.IsVerified, photo.CreatedAt)
-	return err
+// AddPhoto - Fotoğrafı kullanıcının sırasının sonuna ekle (ilk fotoğraf birincil olur)
+// Sayım ve ekleme tek işlemdedir. İşlem önce kullanıcının satırını günceller: PostgreSQL'de
+// satır kilitlenir, SQLite'ta yazma kilidi alınır; böylece aynı kullanıcının eşzamanlı
+// yüklemeleri sırayla çalışır ve limit aşılamaz. Limit doluysa ErrPhotoLimitReached döner.
+// İşlenemeyen (failed) fotoğraflar limite sayılmaz ve sıranın sonunda kalır.
+func (r *PhotoRepository) AddPhoto(photo *Photo, limit int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE users SET updated_at = updated_at WHERE id = ?`, photo.UserID); err != nil {
+		return err
+	}
+
+	var count, total int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM photos WHERE user_id = ? AND status != 'failed'`, photo.UserID).Scan(&count); err != nil {
+		return err
+	}
+	if count >= limit {
+		return ErrPhotoLimitReached
+	}
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM photos WHERE user_id = ?`, photo.UserID).Scan(&total); err != nil {
+		return err
+	}
+	photo.OrderIndex = total + 1
+	photo.IsPrimary = count == 0
+
+	bands := hashBands(photo.PHash)
+	id, err := tx.InsertID(`
+		INSERT INTO photos (user_id, url, is_primary, order_index, ai_score, is_verified, status, phash,
+			phash_band0, phash_band1, phash_band2, phash_band3, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, photo.UserID, photo.URL, photo.IsPrimary, photo.OrderIndex, photo.AIScore, photo.IsVerified, photo.Status, photo.PHash,
+		bands[0], bands[1], bands[2], bands[3], photo.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	// Başarısız fotoğraflar yeni fotoğrafın arkasına alınır
+	if total > count {
+		ids, err := userPhotoIDs(tx, photo.UserID)
+		if err != nil {
+			return err
+		}
+		if err := renumberPhotos(tx, ids); err != nil {
+			return err
+		}
+		photo.OrderIndex = count + 1
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	photo.ID = int(id)
+	return nil
 }
 
 func (r *PhotoRepository) GetPhotosByUser(userID int) ([]Photo, error) {
-	rows, err := r.db.Query(`
-		SELECT id, user_id, url, is_primary, order_index, ai_score, is_verified, created_at
-		FROM photos WHERE user_id = ?
+	return r.queryPhotos(`SELECT `+photoColumns+` FROM photos WHERE user_id = ? ORDER BY order_index, id`, userID)
+}
+
+// CountPhotosByUser - Kullanıcının limite sayılan (işlenemeyenler hariç) fotoğraf sayısı
+func (r *PhotoRepository) CountPhotosByUser(userID int) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM photos WHERE user_id = ? AND status != 'failed'`, userID).Scan(&count)
+	return count, err
+}
+
+// ReorderPhotos - Sıralamayı tek işlemde uygula: order_index 1..n, ilk fotoğraf birincil
+// photoIDs kullanıcının tüm fotoğraflarını tam bir kez içermelidir; kullanıcıya ait olmayan
+// bir ID varsa ErrPhotoNotFound döner.
+func (r *PhotoRepository) ReorderPhotos(userID int, photoIDs []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := userPhotoIDs(tx, userID)
+	if err != nil {
+		return err
+	}
+	owned := make(map[int]bool, len(current))
+	for _, id := range current {
+		owned[id] = true
+	}
+	// Başka kullanıcının (veya olmayan) fotoğrafı bulunamadı sayılır
+	for _, id := range photoIDs {
+		if !owned[id] {
+			return ErrPhotoNotFound
+		}
+	}
+	if len(current) != len(photoIDs) {
+		return ErrInvalidPhotoOrder
+	}
+	for _, id := range photoIDs {
+		if !owned[id] {
+			return ErrInvalidPhotoOrder
+		}
+		delete(owned, id) // Tekrar eden ID'ler de reddedilir
+	}
+
+	if err := renumberPhotos(tx, photoIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeletePhoto - Kullanıcının fotoğrafını sil, kalanları 1..n sırala ve gerekirse birincili devret
+// Son doğrulanmış fotoğraf silinirse kullanıcının doğrulama rozeti (verified_at) kaldırılır.
+// Silinen kayıt depolama temizliği için döndürülür.
+func (r *PhotoRepository) DeletePhoto(userID, photoID int) (*Photo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	photo, err := scanPhoto(tx.QueryRow(`SELECT `+photoColumns+` FROM photos WHERE id = ? AND user_id = ?`, photoID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrPhotoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM photos WHERE id = ?`, photoID); err != nil {
+		return nil, err
+	}
+
+	remaining, err := userPhotoIDs(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := renumberPhotos(tx, remaining); err != nil {
+		return nil, err
+	}
+
+	// Rozet selfie ile eşleşen fotoğraflara dayanır; hiçbiri kalmadıysa rozet de kalkar
+	if photo.IsVerified {
+		_, err := tx.Exec(`
+			UPDATE users SET verified_at = NULL
+			WHERE id = ? AND NOT EXISTS (SELECT 1 FROM photos WHERE user_id = ? AND is_verified = TRUE)
+		`, userID, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return photo, tx.Commit()
+}
+
+// userPhotoIDs - Kullanıcının fotoğraf ID'leri (mevcut sırayla, işlenemeyenler en sonda)
+func userPhotoIDs(tx *sqldb.Tx, userID int) ([]int, error) {
+	rows, err := tx.Query(`
+		SELECT id FROM photos WHERE user_id = ?
+		ORDER BY CASE WHEN status = 'failed' THEN 1 ELSE 0 END, order_index, id
 	`, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// renumberPhotos - Verilen sırayla order_index 1..n ata, ilk fotoğrafı birincil yap
+// İşlenemeyen fotoğraf birincil olamaz.
+// (user_id, order_index) tekil olduğundan numaralar önce geçici olarak negatife çekilir;
+// aksi halde yer değiştiren iki fotoğraf ara adımda aynı numaraya düşerdi.
+func renumberPhotos(tx *sqldb.Tx, photoIDs []int) error {
+	for i, id := range photoIDs {
+		if _, err := tx.Exec(`UPDATE photos SET order_index = ? WHERE id = ?`, -(i + 1), id); err != nil {
+			return err
+		}
+	}
+	for i, id := range photoIDs {
+		if _, err := tx.Exec(`UPDATE photos SET order_index = ?, is_primary = (? AND status != 'failed') WHERE id = ?`, i+1, i == 0, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPhotoByID - ID ile fotoğraf getir
+func (r *PhotoRepository) GetPhotoByID(photoID int) (*Photo, error) {
+	row := r.db.QueryRow(`SELECT `+photoColumns+` FROM photos WHERE id = ?`, photoID)
+	return scanPhoto(row)
+}
+
+// GetPhotosByStatus - Belirli işleme durumundaki fotoğraflar
+func (r *PhotoRepository) GetPhotosByStatus(status string) ([]Photo, error) {
+	return r.queryPhotos(`SELECT `+photoColumns+` FROM photos WHERE status = ? ORDER BY id`, status)
+}
+
+// GetHashCandidates - Özete maxDistance içinde olabilecek, başka kullanıcılara ait fotoğraflar
+// Adaylar bant indeksinden seçilir (bkz. bandFilter); eşik indeks için çok büyükse
+// özeti olan tüm fotoğraflar döner. Kesin mesafe çağıran tarafından hesaplanır.
+func (r *PhotoRepository) GetHashCandidates(phash string, maxDistance, excludeUserID int) ([]Photo, error) {
+	query := `SELECT ` + photoColumns + ` FROM photos WHERE phash IS NOT NULL AND phash != '' AND user_id != ?`
+	args := []interface{}{excludeUserID}
+	if clause, bandArgs, ok := bandFilter(phash, maxDistance); ok {
+		query += ` AND ` + clause
+		args = append(args, bandArgs...)
+	}
+	return r.queryPhotos(query+` ORDER BY id`, args...)
+}
+
+// SetVerified - Fotoğrafın doğrulanmış işaretini güncelle
+func (r *PhotoRepository) SetVerified(photoID int, verified bool) error {
+	_, err := r.db.Exec(`UPDATE photos SET is_verified = ? WHERE id = ?`, verified, photoID)
+	return err
+}
+
+// SetStatus - Fotoğrafın işleme durumunu güncelle
+func (r *PhotoRepository) SetStatus(photoID int, status string) error {
+	_, err := r.db.Exec(`UPDATE photos SET status = ? WHERE id = ?`, status, photoID)
+	return err
+}
+
+// FailPhoto - Fotoğrafı işlenemedi olarak işaretle ve sıranın sonuna al
+// Birincil fotoğraf başarısız olursa sıradaki fotoğraf birincil olur.
+func (r *PhotoRepository) FailPhoto(photoID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var userID int
+	if err := tx.QueryRow(`SELECT user_id FROM photos WHERE id = ?`, photoID).Scan(&userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE photos SET status = 'failed' WHERE id = ?`, photoID); err != nil {
+		return err
+	}
+
+	ids, err := userPhotoIDs(tx, userID)
+	if err != nil {
+		return err
+	}
+	if err := renumberPhotos(tx, ids); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SaveProcessed - İşlenmiş varyantların anahtarlarını ve algısal özeti kaydet
+func (r *PhotoRepository) SaveProcessed(photoID int, fullURL, thumbURL, cardURL, phash string) error {
+	bands := hashBands(phash)
+	_, err := r.db.Exec(`
+		UPDATE photos SET url = ?, thumb_url = ?, card_url = ?, phash = ?, status = 'ready',
+			phash_band0 = ?, phash_band1 = ?, phash_band2 = ?, phash_band3 = ?
+		WHERE id = ?
+	`, fullURL, thumbURL, cardURL, phash, bands[0], bands[1], bands[2], bands[3], photoID)
+	return err
+}
+
+func (r *PhotoRepository) queryPhotos(query string, args ...interface{}) ([]Photo, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
 	var photos []Photo
 	for rows.Next() {
-		var p Photo
-		err := rows.Scan(&p.ID, &p.UserID, &p.URL, &p.IsPrimary, &p.OrderIndex, &p.AIScore, &p.IsVerified, &p.CreatedAt)
+		p, err := scanPhoto(rows)
 		if err != nil {
 			return nil, err
 		}
-		photos = append(photos, p)
+		photos = append(photos, *p)
 	}
-	return photos, nil
+	return photos, rows.Err()
+}
+
+// rowScanner - *sql.Row ve *sql.Rows için ortak arayüz
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPhoto - Satırı fotoğrafa çevir
+func scanPhoto(row rowScanner) (*Photo, error) {
+	var p Photo
+	var status, thumbURL, cardURL, phash sql.NullString
+	err := row.Scan(&p.ID, &p.UserID, &p.URL, &p.IsPrimary, &p.OrderIndex, &p.AIScore, &p.IsVerified,
+		&status, &thumbURL, &cardURL, &phash, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Status = status.String
+	p.ThumbURL = thumbURL.String
+	p.CardURL = cardURL.String
+	p.PHash = phash.String
+	return &p, nil
 }
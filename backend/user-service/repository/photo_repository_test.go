@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"eros/shared/sqldb"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newFileDB - Göçleri uygulanmış dosya tabanlı SQLite
+// Eşzamanlı işlemler ayrı bağlantılar ister; bellek içi veritabanı bağlantıya özeldir.
+func newFileDB(t *testing.T) *sqldb.DB {
+	t.Helper()
+	db, err := sqldb.Open(sqldb.SQLite, filepath.Join(t.TempDir(), "users.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := InitDatabase(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// createTestUser - Fotoğraf testleri için en küçük kullanıcı kaydı
+func createTestUser(t *testing.T, db *sqldb.DB, email string) int {
+	t.Helper()
+	id, err := db.InsertID(`INSERT INTO users (name, email, password) VALUES (?, ?, ?)`, "Ayşe", email, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int(id)
+}
+
+// isVerified - Kullanıcının doğrulama rozeti (verified_at dolu mu)
+func isVerified(t *testing.T, db *sqldb.DB, userID int) bool {
+	t.Helper()
+	var verified bool
+	if err := db.QueryRow(`SELECT verified_at IS NOT NULL FROM users WHERE id = ?`, userID).Scan(&verified); err != nil {
+		t.Fatal(err)
+	}
+	return verified
+}
+
+func TestAddPhotoLimitUnderConcurrency(t *testing.T) {
+	db := newFileDB(t)
+	repo := NewPhotoRepository(db)
+	userID := createTestUser(t, db, "ayse@example.com")
+
+	const limit, uploads = 6, 20
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var added, rejected int
+	for i := 0; i < uploads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.AddPhoto(&Photo{UserID: userID, URL: "photos/x.jpg", Status: "ready", CreatedAt: time.Now()}, limit)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				added++
+			case errors.Is(err, ErrPhotoLimitReached):
+				rejected++
+			default:
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if added != limit || rejected != uploads-limit {
+		t.Fatalf("added %d, rejected %d; want %d and %d", added, rejected, limit, uploads-limit)
+	}
+
+	photos, err := repo.GetPhotosByUser(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range photos {
+		if p.OrderIndex != i+1 || p.IsPrimary != (i == 0) {
+			t.Errorf("photo %d: order_index %d, primary %v", p.ID, p.OrderIndex, p.IsPrimary)
+		}
+	}
+}
+
+func TestPhotoOrderIsUnique(t *testing.T) {
+	db := newFileDB(t)
+	repo := NewPhotoRepository(db)
+	userID := createTestUser(t, db, "ayse@example.com")
+
+	var ids []int
+	for i := 0; i < 3; i++ {
+		p := &Photo{UserID: userID, URL: "photos/x.jpg", CreatedAt: time.Now()}
+		if err := repo.AddPhoto(p, 6); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, p.ID)
+	}
+
+	_, err := db.Exec(`INSERT INTO photos (user_id, url, order_index) VALUES (?, 'photos/y.jpg', 2)`, userID)
+	if err == nil {
+		t.Fatal("duplicate order_index was accepted")
+	}
+
+	// Yer değiştirme ve silme ara adımda tekil kısıta takılmamalı
+	if err := repo.ReorderPhotos(userID, []int{ids[2], ids[1], ids[0]}); err != nil {
+		t.Fatalf("ReorderPhotos: %v", err)
+	}
+	if _, err := repo.DeletePhoto(userID, ids[2]); err != nil {
+		t.Fatalf("DeletePhoto: %v", err)
+	}
+	photos, err := repo.GetPhotosByUser(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(photos) != 2 || photos[0].ID != ids[1] || photos[1].ID != ids[0] || !photos[0].IsPrimary {
+		t.Fatalf("photos after reorder and delete = %+v", photos)
+	}
+}
+
+func TestUniqueOrderMigrationRenumbersDuplicates(t *testing.T) {
+	db, err := sqldb.Open(sqldb.SQLite, filepath.Join(t.TempDir(), "users.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := migrator.UpTo(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	// Eski eşzamanlı yüklemelerin bıraktığı tekrarlı sıra numaraları
+	userID := createTestUser(t, db, "ayse@example.com")
+	for _, order := range []int{1, 2, 2, 2} {
+		if _, err := db.Exec(`INSERT INTO photos (user_id, url, order_index) VALUES (?, 'photos/x.jpg', ?)`, userID, order); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	photos, err := NewPhotoRepository(db).GetPhotosByUser(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range photos {
+		if p.OrderIndex != i+1 {
+			t.Errorf("photo %d: order_index %d, want %d", p.ID, p.OrderIndex, i+1)
+		}
+	}
+}
+
+func TestDeletePhotoClearsStaleVerification(t *testing.T) {
+	db := newFileDB(t)
+	photos := NewPhotoRepository(db)
+	users := NewUserRepository(db)
+	userID := createTestUser(t, db, "ayse@example.com")
+
+	var ids []int
+	for _, verified := range []bool{true, true, false} {
+		p := &Photo{UserID: userID, URL: "photos/x.jpg", IsVerified: verified, CreatedAt: time.Now()}
+		if err := photos.AddPhoto(p, 6); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, p.ID)
+	}
+	if err := users.SetVerified(userID, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Her silmeden sonra rozet yalnızca doğrulanmış fotoğraf kaldıysa durmalı
+	for i, want := range []bool{true, false} {
+		if _, err := photos.DeletePhoto(userID, ids[i]); err != nil {
+			t.Fatal(err)
+		}
+		if got := isVerified(t, db, userID); got != want {
+			t.Errorf("after deleting photo %d: verified = %v, want %v", ids[i], got, want)
+		}
+	}
+}
+
+func TestClearStaleVerifiedAtMigration(t *testing.T) {
+	db, err := sqldb.Open(sqldb.SQLite, filepath.Join(t.TempDir(), "users.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	stale := createTestUser(t, db, "stale@example.com")
+	valid := createTestUser(t, db, "valid@example.com")
+	if _, err := db.Exec(`UPDATE users SET verified_at = CURRENT_TIMESTAMP`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO photos (user_id, url, order_index, is_verified) VALUES (?, 'photos/a.jpg', 1, FALSE), (?, 'photos/b.jpg', 1, TRUE)`, stale, valid); err != nil {
+		t.Fatal(err)
+	}
+	// Eski baseline rozet kolonunu da oluşturuyordu; göç 4'ten önceki bir veritabanını taklit et
+	if _, err := db.Exec(`DELETE FROM user_schema_migrations WHERE version = 4`); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[int]bool{stale: false, valid: true} {
+		if got := isVerified(t, db, id); got != want {
+			t.Errorf("user %d: verified = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestFailedPhotosDoNotCountTowardLimit(t *testing.T) {
+	db := newFileDB(t)
+	repo := NewPhotoRepository(db)
+	userID := createTestUser(t, db, "ayse@example.com")
+
+	const limit = 3
+	var ids []int
+	for i := 0; i < limit; i++ {
+		p := &Photo{UserID: userID, URL: "photos/x.jpg", Status: "pending", CreatedAt: time.Now()}
+		if err := repo.AddPhoto(p, limit); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, p.ID)
+	}
+	if err := repo.AddPhoto(&Photo{UserID: userID, URL: "photos/x.jpg", CreatedAt: time.Now()}, limit); !errors.Is(err, ErrPhotoLimitReached) {
+		t.Fatalf("err = %v, want ErrPhotoLimitReached", err)
+	}
+
+	// Ana fotoğrafın işlenmesi başarısız oldu: sona gider, ana fotoğraf bir sonrakine geçer
+	if err := repo.FailPhoto(ids[0]); err != nil {
+		t.Fatal(err)
+	}
+	if count, err := repo.CountPhotosByUser(userID); err != nil || count != limit-1 {
+		t.Fatalf("count = %d, err = %v; want %d", count, err, limit-1)
+	}
+
+	added := &Photo{UserID: userID, URL: "photos/y.jpg", Status: "pending", CreatedAt: time.Now()}
+	if err := repo.AddPhoto(added, limit); err != nil {
+		t.Fatalf("AddPhoto after a failure: %v", err)
+	}
+
+	photos, err := repo.GetPhotosByUser(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantOrder := []int{ids[1], ids[2], added.ID, ids[0]}
+	if len(photos) != len(wantOrder) {
+		t.Fatalf("got %d photos, want %d", len(photos), len(wantOrder))
+	}
+	for i, p := range photos {
+		if p.ID != wantOrder[i] || p.OrderIndex != i+1 || p.IsPrimary != (i == 0) {
+			t.Errorf("position %d: photo %d, order_index %d, primary %v; want photo %d", i, p.ID, p.OrderIndex, p.IsPrimary, wantOrder[i])
+		}
+	}
+	if photos[3].Status != "failed" {
+		t.Errorf("failed photo status = %q", photos[3].Status)
+	}
+}
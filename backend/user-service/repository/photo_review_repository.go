@@ -0,0 +1,151 @@
+// photo_review_repository.go - Kopya fotoğraf incelemeleri ve engelli özetler
+package repository
+
+import (
+	"database/sql"
+	"eros/shared/sqldb"
+	"eros/user-service/model"
+	"time"
+)
+
+// BlockedHash - Askıya alınan hesaba ait fotoğrafın özeti
+type BlockedHash struct {
+	PHash   string
+	UserID  int
+	PhotoID int
+}
+
+type PhotoReviewRepository struct {
+	db *sqldb.DB
+}
+
+func NewPhotoReviewRepository(db *sqldb.DB) *PhotoReviewRepository {
+	return &PhotoReviewRepository{db: db}
+}
+
+// CreateReview - İnceleme kaydı oluştur
+func (r *PhotoReviewRepository) CreateReview(review *model.PhotoReview) error {
+	id, err := r.db.InsertID(`
+		INSERT INTO photo_reviews (photo_id, user_id, matched_photo_id, matched_user_id, distance, reason, status, reviewed_by, reviewed_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, review.PhotoID, review.UserID, review.MatchedPhotoID, review.MatchedUserID, review.Distance,
+		review.Reason, review.Status, review.ReviewedBy, review.ReviewedAt, review.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	review.ID = int(id)
+	return nil
+}
+
+// GetReviewByID - ID ile inceleme getir
+func (r *PhotoReviewRepository) GetReviewByID(reviewID int) (*model.PhotoReview, error) {
+	row := r.db.QueryRow(`
+		SELECT id, photo_id, user_id, matched_photo_id, matched_user_id, distance, reason, status, reviewed_by, reviewed_at, created_at
+		FROM photo_reviews WHERE id = ?
+	`, reviewID)
+
+	return scanReview(row)
+}
+
+// ListReviews - İncelemeleri getir (en yeniler önce, status boşsa hepsi)
+func (r *PhotoReviewRepository) ListReviews(status string, limit int) ([]model.PhotoReview, error) {
+	query := `
+		SELECT id, photo_id, user_id, matched_photo_id, matched_user_id, distance, reason, status, reviewed_by, reviewed_at, created_at
+		FROM photo_reviews WHERE 1 = 1
+	`
+	var args []interface{}
+
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []model.PhotoReview{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, *review)
+	}
+
+	return reviews, rows.Err()
+}
+
+// ResolveReview - Bekleyen incelemeyi kapat
+func (r *PhotoReviewRepository) ResolveReview(reviewID int, status, reviewer string, reviewedAt time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE photo_reviews SET status = ?, reviewed_by = ?, reviewed_at = ?
+		WHERE id = ?
+	`, status, reviewer, reviewedAt, reviewID)
+	return err
+}
+
+// BlockHash - Özeti engelli listesine ekle (zaten varsa dokunma)
+func (r *PhotoReviewRepository) BlockHash(hash BlockedHash, createdAt time.Time) error {
+	bands := hashBands(hash.PHash)
+	_, err := r.db.Exec(`
+		INSERT INTO blocked_photo_hashes (phash, user_id, photo_id, phash_band0, phash_band1, phash_band2, phash_band3, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (phash) DO NOTHING
+	`, hash.PHash, hash.UserID, hash.PhotoID, bands[0], bands[1], bands[2], bands[3], createdAt)
+	return err
+}
+
+// GetBlockedHashCandidates - Özete maxDistance içinde olabilecek engelli özetler
+// Adaylar fotoğraflarla aynı bant indeksinden seçilir (bkz. PhotoRepository.GetHashCandidates).
+func (r *PhotoReviewRepository) GetBlockedHashCandidates(phash string, maxDistance int) ([]BlockedHash, error) {
+	query := `SELECT phash, user_id, photo_id FROM blocked_photo_hashes`
+	var args []interface{}
+	if clause, bandArgs, ok := bandFilter(phash, maxDistance); ok {
+		query += ` WHERE ` + clause
+		args = bandArgs
+	}
+	rows, err := r.db.Query(query+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashes []BlockedHash
+	for rows.Next() {
+		var h BlockedHash
+		if err := rows.Scan(&h.PHash, &h.UserID, &h.PhotoID); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, h)
+	}
+	return hashes, rows.Err()
+}
+
+// scanReview - Satırı incelemeye çevir
+func scanReview(row rowScanner) (*model.PhotoReview, error) {
+	var review model.PhotoReview
+	var reviewedBy sql.NullString
+	var reviewedAt sql.NullTime
+
+	err := row.Scan(&review.ID, &review.PhotoID, &review.UserID, &review.MatchedPhotoID, &review.MatchedUserID,
+		&review.Distance, &review.Reason, &review.Status, &reviewedBy, &reviewedAt, &review.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	review.ReviewedBy = reviewedBy.String
+	if reviewedAt.Valid {
+		review.ReviewedAt = &reviewedAt.Time
+	}
+	return &review, nil
+}
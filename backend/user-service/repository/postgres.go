@@ -0,0 +1,281 @@
+// postgres.go - PostgreSQL sürücüsü ve şema göçleri (User Service)
+package repository
+
+import (
+	"database/sql"
+	"eros/shared/migrate"
+
+	_ "github.com/lib/pq"
+)
+
+// postgresMigrations - userMigrations ile aynı versiyonlar, PostgreSQL söz dizimiyle
+// SQLite'ta JSON metin olarak saklanan listeler burada JSONB'dir; depolar her iki
+// lehçede de JSON metin okuyup yazar.
+var postgresMigrations = []migrate.Migration{
+	{
+		Version: 1,
+		Name:    "baseline",
+		Up: migrate.SQL(
+			`CREATE TABLE IF NOT EXISTS users (
+				id SERIAL PRIMARY KEY,
+				name TEXT NOT NULL,
+				email TEXT UNIQUE NOT NULL,
+				password TEXT NOT NULL,
+				bio TEXT,
+				age INTEGER,
+				age_range TEXT,
+				distance INTEGER DEFAULT 50,
+				seriousness INTEGER DEFAULT 5,
+				height INTEGER,
+				weight INTEGER,
+				smokes BOOLEAN DEFAULT FALSE,
+				drinks BOOLEAN DEFAULT FALSE,
+				job TEXT,
+				job_category TEXT,
+				education TEXT,
+				hobbies JSONB NOT NULL DEFAULT '[]',
+				hobby_categories JSONB NOT NULL DEFAULT '[]',
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+			// Hobiye göre arama (hobbies @> '["müzik"]') için
+			`CREATE INDEX IF NOT EXISTS idx_users_hobbies ON users USING GIN (hobbies)`,
+
+			`CREATE TABLE IF NOT EXISTS photos (
+				id SERIAL PRIMARY KEY,
+				user_id INTEGER NOT NULL REFERENCES users (id),
+				url TEXT NOT NULL,
+				is_primary BOOLEAN DEFAULT FALSE,
+				order_index INTEGER DEFAULT 0,
+				ai_score DOUBLE PRECISION DEFAULT 0.0,
+				is_verified BOOLEAN DEFAULT FALSE,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_photos_user ON photos (user_id, order_index)`,
+
+			`CREATE TABLE IF NOT EXISTS user_preferences (
+				id SERIAL PRIMARY KEY,
+				user_id INTEGER UNIQUE NOT NULL REFERENCES users (id),
+				min_age INTEGER DEFAULT 18,
+				max_age INTEGER DEFAULT 100,
+				min_height INTEGER DEFAULT 150,
+				max_height INTEGER DEFAULT 200,
+				accepts_smokers BOOLEAN DEFAULT TRUE,
+				accepts_drinkers BOOLEAN DEFAULT TRUE,
+				min_seriousness INTEGER DEFAULT 1,
+				max_seriousness INTEGER DEFAULT 10
+			)`,
+		),
+		Down: migrate.SQL(`DROP TABLE IF EXISTS user_preferences`, `DROP TABLE IF EXISTS photos`, `DROP TABLE IF EXISTS users`),
+	},
+	{
+		Version: 2,
+		Name:    "user_preferences_categories",
+		Up: migrate.SQL(
+			`ALTER TABLE user_preferences ADD COLUMN preferred_job_categories JSONB DEFAULT '[]'`,
+			`ALTER TABLE user_preferences ADD COLUMN preferred_hobby_categories JSONB DEFAULT '[]'`,
+		),
+		Down: migrate.SQL(
+			`ALTER TABLE user_preferences DROP COLUMN preferred_hobby_categories`,
+			`ALTER TABLE user_preferences DROP COLUMN preferred_job_categories`,
+		),
+	}, {
+		Version: 3,
+		Name:    "photos_unique_order",
+		Up:      migrate.SQL(photosUniqueOrder...),
+		Down:    migrate.SQL(`DROP INDEX IF EXISTS idx_photos_user_order`),
+	},
+	{
+		Version: 4,
+		Name:    "clear_stale_verified_at",
+		Up:      clearStaleVerifiedAtPostgres,
+		Down:    migrate.SQL(), // Yalnızca veri düzeltmesi; geri alınacak şema yok
+	},
+	{
+		Version: 5,
+		Name:    "photo_processing",
+		Up: migrate.SQL(
+			`ALTER TABLE photos ADD COLUMN IF NOT EXISTS status TEXT DEFAULT 'ready'`,
+			`ALTER TABLE photos ADD COLUMN IF NOT EXISTS thumb_url TEXT`,
+			`ALTER TABLE photos ADD COLUMN IF NOT EXISTS card_url TEXT`,
+			`ALTER TABLE photos ADD COLUMN IF NOT EXISTS phash TEXT`,
+		),
+		Down: migrate.SQL(
+			`ALTER TABLE photos DROP COLUMN IF EXISTS phash`,
+			`ALTER TABLE photos DROP COLUMN IF EXISTS card_url`,
+			`ALTER TABLE photos DROP COLUMN IF EXISTS thumb_url`,
+			`ALTER TABLE photos DROP COLUMN IF EXISTS status`,
+		),
+	},
+	{
+		Version: 6,
+		Name:    "photo_reviews",
+		Up: migrate.SQL(
+			`ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ`,
+			`CREATE TABLE IF NOT EXISTS photo_reviews (
+				id SERIAL PRIMARY KEY,
+				photo_id INTEGER NOT NULL, -- Engellenen yüklemelerde 0 (fotoğraf kaydedilmedi)
+				user_id INTEGER NOT NULL,
+				matched_photo_id INTEGER NOT NULL,
+				matched_user_id INTEGER NOT NULL,
+				distance INTEGER NOT NULL,
+				reason TEXT NOT NULL,
+				status TEXT NOT NULL,
+				reviewed_by TEXT,
+				reviewed_at TIMESTAMPTZ,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_photo_reviews_status ON photo_reviews (status, created_at)`,
+
+			`CREATE TABLE IF NOT EXISTS blocked_photo_hashes (
+				id SERIAL PRIMARY KEY,
+				phash TEXT UNIQUE NOT NULL,
+				user_id INTEGER NOT NULL,
+				photo_id INTEGER NOT NULL,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+		),
+		Down: migrate.SQL(
+			`DROP TABLE IF EXISTS blocked_photo_hashes`,
+			`DROP TABLE IF EXISTS photo_reviews`,
+			`ALTER TABLE users DROP COLUMN IF EXISTS suspended_at`,
+		),
+	},
+	{
+		Version: 7,
+		Name:    "selfie_verification",
+		Up: migrate.SQL(
+			`ALTER TABLE users ADD COLUMN IF NOT EXISTS verified_at TIMESTAMPTZ`,
+			`CREATE TABLE IF NOT EXISTS verification_challenges (
+				id SERIAL PRIMARY KEY,
+				user_id INTEGER NOT NULL REFERENCES users (id),
+				pose TEXT NOT NULL,
+				status TEXT NOT NULL,
+				similarity DOUBLE PRECISION DEFAULT 0.0,
+				expires_at TIMESTAMPTZ NOT NULL,
+				completed_at TIMESTAMPTZ,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_verification_challenges_user ON verification_challenges (user_id, created_at)`,
+		),
+		Down: migrate.SQL(
+			`DROP TABLE IF EXISTS verification_challenges`,
+			`ALTER TABLE users DROP COLUMN IF EXISTS verified_at`,
+		),
+	},
+	{
+		Version: 8,
+		Name:    "email_verification",
+		Up:      emailVerificationPostgres,
+		Down: migrate.SQL(
+			`DROP TABLE IF EXISTS auth_tokens`,
+			`ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at`,
+		),
+	},
+	{
+		Version: 9,
+		Name:    "login_audit",
+		Up: migrate.SQL(
+			`CREATE TABLE IF NOT EXISTS login_events (
+				id BIGSERIAL PRIMARY KEY,
+				user_id INTEGER NOT NULL DEFAULT 0, -- Bilinmeyen e-postalarda 0
+				email TEXT NOT NULL,
+				ip TEXT NOT NULL,
+				user_agent TEXT NOT NULL,
+				outcome TEXT NOT NULL,
+				new_device BOOLEAN DEFAULT FALSE,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_login_events_email ON login_events (email, created_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_login_events_ip ON login_events (ip, created_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_login_events_user ON login_events (user_id, created_at)`,
+
+			`CREATE TABLE IF NOT EXISTS known_devices (
+				id SERIAL PRIMARY KEY,
+				user_id INTEGER NOT NULL REFERENCES users (id),
+				device_hash TEXT NOT NULL,
+				user_agent TEXT NOT NULL,
+				last_ip TEXT NOT NULL,
+				first_seen_at TIMESTAMPTZ NOT NULL,
+				last_seen_at TIMESTAMPTZ NOT NULL,
+				UNIQUE (user_id, device_hash)
+			)`,
+		),
+		Down: migrate.SQL(`DROP TABLE IF EXISTS known_devices`, `DROP TABLE IF EXISTS login_events`),
+	},
+	{
+		Version: 10,
+		Name:    "photo_hash_bands",
+		Up:      photoHashBandsPostgres,
+		Down:    migrate.SQL(dropHashBands(true)...),
+	},
+}
+
+// pgHasColumn - Tabloda kolon var mı (information_schema)
+func pgHasColumn(tx *sql.Tx, table, column string) (bool, error) {
+	var exists bool
+	err := tx.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = $1 AND column_name = $2
+		)`, table, column).Scan(&exists)
+	return exists, err
+}
+
+// clearStaleVerifiedAtPostgres - Rozet kolonu varsa eski rozetleri temizle
+func clearStaleVerifiedAtPostgres(tx *sql.Tx) error {
+	exists, err := pgHasColumn(tx, "users", "verified_at")
+	if err != nil || !exists {
+		return err
+	}
+	_, err = tx.Exec(clearStaleVerifiedAt)
+	return err
+}
+
+// emailVerificationPostgres - E-posta doğrulaması ve tek kullanımlık jetonlar
+// Bu kolondan önce açılmış hesaplar doğrulanmış sayılır.
+func emailVerificationPostgres(tx *sql.Tx) error {
+	exists, err := pgHasColumn(tx, "users", "email_verified_at")
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := tx.Exec(`ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMPTZ`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE users SET email_verified_at = created_at`); err != nil {
+			return err
+		}
+	}
+
+	return migrate.SQL(
+		`CREATE TABLE IF NOT EXISTS auth_tokens (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users (id),
+			purpose TEXT NOT NULL,
+			token_hash TEXT UNIQUE NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL,
+			used_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_auth_tokens_user ON auth_tokens (user_id, purpose, created_at)`,
+	)(tx)
+}
+
+// photoHashBandsPostgres - Kopya taraması için algısal özet bantları (bkz. phash_index.go)
+func photoHashBandsPostgres(tx *sql.Tx) error {
+	var statements []string
+	for _, table := range []string{"photos", "blocked_photo_hashes"} {
+		for _, column := range bandColumns {
+			statements = append(statements, `ALTER TABLE `+table+` ADD COLUMN IF NOT EXISTS `+column+` INTEGER`)
+		}
+	}
+	if err := migrate.SQL(append(statements, hashBandIndexes()...)...)(tx); err != nil {
+		return err
+	}
+
+	if err := backfillHashBands(tx, "photos"); err != nil {
+		return err
+	}
+	return backfillHashBands(tx, "blocked_photo_hashes")
+}
@@ -2,83 +2,64 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"eros/shared/sqldb"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
-func NewSQLiteDB(dbPath string) (*sql.DB, error) {
-	return sql.Open("sqlite3", dbPath)
+func NewSQLiteDB(dbPath string) (*sqldb.DB, error) {
+	return sqldb.Open(sqldb.SQLite, dbPath)
 }
 
-// InitDatabase - Veritabanı tablolarını oluştur
-func InitDatabase(db *sql.DB) error {
-	// Users tablosu
-	_, err := db.Exec(`
-        CREATE TABLE IF NOT EXISTS users (
-            id INTEGER PRIMARY KEY AUTOINCREMENT,
-            name TEXT NOT NULL,
-            email TEXT UNIQUE NOT NULL,
-            password TEXT NOT NULL,
-            bio TEXT,
-            age INTEGER,
-            age_range TEXT,
-            distance INTEGER DEFAULT 50,
-            seriousness INTEGER DEFAULT 5,
-            height INTEGER,
-            weight INTEGER,
-            smokes BOOLEAN DEFAULT FALSE,
-            drinks BOOLEAN DEFAULT FALSE,
-            job TEXT,
-            job_category TEXT,
-            education TEXT,
-            hobbies TEXT, -- JSON string olarak saklanacak
-            hobby_categories TEXT, -- JSON string olarak saklanacak
-            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-        )
-    `)
+// InitDatabase - Bekleyen şema göçlerini uygula
+func InitDatabase(db *sqldb.DB) error {
+	migrator, err := NewMigrator(db)
 	if err != nil {
 		return err
 	}
 
-	// Photos tablosu
-	_, err = db.Exec(`
-        CREATE TABLE IF NOT EXISTS photos (
-            id INTEGER PRIMARY KEY AUTOINCREMENT,
-            user_id INTEGER NOT NULL,
-            url TEXT NOT NULL,
-            is_primary BOOLEAN DEFAULT FALSE,
-            order_index INTEGER DEFAULT 0,
-            ai_score REAL DEFAULT 0.0,
-            is_verified BOOLEAN DEFAULT FALSE,
-            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-            FOREIGN KEY (user_id) REFERENCES users (id)
-        )
-    `)
-	if err != nil {
+	_, err = migrator.Up(context.Background())
+	return err
+}
+
+// schemaExecer - Göç adımlarının çalıştığı bağlantı veya işlem
+type schemaExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// ensureColumn - Kolon yoksa tabloya ekle
+func ensureColumn(db schemaExecer, table, column, definition string) error {
+	exists, err := hasColumn(db, table, column)
+	if err != nil || exists {
 		return err
 	}
 
-	// User preferences tablosu
-	_, err = db.Exec(`
-        CREATE TABLE IF NOT EXISTS user_preferences (
-            id INTEGER PRIMARY KEY AUTOINCREMENT,
-            user_id INTEGER UNIQUE NOT NULL,
-            min_age INTEGER DEFAULT 18,
-            max_age INTEGER DEFAULT 100,
-            min_height INTEGER DEFAULT 150,
-            max_height INTEGER DEFAULT 200,
-            accepts_smokers BOOLEAN DEFAULT TRUE,
-            accepts_drinkers BOOLEAN DEFAULT TRUE,
-            min_seriousness INTEGER DEFAULT 1,
-            max_seriousness INTEGER DEFAULT 10,
-            FOREIGN KEY (user_id) REFERENCES users (id)
-        )
-    `)
+	_, err = db.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition)
+	return err
+}
+
+// hasColumn - Tabloda kolon var mı
+func hasColumn(db schemaExecer, table, column string) (bool, error) {
+	rows, err := db.Query("PRAGMA table_info(" + table + ")")
 	if err != nil {
-		return err
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
 	}
 
-	return nil
+	return false, rows.Err()
 }
@@ -0,0 +1,86 @@
+// backends.go - Uyumluluk testlerinin çalıştırılacağı SQL veritabanları
+package storetest
+
+import (
+	"eros/shared/sqldb"
+	"eros/shared/sqldb/sqldbtest"
+	"eros/user-service/repository"
+)
+
+// Backend - Adı ve boş depo üreticisiyle bir veritabanı
+type Backend struct {
+	Name     string
+	NewStore NewStoreFunc
+}
+
+// Backends - Her zaman SQLite (bellek içi) ve PostgreSQL parametreli SQLite; TEST_DATABASE_URL tanımlıysa PostgreSQL de
+//
+//	for _, b := range storetest.Backends() {
+//		if err := storetest.TestUserStore(b.NewStore); err != nil {
+//			t.Errorf("%s: %v", b.Name, err)
+//		}
+//	}
+func Backends() []Backend {
+	backends := []Backend{
+		{Name: sqldb.SQLite.Name, NewStore: NewSQLiteStore()},
+		{Name: "sqlite-rebind", NewStore: NewRebindStore()},
+	}
+	if sqldbtest.URL() != "" {
+		backends = append(backends, Backend{Name: sqldb.Postgres.Name, NewStore: NewPostgresStore()})
+	}
+	return backends
+}
+
+// NewSQLiteStore - Her çağrıda göçleri uygulanmış boş, bellek içi bir UserRepository döner
+func NewSQLiteStore() NewStoreFunc {
+	return func() (repository.UserStore, error) {
+		db, err := sqldb.Open(sqldb.SQLite, ":memory:")
+		if err != nil {
+			return nil, err
+		}
+		// Bellek içi SQLite her bağlantıda ayrı bir veritabanıdır
+		db.SetMaxOpenConns(1)
+		return initStore(db)
+	}
+}
+
+// NewPostgresStore - TEST_DATABASE_URL'de her çağrıda boşaltılan "user_store_test" şemasıyla depo
+// Veritabanı test veritabanı olarak işaretlenmemişse şema silinmez (bkz. sqldbtest.OpenPostgres).
+func NewPostgresStore() NewStoreFunc {
+	return func() (repository.UserStore, error) {
+		db, err := sqldbtest.OpenPostgres("user_store_test")
+		if err != nil {
+			return nil, err
+		}
+		db.SetMaxOpenConns(1)
+		return initStore(db)
+	}
+}
+
+// initStore - Göçleri uygula ve depoyu oluştur
+func initStore(db *sqldb.DB) (repository.UserStore, error) {
+	if err := repository.InitDatabase(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return repository.NewUserRepository(db), nil
+}
+
+// NewRebindStore - Sorguları PostgreSQL lehçesiyle ($1, $2, ...) çalıştıran SQLite deposu
+// SQLite "$N" parametrelerini de sırayla bağlar; böylece PostgreSQL sunucusu olmadan
+// Rebind ve RETURNING yolu denenir. Şema SQLite göçleriyle kurulur.
+func NewRebindStore() NewStoreFunc {
+	return func() (repository.UserStore, error) {
+		db, err := sqldb.Open(sqldb.SQLite, ":memory:")
+		if err != nil {
+			return nil, err
+		}
+		db.SetMaxOpenConns(1)
+
+		if err := repository.InitDatabase(db); err != nil {
+			db.Close()
+			return nil, err
+		}
+		return repository.NewUserRepository(sqldb.Wrap(db.DB, sqldb.Postgres)), nil
+	}
+}
@@ -0,0 +1,366 @@
+// user_store.go - UserStore uygulamaları için uyumluluk testleri
+//
+// Kullanım (uygulamanın test dosyasında):
+//
+//	err := storetest.TestUserStore(func() (repository.UserStore, error) {
+//		return newEmptyStore()
+//	})
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+package storetest
+
+import (
+	"database/sql"
+	"eros/user-service/model"
+	"eros/user-service/repository"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// NewStoreFunc - Her test için boş (kullanıcısı olmayan) bir depo döner
+type NewStoreFunc func() (repository.UserStore, error)
+
+// userStoreCases - Tüm uygulamaların aynı davranması gereken durumlar
+var userStoreCases = []struct {
+	name string
+	run  func(store repository.UserStore) error
+}{
+	{"CreateAssignsID", testCreateAssignsID},
+	{"RoundTrip", testRoundTrip},
+	{"NotFound", testNotFound},
+	{"DuplicateEmail", testDuplicateEmail},
+	{"EmailExists", testEmailExists},
+	{"UpdateUser", testUpdateUser},
+	{"UpdatePassword", testUpdatePassword},
+	{"EmailVerification", testEmailVerification},
+	{"SelfieVerification", testSelfieVerification},
+	{"SuspendUser", testSuspendUser},
+	{"PotentialMatches", testPotentialMatches},
+}
+
+// TestUserStore - Uyumluluk testlerini çalıştır; başarısız durumları tek bir hatada toplar
+func TestUserStore(newStore NewStoreFunc) error {
+	var failures []string
+	for _, c := range userStoreCases {
+		store, err := newStore()
+		if err != nil {
+			return fmt.Errorf("storetest: creating store for %s: %w", c.name, err)
+		}
+		if err := c.run(store); err != nil {
+			failures = append(failures, c.name+": "+err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return errors.New("storetest: UserStore conformance failed:\n\t" + strings.Join(failures, "\n\t"))
+	}
+	return nil
+}
+
+// newUser - Tüm alanları dolu örnek kullanıcı
+func newUser(email string, seriousness int) *model.User {
+	now := time.Now().Truncate(time.Second)
+	return &model.User{
+		Name:            "Deniz",
+		Email:           email,
+		Password:        "$2a$10$hash",
+		Bio:             "Kitap ve kahve",
+		Age:             28,
+		AgeRange:        "25-35",
+		Distance:        30,
+		Seriousness:     seriousness,
+		Height:          172,
+		Weight:          64,
+		Smokes:          false,
+		Drinks:          true,
+		Job:             "Mühendis",
+		JobCategory:     "Teknoloji",
+		Education:       "Lisans",
+		Hobbies:         []string{"Okuma", "Yüzme"},
+		HobbyCategories: []string{"Sanat", "Spor"},
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+func createUser(store repository.UserStore, email string, seriousness int) (*model.User, error) {
+	user := newUser(email, seriousness)
+	if err := store.CreateUser(user); err != nil {
+		return nil, fmt.Errorf("CreateUser(%s): %w", email, err)
+	}
+	return user, nil
+}
+
+func testCreateAssignsID(store repository.UserStore) error {
+	a, err := createUser(store, "a@example.com", 5)
+	if err != nil {
+		return err
+	}
+	b, err := createUser(store, "b@example.com", 5)
+	if err != nil {
+		return err
+	}
+
+	if a.ID <= 0 || b.ID <= 0 || a.ID == b.ID {
+		return fmt.Errorf("expected distinct positive IDs, got %d and %d", a.ID, b.ID)
+	}
+	return nil
+}
+
+func testRoundTrip(store repository.UserStore) error {
+	want, err := createUser(store, "round@example.com", 7)
+	if err != nil {
+		return err
+	}
+
+	byID, err := store.GetUserByID(want.ID)
+	if err != nil {
+		return fmt.Errorf("GetUserByID: %w", err)
+	}
+	if err := sameProfile(byID, want); err != nil {
+		return fmt.Errorf("GetUserByID: %w", err)
+	}
+	if byID.Password != want.Password {
+		return fmt.Errorf("GetUserByID: password hash not returned")
+	}
+	if !byID.CreatedAt.Equal(want.CreatedAt) {
+		return fmt.Errorf("GetUserByID: created_at %v, want %v", byID.CreatedAt, want.CreatedAt)
+	}
+	if byID.EmailVerified || byID.IsVerified || byID.VerifiedAt != nil {
+		return fmt.Errorf("new user should not be verified")
+	}
+
+	byEmail, err := store.GetUserByEmail(want.Email)
+	if err != nil {
+		return fmt.Errorf("GetUserByEmail: %w", err)
+	}
+	if byEmail.ID != want.ID {
+		return fmt.Errorf("GetUserByEmail: id %d, want %d", byEmail.ID, want.ID)
+	}
+	return sameProfile(byEmail, want)
+}
+
+func testNotFound(store repository.UserStore) error {
+	if _, err := store.GetUserByID(424242); !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("GetUserByID on missing user: got %v, want sql.ErrNoRows", err)
+	}
+	if _, err := store.GetUserByEmail("nobody@example.com"); !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("GetUserByEmail on missing user: got %v, want sql.ErrNoRows", err)
+	}
+	return nil
+}
+
+func testDuplicateEmail(store repository.UserStore) error {
+	if _, err := createUser(store, "dup@example.com", 5); err != nil {
+		return err
+	}
+	if err := store.CreateUser(newUser("dup@example.com", 5)); err == nil {
+		return fmt.Errorf("second CreateUser with the same email succeeded")
+	}
+	return nil
+}
+
+func testEmailExists(store repository.UserStore) error {
+	if _, err := createUser(store, "exists@example.com", 5); err != nil {
+		return err
+	}
+
+	exists, err := store.EmailExists("exists@example.com")
+	if err != nil || !exists {
+		return fmt.Errorf("EmailExists(existing) = %v, %v", exists, err)
+	}
+	exists, err = store.EmailExists("missing@example.com")
+	if err != nil || exists {
+		return fmt.Errorf("EmailExists(missing) = %v, %v", exists, err)
+	}
+	return nil
+}
+
+func testUpdateUser(store repository.UserStore) error {
+	user, err := createUser(store, "update@example.com", 5)
+	if err != nil {
+		return err
+	}
+
+	user.Name = "Deniz Yılmaz"
+	user.Bio = "Yeni biyografi"
+	user.Age = 29
+	user.Smokes = true
+	user.Hobbies = []string{"Koşu"}
+	user.HobbyCategories = []string{"Spor"}
+	user.Password = "must-not-change"
+	user.UpdatedAt = user.UpdatedAt.Add(time.Hour)
+	if err := store.UpdateUser(user); err != nil {
+		return fmt.Errorf("UpdateUser: %w", err)
+	}
+
+	got, err := store.GetUserByID(user.ID)
+	if err != nil {
+		return fmt.Errorf("GetUserByID: %w", err)
+	}
+	if err := sameProfile(got, user); err != nil {
+		return err
+	}
+	if got.Password == "must-not-change" {
+		return fmt.Errorf("UpdateUser changed the password hash")
+	}
+	if !got.UpdatedAt.Equal(user.UpdatedAt) {
+		return fmt.Errorf("updated_at %v, want %v", got.UpdatedAt, user.UpdatedAt)
+	}
+	return nil
+}
+
+func testUpdatePassword(store repository.UserStore) error {
+	user, err := createUser(store, "password@example.com", 5)
+	if err != nil {
+		return err
+	}
+
+	if err := store.UpdatePassword(user.ID, "$2a$10$new", time.Now()); err != nil {
+		return fmt.Errorf("UpdatePassword: %w", err)
+	}
+	got, err := store.GetUserByID(user.ID)
+	if err != nil {
+		return fmt.Errorf("GetUserByID: %w", err)
+	}
+	if got.Password != "$2a$10$new" {
+		return fmt.Errorf("password hash %q, want %q", got.Password, "$2a$10$new")
+	}
+	return nil
+}
+
+func testEmailVerification(store repository.UserStore) error {
+	user, err := createUser(store, "email@example.com", 5)
+	if err != nil {
+		return err
+	}
+
+	// İkinci çağrı hata vermemeli
+	for i := 0; i < 2; i++ {
+		if err := store.SetEmailVerified(user.ID, time.Now()); err != nil {
+			return fmt.Errorf("SetEmailVerified: %w", err)
+		}
+	}
+	got, err := store.GetUserByEmail(user.Email)
+	if err != nil {
+		return fmt.Errorf("GetUserByEmail: %w", err)
+	}
+	if !got.EmailVerified {
+		return fmt.Errorf("email not verified after SetEmailVerified")
+	}
+	return nil
+}
+
+func testSelfieVerification(store repository.UserStore) error {
+	user, err := createUser(store, "selfie@example.com", 5)
+	if err != nil {
+		return err
+	}
+
+	at := time.Now().Truncate(time.Second)
+	if err := store.SetVerified(user.ID, at); err != nil {
+		return fmt.Errorf("SetVerified: %w", err)
+	}
+	got, err := store.GetUserByID(user.ID)
+	if err != nil {
+		return fmt.Errorf("GetUserByID: %w", err)
+	}
+	if !got.IsVerified || got.VerifiedAt == nil || !got.VerifiedAt.Equal(at) {
+		return fmt.Errorf("verified = %v at %v, want true at %v", got.IsVerified, got.VerifiedAt, at)
+	}
+	return nil
+}
+
+func testSuspendUser(store repository.UserStore) error {
+	if err := store.SuspendUser(424242, time.Now()); !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("SuspendUser on missing user: got %v, want sql.ErrNoRows", err)
+	}
+
+	user, err := createUser(store, "suspend@example.com", 5)
+	if err != nil {
+		return err
+	}
+	// Zaten askıdaki hesabı tekrar askıya almak hata değildir
+	for i := 0; i < 2; i++ {
+		if err := store.SuspendUser(user.ID, time.Now()); err != nil {
+			return fmt.Errorf("SuspendUser: %w", err)
+		}
+	}
+	return nil
+}
+
+func testPotentialMatches(store repository.UserStore) error {
+	self, err := createUser(store, "self@example.com", 5)
+	if err != nil {
+		return err
+	}
+	// Ciddiyet ±2 aralığındakiler aday olur
+	inRange := map[int]bool{}
+	for i, seriousness := range []int{3, 5, 7} {
+		u, err := createUser(store, fmt.Sprintf("in%d@example.com", i), seriousness)
+		if err != nil {
+			return err
+		}
+		inRange[u.ID] = true
+	}
+	for i, seriousness := range []int{1, 9, 10} {
+		if _, err := createUser(store, fmt.Sprintf("out%d@example.com", i), seriousness); err != nil {
+			return err
+		}
+	}
+
+	matches, err := store.GetPotentialMatches(self, 10)
+	if err != nil {
+		return fmt.Errorf("GetPotentialMatches: %w", err)
+	}
+	if len(matches) != len(inRange) {
+		return fmt.Errorf("got %d matches, want %d", len(matches), len(inRange))
+	}
+	for _, m := range matches {
+		if m.ID == self.ID {
+			return fmt.Errorf("user returned as their own match")
+		}
+		if !inRange[m.ID] {
+			return fmt.Errorf("user %d with seriousness %d is outside ±2", m.ID, m.Seriousness)
+		}
+		if m.Email == "" || m.Hobbies == nil {
+			return fmt.Errorf("match %d is missing fields: %+v", m.ID, m)
+		}
+	}
+
+	limited, err := store.GetPotentialMatches(self, 2)
+	if err != nil {
+		return fmt.Errorf("GetPotentialMatches with limit: %w", err)
+	}
+	if len(limited) != 2 {
+		return fmt.Errorf("limit 2 returned %d matches", len(limited))
+	}
+	return nil
+}
+
+// sameProfile - Profil alanlarını karşılaştır (şifre ve zaman damgaları hariç)
+func sameProfile(got, want *model.User) error {
+	type profile struct {
+		Name, Email, Bio, AgeRange, Job, JobCategory, Education string
+		Age, Distance, Seriousness, Height, Weight              int
+		Smokes, Drinks                                          bool
+		Hobbies, HobbyCategories                                []string
+	}
+	toProfile := func(u *model.User) profile {
+		return profile{
+			u.Name, u.Email, u.Bio, u.AgeRange, u.Job, u.JobCategory, u.Education,
+			u.Age, u.Distance, u.Seriousness, u.Height, u.Weight,
+			u.Smokes, u.Drinks,
+			u.Hobbies, u.HobbyCategories,
+		}
+	}
+
+	if g, w := toProfile(got), toProfile(want); !reflect.DeepEqual(g, w) {
+		return fmt.Errorf("profile mismatch:\n\t got  %+v\n\t want %+v", g, w)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// token_repository.go - Tek kullanımlık e-posta doğrulama ve şifre sıfırlama jetonları
+package repository
+
+import (
+	"database/sql"
+	"eros/shared/sqldb"
+	"errors"
+	"time"
+)
+
+var (
+	ErrTokenNotFound = errors.New("token not found")
+	ErrTokenUsed     = errors.New("token was already used")
+	ErrTokenExpired  = errors.New("token has expired")
+)
+
+// AuthToken - Jeton kaydı (jetonun kendisi değil SHA-256 özeti saklanır)
+type AuthToken struct {
+	ID        int
+	UserID    int
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type TokenRepository struct {
+	db *sqldb.DB
+}
+
+func NewTokenRepository(db *sqldb.DB) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+// CreateToken - Jeton kaydı oluştur
+func (r *TokenRepository) CreateToken(token *AuthToken) error {
+	id, err := r.db.InsertID(`
+		INSERT INTO auth_tokens (user_id, purpose, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	token.ID = int(id)
+	return nil
+}
+
+// ConsumeToken - Jetonu kullanılmış olarak işaretle ve sahibini döndür
+// Aynı jeton eşzamanlı iki istekte kullanılsa bile yalnızca biri başarılı olur.
+func (r *TokenRepository) ConsumeToken(tokenHash, purpose string, now time.Time) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var id, userID int
+	var expiresAt time.Time
+	var usedAt sql.NullTime
+	err = tx.QueryRow(`
+		SELECT id, user_id, expires_at, used_at FROM auth_tokens WHERE token_hash = ? AND purpose = ?
+	`, tokenHash, purpose).Scan(&id, &userID, &expiresAt, &usedAt)
+	if err == sql.ErrNoRows {
+		return 0, ErrTokenNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if usedAt.Valid {
+		return 0, ErrTokenUsed
+	}
+	if now.After(expiresAt) {
+		return 0, ErrTokenExpired
+	}
+
+	result, err := tx.Exec(`UPDATE auth_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL`, now, id)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return 0, err
+	} else if n == 0 {
+		return 0, ErrTokenUsed
+	}
+
+	return userID, tx.Commit()
+}
+
+// InvalidateTokens - Kullanıcının bu amaçla verilmiş kullanılmamış jetonlarını geçersiz kıl
+func (r *TokenRepository) InvalidateTokens(userID int, purpose string, now time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE auth_tokens SET used_at = ? WHERE user_id = ? AND purpose = ? AND used_at IS NULL
+	`, now, userID, purpose)
+	return err
+}
+
+// CountTokensSince - Kullanıcıya belirli andan sonra bu amaçla verilen jeton sayısı
+func (r *TokenRepository) CountTokensSince(userID int, purpose string, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM auth_tokens WHERE user_id = ? AND purpose = ? AND created_at >= ?
+	`, userID, purpose, since).Scan(&count)
+	return count, err
+}
@@ -3,14 +3,16 @@ package repository
 import (
 	"database/sql"
 	"encoding/json"
+	"eros/shared/sqldb"
 	"eros/user-service/model"
+	"time"
 )
 
 type UserRepository struct {
-	db *sql.DB
+	db *sqldb.DB
 }
 
-func NewUserRepository(db *sql.DB) *UserRepository {
+func NewUserRepository(db *sqldb.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
@@ -25,7 +27,7 @@ func (r *UserRepository) CreateUser(user *model.User) error {
 		return err
 	}
 
-	result, err := r.db.Exec(`
+	id, err := r.db.InsertID(`
 		INSERT INTO users (name, email, password, bio, age, age_range, distance, seriousness, height, weight, smokes, drinks, job, job_category, education, hobbies, hobby_categories, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, user.Name, user.Email, user.Password, user.Bio, user.Age, user.AgeRange, user.Distance, user.Seriousness, user.Height, user.Weight, user.Smokes, user.Drinks, user.Job, user.JobCategory, user.Education, string(hobbiesJSON), string(hobbyCategoriesJSON), user.CreatedAt, user.UpdatedAt)
@@ -34,11 +36,6 @@ func (r *UserRepository) CreateUser(user *model.User) error {
 		return err
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return err
-	}
-
 	user.ID = int(id)
 	return nil
 }
@@ -46,11 +43,12 @@ func (r *UserRepository) CreateUser(user *model.User) error {
 func (r *UserRepository) GetUserByEmail(email string) (*model.User, error) {
 	var user model.User
 	var hobbiesJSON, hobbyCategoriesJSON string
+	var verifiedAt sql.NullTime
 
 	err := r.db.QueryRow(`
-		SELECT id, name, email, password, bio, age, age_range, distance, seriousness, height, weight, smokes, drinks, job, job_category, education, hobbies, hobby_categories, created_at, updated_at
+		SELECT id, name, email, password, bio, age, age_range, distance, seriousness, height, weight, smokes, drinks, job, job_category, education, hobbies, hobby_categories, email_verified_at IS NOT NULL, verified_at, created_at, updated_at
 		FROM users WHERE email = ?
-	`, email).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Bio, &user.Age, &user.AgeRange, &user.Distance, &user.Seriousness, &user.Height, &user.Weight, &user.Smokes, &user.Drinks, &user.Job, &user.JobCategory, &user.Education, &hobbiesJSON, &hobbyCategoriesJSON, &user.CreatedAt, &user.UpdatedAt)
+	`, email).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Bio, &user.Age, &user.AgeRange, &user.Distance, &user.Seriousness, &user.Height, &user.Weight, &user.Smokes, &user.Drinks, &user.Job, &user.JobCategory, &user.Education, &hobbiesJSON, &hobbyCategoriesJSON, &user.EmailVerified, &verifiedAt, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		return nil, err
@@ -64,17 +62,19 @@ func (r *UserRepository) GetUserByEmail(email string) (*model.User, error) {
 		return nil, err
 	}
 
+	setVerified(&user, verifiedAt)
 	return &user, nil
 }
 
 func (r *UserRepository) GetUserByID(userID int) (*model.User, error) {
 	var user model.User
 	var hobbiesJSON, hobbyCategoriesJSON string
+	var verifiedAt sql.NullTime
 
 	err := r.db.QueryRow(`
-		SELECT id, name, email, password, bio, age, age_range, distance, seriousness, height, weight, smokes, drinks, job, job_category, education, hobbies, hobby_categories, created_at, updated_at
+		SELECT id, name, email, password, bio, age, age_range, distance, seriousness, height, weight, smokes, drinks, job, job_category, education, hobbies, hobby_categories, email_verified_at IS NOT NULL, verified_at, created_at, updated_at
 		FROM users WHERE id = ?
-	`, userID).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Bio, &user.Age, &user.AgeRange, &user.Distance, &user.Seriousness, &user.Height, &user.Weight, &user.Smokes, &user.Drinks, &user.Job, &user.JobCategory, &user.Education, &hobbiesJSON, &hobbyCategoriesJSON, &user.CreatedAt, &user.UpdatedAt)
+	`, userID).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Bio, &user.Age, &user.AgeRange, &user.Distance, &user.Seriousness, &user.Height, &user.Weight, &user.Smokes, &user.Drinks, &user.Job, &user.JobCategory, &user.Education, &hobbiesJSON, &hobbyCategoriesJSON, &user.EmailVerified, &verifiedAt, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		return nil, err
@@ -88,6 +88,7 @@ func (r *UserRepository) GetUserByID(userID int) (*model.User, error) {
 		return nil, err
 	}
 
+	setVerified(&user, verifiedAt)
 	return &user, nil
 }
 
@@ -112,7 +113,7 @@ func (r *UserRepository) UpdateUser(user *model.User) error {
 
 func (r *UserRepository) GetPotentialMatches(user *model.User, limit int) ([]model.User, error) {
 	rows, err := r.db.Query(`
-		SELECT id, name, email, password, bio, age, age_range, distance, seriousness, height, weight, smokes, drinks, job, job_category, education, hobbies, hobby_categories, created_at, updated_at
+		SELECT id, name, email, password, bio, age, age_range, distance, seriousness, height, weight, smokes, drinks, job, job_category, education, hobbies, hobby_categories, email_verified_at IS NOT NULL, verified_at, created_at, updated_at
 		FROM users 
 		WHERE id != ? AND seriousness BETWEEN ? AND ?
 		LIMIT ?
@@ -127,7 +128,8 @@ func (r *UserRepository) GetPotentialMatches(user *model.User, limit int) ([]mod
 	for rows.Next() {
 		var u model.User
 		var hobbiesJSON, hobbyCategoriesJSON string
-		err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Bio, &u.Age, &u.AgeRange, &u.Distance, &u.Seriousness, &u.Height, &u.Weight, &u.Smokes, &u.Drinks, &u.Job, &u.JobCategory, &u.Education, &hobbiesJSON, &hobbyCategoriesJSON, &u.CreatedAt, &u.UpdatedAt)
+		var verifiedAt sql.NullTime
+		err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Bio, &u.Age, &u.AgeRange, &u.Distance, &u.Seriousness, &u.Height, &u.Weight, &u.Smokes, &u.Drinks, &u.Job, &u.JobCategory, &u.Education, &hobbiesJSON, &hobbyCategoriesJSON, &u.EmailVerified, &verifiedAt, &u.CreatedAt, &u.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -140,6 +142,7 @@ func (r *UserRepository) GetPotentialMatches(user *model.User, limit int) ([]mod
 			return nil, err
 		}
 
+		setVerified(&u, verifiedAt)
 		users = append(users, u)
 	}
 
@@ -151,3 +154,41 @@ func (r *UserRepository) EmailExists(email string) (bool, error) {
 	err := r.db.QueryRow("SELECT COUNT(*) FROM users WHERE email = ?", email).Scan(&exists)
 	return exists > 0, err
 }
+
+// SuspendUser - Hesabı askıya alındı olarak işaretle (zaten askıdaysa ilk tarih korunur)
+// Kullanıcı yoksa sql.ErrNoRows döner.
+func (r *UserRepository) SuspendUser(userID int, at time.Time) error {
+	var id int
+	if err := r.db.QueryRow(`SELECT id FROM users WHERE id = ?`, userID).Scan(&id); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(`UPDATE users SET suspended_at = ? WHERE id = ? AND suspended_at IS NULL`, at, userID)
+	return err
+}
+
+// SetVerified - Selfie doğrulama rozetini ver
+func (r *UserRepository) SetVerified(userID int, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE users SET verified_at = ? WHERE id = ?`, at, userID)
+	return err
+}
+
+// setVerified - verified_at kolonundan rozet alanlarını doldur
+func setVerified(user *model.User, verifiedAt sql.NullTime) {
+	if verifiedAt.Valid {
+		user.IsVerified = true
+		user.VerifiedAt = &verifiedAt.Time
+	}
+}
+
+// SetEmailVerified - E-posta adresini doğrulanmış olarak işaretle
+func (r *UserRepository) SetEmailVerified(userID int, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE users SET email_verified_at = ? WHERE id = ? AND email_verified_at IS NULL`, at, userID)
+	return err
+}
+
+// UpdatePassword - Şifre hash'ini değiştir
+func (r *UserRepository) UpdatePassword(userID int, passwordHash string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE users SET password = ?, updated_at = ? WHERE id = ?`, passwordHash, at, userID)
+	return err
+}
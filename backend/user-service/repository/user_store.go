@@ -0,0 +1,26 @@
+// user_store.go - Kullanıcı deposu arayüzü
+package repository
+
+import (
+	"eros/user-service/model"
+	"time"
+)
+
+// UserStore - Servislerin kullanıcı kayıtlarına eriştiği arayüz
+// Her uygulama storetest.TestUserStore uyumluluk testlerini geçmelidir. Bulunamayan kayıtlar
+// için sql.ErrNoRows döner.
+type UserStore interface {
+	CreateUser(user *model.User) error
+	GetUserByEmail(email string) (*model.User, error)
+	GetUserByID(userID int) (*model.User, error)
+	UpdateUser(user *model.User) error
+	GetPotentialMatches(user *model.User, limit int) ([]model.User, error)
+	EmailExists(email string) (bool, error)
+	SuspendUser(userID int, at time.Time) error
+	SetVerified(userID int, at time.Time) error
+	SetEmailVerified(userID int, at time.Time) error
+	UpdatePassword(userID int, passwordHash string, at time.Time) error
+}
+
+// UserRepository SQLite uygulamasıdır
+var _ UserStore = (*UserRepository)(nil)
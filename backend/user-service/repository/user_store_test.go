@@ -0,0 +1,21 @@
+package repository_test
+
+import (
+	"eros/user-service/repository/storetest"
+	"os"
+	"testing"
+)
+
+// SQLite her zaman, PostgreSQL yalnızca TEST_DATABASE_URL tanımlıysa denenir
+func TestUserRepositoryConformance(t *testing.T) {
+	if os.Getenv("TEST_DATABASE_URL") == "" {
+		t.Log("TEST_DATABASE_URL not set, PostgreSQL is skipped")
+	}
+	for _, b := range storetest.Backends() {
+		t.Run(b.Name, func(t *testing.T) {
+			if err := storetest.TestUserStore(b.NewStore); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// verification_repository.go - Selfie doğrulama meydan okumaları veritabanı işlemleri
+package repository
+
+import (
+	"database/sql"
+	"eros/shared/sqldb"
+	"eros/user-service/model"
+	"time"
+)
+
+type VerificationRepository struct {
+	db *sqldb.DB
+}
+
+func NewVerificationRepository(db *sqldb.DB) *VerificationRepository {
+	return &VerificationRepository{db: db}
+}
+
+// CreateChallenge - Meydan okuma oluştur
+func (r *VerificationRepository) CreateChallenge(challenge *model.VerificationChallenge) error {
+	id, err := r.db.InsertID(`
+		INSERT INTO verification_challenges (user_id, pose, status, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, challenge.UserID, challenge.Pose, challenge.Status, challenge.ExpiresAt, challenge.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	challenge.ID = int(id)
+	return nil
+}
+
+// GetChallenge - Kullanıcının meydan okumasını getir
+func (r *VerificationRepository) GetChallenge(userID, challengeID int) (*model.VerificationChallenge, error) {
+	var challenge model.VerificationChallenge
+	var completedAt sql.NullTime
+
+	err := r.db.QueryRow(`
+		SELECT id, user_id, pose, status, similarity, expires_at, completed_at, created_at
+		FROM verification_challenges WHERE id = ? AND user_id = ?
+	`, challengeID, userID).Scan(&challenge.ID, &challenge.UserID, &challenge.Pose, &challenge.Status,
+		&challenge.Similarity, &challenge.ExpiresAt, &completedAt, &challenge.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if completedAt.Valid {
+		challenge.CompletedAt = &completedAt.Time
+	}
+	return &challenge, nil
+}
+
+// CountChallengesSince - Kullanıcının belirli andan sonra aldığı meydan okuma sayısı
+func (r *VerificationRepository) CountChallengesSince(userID int, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM verification_challenges WHERE user_id = ? AND created_at >= ?
+	`, userID, since).Scan(&count)
+	return count, err
+}
+
+// CompleteChallenge - Bekleyen meydan okumayı sonuçlandır
+// Meydan okuma zaten kullanılmışsa false döner (aynı selfie iki kez gönderilemez).
+func (r *VerificationRepository) CompleteChallenge(challengeID int, status string, similarity float64, completedAt time.Time) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE verification_challenges SET status = ?, similarity = ?, completed_at = ?
+		WHERE id = ? AND status = ?
+	`, status, similarity, completedAt, challengeID, model.VerificationStatusPending)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
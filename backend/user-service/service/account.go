@@ -0,0 +1,261 @@
+// account.go - E-posta doğrulama ve şifre sıfırlama akışları
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"eros/user-service/mail"
+	"eros/user-service/model"
+	"eros/user-service/repository"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Jeton amaçları
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+)
+
+// tokenResendCooldown - Aynı kullanıcıya art arda e-posta gönderilmesini engeller
+const tokenResendCooldown = time.Minute
+
+var (
+	ErrInvalidToken     = errors.New("invalid or expired token")
+	ErrEmailNotVerified = errors.New("email address is not verified")
+)
+
+// AccountPolicy - Jeton süreleri ve e-postadaki bağlantıların adresi
+type AccountPolicy struct {
+	VerificationTTL time.Duration
+	ResetTTL        time.Duration
+	BaseURL         string // Uygulama adresi; bağlantılar {BaseURL}/verify-email?token=... biçimindedir
+}
+
+// DefaultAccountPolicy - Varsayılan süreler
+func DefaultAccountPolicy() AccountPolicy {
+	return AccountPolicy{
+		VerificationTTL: 24 * time.Hour,
+		ResetTTL:        time.Hour,
+		BaseURL:         "http://localhost:3000",
+	}
+}
+
+// AccountPolicyFromEnv - Süreleri ve adresi ortam değişkenlerinden oku
+func AccountPolicyFromEnv() AccountPolicy {
+	policy := DefaultAccountPolicy()
+
+	if v := os.Getenv("EMAIL_VERIFICATION_TTL_HOURS"); v != "" {
+		if hours, err := strconv.ParseFloat(v, 64); err == nil && hours > 0 {
+			policy.VerificationTTL = time.Duration(hours * float64(time.Hour))
+		}
+	}
+	if v := os.Getenv("PASSWORD_RESET_TTL_MINUTES"); v != "" {
+		if minutes, err := strconv.ParseFloat(v, 64); err == nil && minutes > 0 {
+			policy.ResetTTL = time.Duration(minutes * float64(time.Minute))
+		}
+	}
+	if v := os.Getenv("APP_BASE_URL"); v != "" {
+		policy.BaseURL = strings.TrimRight(v, "/")
+	}
+
+	return policy
+}
+
+// AccountService - Hesap sahipliğini e-posta ile kanıtlayan akışlar
+type AccountService struct {
+	userRepo  repository.UserStore
+	tokenRepo *repository.TokenRepository
+	mailer    mail.Mailer
+	policy    AccountPolicy
+	now       func() time.Time
+}
+
+func NewAccountService(userRepo repository.UserStore, tokenRepo *repository.TokenRepository, mailer mail.Mailer, policy AccountPolicy) *AccountService {
+	return &AccountService{
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		mailer:    mailer,
+		policy:    policy,
+		now:       time.Now,
+	}
+}
+
+// SendVerificationEmail - Doğrulama bağlantısı gönder (önceki bağlantılar geçersiz olur)
+func (s *AccountService) SendVerificationEmail(ctx context.Context, user *model.User) error {
+	token, err := s.issueToken(user.ID, TokenPurposeEmailVerification, s.policy.VerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "EROS hesabını doğrula",
+		Body: fmt.Sprintf("Merhaba %s,\n\n"+
+			"EROS'a hoş geldin! Hesabını etkinleştirmek için aşağıdaki bağlantıya tıkla:\n\n%s\n\n"+
+			"Bağlantı %s geçerlidir. Bu kaydı sen yapmadıysan bu e-postayı yok sayabilirsin.\n",
+			user.Name, s.link("/verify-email", token), formatTTL(s.policy.VerificationTTL)),
+	})
+}
+
+// ResendVerificationEmail - Doğrulanmamış hesaba bağlantıyı yeniden gönder
+// Hesap yoksa veya zaten doğrulanmışsa da hata dönmez (kayıtlı adresler sızdırılmaz).
+func (s *AccountService) ResendVerificationEmail(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetUserByEmail(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return nil
+	}
+
+	if cooling, err := s.coolingDown(user.ID, TokenPurposeEmailVerification); err != nil || cooling {
+		return err
+	}
+	return s.SendVerificationEmail(ctx, user)
+}
+
+// VerifyEmail - Doğrulama jetonunu kullan ve hesabı etkinleştir
+func (s *AccountService) VerifyEmail(token string) error {
+	userID, err := s.consumeToken(token, TokenPurposeEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.SetEmailVerified(userID, s.now())
+}
+
+// RequestPasswordReset - Şifre sıfırlama bağlantısı gönder
+// Hesap yoksa da hata dönmez (kayıtlı adresler sızdırılmaz).
+func (s *AccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetUserByEmail(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if cooling, err := s.coolingDown(user.ID, TokenPurposePasswordReset); err != nil || cooling {
+		return err
+	}
+
+	token, err := s.issueToken(user.ID, TokenPurposePasswordReset, s.policy.ResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "EROS şifre sıfırlama",
+		Body: fmt.Sprintf("Merhaba %s,\n\n"+
+			"Şifreni sıfırlamak için aşağıdaki bağlantıya tıkla:\n\n%s\n\n"+
+			"Bağlantı %s geçerlidir ve yalnızca bir kez kullanılabilir. "+
+			"Bu isteği sen yapmadıysan şifren değişmedi, bu e-postayı yok sayabilirsin.\n",
+			user.Name, s.link("/reset-password", token), formatTTL(s.policy.ResetTTL)),
+	})
+}
+
+// ResetPassword - Sıfırlama jetonunu kullan ve yeni şifreyi kaydet
+// Bağlantıya ulaşabilmek adres sahipliğini kanıtladığı için e-posta da doğrulanmış olur.
+func (s *AccountService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	userID, err := s.consumeToken(token, TokenPurposePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	if err := s.userRepo.UpdatePassword(userID, string(hashedPassword), now); err != nil {
+		return err
+	}
+	if err := s.userRepo.SetEmailVerified(userID, now); err != nil {
+		return err
+	}
+
+	// Aynı anda istenmiş diğer sıfırlama bağlantıları da kapanır
+	if err := s.tokenRepo.InvalidateTokens(userID, TokenPurposePasswordReset, now); err != nil {
+		slog.ErrorContext(ctx, "failed to invalidate reset tokens", "user_id", userID, "error", err)
+	}
+	return nil
+}
+
+// issueToken - Rastgele jeton üret, önceki jetonları geçersiz kıl ve özetini kaydet
+func (s *AccountService) issueToken(userID int, purpose string, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := s.now()
+	if err := s.tokenRepo.InvalidateTokens(userID, purpose, now); err != nil {
+		return "", err
+	}
+
+	err := s.tokenRepo.CreateToken(&repository.AuthToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	return token, err
+}
+
+// consumeToken - Jetonu tek kullanımlık olarak harca; tüm geçersizlik durumları ErrInvalidToken döner
+func (s *AccountService) consumeToken(token, purpose string) (int, error) {
+	if token == "" {
+		return 0, ErrInvalidToken
+	}
+
+	userID, err := s.tokenRepo.ConsumeToken(hashToken(token), purpose, s.now())
+	if errors.Is(err, repository.ErrTokenNotFound) || errors.Is(err, repository.ErrTokenUsed) || errors.Is(err, repository.ErrTokenExpired) {
+		return 0, ErrInvalidToken
+	}
+	return userID, err
+}
+
+// coolingDown - Son bir dakika içinde aynı amaçla e-posta gönderildi mi
+func (s *AccountService) coolingDown(userID int, purpose string) (bool, error) {
+	count, err := s.tokenRepo.CountTokensSince(userID, purpose, s.now().Add(-tokenResendCooldown))
+	return count > 0, err
+}
+
+// link - Uygulamadaki sayfaya jetonlu bağlantı
+func (s *AccountService) link(path, token string) string {
+	return s.policy.BaseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// hashToken - Veritabanında saklanan jeton özeti
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// formatTTL - Süreyi e-postada okunur biçimde yaz ("24 saat", "60 dakika")
+func formatTTL(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		return fmt.Sprintf("%d saat", int(ttl/time.Hour))
+	}
+	return fmt.Sprintf("%d dakika", int(ttl/time.Minute))
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"eros/shared/moderation"
+	"eros/shared/server"
+	"eros/user-service/mail"
+	"eros/user-service/model"
+	"eros/user-service/repository"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// recordingMailer - Gönderilen e-postaları saklayan mailer
+type recordingMailer struct {
+	sent []mail.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// lastToken - Son e-postadaki bağlantının jetonu
+func (m *recordingMailer) lastToken(t *testing.T) string {
+	t.Helper()
+	if len(m.sent) == 0 {
+		t.Fatal("no email was sent")
+	}
+	body := m.sent[len(m.sent)-1].Body
+	start := strings.Index(body, "?token=")
+	if start < 0 {
+		t.Fatalf("email has no link: %q", body)
+	}
+	raw := body[start+len("?token="):]
+	raw = raw[:strings.IndexAny(raw, "\n ")]
+	token, err := url.QueryUnescape(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// accountEnv - Gerçek repository'ler ve sabit saatle hesap servisi
+type accountEnv struct {
+	service *AccountService
+	users   *UserService
+	tokens  *repository.TokenRepository
+	mailer  *recordingMailer
+	user    *model.User
+	now     time.Time
+}
+
+func newAccountEnv(t *testing.T) *accountEnv {
+	t.Helper()
+	db := newTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	tokens := repository.NewTokenRepository(db)
+	workers := server.NewWorkers()
+	t.Cleanup(func() { workers.Stop(context.Background()) })
+
+	env := &accountEnv{
+		tokens: tokens,
+		mailer: &recordingMailer{},
+		users: NewUserService(userRepo, repository.NewPhotoRepository(db), repository.NewPhotoReviewRepository(db), nil,
+			nil, NewProfileValidator(moderation.Default()), DefaultDuplicatePolicy(), workers),
+		now: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	env.service = NewAccountService(userRepo, tokens, env.mailer, DefaultAccountPolicy())
+	env.service.now = func() time.Time { return env.now }
+
+	password, err := bcrypt.GenerateFromPassword([]byte("eski-şifre"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.user = &model.User{Name: "Ayşe", Email: "ayse@example.com", Password: string(password), Seriousness: 5, CreatedAt: env.now, UpdatedAt: env.now}
+	if err := userRepo.CreateUser(env.user); err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func TestEmailVerificationFlow(t *testing.T) {
+	env := newAccountEnv(t)
+	ctx := context.Background()
+
+	if err := env.service.SendVerificationEmail(ctx, env.user); err != nil {
+		t.Fatal(err)
+	}
+	if msg := env.mailer.sent[0]; msg.To != "ayse@example.com" || !strings.Contains(msg.Body, "http://localhost:3000/verify-email?token=") {
+		t.Fatalf("verification email = %+v", msg)
+	}
+	token := env.mailer.lastToken(t)
+
+	if _, err := env.users.AuthenticateUser("ayse@example.com", "eski-şifre"); !errors.Is(err, ErrEmailNotVerified) {
+		t.Fatalf("login before verification: err = %v, want ErrEmailNotVerified", err)
+	}
+	if err := env.service.VerifyEmail(token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.users.AuthenticateUser("ayse@example.com", "eski-şifre"); err != nil {
+		t.Fatalf("login after verification: %v", err)
+	}
+
+	// Jeton tek kullanımlıktır; doğrulanmış hesaba yeniden gönderim yapılmaz
+	if err := env.service.VerifyEmail(token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("reused token: err = %v, want ErrInvalidToken", err)
+	}
+	env.now = env.now.Add(time.Hour)
+	if err := env.service.ResendVerificationEmail(ctx, "ayse@example.com"); err != nil || len(env.mailer.sent) != 1 {
+		t.Fatalf("resend to a verified account: err = %v, %d emails", err, len(env.mailer.sent))
+	}
+}
+
+func TestResendVerificationEmail(t *testing.T) {
+	env := newAccountEnv(t)
+	ctx := context.Background()
+
+	if err := env.service.SendVerificationEmail(ctx, env.user); err != nil {
+		t.Fatal(err)
+	}
+	first := env.mailer.lastToken(t)
+
+	// Bir dakika dolmadan yeniden gönderilmez
+	env.now = env.now.Add(30 * time.Second)
+	if err := env.service.ResendVerificationEmail(ctx, "ayse@example.com"); err != nil || len(env.mailer.sent) != 1 {
+		t.Fatalf("resend during cooldown: err = %v, %d emails", err, len(env.mailer.sent))
+	}
+
+	env.now = env.now.Add(time.Minute)
+	if err := env.service.ResendVerificationEmail(ctx, "ayse@example.com"); err != nil || len(env.mailer.sent) != 2 {
+		t.Fatalf("resend after cooldown: err = %v, %d emails", err, len(env.mailer.sent))
+	}
+	second := env.mailer.lastToken(t)
+
+	// Yeni bağlantı eskisini geçersiz kılar
+	if err := env.service.VerifyEmail(first); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("superseded token: err = %v, want ErrInvalidToken", err)
+	}
+	if err := env.service.VerifyEmail(second); err != nil {
+		t.Fatal(err)
+	}
+
+	// Kayıtlı olmayan adres sızdırılmaz
+	if err := env.service.ResendVerificationEmail(ctx, "yok@example.com"); err != nil || len(env.mailer.sent) != 2 {
+		t.Fatalf("resend to an unknown address: err = %v, %d emails", err, len(env.mailer.sent))
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		request func(env *accountEnv) error
+		consume func(env *accountEnv, token string) error
+	}{
+		{
+			name:    "email verification",
+			ttl:     DefaultAccountPolicy().VerificationTTL,
+			request: func(env *accountEnv) error { return env.service.SendVerificationEmail(context.Background(), env.user) },
+			consume: func(env *accountEnv, token string) error { return env.service.VerifyEmail(token) },
+		},
+		{
+			name: "password reset",
+			ttl:  DefaultAccountPolicy().ResetTTL,
+			request: func(env *accountEnv) error {
+				return env.service.RequestPasswordReset(context.Background(), env.user.Email)
+			},
+			consume: func(env *accountEnv, token string) error {
+				return env.service.ResetPassword(context.Background(), token, "yeni-şifre")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, c := range []struct {
+				after   time.Duration
+				wantErr error
+			}{
+				{tt.ttl - time.Second, nil},
+				{tt.ttl + time.Second, ErrInvalidToken},
+			} {
+				env := newAccountEnv(t)
+				if err := tt.request(env); err != nil {
+					t.Fatal(err)
+				}
+				token := env.mailer.lastToken(t)
+
+				env.now = env.now.Add(c.after)
+				if err := tt.consume(env, token); !errors.Is(err, c.wantErr) {
+					t.Errorf("after %v: err = %v, want %v", c.after, err, c.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestPasswordReset(t *testing.T) {
+	env := newAccountEnv(t)
+	ctx := context.Background()
+
+	// Kayıtlı olmayan adres için e-posta gönderilmez ama hata da dönmez
+	if err := env.service.RequestPasswordReset(ctx, "yok@example.com"); err != nil || len(env.mailer.sent) != 0 {
+		t.Fatalf("reset for an unknown address: err = %v, %d emails", err, len(env.mailer.sent))
+	}
+
+	// Aynı anda açık kalmış iki sıfırlama jetonu
+	for _, token := range []string{"birinci", "ikinci"} {
+		err := env.tokens.CreateToken(&repository.AuthToken{
+			UserID: env.user.ID, Purpose: TokenPurposePasswordReset, TokenHash: hashToken(token),
+			ExpiresAt: env.now.Add(time.Hour), CreatedAt: env.now,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := env.service.ResetPassword(ctx, "birinci", "yeni-şifre"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.users.AuthenticateUser(env.user.Email, "eski-şifre"); err == nil {
+		t.Fatal("old password still works")
+	}
+	// Sıfırlama bağlantısı adres sahipliğini kanıtladığı için e-posta da doğrulanır
+	if _, err := env.users.AuthenticateUser(env.user.Email, "yeni-şifre"); err != nil {
+		t.Fatalf("login with the new password: %v", err)
+	}
+
+	for _, token := range []string{"birinci", "ikinci"} {
+		if err := env.service.ResetPassword(ctx, token, "başka-şifre"); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("token %q after reset: err = %v, want ErrInvalidToken", token, err)
+		}
+	}
+
+	// Doğrulama jetonu şifre sıfırlamada geçmez
+	if err := env.service.SendVerificationEmail(ctx, env.user); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.service.ResetPassword(ctx, env.mailer.lastToken(t), "başka-şifre"); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("verification token used for a reset: err = %v, want ErrInvalidToken", err)
+	}
+	if err := env.service.ResetPassword(ctx, "", "başka-şifre"); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("empty token: err = %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,278 @@
+// login_guard.go - Kaba kuvvet koruması, giriş denetimi ve yeni cihaz bildirimi
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"eros/shared/server"
+	"eros/user-service/mail"
+	"eros/user-service/model"
+	"eros/user-service/repository"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoginPolicy - Deneme sınırları
+// İlk FreeAttempts başarısızlık beklemesizdir; sonrasında her başarısızlık bekleme süresini
+// BaseDelay'den başlayarak ikiye katlar. LockoutAttempts başarısızlıkta hesap/IP LockoutDuration
+// boyunca kilitlenir. Window'dan eski başarısızlıklar unutulur.
+type LoginPolicy struct {
+	Window                 time.Duration
+	BaseDelay              time.Duration
+	LockoutDuration        time.Duration
+	AccountFreeAttempts    int
+	AccountLockoutAttempts int
+	IPFreeAttempts         int // Bir IP'nin arkasında birden çok kullanıcı olabilir (NAT), sınırlar daha geniştir
+	IPLockoutAttempts      int
+}
+
+// DefaultLoginPolicy - Varsayılan sınırlar
+func DefaultLoginPolicy() LoginPolicy {
+	return LoginPolicy{
+		Window:                 time.Hour,
+		BaseDelay:              time.Second,
+		LockoutDuration:        15 * time.Minute,
+		AccountFreeAttempts:    3,
+		AccountLockoutAttempts: 10,
+		IPFreeAttempts:         10,
+		IPLockoutAttempts:      50,
+	}
+}
+
+// LoginPolicyFromEnv - Sınırları ortam değişkenlerinden oku
+func LoginPolicyFromEnv() LoginPolicy {
+	policy := DefaultLoginPolicy()
+
+	if v := os.Getenv("LOGIN_LOCKOUT_MINUTES"); v != "" {
+		if minutes, err := strconv.ParseFloat(v, 64); err == nil && minutes > 0 {
+			policy.LockoutDuration = time.Duration(minutes * float64(time.Minute))
+		}
+	}
+	if v := os.Getenv("LOGIN_MAX_ACCOUNT_FAILURES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			policy.AccountLockoutAttempts = n
+		}
+	}
+	if v := os.Getenv("LOGIN_MAX_IP_FAILURES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			policy.IPLockoutAttempts = n
+		}
+	}
+
+	return policy
+}
+
+// LoginAttempt - Giriş isteğinin kimden geldiği
+type LoginAttempt struct {
+	Email     string
+	IP        string
+	UserAgent string
+}
+
+// NewDeviceNotifier - Daha önce görülmemiş bir cihazdan başarılı giriş yapıldığında çağrılır
+// Kullanıcının ilk girişi bildirilmez.
+type NewDeviceNotifier interface {
+	NotifyNewDevice(ctx context.Context, user *model.User, event *model.LoginEvent) error
+}
+
+// LoginGuard - Giriş denemelerini kaydeder ve çok fazla başarısızlıkta beklemeye zorlar
+type LoginGuard struct {
+	loginRepo *repository.LoginRepository
+	userRepo  repository.UserStore
+	notifier  NewDeviceNotifier
+	policy    LoginPolicy
+	workers   *server.Workers
+	now       func() time.Time
+}
+
+// NewLoginGuard - notifier nil ise yeni cihaz bildirimi gönderilmez
+func NewLoginGuard(loginRepo *repository.LoginRepository, userRepo repository.UserStore, notifier NewDeviceNotifier, policy LoginPolicy, workers *server.Workers) *LoginGuard {
+	return &LoginGuard{
+		loginRepo: loginRepo,
+		userRepo:  userRepo,
+		notifier:  notifier,
+		policy:    policy,
+		workers:   workers,
+		now:       time.Now,
+	}
+}
+
+// Check - Denemeye izin veriliyor mu; kilitliyse kalan bekleme süresini döner
+// Kilitliyken gelen denemeler kaydedilir ama sayaca eklenmez (kilit süresi uzamaz).
+func (g *LoginGuard) Check(ctx context.Context, attempt LoginAttempt) (time.Duration, error) {
+	now := g.now()
+	since := now.Add(-g.policy.Window)
+	email := normalizeEmail(attempt.Email)
+
+	count, latest, err := g.loginRepo.EmailFailuresSince(email, since)
+	if err != nil {
+		return 0, err
+	}
+	wait := g.backoff(count, latest, g.policy.AccountFreeAttempts, g.policy.AccountLockoutAttempts, now)
+
+	count, latest, err = g.loginRepo.IPFailuresSince(attempt.IP, since)
+	if err != nil {
+		return 0, err
+	}
+	if ipWait := g.backoff(count, latest, g.policy.IPFreeAttempts, g.policy.IPLockoutAttempts, now); ipWait > wait {
+		wait = ipWait
+	}
+
+	if wait > 0 {
+		g.record(ctx, attempt, g.lookupUserID(attempt.Email), model.LoginOutcomeLocked, false, now)
+	}
+	return wait, nil
+}
+
+// RecordResult - Şifre kontrolünün sonucunu kaydet
+// Başarılı girişte cihaz tanınan cihazlara eklenir; yeni bir cihazsa bildirim gönderilir.
+func (g *LoginGuard) RecordResult(ctx context.Context, attempt LoginAttempt, user *model.User, authErr error) {
+	now := g.now()
+
+	switch {
+	case authErr == nil:
+		newDevice := g.touchDevice(ctx, user, attempt, now)
+		event := g.record(ctx, attempt, user.ID, model.LoginOutcomeSuccess, newDevice, now)
+		if newDevice && g.notifier != nil {
+			// Kapanışta gönderimi yarıda kesme; Workers.Stop bitmesini bekler
+			notifyCtx := context.WithoutCancel(ctx)
+			g.workers.Go(func(context.Context) {
+				if err := g.notifier.NotifyNewDevice(notifyCtx, user, event); err != nil {
+					slog.ErrorContext(notifyCtx, "failed to send new device notification", "user_id", user.ID, "error", err)
+				}
+			})
+		}
+	case errors.Is(authErr, ErrEmailNotVerified):
+		g.record(ctx, attempt, g.lookupUserID(attempt.Email), model.LoginOutcomeEmailNotVerified, false, now)
+	case errors.Is(authErr, sql.ErrNoRows):
+		g.record(ctx, attempt, 0, model.LoginOutcomeUnknownEmail, false, now)
+	default:
+		g.record(ctx, attempt, g.lookupUserID(attempt.Email), model.LoginOutcomeInvalidPassword, false, now)
+	}
+}
+
+// ListUserEvents - Kullanıcının son giriş kayıtları (admin)
+func (g *LoginGuard) ListUserEvents(userID, limit int) ([]model.LoginEvent, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	user, err := g.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return g.loginRepo.ListUserEvents(userID, normalizeEmail(user.Email), limit)
+}
+
+// backoff - failures başarısızlıktan sonra son denemeden itibaren beklenmesi gereken süre
+func (g *LoginGuard) backoff(failures int, latest time.Time, free, lockout int, now time.Time) time.Duration {
+	if failures < free {
+		return 0
+	}
+
+	wait := g.policy.LockoutDuration
+	if failures < lockout {
+		// 1s, 2s, 4s, ... (kilit süresini geçmez)
+		if shift := failures - free; shift < 30 {
+			if d := g.policy.BaseDelay << uint(shift); d < wait {
+				wait = d
+			}
+		}
+	}
+
+	if remaining := latest.Add(wait).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// touchDevice - Cihazı işaretle; yeni cihazsa ve kullanıcının ilk cihazı değilse true
+func (g *LoginGuard) touchDevice(ctx context.Context, user *model.User, attempt LoginAttempt, now time.Time) bool {
+	isNew, err := g.loginRepo.TouchDevice(user.ID, deviceHash(attempt.UserAgent), attempt.UserAgent, attempt.IP, now)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record device", "user_id", user.ID, "error", err)
+		return false
+	}
+	if !isNew {
+		return false
+	}
+
+	count, err := g.loginRepo.CountDevices(user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to count devices", "user_id", user.ID, "error", err)
+		return false
+	}
+	return count > 1
+}
+
+// record - Denetim kaydı yaz (kayıt hatası girişi engellemez)
+func (g *LoginGuard) record(ctx context.Context, attempt LoginAttempt, userID int, outcome string, newDevice bool, now time.Time) *model.LoginEvent {
+	event := &model.LoginEvent{
+		UserID:    userID,
+		Email:     normalizeEmail(attempt.Email),
+		IP:        attempt.IP,
+		UserAgent: attempt.UserAgent,
+		Outcome:   outcome,
+		NewDevice: newDevice,
+		CreatedAt: now,
+	}
+	if err := g.loginRepo.RecordEvent(event); err != nil {
+		slog.ErrorContext(ctx, "failed to record login event", "email", event.Email, "error", err)
+	}
+	return event
+}
+
+// lookupUserID - Denetim kaydı için e-postanın sahibi (yoksa 0)
+func (g *LoginGuard) lookupUserID(email string) int {
+	user, err := g.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return 0
+	}
+	return user.ID
+}
+
+// normalizeEmail - Sayaçlar büyük/küçük harf farkıyla atlatılamasın
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// deviceHash - Cihaz kimliği olarak tarayıcı/uygulama imzası (User-Agent)
+func deviceHash(userAgent string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(userAgent)))
+	return hex.EncodeToString(sum[:])
+}
+
+// MailNewDeviceNotifier - Yeni cihaz girişini e-postayla bildirir
+type MailNewDeviceNotifier struct {
+	mailer  mail.Mailer
+	baseURL string
+}
+
+func NewMailNewDeviceNotifier(mailer mail.Mailer, baseURL string) *MailNewDeviceNotifier {
+	return &MailNewDeviceNotifier{mailer: mailer, baseURL: baseURL}
+}
+
+// NotifyNewDevice - Kullanıcıya cihaz, IP ve zaman bilgisiyle e-posta gönder
+func (n *MailNewDeviceNotifier) NotifyNewDevice(ctx context.Context, user *model.User, event *model.LoginEvent) error {
+	device := event.UserAgent
+	if device == "" {
+		device = "Bilinmeyen cihaz"
+	}
+
+	return n.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "EROS hesabına yeni bir cihazdan giriş yapıldı",
+		Body: fmt.Sprintf("Merhaba %s,\n\n"+
+			"Hesabına daha önce görmediğimiz bir cihazdan giriş yapıldı:\n\n"+
+			"Cihaz: %s\nIP adresi: %s\nZaman: %s\n\n"+
+			"Bu sen değilsen hemen şifreni değiştir:\n\n%s\n",
+			user.Name, device, event.IP, event.CreatedAt.Format("02.01.2006 15:04 MST"), n.baseURL+"/forgot-password"),
+	})
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eros/shared/server"
+	"eros/user-service/model"
+	"eros/user-service/repository"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errWrongPassword = errors.New("invalid credentials")
+
+// recordingNotifier - Yeni cihaz bildirimlerini saklar
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []*model.LoginEvent
+}
+
+func (n *recordingNotifier) NotifyNewDevice(ctx context.Context, user *model.User, event *model.LoginEvent) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, event)
+	return nil
+}
+
+// loginEnv - Gerçek repository'ler ve elle ilerletilen saatle giriş koruması
+type loginEnv struct {
+	guard    *LoginGuard
+	logins   *repository.LoginRepository
+	notifier *recordingNotifier
+	workers  *server.Workers
+	user     *model.User
+	now      time.Time
+}
+
+func newLoginEnv(t *testing.T) *loginEnv {
+	t.Helper()
+	db := newTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	env := &loginEnv{
+		logins:   repository.NewLoginRepository(db),
+		notifier: &recordingNotifier{},
+		workers:  server.NewWorkers(),
+		now:      time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	t.Cleanup(func() { env.workers.Stop(context.Background()) })
+	env.guard = NewLoginGuard(env.logins, userRepo, env.notifier, DefaultLoginPolicy(), env.workers)
+	env.guard.now = func() time.Time { return env.now }
+
+	env.user = &model.User{Name: "Ayşe", Email: "ayse@example.com", Password: "x", Seriousness: 5, CreatedAt: env.now, UpdatedAt: env.now}
+	if err := userRepo.CreateUser(env.user); err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func (e *loginEnv) attempt(email, ip string) LoginAttempt {
+	return LoginAttempt{Email: email, IP: ip, UserAgent: "Mozilla/5.0 (iPhone)"}
+}
+
+func (e *loginEnv) check(t *testing.T, attempt LoginAttempt) time.Duration {
+	t.Helper()
+	wait, err := e.guard.Check(context.Background(), attempt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wait
+}
+
+func TestLoginBackoffGrowth(t *testing.T) {
+	env := newLoginEnv(t)
+	attempt := env.attempt("ayse@example.com", "10.0.0.1")
+
+	// İlk üç başarısızlık beklemesiz, sonra 1s'den ikiye katlanır, onuncuda kilit
+	want := []time.Duration{0, 0, 0, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+		16 * time.Second, 32 * time.Second, 64 * time.Second, 15 * time.Minute}
+	for failures, wantWait := range want {
+		if wait := env.check(t, attempt); wait != wantWait {
+			t.Fatalf("after %d failures: wait %v, want %v", failures, wait, wantWait)
+		}
+		env.now = env.now.Add(wantWait)
+		if failures < len(want)-1 {
+			env.guard.RecordResult(context.Background(), attempt, nil, errWrongPassword)
+		}
+	}
+}
+
+func TestLoginBackoff(t *testing.T) {
+	g := &LoginGuard{policy: DefaultLoginPolicy()}
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		failures int
+		ago      time.Duration
+		want     time.Duration
+	}{
+		{"under the free attempts", 2, 0, 0},
+		{"first delay", 3, 0, time.Second},
+		{"delay partly elapsed", 5, time.Second, 3 * time.Second},
+		{"delay elapsed", 5, 10 * time.Second, 0},
+		{"lockout", 10, 0, 15 * time.Minute},
+		{"lockout partly elapsed", 12, 5 * time.Minute, 10 * time.Minute},
+		{"lockout elapsed", 12, 15 * time.Minute, 0},
+		{"huge count does not overflow", 1000, 0, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.backoff(tt.failures, now.Add(-tt.ago), 3, 10, now); got != tt.want {
+				t.Fatalf("backoff = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Kilit süresinden uzun olamayan gecikme (geniş serbest aralık, kısa kilit)
+	short := &LoginGuard{policy: LoginPolicy{BaseDelay: time.Minute, LockoutDuration: 5 * time.Minute}}
+	if got := short.backoff(9, now, 0, 100, now); got != 5*time.Minute {
+		t.Fatalf("capped delay = %v, want 5m", got)
+	}
+}
+
+func TestLoginLockoutAndUnlock(t *testing.T) {
+	env := newLoginEnv(t)
+	ctx := context.Background()
+	attempt := env.attempt("Ayse@Example.com ", "10.0.0.1")
+
+	lockout := DefaultLoginPolicy().AccountLockoutAttempts
+	for i := 0; i < lockout; i++ {
+		env.guard.RecordResult(ctx, attempt, nil, errWrongPassword)
+	}
+	if wait := env.check(t, attempt); wait != 15*time.Minute {
+		t.Fatalf("wait after %d failures = %v, want 15m", lockout, wait)
+	}
+
+	// Büyük/küçük harf ve boşlukla sayaç atlatılamaz; kilitliyken gelen denemeler kilidi uzatmaz
+	env.now = env.now.Add(5 * time.Minute)
+	if wait := env.check(t, env.attempt("ayse@example.com", "10.0.0.2")); wait != 10*time.Minute {
+		t.Fatalf("wait during lockout = %v, want 10m", wait)
+	}
+
+	// Kilit dolunca deneme açılır ama pencere içindeki başarısızlıklar sayılmaya devam eder
+	env.now = env.now.Add(10 * time.Minute)
+	if wait := env.check(t, attempt); wait != 0 {
+		t.Fatalf("wait after lockout = %v, want 0", wait)
+	}
+	env.guard.RecordResult(ctx, attempt, nil, errWrongPassword)
+	if wait := env.check(t, attempt); wait != 15*time.Minute {
+		t.Fatalf("failure right after lockout: wait %v, want 15m", wait)
+	}
+
+	// Pencereden eski başarısızlıklar unutulur
+	env.now = env.now.Add(DefaultLoginPolicy().Window)
+	if wait := env.check(t, attempt); wait != 0 {
+		t.Fatalf("wait after the window = %v, want 0", wait)
+	}
+	env.guard.RecordResult(ctx, attempt, nil, errWrongPassword)
+	if wait := env.check(t, attempt); wait != 0 {
+		t.Fatalf("first failure in a new window: wait %v, want 0", wait)
+	}
+}
+
+func TestLoginSuccessResetsAccountButNotIP(t *testing.T) {
+	env := newLoginEnv(t)
+	ctx := context.Background()
+	attempt := env.attempt("ayse@example.com", "10.0.0.1")
+
+	for i := 0; i < 5; i++ {
+		env.guard.RecordResult(ctx, attempt, nil, errWrongPassword)
+	}
+	env.now = env.now.Add(time.Minute)
+	env.guard.RecordResult(ctx, attempt, env.user, nil)
+
+	env.now = env.now.Add(time.Second)
+	if count, _, err := env.logins.EmailFailuresSince("ayse@example.com", env.now.Add(-time.Hour)); err != nil || count != 0 {
+		t.Fatalf("email failures after success = %d, err = %v; want 0", count, err)
+	}
+	if count, _, err := env.logins.IPFailuresSince("10.0.0.1", env.now.Add(-time.Hour)); err != nil || count != 5 {
+		t.Fatalf("ip failures after success = %d, err = %v; want 5", count, err)
+	}
+}
+
+func TestLoginIPLimit(t *testing.T) {
+	env := newLoginEnv(t)
+	ctx := context.Background()
+	policy := DefaultLoginPolicy()
+
+	// Her e-posta kendi sınırının altında; IP sınırı yine devreye girer
+	for i := 0; i < policy.IPLockoutAttempts; i++ {
+		env.guard.RecordResult(ctx, env.attempt(fmt.Sprintf("user%d@example.com", i), "10.0.0.9"), nil, sql.ErrNoRows)
+	}
+	if wait := env.check(t, env.attempt("fresh@example.com", "10.0.0.9")); wait != policy.LockoutDuration {
+		t.Fatalf("ip wait = %v, want %v", wait, policy.LockoutDuration)
+	}
+	if wait := env.check(t, env.attempt("fresh@example.com", "10.0.0.10")); wait != 0 {
+		t.Fatalf("other ip wait = %v, want 0", wait)
+	}
+}
+
+func TestNewDeviceNotification(t *testing.T) {
+	env := newLoginEnv(t)
+	ctx := context.Background()
+
+	login := func(userAgent string) {
+		env.now = env.now.Add(time.Minute)
+		env.guard.RecordResult(ctx, LoginAttempt{Email: env.user.Email, IP: "10.0.0.1", UserAgent: userAgent}, env.user, nil)
+	}
+	login("Mozilla/5.0 (iPhone)")  // İlk cihaz bildirilmez
+	login("Mozilla/5.0 (iPhone)")  // Bilinen cihaz
+	login("Mozilla/5.0 (Windows)") // Yeni cihaz
+	env.workers.Stop(ctx)
+
+	if len(env.notifier.events) != 1 || env.notifier.events[0].UserAgent != "Mozilla/5.0 (Windows)" || !env.notifier.events[0].NewDevice {
+		t.Fatalf("notifications = %+v, want one for the new device", env.notifier.events)
+	}
+}
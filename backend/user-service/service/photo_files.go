@@ -0,0 +1,64 @@
+// photo_files.go - Fotoğraf dosyalarının depolanması ve imzalı adresleri
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"eros/user-service/imaging"
+	"eros/user-service/storage"
+	"fmt"
+	"io"
+)
+
+// SavePhotoFile - Resmi içerik türünü koklayarak doğrula, metadata'sını at, depolamaya yaz ve anahtarını döndür
+// Yüklenen dosya EXIF (GPS dahil) taşıyabileceği için depoya hiçbir zaman olduğu gibi yazılmaz.
+func (s *UserService) SavePhotoFile(ctx context.Context, userID int, data []byte) (string, error) {
+	if _, _, err := storage.DetectImageType(data); err != nil {
+		return "", err
+	}
+	data, err := imaging.StripMetadata(data)
+	if err != nil {
+		return "", err
+	}
+	contentType, ext, err := storage.DetectImageType(data)
+	if err != nil {
+		return "", err
+	}
+
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("photos/%d/%s%s", userID, hex.EncodeToString(name), ext)
+	if err := s.photoStore.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+// OpenPhotoFile - Depolamadaki fotoğraf dosyasını aç
+func (s *UserService) OpenPhotoFile(ctx context.Context, key string) (io.ReadCloser, storage.ObjectInfo, error) {
+	return s.photoStore.Get(ctx, key)
+}
+
+// DeletePhotoFile - Kaydı oluşturulamayan dosyayı depodan sil
+func (s *UserService) DeletePhotoFile(ctx context.Context, key string) error {
+	return s.photoStore.Delete(ctx, key)
+}
+
+// VerifyPhotoURL - İndirme adresinin imzası ve süresi geçerli mi
+func (s *UserService) VerifyPhotoURL(key, expires, sig string) bool {
+	return s.urlSigner.Verify(key, expires, sig)
+}
+
+// PhotoURL - Depolama anahtarını imzalı indirme adresine çevir (eski kayıtlar olduğu gibi döner)
+func (s *UserService) PhotoURL(value string) string {
+	if !storage.IsKey(value) {
+		return value
+	}
+	return s.urlSigner.SignedURL(value)
+}
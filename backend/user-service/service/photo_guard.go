@@ -0,0 +1,212 @@
+// photo_guard.go - Kopya ve çalıntı fotoğraf tespiti (algısal özet karşılaştırması)
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eros/user-service/imaging"
+	"eros/user-service/model"
+	"eros/user-service/repository"
+	"errors"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrPhotoBlocked          = errors.New("photo belongs to a suspended account")
+	ErrReviewNotFound        = errors.New("photo review not found")
+	ErrReviewResolved        = errors.New("photo review is already resolved")
+	ErrInvalidReviewDecision = errors.New("decision must be approve or reject")
+)
+
+// DuplicatePolicy - Kopya sayılma eşiği
+type DuplicatePolicy struct {
+	MaxDistance int // Algısal özetler arası bu Hamming mesafesi ve altı aynı fotoğraf sayılır
+}
+
+// DefaultDuplicatePolicy - Varsayılan eşik
+// Aynı fotoğrafın yeniden sıkıştırılmış/boyutlandırılmış kopyaları 0-4, farklı fotoğraflar ~20+ bit uzaklıktadır.
+func DefaultDuplicatePolicy() DuplicatePolicy {
+	return DuplicatePolicy{MaxDistance: 8}
+}
+
+// DuplicatePolicyFromEnv - Eşiği ortam değişkeninden oku (PHOTO_DUPLICATE_MAX_DISTANCE)
+// 11 bitin üstündeki eşiklerde bant indeksi kullanılamaz ve her yüklemede tüm özetler taranır.
+func DuplicatePolicyFromEnv() DuplicatePolicy {
+	policy := DefaultDuplicatePolicy()
+
+	if v := os.Getenv("PHOTO_DUPLICATE_MAX_DISTANCE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 && n <= 64 {
+			policy.MaxDistance = n
+		}
+	}
+
+	return policy
+}
+
+// DuplicateMatch - Yüklemeye en çok benzeyen mevcut fotoğraf
+type DuplicateMatch struct {
+	PhotoID   int
+	UserID    int
+	Distance  int
+	Suspended bool // Askıya alınmış hesabın fotoğrafı (yükleme engellenir)
+}
+
+// PhotoGuard - Yeni yüklemeleri mevcut ve engelli fotoğraf özetleriyle karşılaştırır
+type PhotoGuard struct {
+	photoRepo  *repository.PhotoRepository
+	reviewRepo *repository.PhotoReviewRepository
+	policy     DuplicatePolicy
+}
+
+func NewPhotoGuard(photoRepo *repository.PhotoRepository, reviewRepo *repository.PhotoReviewRepository, policy DuplicatePolicy) *PhotoGuard {
+	return &PhotoGuard{
+		photoRepo:  photoRepo,
+		reviewRepo: reviewRepo,
+		policy:     policy,
+	}
+}
+
+// FindDuplicate - Özete eşik içinde en yakın fotoğrafı bul (yoksa nil)
+// Önce askıya alınan hesapların özetlerine, sonra diğer kullanıcıların fotoğraflarına bakılır.
+// Adaylar veritabanındaki bant indeksinden gelir; tüm fotoğraflar belleğe okunmaz.
+// Kullanıcının kendi fotoğrafları taranmaz; yüklemenin başka hesaptaki eşi yine bulunur.
+func (g *PhotoGuard) FindDuplicate(userID int, phash string) (*DuplicateMatch, error) {
+	hash, err := imaging.ParseHash(phash)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := g.reviewRepo.GetBlockedHashCandidates(phash, g.policy.MaxDistance)
+	if err != nil {
+		return nil, err
+	}
+	var best *DuplicateMatch
+	for _, b := range blocked {
+		if d, ok := g.distance(hash, b.PHash); ok && (best == nil || d < best.Distance) {
+			best = &DuplicateMatch{PhotoID: b.PhotoID, UserID: b.UserID, Distance: d, Suspended: true}
+		}
+	}
+	if best != nil {
+		return best, nil
+	}
+
+	// Kendi fotoğrafını yeniden yüklemek kopya değildir; ama başka hesaptaki eşini de gizlemez
+	photos, err := g.photoRepo.GetHashCandidates(phash, g.policy.MaxDistance, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range photos {
+		d, ok := g.distance(hash, p.PHash)
+		if !ok {
+			continue
+		}
+		if best == nil || d < best.Distance {
+			best = &DuplicateMatch{PhotoID: p.ID, UserID: p.UserID, Distance: d}
+		}
+	}
+
+	return best, nil
+}
+
+// distance - Saklanan özete uzaklık; eşik dışındaysa veya özet bozuksa ok=false
+func (g *PhotoGuard) distance(hash uint64, stored string) (int, bool) {
+	other, err := imaging.ParseHash(stored)
+	if err != nil {
+		return 0, false
+	}
+	d := imaging.HammingDistance(hash, other)
+	return d, d <= g.policy.MaxDistance
+}
+
+// RecordMatch - Eşleşmeyi inceleme kuyruğuna yaz
+// Askıya alınmış hesap eşleşmeleri "blocked" olarak kapatılmış kaydedilir, diğerleri admin kararı bekler.
+func (g *PhotoGuard) RecordMatch(photoID, userID int, match *DuplicateMatch) error {
+	review := &model.PhotoReview{
+		PhotoID:        photoID,
+		UserID:         userID,
+		MatchedPhotoID: match.PhotoID,
+		MatchedUserID:  match.UserID,
+		Distance:       match.Distance,
+		Reason:         model.PhotoReviewReasonDuplicate,
+		Status:         model.PhotoReviewStatusPending,
+		CreatedAt:      time.Now(),
+	}
+	if match.Suspended {
+		review.Reason = model.PhotoReviewReasonSuspendedAccount
+		review.Status = model.PhotoReviewStatusBlocked
+		review.ReviewedBy = "system"
+		review.ReviewedAt = &review.CreatedAt
+	}
+	return g.reviewRepo.CreateReview(review)
+}
+
+// ListPhotoReviews - İnceleme kuyruğu (status boşsa hepsi)
+func (s *UserService) ListPhotoReviews(status string, limit int) ([]model.PhotoReview, error) {
+	return s.reviewRepo.ListReviews(status, limit)
+}
+
+// ResolvePhotoReview - Admin kararı: "approve" fotoğrafı bırakır, "reject" fotoğrafı siler
+// Onay fotoğrafı doğrulanmış yapmaz; bu sadece selfie doğrulamasıyla olur.
+func (s *UserService) ResolvePhotoReview(ctx context.Context, reviewID int, decision, reviewer string) (*model.PhotoReview, error) {
+	review, err := s.reviewRepo.GetReviewByID(reviewID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != model.PhotoReviewStatusPending {
+		return nil, ErrReviewResolved
+	}
+
+	switch decision {
+	case "approve":
+		review.Status = model.PhotoReviewStatusApproved
+	case "reject":
+		// Kullanıcı fotoğrafı kendisi silmiş olabilir
+		if err := s.DeletePhoto(ctx, review.UserID, review.PhotoID); err != nil && !errors.Is(err, ErrPhotoNotFound) {
+			return nil, err
+		}
+		review.Status = model.PhotoReviewStatusRejected
+	default:
+		return nil, ErrInvalidReviewDecision
+	}
+
+	now := time.Now()
+	if err := s.reviewRepo.ResolveReview(review.ID, review.Status, reviewer, now); err != nil {
+		return nil, err
+	}
+	review.ReviewedBy = reviewer
+	review.ReviewedAt = &now
+	return review, nil
+}
+
+// SuspendUser - Hesabı askıya al ve fotoğraflarının başka hesaplarda kullanılmasını engelle
+// Özetler engelli listesine kopyalandığı için fotoğraflar sonradan silinse de engel sürer.
+func (s *UserService) SuspendUser(ctx context.Context, userID int) error {
+	now := time.Now()
+	if err := s.userRepo.SuspendUser(userID, now); err != nil {
+		return err
+	}
+
+	photos, err := s.photoRepo.GetPhotosByUser(userID)
+	if err != nil {
+		return err
+	}
+	blocked := 0
+	for _, p := range photos {
+		if p.PHash == "" {
+			continue
+		}
+		if err := s.reviewRepo.BlockHash(repository.BlockedHash{PHash: p.PHash, UserID: userID, PhotoID: p.ID}, now); err != nil {
+			return err
+		}
+		blocked++
+	}
+
+	slog.InfoContext(ctx, "user suspended", "user_id", userID, "blocked_hashes", blocked)
+	return nil
+}
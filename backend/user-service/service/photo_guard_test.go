@@ -0,0 +1,123 @@
+package service
+
+import (
+	"eros/shared/sqldb"
+	"eros/user-service/imaging"
+	"eros/user-service/repository"
+	"testing"
+	"time"
+)
+
+// newTestDB - Göçleri uygulanmış bellek içi SQLite
+func newTestDB(t *testing.T) *sqldb.DB {
+	t.Helper()
+	db, err := sqldb.Open(sqldb.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Bellek içi SQLite her bağlantıda ayrı bir veritabanıdır
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if err := repository.InitDatabase(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// createTestUser - Testler için en küçük kullanıcı kaydı
+func createTestUser(t *testing.T, db *sqldb.DB, email string) int {
+	t.Helper()
+	id, err := db.InsertID(`INSERT INTO users (name, email, password) VALUES (?, ?, ?)`, "Ayşe", email, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int(id)
+}
+
+// addHashedPhoto - Algısal özeti hesaplanmış fotoğraf ekle
+func addHashedPhoto(t *testing.T, photos *repository.PhotoRepository, userID int, hash uint64) int {
+	t.Helper()
+	p := &repository.Photo{UserID: userID, URL: "photos/x.jpg", Status: "ready", PHash: imaging.FormatHash(hash), CreatedAt: time.Now()}
+	if err := photos.AddPhoto(p, MaxPhotosPerUser); err != nil {
+		t.Fatal(err)
+	}
+	return p.ID
+}
+
+func TestFindDuplicate(t *testing.T) {
+	const stolen, other = uint64(0xF0F0F0F0F0F0F0F0), uint64(0x0F0F0F0F0F0F0F0F)
+
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, photos *repository.PhotoRepository, reviews *repository.PhotoReviewRepository, uploader, owner int) int
+		wantHit bool
+		blocked bool
+	}{
+		{
+			name: "another account has the photo",
+			setup: func(t *testing.T, photos *repository.PhotoRepository, _ *repository.PhotoReviewRepository, _, owner int) int {
+				return addHashedPhoto(t, photos, owner, stolen^0b111) // yeniden sıkıştırılmış kopya
+			},
+			wantHit: true,
+		},
+		{
+			name: "uploader re-uploads a stolen photo they already have",
+			setup: func(t *testing.T, photos *repository.PhotoRepository, _ *repository.PhotoReviewRepository, uploader, owner int) int {
+				addHashedPhoto(t, photos, uploader, stolen)
+				return addHashedPhoto(t, photos, owner, stolen)
+			},
+			wantHit: true,
+		},
+		{
+			name: "only the uploader has the photo",
+			setup: func(t *testing.T, photos *repository.PhotoRepository, _ *repository.PhotoReviewRepository, uploader, owner int) int {
+				addHashedPhoto(t, photos, uploader, stolen)
+				addHashedPhoto(t, photos, owner, other)
+				return 0
+			},
+		},
+		{
+			name: "suspended account's photo",
+			setup: func(t *testing.T, photos *repository.PhotoRepository, reviews *repository.PhotoReviewRepository, _, owner int) int {
+				id := addHashedPhoto(t, photos, owner, stolen)
+				if err := reviews.BlockHash(repository.BlockedHash{PHash: imaging.FormatHash(stolen), UserID: owner, PhotoID: id}, time.Now()); err != nil {
+					t.Fatal(err)
+				}
+				return id
+			},
+			wantHit: true,
+			blocked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			photos := repository.NewPhotoRepository(db)
+			reviews := repository.NewPhotoReviewRepository(db)
+			guard := NewPhotoGuard(photos, reviews, DefaultDuplicatePolicy())
+
+			uploader := createTestUser(t, db, "uploader@example.com")
+			owner := createTestUser(t, db, "owner@example.com")
+			wantPhoto := tt.setup(t, photos, reviews, uploader, owner)
+
+			match, err := guard.FindDuplicate(uploader, imaging.FormatHash(stolen))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.wantHit {
+				if match != nil {
+					t.Fatalf("match = %+v, want none", match)
+				}
+				return
+			}
+			if match == nil {
+				t.Fatal("no match, want the other account's photo")
+			}
+			if match.UserID != owner || match.PhotoID != wantPhoto || match.Suspended != tt.blocked {
+				t.Errorf("match = %+v, want photo %d of user %d (suspended %v)", match, wantPhoto, owner, tt.blocked)
+			}
+		})
+	}
+}
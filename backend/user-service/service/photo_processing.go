@@ -0,0 +1,158 @@
+// photo_processing.go - Yüklenen fotoğrafların asenkron işlenmesi
+package service
+
+import (
+	"bytes"
+	"context"
+	"eros/user-service/imaging"
+	"eros/user-service/model"
+	"eros/user-service/repository"
+	"io"
+	"log/slog"
+	"path"
+	"strings"
+)
+
+// photoProcessingWorkers - Aynı anda işlenebilecek fotoğraf sayısı
+const photoProcessingWorkers = 2
+
+// QueuePhotoProcessing - Fotoğrafı arka planda işle
+// Kapanış başladığında sırada bekleyen fotoğraflar başlatılmaz (açılışta ResumePhotoProcessing
+// devam ettirir); işlenmekte olanın bitmesi beklenir.
+func (s *UserService) QueuePhotoProcessing(ctx context.Context, photoID int) {
+	// İstek bitse de işleme sürer; bağlam günlükler için istek kimliğini taşır
+	jobCtx := context.WithoutCancel(ctx)
+	s.workers.Go(func(ctx context.Context) {
+		select {
+		case s.processingSlots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		defer func() { <-s.processingSlots }()
+
+		if err := s.ProcessPhoto(jobCtx, photoID); err != nil {
+			slog.ErrorContext(jobCtx, "photo processing failed", "photo_id", photoID, "error", err)
+		}
+	})
+}
+
+// ResumePhotoProcessing - Yarım kalmış fotoğrafları yeniden kuyruğa al (servis açılışında)
+func (s *UserService) ResumePhotoProcessing() error {
+	for _, status := range []string{model.PhotoStatusPending, model.PhotoStatusProcessing} {
+		photos, err := s.photoRepo.GetPhotosByStatus(status)
+		if err != nil {
+			return err
+		}
+		for _, p := range photos {
+			s.QueuePhotoProcessing(context.Background(), p.ID)
+		}
+	}
+	return nil
+}
+
+// ProcessPhoto - Orijinali çöz, varyantları ve algısal özeti üret, orijinali sil
+// Orijinal yüklemede metadata'sından arındırılmıştır ama işlenene kadar servis edilmez.
+// Herhangi bir adım başarısız olursa fotoğraf failed olur; orijinal ve yazılmış
+// varyantlar silinir, fotoğraf limite sayılmaz.
+func (s *UserService) ProcessPhoto(ctx context.Context, photoID int) error {
+	photo, err := s.photoRepo.GetPhotoByID(photoID)
+	if err != nil {
+		return err
+	}
+	if photo.Status == model.PhotoStatusReady || photo.Status == model.PhotoStatusFailed {
+		return nil
+	}
+
+	if err := s.photoRepo.SetStatus(photoID, model.PhotoStatusProcessing); err != nil {
+		return err
+	}
+
+	original := photo.URL
+	keys, phash, err := s.renderVariants(ctx, original)
+	if err == nil {
+		err = s.photoRepo.SaveProcessed(photoID, keys["full"], keys["thumb"], keys["card"], phash)
+	}
+	if err != nil {
+		s.failPhoto(ctx, photoID, original, keys)
+		return err
+	}
+
+	return s.photoStore.Delete(ctx, original)
+}
+
+// renderVariants - Orijinali oku, varyantları depoya yaz; yazılan anahtarlar hata durumunda da döner
+func (s *UserService) renderVariants(ctx context.Context, original string) (map[string]string, string, error) {
+	keys := make(map[string]string)
+	body, _, err := s.photoStore.Get(ctx, original)
+	if err != nil {
+		return keys, "", err
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return keys, "", err
+	}
+
+	result, err := imaging.Process(data, imaging.DefaultVariants)
+	if err != nil {
+		return keys, "", err
+	}
+
+	// photos/12/abc.jpg → photos/12/abc_thumb.jpg, ...
+	base := strings.TrimSuffix(original, path.Ext(original))
+	for name, variant := range result.Variants {
+		key := base + "_" + name + ".jpg"
+		if err := s.photoStore.Put(ctx, key, bytes.NewReader(variant), int64(len(variant)), "image/jpeg"); err != nil {
+			return keys, "", err
+		}
+		keys[name] = key
+	}
+	return keys, imaging.FormatHash(result.PHash), nil
+}
+
+// failPhoto - Fotoğrafı failed işaretle, orijinali ve yazılmış varyantları sil
+func (s *UserService) failPhoto(ctx context.Context, photoID int, original string, variants map[string]string) {
+	if err := s.photoRepo.FailPhoto(photoID); err != nil {
+		slog.ErrorContext(ctx, "failed to mark photo as failed", "photo_id", photoID, "error", err)
+	}
+
+	keys := []string{original}
+	for _, key := range variants {
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		if err := s.photoStore.Delete(ctx, key); err != nil {
+			slog.ErrorContext(ctx, "failed to delete photo file", "photo_id", photoID, "key", key, "error", err)
+		}
+	}
+}
+
+// toModelPhoto - Veritabanı kaydını API modeline çevir (anahtarlar imzalı adrese dönüşür)
+func (s *UserService) toModelPhoto(rp repository.Photo) model.Photo {
+	photo := model.Photo{
+		ID:         rp.ID,
+		UserID:     rp.UserID,
+		IsPrimary:  rp.IsPrimary,
+		OrderIndex: rp.OrderIndex,
+		AIScore:    rp.AIScore,
+		IsVerified: rp.IsVerified,
+		Status:     rp.Status,
+		PHash:      rp.PHash,
+		CreatedAt:  rp.CreatedAt,
+	}
+
+	// İşlenmemiş orijinal servis edilmez
+	if rp.Status != model.PhotoStatusReady {
+		return photo
+	}
+
+	photo.URL = s.PhotoURL(rp.URL)
+	if rp.ThumbURL != "" || rp.CardURL != "" {
+		photo.Variants = map[string]string{
+			"thumb": s.PhotoURL(rp.ThumbURL),
+			"card":  s.PhotoURL(rp.CardURL),
+			"full":  photo.URL,
+		}
+	}
+	return photo
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"eros/shared/moderation"
+	"eros/shared/server"
+	"eros/user-service/model"
+	"eros/user-service/repository"
+	"eros/user-service/storage"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingStore - Belirli bir son eke sahip anahtarlara yazmayı reddeden depo
+type failingStore struct {
+	*storage.LocalStore
+	failSuffix string
+}
+
+func (s *failingStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if s.failSuffix != "" && strings.HasSuffix(key, s.failSuffix) {
+		return errors.New("disk full")
+	}
+	return s.LocalStore.Put(ctx, key, body, size, contentType)
+}
+
+// processingEnv - Gerçek repository'ler ve yerel depo ile fotoğraf işleme
+type processingEnv struct {
+	photos  *repository.PhotoRepository
+	store   *failingStore
+	service *UserService
+	userID  int
+}
+
+func newProcessingEnv(t *testing.T) *processingEnv {
+	t.Helper()
+	db := newTestDB(t)
+	local, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &failingStore{LocalStore: local}
+
+	userRepo := repository.NewUserRepository(db)
+	photoRepo := repository.NewPhotoRepository(db)
+	workers := server.NewWorkers()
+	t.Cleanup(func() { workers.Stop(context.Background()) })
+
+	return &processingEnv{
+		photos: photoRepo,
+		store:  store,
+		service: NewUserService(userRepo, photoRepo, repository.NewPhotoReviewRepository(db), store,
+			storage.NewURLSigner([]byte("test"), "/api/photos/file/", time.Hour), NewProfileValidator(moderation.Default()), DefaultDuplicatePolicy(), workers),
+		userID: createTestUser(t, db, "ayse@example.com"),
+	}
+}
+
+// upload - Dosyayı depoya yaz ve bekleyen fotoğraf kaydını oluştur
+func (e *processingEnv) upload(t *testing.T, data []byte) (int, string) {
+	t.Helper()
+	key, err := e.service.SavePhotoFile(context.Background(), e.userID, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &repository.Photo{UserID: e.userID, URL: key, Status: model.PhotoStatusPending, CreatedAt: time.Now()}
+	if err := e.photos.AddPhoto(p, MaxPhotosPerUser); err != nil {
+		t.Fatal(err)
+	}
+	return p.ID, key
+}
+
+// exists - Anahtar depoda var mı
+func (e *processingEnv) exists(t *testing.T, key string) bool {
+	t.Helper()
+	body, _, err := e.store.Get(context.Background(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	body.Close()
+	return true
+}
+
+// exifJPEG - GPS içeren APP1 Exif bölümü taşıyan JPEG
+func exifJPEG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 4), G: uint8(y * 5), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	payload := []byte("Exif\x00\x00MM\x00\x2A\x00\x00\x00\x08\x00\x00GPS 41.0082 N 28.9784 E")
+	segment := append([]byte{0xFF, 0xE1, 0, byte(len(payload) + 2)}, payload...)
+	return append(append(append([]byte{}, data[:2]...), segment...), data[2:]...)
+}
+
+func TestSavePhotoFileStripsMetadata(t *testing.T) {
+	env := newProcessingEnv(t)
+	_, key := env.upload(t, exifJPEG(t))
+
+	body, info, err := env.store.Get(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	stored, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(stored, []byte("Exif")) || bytes.Contains(stored, []byte("GPS")) {
+		t.Fatal("stored original still carries exif")
+	}
+	if info.ContentType != "image/jpeg" || !strings.HasSuffix(key, ".jpg") {
+		t.Fatalf("stored as %s (%s)", key, info.ContentType)
+	}
+
+	if _, err := env.service.SavePhotoFile(context.Background(), env.userID, []byte("GIF89a not really")); err == nil {
+		t.Fatal("unsupported upload was stored")
+	}
+}
+
+func TestProcessPhoto(t *testing.T) {
+	env := newProcessingEnv(t)
+	ctx := context.Background()
+	photoID, original := env.upload(t, exifJPEG(t))
+
+	if err := env.service.ProcessPhoto(ctx, photoID); err != nil {
+		t.Fatal(err)
+	}
+	photo, err := env.photos.GetPhotoByID(photoID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if photo.Status != model.PhotoStatusReady || photo.PHash == "" {
+		t.Fatalf("photo = %+v, want ready with a hash", photo)
+	}
+	for _, key := range []string{photo.URL, photo.ThumbURL, photo.CardURL} {
+		if !env.exists(t, key) {
+			t.Errorf("variant %q is missing", key)
+		}
+	}
+	if env.exists(t, original) {
+		t.Error("original was not deleted")
+	}
+}
+
+func TestProcessPhotoFailureCleansUp(t *testing.T) {
+	tests := []struct {
+		name       string
+		original   func(t *testing.T, env *processingEnv) (int, string)
+		failSuffix string
+	}{
+		{
+			name: "undecodable original",
+			original: func(t *testing.T, env *processingEnv) (int, string) {
+				key := "photos/1/broken.jpg"
+				data := []byte("\xFF\xD8\xFF not a jpeg")
+				if err := env.store.Put(context.Background(), key, bytes.NewReader(data), int64(len(data)), "image/jpeg"); err != nil {
+					t.Fatal(err)
+				}
+				p := &repository.Photo{UserID: env.userID, URL: key, Status: model.PhotoStatusPending, CreatedAt: time.Now()}
+				if err := env.photos.AddPhoto(p, MaxPhotosPerUser); err != nil {
+					t.Fatal(err)
+				}
+				return p.ID, key
+			},
+		},
+		{
+			name:       "variant write fails",
+			original:   func(t *testing.T, env *processingEnv) (int, string) { return env.upload(t, exifJPEG(t)) },
+			failSuffix: "_card.jpg",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newProcessingEnv(t)
+			ctx := context.Background()
+			photoID, original := tt.original(t, env)
+			env.store.failSuffix = tt.failSuffix
+
+			if err := env.service.ProcessPhoto(ctx, photoID); err == nil {
+				t.Fatal("processing succeeded")
+			}
+
+			photo, err := env.photos.GetPhotoByID(photoID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if photo.Status != model.PhotoStatusFailed {
+				t.Fatalf("status = %q, want failed", photo.Status)
+			}
+			base := strings.TrimSuffix(original, ".jpg")
+			for _, key := range []string{original, base + "_thumb.jpg", base + "_card.jpg", base + "_full.jpg"} {
+				if env.exists(t, key) {
+					t.Errorf("%q was left in the store", key)
+				}
+			}
+			if count, err := env.service.GetUserPhotoCount(env.userID); err != nil || count != 0 {
+				t.Fatalf("photo count = %d, err = %v; failed photos must not count", count, err)
+			}
+
+			// Başarısız fotoğraf yeniden işlenmez
+			if err := env.service.ProcessPhoto(ctx, photoID); err != nil {
+				t.Fatalf("reprocessing a failed photo: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// profile_validation.go - Profil metinlerinin yazım anında doğrulanması ve moderasyonu
+package service
+
+import (
+	"eros/shared/apierror"
+	"eros/shared/moderation"
+	"eros/shared/utils"
+	"eros/user-service/model"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Doğrulama hata kodları
+const (
+	CodeRequired      = "required"
+	CodeTooShort      = "too_short"
+	CodeTooLong       = "too_long"
+	CodeOutOfRange    = "out_of_range"
+	CodeInvalidFormat = "invalid_format"
+	CodeContactInfo   = "contact_info"
+	CodeInappropriate = "inappropriate"
+)
+
+// FieldError - Alan bazlı doğrulama hatası (yanıtta "fields" dizisinin elemanı)
+type FieldError = apierror.FieldError
+
+// ValidationError - Bir istekteki tüm alan hataları
+type ValidationError = apierror.ValidationError
+
+// namePattern - Harfle başlayan; harf, boşluk, kesme, tire ve nokta içeren isimler
+var namePattern = regexp.MustCompile(`^\p{L}[\p{L}\p{M} '’.-]*$`)
+
+const (
+	nameMinLength = 2
+	nameMaxLength = 50
+	bioMaxLength  = 500
+	jobMaxLength  = 100
+)
+
+// ProfileValidator - Profil metinleri için alana özel kurallar
+// İsim ve meslek uyarı seviyesinde bile reddedilir; biyografide müstehcen içerik
+// maskelenmek yerine reddedilir, hafif uyarılar geçer.
+type ProfileValidator struct {
+	nameEngine *moderation.Engine
+	bioEngine  *moderation.Engine
+}
+
+func NewProfileValidator(engine *moderation.Engine) *ProfileValidator {
+	return &ProfileValidator{
+		nameEngine: engine,
+		bioEngine: engine.WithPolicies(map[moderation.Category]moderation.Severity{
+			moderation.CategorySexual: moderation.SeverityMask,
+		}),
+	}
+}
+
+// ValidateName - İsim alanını doğrula
+func (v *ProfileValidator) ValidateName(errs *ValidationError, name string) {
+	name = strings.TrimSpace(name)
+	length := utf8.RuneCountInString(name)
+
+	switch {
+	case name == "":
+		errs.Add("name", CodeRequired, "name is required")
+	case length < nameMinLength:
+		errs.Add("name", CodeTooShort, fmt.Sprintf("name must be at least %d characters", nameMinLength))
+	case length > nameMaxLength:
+		errs.Add("name", CodeTooLong, fmt.Sprintf("name must be at most %d characters", nameMaxLength))
+	case !namePattern.MatchString(name):
+		errs.Add("name", CodeInvalidFormat, "name may only contain letters, spaces, apostrophes, hyphens and dots")
+	case v.nameEngine.Evaluate(name).Action >= moderation.SeverityWarn:
+		errs.Add("name", CodeInappropriate, "name contains inappropriate language")
+	}
+}
+
+// ValidateProfile - İsim, biyografi ve meslek alanlarını doğrula
+func (v *ProfileValidator) ValidateProfile(errs *ValidationError, user *model.User) {
+	v.ValidateName(errs, user.Name)
+	v.validateBio(errs, user.Bio)
+	v.validateJob(errs, user.Job)
+}
+
+// ValidateChanges - Yalnızca güncellemede değişen profil metinlerini doğrula
+// Kurallar sonradan sıkılaştığında eski kayıtlar geçersiz kalabilir; kullanıcı dokunmadığı
+// bir alan yüzünden (ör. yalnızca tercihlerini değiştirirken) reddedilmez.
+func (v *ProfileValidator) ValidateChanges(errs *ValidationError, stored, updated *model.User) {
+	if updated.Name != stored.Name {
+		v.ValidateName(errs, updated.Name)
+	}
+	if updated.Bio != stored.Bio {
+		v.validateBio(errs, updated.Bio)
+	}
+	if updated.Job != stored.Job {
+		v.validateJob(errs, updated.Job)
+	}
+}
+
+// validateBio - Biyografi: iletişim bilgisi yok, maskelenecek/engellenecek içerik yok
+func (v *ProfileValidator) validateBio(errs *ValidationError, bio string) {
+	switch {
+	case utf8.RuneCountInString(bio) > bioMaxLength:
+		errs.Add("bio", CodeTooLong, fmt.Sprintf("bio must be at most %d characters", bioMaxLength))
+	case utils.ContainsContactInfo(bio):
+		errs.Add("bio", CodeContactInfo, "bio must not contain contact information")
+	case v.bioEngine.Evaluate(bio).Action >= moderation.SeverityMask:
+		errs.Add("bio", CodeInappropriate, "bio contains inappropriate content")
+	}
+}
+
+// validateJob - Meslek: kısa serbest metin, isim kadar sıkı
+func (v *ProfileValidator) validateJob(errs *ValidationError, job string) {
+	switch {
+	case utf8.RuneCountInString(job) > jobMaxLength:
+		errs.Add("job", CodeTooLong, fmt.Sprintf("job must be at most %d characters", jobMaxLength))
+	case utils.ContainsContactInfo(job):
+		errs.Add("job", CodeContactInfo, "job must not contain contact information")
+	case v.nameEngine.Evaluate(job).Action >= moderation.SeverityWarn:
+		errs.Add("job", CodeInappropriate, "job contains inappropriate language")
+	}
+}
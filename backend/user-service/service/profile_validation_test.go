@@ -0,0 +1,185 @@
+package service
+
+import (
+	"encoding/json"
+	"eros/shared/moderation"
+	"eros/user-service/model"
+	"eros/user-service/repository"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fieldCodes - Doğrulama hatalarını alan -> kod eşlemesine çevir
+func fieldCodes(errs *ValidationError) map[string]string {
+	codes := map[string]string{}
+	for _, f := range errs.Fields {
+		codes[f.Field] = f.Code
+	}
+	return codes
+}
+
+func TestValidateProfile(t *testing.T) {
+	v := NewProfileValidator(moderation.Default())
+
+	tests := []struct {
+		name string
+		user model.User
+		want map[string]string
+	}{
+		{name: "valid profile", user: model.User{Name: "Ayşe Nur", Bio: "Kitap, doğa ve kahve", Job: "Mühendis"}, want: map[string]string{}},
+		{name: "name with apostrophe and hyphen", user: model.User{Name: "D'Angelo Jean-Luc"}, want: map[string]string{}},
+		{name: "name missing", user: model.User{Name: "  "}, want: map[string]string{"name": CodeRequired}},
+		{name: "name too short", user: model.User{Name: "A"}, want: map[string]string{"name": CodeTooShort}},
+		{name: "name too long", user: model.User{Name: strings.Repeat("a", nameMaxLength+1)}, want: map[string]string{"name": CodeTooLong}},
+		{name: "name with digits", user: model.User{Name: "Ayşe123"}, want: map[string]string{"name": CodeInvalidFormat}},
+		{name: "name starting with a symbol", user: model.User{Name: ".Ayşe"}, want: map[string]string{"name": CodeInvalidFormat}},
+		{name: "flagged name", user: model.User{Name: "Salak"}, want: map[string]string{"name": CodeInappropriate}},
+		{name: "bio with phone number", user: model.User{Name: "Ayşe", Bio: "Ara beni 0532 123 45 67"}, want: map[string]string{"bio": CodeContactInfo}},
+		{name: "bio with email", user: model.User{Name: "Ayşe", Bio: "yaz: ayse@example.com"}, want: map[string]string{"bio": CodeContactInfo}},
+		{name: "bio too long", user: model.User{Name: "Ayşe", Bio: strings.Repeat("a", bioMaxLength+1)}, want: map[string]string{"bio": CodeTooLong}},
+		{name: "bio with profanity", user: model.User{Name: "Ayşe", Bio: "siktir git"}, want: map[string]string{"bio": CodeInappropriate}},
+		{name: "bio with sexual content", user: model.User{Name: "Ayşe", Bio: "sexy and fun"}, want: map[string]string{"bio": CodeInappropriate}},
+		{name: "bio warning passes", user: model.User{Name: "Ayşe", Bio: "that was stupid"}, want: map[string]string{}},
+		{name: "job with contact info", user: model.User{Name: "Ayşe", Job: "instagram: @ayse"}, want: map[string]string{"job": CodeContactInfo}},
+		{name: "job too long", user: model.User{Name: "Ayşe", Job: strings.Repeat("a", jobMaxLength+1)}, want: map[string]string{"job": CodeTooLong}},
+		{name: "job warning is rejected", user: model.User{Name: "Ayşe", Job: "stupid"}, want: map[string]string{"job": CodeInappropriate}},
+		{
+			name: "every field invalid",
+			user: model.User{Name: "A", Bio: "ayse@example.com", Job: "stupid"},
+			want: map[string]string{"name": CodeTooShort, "bio": CodeContactInfo, "job": CodeInappropriate},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := &ValidationError{}
+			v.ValidateProfile(errs, &tt.user)
+			got := fieldCodes(errs)
+			if len(got) != len(tt.want) {
+				t.Fatalf("errors = %v, want %v", got, tt.want)
+			}
+			for field, code := range tt.want {
+				if got[field] != code {
+					t.Errorf("%s = %q, want %q (all: %v)", field, got[field], code, got)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateChanges(t *testing.T) {
+	v := NewProfileValidator(moderation.Default())
+	// Kurallardan önce kaydolmuş, bugün geçersiz sayılan profil
+	legacy := model.User{Name: "X", Bio: "ara 05321234567", Job: "stupid"}
+
+	tests := []struct {
+		name   string
+		update func(u *model.User)
+		want   map[string]string
+	}{
+		{name: "untouched legacy fields", update: func(u *model.User) { u.Seriousness = 8 }, want: map[string]string{}},
+		{name: "changed name is checked", update: func(u *model.User) { u.Name = "Y" }, want: map[string]string{"name": CodeTooShort}},
+		{name: "fixed name passes", update: func(u *model.User) { u.Name = "Ayşe" }, want: map[string]string{}},
+		{name: "changed bio is checked", update: func(u *model.User) { u.Bio = "ayse@example.com" }, want: map[string]string{"bio": CodeContactInfo}},
+		{name: "changed job is checked", update: func(u *model.User) { u.Job = "salak" }, want: map[string]string{"job": CodeInappropriate}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := legacy
+			tt.update(&updated)
+			errs := &ValidationError{}
+			v.ValidateChanges(errs, &legacy, &updated)
+			got := fieldCodes(errs)
+			if len(got) != len(tt.want) {
+				t.Fatalf("errors = %v, want %v", got, tt.want)
+			}
+			for field, code := range tt.want {
+				if got[field] != code {
+					t.Errorf("%s = %q, want %q", field, got[field], code)
+				}
+			}
+		})
+	}
+}
+
+// newProfileTestService - Kuralları karşılamayan eski bir kullanıcıyla kurulmuş servis
+func newProfileTestService(t *testing.T) (*UserService, *repository.UserRepository, int) {
+	t.Helper()
+	db := newTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	users := &UserService{userRepo: userRepo, profileValidator: NewProfileValidator(moderation.Default())}
+
+	legacy := &model.User{Name: "X", Email: "x@example.com", Password: "x", Bio: "Kahve", Job: "Öğretmen", Seriousness: 5, Hobbies: []string{"Yüzme"}, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := userRepo.CreateUser(legacy); err != nil {
+		t.Fatal(err)
+	}
+	return users, userRepo, legacy.ID
+}
+
+func TestUpdateUserLegacyPreferences(t *testing.T) {
+	users, userRepo, id := newProfileTestService(t)
+
+	if _, err := users.UpdateUser(id, func(u *model.User) error {
+		u.Seriousness = 9
+		u.AgeRange = "25-35"
+		return nil
+	}); err != nil {
+		t.Fatalf("preference update rejected for a legacy name: %v", err)
+	}
+
+	stored, err := userRepo.GetUserByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Seriousness != 9 || stored.AgeRange != "25-35" || stored.Name != "X" {
+		t.Fatalf("stored = %+v", stored)
+	}
+
+	// Eski isim değiştirilirse yeni değer kurallara uymalı
+	_, err = users.UpdateUser(id, func(u *model.User) error {
+		u.Name = "Y"
+		return nil
+	})
+	var verr *ValidationError
+	if !errors.As(err, &verr) || fieldCodes(verr)["name"] != CodeTooShort {
+		t.Fatalf("err = %v, want name too_short", err)
+	}
+}
+
+func TestUpdateUserMergesPartialBody(t *testing.T) {
+	users, userRepo, id := newProfileTestService(t)
+
+	// Yalnızca biyografi gönderen istemci diğer alanları silmez
+	if _, err := users.UpdateUser(id, func(u *model.User) error {
+		return json.Unmarshal([]byte(`{"bio":"Kitap ve doğa","id":999}`), u)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := userRepo.GetUserByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Bio != "Kitap ve doğa" || stored.Job != "Öğretmen" || stored.Seriousness != 5 || len(stored.Hobbies) != 1 {
+		t.Fatalf("stored = %+v, want only bio changed", stored)
+	}
+
+	// Geçersiz değişiklik hiçbir alanı yazmaz
+	_, err = users.UpdateUser(id, func(u *model.User) error {
+		return json.Unmarshal([]byte(`{"bio":"ayse@example.com","seriousness":1}`), u)
+	})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v, want a validation error", err)
+	}
+	if stored, _ := userRepo.GetUserByID(id); stored.Seriousness != 5 {
+		t.Fatalf("seriousness = %d, rejected update was written", stored.Seriousness)
+	}
+
+	if _, err := users.UpdateUser(id+100, func(*model.User) error { return nil }); err == nil {
+		t.Fatal("update of a missing user succeeded")
+	}
+}
@@ -2,26 +2,63 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"eros/shared/apierror"
+	"eros/shared/server"
+	"eros/user-service/imaging"
 	"eros/user-service/model"
 	"eros/user-service/repository"
+	"eros/user-service/storage"
 	"errors"
+	"log/slog"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// MaxPhotosPerUser - Kullanıcı başına en fazla fotoğraf
+const MaxPhotosPerUser = 6
+
+var (
+	ErrPhotoLimitReached = errors.New("maximum 6 photos allowed")
+	ErrPhotoNotFound     = repository.ErrPhotoNotFound
+	ErrInvalidPhotoOrder = repository.ErrInvalidPhotoOrder
+	ErrInvalidImage      = imaging.ErrInvalidImage
+	ErrImageTooLarge     = imaging.ErrImageTooLarge
+)
+
 type UserService struct {
-	userRepo  *repository.UserRepository
-	photoRepo *repository.PhotoRepository
+	userRepo         repository.UserStore
+	photoRepo        *repository.PhotoRepository
+	photoStore       storage.PhotoStore
+	urlSigner        *storage.URLSigner
+	profileValidator *ProfileValidator
+	reviewRepo       *repository.PhotoReviewRepository
+	photoGuard       *PhotoGuard
+	processingSlots  chan struct{} // Aynı anda işlenen fotoğraf sınırı
+	workers          *server.Workers
 }
 
-func NewUserService(userRepo *repository.UserRepository, photoRepo *repository.PhotoRepository) *UserService {
+func NewUserService(userRepo repository.UserStore, photoRepo *repository.PhotoRepository, reviewRepo *repository.PhotoReviewRepository, photoStore storage.PhotoStore, urlSigner *storage.URLSigner, profileValidator *ProfileValidator, duplicatePolicy DuplicatePolicy, workers *server.Workers) *UserService {
 	return &UserService{
-		userRepo:  userRepo,
-		photoRepo: photoRepo,
+		userRepo:         userRepo,
+		photoRepo:        photoRepo,
+		photoStore:       photoStore,
+		urlSigner:        urlSigner,
+		profileValidator: profileValidator,
+		reviewRepo:       reviewRepo,
+		photoGuard:       NewPhotoGuard(photoRepo, reviewRepo, duplicatePolicy),
+		processingSlots:  make(chan struct{}, photoProcessingWorkers),
+		workers:          workers,
 	}
 }
 
+// ValidateProfile - Profil metinlerini doğrula ve hataları errs'e ekle
+func (s *UserService) ValidateProfile(errs *ValidationError, user *model.User) {
+	s.profileValidator.ValidateProfile(errs, user)
+}
+
 // CreateUser - Yeni kullanıcı oluştur
 func (s *UserService) CreateUser(user *model.User) error {
 	// Email kontrolü
@@ -30,12 +67,19 @@ func (s *UserService) CreateUser(user *model.User) error {
 		return err
 	}
 	if exists {
-		return errors.New("email already exists")
+		return apierror.Conflict("email already exists")
 	}
 
 	// Minimum profil bilgisi kontrolü
 	if user.Name == "" || user.Email == "" {
-		return errors.New("name and email are required")
+		return apierror.BadRequest("name and email are required")
+	}
+
+	// Profil metinleri yazılmadan önce denetlenir
+	errs := &ValidationError{}
+	s.profileValidator.ValidateProfile(errs, user)
+	if err := errs.OrNil(); err != nil {
+		return err
 	}
 
 	user.CreatedAt = time.Now()
@@ -55,6 +99,11 @@ func (s *UserService) AuthenticateUser(email, password string) (*model.User, err
 		return nil, errors.New("invalid credentials")
 	}
 
+	// Doğrulama durumu sadece şifreyi bilen kişiye açıklanır
+	if !user.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
 	return user, nil
 }
 
@@ -63,26 +112,90 @@ func (s *UserService) GetUserByID(userID int) (*model.User, error) {
 	return s.userRepo.GetUserByID(userID)
 }
 
-// UpdateUser - Kullanıcı bilgilerini güncelle
-func (s *UserService) UpdateUser(user *model.User) error {
-	user.UpdatedAt = time.Now()
-	return s.userRepo.UpdateUser(user)
+// UpdateUser - Kayıtlı kullanıcıya apply ile kısmi güncelleme uygula
+// apply kayıtlı kullanıcının kopyasını değiştirir; dokunmadığı alanlar olduğu gibi kalır.
+// Yalnızca değişen profil metinleri doğrulanır.
+func (s *UserService) UpdateUser(userID int, apply func(user *model.User) error) (*model.User, error) {
+	stored, err := s.userRepo.GetUserByID(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apierror.NotFound("user not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *stored
+	if err := apply(&updated); err != nil {
+		return nil, err
+	}
+	updated.ID = userID
+
+	errs := &ValidationError{}
+	s.profileValidator.ValidateChanges(errs, stored, &updated)
+	if err := errs.OrNil(); err != nil {
+		return nil, err
+	}
+
+	updated.UpdatedAt = time.Now()
+	if err := s.userRepo.UpdateUser(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
 }
 
-// AddPhoto - Fotoğraf ekle
-func (s *UserService) AddPhoto(photo *model.Photo) error {
+// AddPhoto - Fotoğraf ekle (sıranın sonuna; ilk fotoğraf birincil olur)
+// PHash doluysa yükleme kopya taramasından geçer: askıya alınmış hesapların fotoğrafları
+// ErrPhotoBlocked ile reddedilir, başka kullanıcılara benzeyenler eklenip incelemeye alınır.
+func (s *UserService) AddPhoto(ctx context.Context, photo *model.Photo) error {
+	// Dolu profilde kopya taramasına gerek yok; kesin kontrol eklemeyle aynı işlemdedir
+	count, err := s.photoRepo.CountPhotosByUser(photo.UserID)
+	if err != nil {
+		return err
+	}
+	if count >= MaxPhotosPerUser {
+		return ErrPhotoLimitReached
+	}
+
+	var match *DuplicateMatch
+	if photo.PHash != "" {
+		if match, err = s.photoGuard.FindDuplicate(photo.UserID, photo.PHash); err != nil {
+			return err
+		}
+	}
+	if match != nil && match.Suspended {
+		if err := s.photoGuard.RecordMatch(0, photo.UserID, match); err != nil {
+			slog.ErrorContext(ctx, "failed to record blocked photo", "user_id", photo.UserID, "error", err)
+		}
+		return ErrPhotoBlocked
+	}
+
 	photo.CreatedAt = time.Now()
 	repoPhoto := &repository.Photo{
 		ID:         photo.ID,
 		UserID:     photo.UserID,
 		URL:        photo.URL,
-		IsPrimary:  photo.IsPrimary,
-		OrderIndex: photo.OrderIndex,
 		AIScore:    photo.AIScore,
 		IsVerified: photo.IsVerified,
+		Status:     photo.Status,
+		PHash:      photo.PHash,
 		CreatedAt:  photo.CreatedAt,
 	}
-	return s.photoRepo.AddPhoto(repoPhoto)
+	if err := s.photoRepo.AddPhoto(repoPhoto, MaxPhotosPerUser); err != nil {
+		if errors.Is(err, repository.ErrPhotoLimitReached) {
+			return ErrPhotoLimitReached
+		}
+		return err
+	}
+	photo.ID = repoPhoto.ID
+	photo.OrderIndex = repoPhoto.OrderIndex
+	photo.IsPrimary = repoPhoto.IsPrimary
+
+	if match != nil {
+		if err := s.photoGuard.RecordMatch(photo.ID, photo.UserID, match); err != nil {
+			slog.ErrorContext(ctx, "failed to flag photo for review", "photo_id", photo.ID, "error", err)
+		}
+	}
+	return nil
 }
 
 // GetUserPhotos - Kullanıcının fotoğraflarını getir
@@ -93,16 +206,7 @@ func (s *UserService) GetUserPhotos(userID int) ([]model.Photo, error) {
 	}
 	var photos []model.Photo
 	for _, rp := range repoPhotos {
-		photos = append(photos, model.Photo{
-			ID:         rp.ID,
-			UserID:     rp.UserID,
-			URL:        rp.URL,
-			IsPrimary:  rp.IsPrimary,
-			OrderIndex: rp.OrderIndex,
-			AIScore:    rp.AIScore,
-			IsVerified: rp.IsVerified,
-			CreatedAt:  rp.CreatedAt,
-		})
+		photos = append(photos, s.toModelPhoto(rp))
 	}
 	return photos, nil
 }
@@ -126,23 +230,46 @@ func (s *UserService) EmailExists(email string) (bool, error) {
 	return s.userRepo.EmailExists(email)
 }
 
-// Kullanıcının fotoğraf sayısını döndür (stub)
+// GetUserPhotoCount - Kullanıcının fotoğraf sayısı
 func (s *UserService) GetUserPhotoCount(userID int) (int, error) {
-	return 0, nil
+	return s.photoRepo.CountPhotosByUser(userID)
 }
 
-// Fotoğrafı analiz et (stub)
-func (s *UserService) AnalyzePhoto(photoURL string) (float64, error) {
-	return 0.0, nil
+// AnalyzePhoto - Fotoğrafın 0-1 arası kalite skoru (çözünürlük, pozlama, kontrast, netlik)
+// ve kopya taraması için algısal özeti. Çözülemeyen görüntüler ErrInvalidImage veya
+// ErrImageTooLarge döner.
+func (s *UserService) AnalyzePhoto(data []byte) (score float64, phash string, err error) {
+	analysis, err := imaging.Analyze(data)
+	if err != nil {
+		return 0, "", err
+	}
+	return analysis.Score, imaging.FormatHash(analysis.PHash), nil
 }
 
-// Fotoğrafları sırala (stub)
+// ReorderPhotos - Fotoğrafları verilen sırayla 1..n numarala, ilk fotoğraf birincil olur
 func (s *UserService) ReorderPhotos(userID int, order []int) error {
-	return nil
+	return s.photoRepo.ReorderPhotos(userID, order)
 }
 
-// Fotoğrafı sil (stub)
-func (s *UserService) DeletePhoto(photoID int) error {
+// DeletePhoto - Kullanıcının fotoğrafını ve depodaki dosyalarını sil
+// Birincil fotoğraf silinirse sıradaki fotoğraf birincil olur; doğrulanmış fotoğrafı
+// kalmayan kullanıcı rozetini kaybeder.
+func (s *UserService) DeletePhoto(ctx context.Context, userID, photoID int) error {
+	photo, err := s.photoRepo.DeletePhoto(userID, photoID)
+	if err != nil {
+		return err
+	}
+
+	// Kayıt silindi; depo temizliği başarısız olsa da istek başarılı sayılır
+	for _, key := range []string{photo.URL, photo.ThumbURL, photo.CardURL} {
+		if !storage.IsKey(key) {
+			continue
+		}
+		if err := s.photoStore.Delete(ctx, key); err != nil {
+			slog.ErrorContext(ctx, "failed to delete photo file", "key", key, "error", err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,273 @@
+// verification.go - Selfie ile profil fotoğrafı doğrulama akışı
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eros/user-service/model"
+	"eros/user-service/repository"
+	"eros/user-service/storage"
+	"eros/user-service/verification"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrVerificationDisabled = errors.New("photo verification is not configured")
+	ErrNoPhotosToVerify     = errors.New("upload at least one processed photo before verifying")
+	ErrTooManyChallenges    = errors.New("too many verification attempts, try again tomorrow")
+	ErrChallengeNotFound    = errors.New("verification challenge not found")
+	ErrChallengeUsed        = errors.New("verification challenge was already used")
+	ErrChallengeExpired     = errors.New("verification challenge has expired")
+	ErrNoFace               = verification.ErrNoFace
+)
+
+// VerificationPolicy - Doğrulama eşikleri
+type VerificationPolicy struct {
+	ChallengeTTL        time.Duration // Poz verildikten sonra selfie için süre
+	MinSimilarity       float64       // Bu benzerlik ve üstü aynı kişi sayılır
+	MaxChallengesPerDay int           // Kullanıcı başına günlük deneme
+}
+
+// DefaultVerificationPolicy - Varsayılan eşikler
+func DefaultVerificationPolicy() VerificationPolicy {
+	return VerificationPolicy{
+		ChallengeTTL:        10 * time.Minute,
+		MinSimilarity:       0.8,
+		MaxChallengesPerDay: 5,
+	}
+}
+
+// VerificationPolicyFromEnv - Eşikleri ortam değişkenlerinden oku
+func VerificationPolicyFromEnv() VerificationPolicy {
+	policy := DefaultVerificationPolicy()
+
+	if v := os.Getenv("VERIFICATION_CHALLENGE_TTL_MINUTES"); v != "" {
+		if minutes, err := strconv.ParseFloat(v, 64); err == nil && minutes > 0 {
+			policy.ChallengeTTL = time.Duration(minutes * float64(time.Minute))
+		}
+	}
+	if v := os.Getenv("VERIFICATION_MIN_SIMILARITY"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 && f <= 1 {
+			policy.MinSimilarity = f
+		}
+	}
+	if v := os.Getenv("VERIFICATION_MAX_PER_DAY"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			policy.MaxChallengesPerDay = n
+		}
+	}
+
+	return policy
+}
+
+// VerificationOutcome - Selfie gönderiminin sonucu
+type VerificationOutcome struct {
+	Verified         bool    `json:"verified"`
+	PoseMatched      bool    `json:"pose_matched"`
+	Similarity       float64 `json:"similarity"`         // En yüksek yüz benzerliği
+	VerifiedPhotoIDs []int   `json:"verified_photo_ids"` // Selfie ile eşleşen profil fotoğrafları
+}
+
+// VerificationService - Poz meydan okuması ve selfie karşılaştırması
+type VerificationService struct {
+	userRepo         repository.UserStore
+	photoRepo        *repository.PhotoRepository
+	verificationRepo *repository.VerificationRepository
+	photoStore       storage.PhotoStore
+	verifier         verification.FaceVerifier
+	policy           VerificationPolicy
+	now              func() time.Time
+}
+
+// NewVerificationService - verifier nil ise doğrulama kapalıdır
+func NewVerificationService(userRepo repository.UserStore, photoRepo *repository.PhotoRepository, verificationRepo *repository.VerificationRepository, photoStore storage.PhotoStore, verifier verification.FaceVerifier, policy VerificationPolicy) *VerificationService {
+	return &VerificationService{
+		userRepo:         userRepo,
+		photoRepo:        photoRepo,
+		verificationRepo: verificationRepo,
+		photoStore:       photoStore,
+		verifier:         verifier,
+		policy:           policy,
+		now:              time.Now,
+	}
+}
+
+// StartChallenge - Kullanıcıya rastgele bir poz ver
+func (s *VerificationService) StartChallenge(userID int) (*model.VerificationChallenge, error) {
+	if s.verifier == nil {
+		return nil, ErrVerificationDisabled
+	}
+
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		return nil, err
+	}
+
+	photos, err := s.verifiablePhotos(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(photos) == 0 {
+		return nil, ErrNoPhotosToVerify
+	}
+
+	now := s.now()
+	count, err := s.verificationRepo.CountChallengesSince(userID, now.Add(-24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if count >= s.policy.MaxChallengesPerDay {
+		return nil, ErrTooManyChallenges
+	}
+
+	pose, err := verification.RandomPose()
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &model.VerificationChallenge{
+		UserID:      userID,
+		Pose:        string(pose),
+		Instruction: pose.Instruction(),
+		Status:      model.VerificationStatusPending,
+		ExpiresAt:   now.Add(s.policy.ChallengeTTL),
+		CreatedAt:   now,
+	}
+	if err := s.verificationRepo.CreateChallenge(challenge); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// SubmitSelfie - Selfie'yi pozla ve profil fotoğraflarıyla karşılaştır
+// Geçerse kullanıcı rozet alır ve selfie ile eşleşen fotoğraflar doğrulanır; eşleşmeyenlerin
+// doğrulaması kaldırılır. Selfie hiçbir yerde saklanmaz. Her meydan okuma tek kullanımlıktır.
+func (s *VerificationService) SubmitSelfie(ctx context.Context, userID, challengeID int, selfie []byte) (*VerificationOutcome, error) {
+	if s.verifier == nil {
+		return nil, ErrVerificationDisabled
+	}
+
+	challenge, err := s.verificationRepo.GetChallenge(userID, challengeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrChallengeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if challenge.Status != model.VerificationStatusPending {
+		return nil, ErrChallengeUsed
+	}
+	if s.now().After(challenge.ExpiresAt) {
+		s.verificationRepo.CompleteChallenge(challengeID, model.VerificationStatusExpired, 0, s.now())
+		return nil, ErrChallengeExpired
+	}
+
+	photos, err := s.verifiablePhotos(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(photos) == 0 {
+		return nil, ErrNoPhotosToVerify
+	}
+
+	references := make([][]byte, 0, len(photos))
+	for _, p := range photos {
+		data, err := s.readPhoto(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		references = append(references, data)
+	}
+
+	result, err := s.verifier.Verify(ctx, selfie, verification.Pose(challenge.Pose), references)
+	if errors.Is(err, verification.ErrNoFace) {
+		s.verificationRepo.CompleteChallenge(challengeID, model.VerificationStatusFailed, 0, s.now())
+		return nil, ErrNoFace
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := &VerificationOutcome{PoseMatched: result.PoseMatched, VerifiedPhotoIDs: []int{}}
+	for i, similarity := range result.Similarities {
+		if similarity > outcome.Similarity {
+			outcome.Similarity = similarity
+		}
+		if similarity >= s.policy.MinSimilarity {
+			outcome.VerifiedPhotoIDs = append(outcome.VerifiedPhotoIDs, photos[i].ID)
+		}
+	}
+	outcome.Verified = result.PoseMatched && len(outcome.VerifiedPhotoIDs) > 0
+
+	status := model.VerificationStatusFailed
+	if outcome.Verified {
+		status = model.VerificationStatusPassed
+	}
+	now := s.now()
+	claimed, err := s.verificationRepo.CompleteChallenge(challengeID, status, outcome.Similarity, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		// Aynı meydan okuma için eşzamanlı başka bir gönderim önce tamamlandı
+		return nil, ErrChallengeUsed
+	}
+
+	if !outcome.Verified {
+		outcome.VerifiedPhotoIDs = []int{}
+		return outcome, nil
+	}
+
+	matched := make(map[int]bool, len(outcome.VerifiedPhotoIDs))
+	for _, id := range outcome.VerifiedPhotoIDs {
+		matched[id] = true
+	}
+	for _, p := range photos {
+		if err := s.photoRepo.SetVerified(p.ID, matched[p.ID]); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.userRepo.SetVerified(userID, now); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "user passed selfie verification", "user_id", userID, "matched", len(matched), "photos", len(photos))
+	return outcome, nil
+}
+
+// verifiablePhotos - İşlenmiş ve depoda duran profil fotoğrafları
+func (s *VerificationService) verifiablePhotos(userID int) ([]repository.Photo, error) {
+	photos, err := s.photoRepo.GetPhotosByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ready []repository.Photo
+	for _, p := range photos {
+		if p.Status == model.PhotoStatusReady && storage.IsKey(p.URL) {
+			ready = append(ready, p)
+		}
+	}
+	return ready, nil
+}
+
+// readPhoto - Karşılaştırma için fotoğrafı oku (varsa daha küçük kart varyantı)
+func (s *VerificationService) readPhoto(ctx context.Context, photo repository.Photo) ([]byte, error) {
+	key := photo.URL
+	if storage.IsKey(photo.CardURL) {
+		key = photo.CardURL
+	}
+
+	body, _, err := s.photoStore.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"eros/shared/moderation"
+	"eros/shared/server"
+	"eros/user-service/model"
+	"eros/user-service/repository"
+	"eros/user-service/storage"
+	"eros/user-service/verification"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+)
+
+// poseVerifier - Pozu tutmayan selfie'yi taklit eden doğrulayıcı
+type poseVerifier struct {
+	verification.FakeVerifier
+	poseMatched bool
+}
+
+func (v *poseVerifier) Verify(ctx context.Context, selfie []byte, pose verification.Pose, references [][]byte) (*verification.Result, error) {
+	result, err := v.FakeVerifier.Verify(ctx, selfie, pose, references)
+	if err != nil {
+		return nil, err
+	}
+	result.PoseMatched = v.poseMatched
+	return result, nil
+}
+
+// verificationEnv - Gerçek repository'ler ve yerel depo ile doğrulama servisi
+type verificationEnv struct {
+	userRepo *repository.UserRepository
+	photos   *repository.PhotoRepository
+	store    *storage.LocalStore
+	service  *VerificationService
+	users    *UserService
+	userID   int
+	now      time.Time
+}
+
+func newVerificationEnv(t *testing.T, verifier verification.FaceVerifier) *verificationEnv {
+	t.Helper()
+	db := newTestDB(t)
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	photoRepo := repository.NewPhotoRepository(db)
+	workers := server.NewWorkers()
+	t.Cleanup(func() { workers.Stop(context.Background()) })
+
+	env := &verificationEnv{
+		userRepo: userRepo,
+		photos:   photoRepo,
+		store:    store,
+		service:  NewVerificationService(userRepo, photoRepo, repository.NewVerificationRepository(db), store, verifier, DefaultVerificationPolicy()),
+		users: NewUserService(userRepo, photoRepo, repository.NewPhotoReviewRepository(db), store,
+			storage.NewURLSigner([]byte("test"), "/api/photos/file/", time.Hour), NewProfileValidator(moderation.Default()), DefaultDuplicatePolicy(), workers),
+		now: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	user := &model.User{Name: "Ayşe", Email: "ayse@example.com", Password: "x", Seriousness: 5, CreatedAt: env.now, UpdatedAt: env.now}
+	if err := userRepo.CreateUser(user); err != nil {
+		t.Fatal(err)
+	}
+	env.userID = user.ID
+	env.service.now = func() time.Time { return env.now }
+	return env
+}
+
+// addPhoto - Depoya yazılmış, işlenmiş bir profil fotoğrafı ekle
+func (e *verificationEnv) addPhoto(t *testing.T, data []byte) int {
+	t.Helper()
+	key := fmt.Sprintf("photos/%d/%d.png", e.userID, time.Now().UnixNano())
+	if err := e.store.Put(context.Background(), key, bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	p := &repository.Photo{UserID: e.userID, URL: key, Status: model.PhotoStatusReady, CreatedAt: e.now}
+	if err := e.photos.AddPhoto(p, MaxPhotosPerUser); err != nil {
+		t.Fatal(err)
+	}
+	return p.ID
+}
+
+func (e *verificationEnv) verified(t *testing.T) bool {
+	t.Helper()
+	user, err := e.userRepo.GetUserByID(e.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user.IsVerified
+}
+
+// testImage - Algısal özeti yöne göre belirgin biçimde değişen PNG
+// vertical true ise sol yarı, değilse üst yarı açıktır.
+func testImage(t *testing.T, vertical bool) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			bright := y < 32
+			if vertical {
+				bright = x < 32
+			}
+			if bright {
+				img.SetGray(x, y, color.Gray{Y: 230})
+			} else {
+				img.SetGray(x, y, color.Gray{Y: 20})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSubmitSelfiePasses(t *testing.T) {
+	env := newVerificationEnv(t, verification.NewFakeVerifier())
+	face := testImage(t, true)
+	matching := env.addPhoto(t, face)
+	other := env.addPhoto(t, testImage(t, false))
+
+	challenge, err := env.service.StartChallenge(env.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if challenge.Pose == "" || !challenge.ExpiresAt.Equal(env.now.Add(DefaultVerificationPolicy().ChallengeTTL)) {
+		t.Fatalf("challenge = %+v", challenge)
+	}
+
+	outcome, err := env.service.SubmitSelfie(context.Background(), env.userID, challenge.ID, face)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !outcome.Verified || len(outcome.VerifiedPhotoIDs) != 1 || outcome.VerifiedPhotoIDs[0] != matching {
+		t.Fatalf("outcome = %+v, want only photo %d verified", outcome, matching)
+	}
+	if !env.verified(t) {
+		t.Fatal("user has no badge after passing")
+	}
+
+	photos, err := env.photos.GetPhotosByUser(env.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range photos {
+		if p.IsVerified != (p.ID == matching) {
+			t.Errorf("photo %d verified = %v (other photo is %d)", p.ID, p.IsVerified, other)
+		}
+	}
+}
+
+func TestSubmitSelfieWrongPose(t *testing.T) {
+	env := newVerificationEnv(t, &poseVerifier{poseMatched: false})
+	face := testImage(t, true)
+	env.addPhoto(t, face)
+
+	challenge, err := env.service.StartChallenge(env.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcome, err := env.service.SubmitSelfie(context.Background(), env.userID, challenge.ID, face)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcome.Verified || outcome.PoseMatched || len(outcome.VerifiedPhotoIDs) != 0 {
+		t.Fatalf("outcome = %+v, want a failed attempt", outcome)
+	}
+	if env.verified(t) {
+		t.Fatal("user got a badge with the wrong pose")
+	}
+
+	// Başarısız deneme de meydan okumayı tüketir
+	if _, err := env.service.SubmitSelfie(context.Background(), env.userID, challenge.ID, face); !errors.Is(err, ErrChallengeUsed) {
+		t.Fatalf("retry err = %v, want ErrChallengeUsed", err)
+	}
+}
+
+func TestSubmitSelfieExpiredChallenge(t *testing.T) {
+	env := newVerificationEnv(t, verification.NewFakeVerifier())
+	face := testImage(t, true)
+	env.addPhoto(t, face)
+
+	challenge, err := env.service.StartChallenge(env.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.now = env.now.Add(DefaultVerificationPolicy().ChallengeTTL + time.Second)
+
+	if _, err := env.service.SubmitSelfie(context.Background(), env.userID, challenge.ID, face); !errors.Is(err, ErrChallengeExpired) {
+		t.Fatalf("err = %v, want ErrChallengeExpired", err)
+	}
+	if env.verified(t) {
+		t.Fatal("user got a badge from an expired challenge")
+	}
+	// Süresi dolan meydan okuma kapanır; tekrar gönderim kullanılmış sayılır
+	if _, err := env.service.SubmitSelfie(context.Background(), env.userID, challenge.ID, face); !errors.Is(err, ErrChallengeUsed) {
+		t.Fatalf("retry err = %v, want ErrChallengeUsed", err)
+	}
+}
+
+func TestSubmitSelfieReusedChallenge(t *testing.T) {
+	env := newVerificationEnv(t, verification.NewFakeVerifier())
+	face := testImage(t, true)
+	env.addPhoto(t, face)
+
+	challenge, err := env.service.StartChallenge(env.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.service.SubmitSelfie(context.Background(), env.userID, challenge.ID, face); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.service.SubmitSelfie(context.Background(), env.userID, challenge.ID, face); !errors.Is(err, ErrChallengeUsed) {
+		t.Fatalf("err = %v, want ErrChallengeUsed", err)
+	}
+
+	// Başka kullanıcının meydan okuması bulunamaz
+	if _, err := env.service.SubmitSelfie(context.Background(), env.userID+1, challenge.ID, face); !errors.Is(err, ErrChallengeNotFound) {
+		t.Fatalf("other user err = %v, want ErrChallengeNotFound", err)
+	}
+}
+
+func TestStartChallengeLimits(t *testing.T) {
+	disabled := newVerificationEnv(t, nil)
+	if _, err := disabled.service.StartChallenge(disabled.userID); !errors.Is(err, ErrVerificationDisabled) {
+		t.Fatalf("nil verifier err = %v, want ErrVerificationDisabled", err)
+	}
+
+	env := newVerificationEnv(t, verification.NewFakeVerifier())
+	if _, err := env.service.StartChallenge(env.userID); !errors.Is(err, ErrNoPhotosToVerify) {
+		t.Fatalf("no photos err = %v, want ErrNoPhotosToVerify", err)
+	}
+
+	env.addPhoto(t, testImage(t, true))
+	for i := 0; i < DefaultVerificationPolicy().MaxChallengesPerDay; i++ {
+		if _, err := env.service.StartChallenge(env.userID); err != nil {
+			t.Fatalf("challenge %d: %v", i+1, err)
+		}
+	}
+	if _, err := env.service.StartChallenge(env.userID); !errors.Is(err, ErrTooManyChallenges) {
+		t.Fatalf("err = %v, want ErrTooManyChallenges", err)
+	}
+
+	// Ertesi gün yeniden denenebilir
+	env.now = env.now.Add(25 * time.Hour)
+	if _, err := env.service.StartChallenge(env.userID); err != nil {
+		t.Fatalf("next day: %v", err)
+	}
+}
+
+func TestDeletingVerifiedPhotoRemovesBadge(t *testing.T) {
+	env := newVerificationEnv(t, verification.NewFakeVerifier())
+	face := testImage(t, true)
+	verifiedPhoto := env.addPhoto(t, face)
+	other := env.addPhoto(t, testImage(t, false))
+
+	challenge, err := env.service.StartChallenge(env.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.service.SubmitSelfie(context.Background(), env.userID, challenge.ID, face); err != nil {
+		t.Fatal(err)
+	}
+
+	// Doğrulanmamış fotoğrafı silmek rozeti etkilemez
+	if err := env.users.DeletePhoto(context.Background(), env.userID, other); err != nil {
+		t.Fatal(err)
+	}
+	if !env.verified(t) {
+		t.Fatal("badge removed after deleting an unverified photo")
+	}
+
+	if err := env.users.DeletePhoto(context.Background(), env.userID, verifiedPhoto); err != nil {
+		t.Fatal(err)
+	}
+	if env.verified(t) {
+		t.Fatal("badge kept after deleting the only verified photo")
+	}
+}
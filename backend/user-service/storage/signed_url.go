@@ -0,0 +1,72 @@
+// signed_url.go - Süreli ve imzalı fotoğraf indirme adresleri
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"eros/shared/logging"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// URLSigner - Depolama anahtarları için HMAC imzalı, süreli indirme adresi üretir
+type URLSigner struct {
+	secret   []byte
+	basePath string // Örn. "/api/photos/file/"
+	ttl      time.Duration
+}
+
+func NewURLSigner(secret []byte, basePath string, ttl time.Duration) *URLSigner {
+	return &URLSigner{secret: secret, basePath: basePath, ttl: ttl}
+}
+
+// URLSignerFromEnv - PHOTO_URL_SECRET ve PHOTO_URL_TTL_MINUTES değişkenlerinden oluştur
+// Secret verilmezse rastgele üretilir; bu durumda adresler yeniden başlatmada geçersizleşir.
+func URLSignerFromEnv(basePath string) *URLSigner {
+	secret := []byte(os.Getenv("PHOTO_URL_SECRET"))
+	if len(secret) == 0 {
+		slog.Warn("PHOTO_URL_SECRET not set, using a random secret (photo URLs will not survive restarts)")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			logging.Fatal("failed to generate photo URL secret", err)
+		}
+	}
+
+	ttl := 15 * time.Minute
+	if v := os.Getenv("PHOTO_URL_TTL_MINUTES"); v != "" {
+		if minutes, err := strconv.Atoi(v); err == nil && minutes > 0 {
+			ttl = time.Duration(minutes) * time.Minute
+		}
+	}
+
+	return NewURLSigner(secret, basePath, ttl)
+}
+
+// SignedURL - Anahtar için şu andan itibaren TTL süre geçerli adres
+func (s *URLSigner) SignedURL(key string) string {
+	expires := time.Now().Add(s.ttl).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("sig", s.signature(key, expires))
+	return s.basePath + key + "?" + query.Encode()
+}
+
+// Verify - İmza geçerli ve süresi dolmamış mı
+func (s *URLSigner) Verify(key, expires, sig string) bool {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(s.signature(key, exp)))
+}
+
+func (s *URLSigner) signature(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
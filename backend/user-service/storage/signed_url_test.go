@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseSignedURL - Adresten anahtar, expires ve sig değerlerini ayır
+func parseSignedURL(t *testing.T, basePath, signed string) (key, expires, sig string) {
+	t.Helper()
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(u.Path, basePath) {
+		t.Fatalf("signed URL %q does not start with %q", signed, basePath)
+	}
+	return strings.TrimPrefix(u.Path, basePath), u.Query().Get("expires"), u.Query().Get("sig")
+}
+
+func TestURLSigner(t *testing.T) {
+	const basePath = "/api/photos/file/"
+	signer := NewURLSigner([]byte("test-secret"), basePath, 15*time.Minute)
+
+	key, expires, sig := parseSignedURL(t, basePath, signer.SignedURL("photos/12/3f2a.jpg"))
+	if key != "photos/12/3f2a.jpg" {
+		t.Fatalf("key = %q", key)
+	}
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left := time.Until(time.Unix(exp, 0)); left < 14*time.Minute || left > 15*time.Minute {
+		t.Errorf("URL expires in %s, want about 15m", left)
+	}
+
+	if !signer.Verify(key, expires, sig) {
+		t.Fatal("fresh signed URL rejected")
+	}
+
+	tests := []struct {
+		name              string
+		key, expires, sig string
+	}{
+		{"other key", "photos/13/3f2a.jpg", expires, sig},
+		{"extended expiry", key, strconv.FormatInt(exp+3600, 10), sig},
+		{"tampered signature", key, expires, strings.Repeat("0", len(sig))},
+		{"malformed expiry", key, "soon", sig},
+		{"missing signature", key, expires, ""},
+	}
+	for _, tt := range tests {
+		if signer.Verify(tt.key, tt.expires, tt.sig) {
+			t.Errorf("%s: Verify accepted a forged URL", tt.name)
+		}
+	}
+
+	other := NewURLSigner([]byte("other-secret"), basePath, 15*time.Minute)
+	if other.Verify(key, expires, sig) {
+		t.Error("URL signed with another secret was accepted")
+	}
+}
+
+func TestURLSignerExpiry(t *testing.T) {
+	const basePath = "/api/photos/file/"
+	signer := NewURLSigner([]byte("test-secret"), basePath, -time.Second)
+
+	key, expires, sig := parseSignedURL(t, basePath, signer.SignedURL("photos/12/3f2a.jpg"))
+	if signer.Verify(key, expires, sig) {
+		t.Fatal("expired signed URL accepted")
+	}
+}
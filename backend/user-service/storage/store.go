@@ -0,0 +1,100 @@
+// store.go - Fotoğraf dosyası depolama arayüzü ve yapılandırması
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNotFound        = errors.New("object not found")
+	ErrInvalidKey      = errors.New("invalid object key")
+	ErrUnsupportedType = errors.New("unsupported image type")
+)
+
+// ObjectInfo - Saklanan dosyanın bilgileri
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// PhotoStore - Fotoğraf dosyalarının saklandığı yer
+// Anahtarlar "photos/12/3f2a...c1.jpg" biçiminde, "/" ile ayrılmış göreli yollardır.
+type PhotoStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// imageTypes - Kabul edilen içerik türleri ve dosya uzantıları
+var imageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// DetectImageType - İçeriğe bakarak (istemcinin bildirdiği türe güvenmeden) resim türünü bul
+func DetectImageType(data []byte) (contentType, ext string, err error) {
+	contentType = http.DetectContentType(data)
+	ext, ok := imageTypes[contentType]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+	return contentType, ext, nil
+}
+
+// IsKey - Değer bir depolama anahtarı mı (eski kayıtlardaki data: ve http URL'leri değil)
+func IsKey(value string) bool {
+	return value != "" && !strings.HasPrefix(value, "data:") && !strings.Contains(value, "://")
+}
+
+// validateKey - Dizin dışına çıkan veya mutlak anahtarları reddet
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+// FromEnv - PHOTO_STORAGE değişkenine göre depolamayı oluştur ("local" veya "s3")
+func FromEnv() (PhotoStore, error) {
+	switch backend := os.Getenv("PHOTO_STORAGE"); backend {
+	case "", "local":
+		dir := os.Getenv("PHOTO_STORAGE_DIR")
+		if dir == "" {
+			dir = "./uploads"
+		}
+		return NewLocalStore(dir)
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown PHOTO_STORAGE %q", backend)
+	}
+}
+
+// MaxPhotoBytesFromEnv - Yüklenebilecek en büyük fotoğraf boyutu (PHOTO_MAX_BYTES, varsayılan 10MB)
+func MaxPhotoBytesFromEnv() int64 {
+	if v := os.Getenv("PHOTO_MAX_BYTES"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 10 << 20
+}
@@ -0,0 +1,103 @@
+// http.go - Harici yüz doğrulama servisine HTTP adaptörü
+package verification
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPVerifier - Yüz karşılaştırmayı harici bir servise devreder
+//
+// İstek (POST {URL}, Authorization: Bearer {API key}):
+//
+//	{"selfie": "<base64>", "pose": "thumbs_up", "references": ["<base64>", ...]}
+//
+// Yanıt:
+//
+//	{"face_found": true, "pose_matched": true, "similarities": [0.93, 0.12, ...]}
+type HTTPVerifier struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func NewHTTPVerifier(url, apiKey string) (*HTTPVerifier, error) {
+	if url == "" {
+		return nil, errors.New("FACE_VERIFIER_URL is required")
+	}
+
+	return &HTTPVerifier{
+		url:    url,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+type httpVerifyRequest struct {
+	Selfie     string   `json:"selfie"`
+	Pose       Pose     `json:"pose"`
+	References []string `json:"references"`
+}
+
+type httpVerifyResponse struct {
+	FaceFound    bool      `json:"face_found"`
+	PoseMatched  bool      `json:"pose_matched"`
+	Similarities []float64 `json:"similarities"`
+}
+
+// Verify - Selfie ve referansları servise gönder
+func (v *HTTPVerifier) Verify(ctx context.Context, selfie []byte, pose Pose, references [][]byte) (*Result, error) {
+	payload := httpVerifyRequest{
+		Selfie:     base64.StdEncoding.EncodeToString(selfie),
+		Pose:       pose,
+		References: make([]string, len(references)),
+	}
+	for i, ref := range references {
+		payload.References[i] = base64.StdEncoding.EncodeToString(ref)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if v.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+v.apiKey)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("face verifier returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	var out httpVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	if !out.FaceFound {
+		return nil, ErrNoFace
+	}
+	if len(out.Similarities) != len(references) {
+		return nil, fmt.Errorf("face verifier returned %d similarities for %d references", len(out.Similarities), len(references))
+	}
+
+	return &Result{PoseMatched: out.PoseMatched, Similarities: out.Similarities}, nil
+}
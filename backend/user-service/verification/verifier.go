@@ -0,0 +1,80 @@
+// verifier.go - Selfie ile profil fotoğrafı doğrulama arayüzü ve poz meydan okumaları
+package verification
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+var ErrNoFace = errors.New("no face found in selfie")
+
+// Pose - Kullanıcıdan selfie'de yapması istenen hareket
+// Rastgele poz, önceden çekilmiş bir fotoğrafın yüklenmesini zorlaştırır.
+type Pose string
+
+const (
+	PoseTurnLeft   Pose = "turn_left"
+	PoseTurnRight  Pose = "turn_right"
+	PoseThumbsUp   Pose = "thumbs_up"
+	PosePeaceSign  Pose = "peace_sign"
+	PoseTouchNose  Pose = "touch_nose"
+	PoseHandOnHead Pose = "hand_on_head"
+)
+
+// poseInstructions - Uygulamada gösterilen yönergeler
+var poseInstructions = map[Pose]string{
+	PoseTurnLeft:   "Başını sola çevir",
+	PoseTurnRight:  "Başını sağa çevir",
+	PoseThumbsUp:   "Başparmağını kaldır",
+	PosePeaceSign:  "Zafer işareti yap",
+	PoseTouchNose:  "İşaret parmağınla burnuna dokun",
+	PoseHandOnHead: "Elini başının üstüne koy",
+}
+
+// poses - Rastgele seçim için sabit sıra
+var poses = []Pose{PoseTurnLeft, PoseTurnRight, PoseThumbsUp, PosePeaceSign, PoseTouchNose, PoseHandOnHead}
+
+// RandomPose - Tahmin edilemeyen bir poz seç
+func RandomPose() (Pose, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(poses))))
+	if err != nil {
+		return "", err
+	}
+	return poses[n.Int64()], nil
+}
+
+// Instruction - Pozun kullanıcıya gösterilecek yönergesi
+func (p Pose) Instruction() string {
+	return poseInstructions[p]
+}
+
+// Result - Selfie karşılaştırma sonucu
+type Result struct {
+	PoseMatched  bool      // Selfie istenen pozu içeriyor mu
+	Similarities []float64 // Her referans fotoğraf için 0-1 arası yüz benzerliği (aynı sırayla)
+}
+
+// FaceVerifier - Selfie'yi istenen poza ve profil fotoğraflarına karşı doğrular
+// Selfie'de yüz bulunamazsa ErrNoFace döner.
+type FaceVerifier interface {
+	Verify(ctx context.Context, selfie []byte, pose Pose, references [][]byte) (*Result, error)
+}
+
+// FromEnv - FACE_VERIFIER değişkenine göre doğrulayıcıyı oluştur ("http"; "fake" yalnızca testler için)
+// Tanımlı değilse nil döner ve doğrulama kapalıdır.
+func FromEnv() (FaceVerifier, error) {
+	switch backend := os.Getenv("FACE_VERIFIER"); backend {
+	case "":
+		return nil, nil
+	case "fake":
+		return NewFakeVerifier(), nil
+	case "http":
+		return NewHTTPVerifier(os.Getenv("FACE_VERIFIER_URL"), os.Getenv("FACE_VERIFIER_API_KEY"))
+	default:
+		return nil, fmt.Errorf("unknown FACE_VERIFIER %q", backend)
+	}
+}